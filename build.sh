@@ -1,3 +1,3 @@
 #!/bin/bash
 
-gx-go rewrite && go build -tags=embed ./... && gx-go rewrite --undo
+gx-go rewrite && go build -tags="embed fts5" ./... && gx-go rewrite --undo
@@ -1,3 +1,9 @@
 #!/bin/bash
 
-gx-go rewrite && go install -tags=embed ./... && gx-go rewrite --undo
+# ./install.sh norocksdb builds without the rocksdb datastore
+tags="embed fts5"
+if [ "$1" = "norocksdb" ]; then
+    tags="norocksdb fts5"
+fi
+
+gx-go rewrite && go install -tags="$tags" ./... && gx-go rewrite --undo
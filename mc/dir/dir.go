@@ -1,4 +1,4 @@
-package main
+package dir
 
 import (
 	p2p_host "github.com/libp2p/go-libp2p-host"
@@ -30,6 +30,17 @@ type ManifestStore interface {
 	Lookup(entity string) []*pb.Manifest
 }
 
+// NewDirectory creates a directory serving the directory protocols on host
+func NewDirectory(id mc.PeerIdentity, host p2p_host.Host) *Directory {
+	dir := &Directory{PeerIdentity: id, host: host, peers: make(map[p2p_peer.ID]PeerRecord), mfs: NewManifestStore()}
+	host.SetStreamHandler("/mediachain/dir/register", dir.registerHandler)
+	host.SetStreamHandler("/mediachain/dir/lookup", dir.lookupHandler)
+	host.SetStreamHandler("/mediachain/dir/list", dir.listHandler)
+	host.SetStreamHandler("/mediachain/dir/listns", dir.listnsHandler)
+	host.SetStreamHandler("/mediachain/dir/listmf", dir.listmfHandler)
+	return dir
+}
+
 func (dir *Directory) registerPeer(rec PeerRecord) {
 	log.Printf("directory: register %s", rec.peer.ID.Pretty())
 	dir.mx.Lock()
@@ -65,13 +76,14 @@ func (dir *Directory) listPeers(ns string) []string {
 
 	case strings.HasSuffix(ns, ".*"):
 		pre := ns[:len(ns)-2]
+		prefix := ns[:len(ns)-1]
 		return dir.listPeersFilter(func(rec PeerRecord) bool {
 			if rec.publisher == nil {
 				return false
 			}
 
 			for _, xns := range rec.publisher.Namespaces {
-				if strings.HasPrefix(xns, pre) {
+				if xns == pre || strings.HasPrefix(xns, prefix) {
 					return true
 				}
 			}
@@ -1,4 +1,4 @@
-package main
+package dir
 
 import (
 	ggproto "github.com/gogo/protobuf/proto"
@@ -72,7 +72,7 @@ func (mfs *ManifestStoreImpl) putManifest(src p2p_peer.ID, mf *pb.Manifest) {
 		mfs.keys[kid] = pubk
 	}
 
-	ok, err = verifyManifest(mf, pubk)
+	ok, err = mc.VerifyManifest(mf, pubk)
 	switch {
 	case err != nil:
 		log.Printf("Error verifying manifest %s: %s", mfh, err.Error())
@@ -140,16 +140,3 @@ func hashManifest(mf *pb.Manifest) (multihash.Multihash, error) {
 
 	return mc.Hash(bytes), nil
 }
-
-func verifyManifest(mf *pb.Manifest, pubk p2p_crypto.PubKey) (bool, error) {
-	sig := mf.Signature
-	mf.Signature = nil
-	bytes, err := ggproto.Marshal(mf)
-	mf.Signature = sig
-
-	if err != nil {
-		return false, err
-	}
-
-	return pubk.Verify(bytes, sig)
-}
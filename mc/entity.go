@@ -6,7 +6,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	ggproto "github.com/gogo/protobuf/proto"
 	p2p_crypto "github.com/libp2p/go-libp2p-crypto"
+	pb "github.com/mediachain/concat/proto"
 	multihash "github.com/multiformats/go-multihash"
 	"log"
 	"net/http"
@@ -83,6 +85,20 @@ func LookupEntityKey(entity string, keyId string) (p2p_crypto.PubKey, error) {
 	return lookup(user, keyId)
 }
 
+// VerifyManifest verifies the signature of a manifest with the entity key
+func VerifyManifest(mf *pb.Manifest, pubk p2p_crypto.PubKey) (bool, error) {
+	sig := mf.Signature
+	mf.Signature = nil
+	bytes, err := ggproto.Marshal(mf)
+	mf.Signature = sig
+
+	if err != nil {
+		return false, err
+	}
+
+	return pubk.Verify(bytes, sig)
+}
+
 type LookupKeyFunc func(user, keyId string) (p2p_crypto.PubKey, error)
 
 func lookupBlockstack(user, keyId string) (p2p_crypto.PubKey, error) {
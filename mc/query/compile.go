@@ -18,6 +18,15 @@ type RowScanner interface {
 	Scan(res ...interface{}) error
 }
 
+// SQLDialect selects the SQL dialect of compiled queries; queries are
+// compiled for sqlite unless otherwise specified.
+type SQLDialect int
+
+const (
+	SQLite SQLDialect = iota
+	Postgres
+)
+
 type QueryCompileError string
 
 func (e QueryCompileError) Error() string {
@@ -29,6 +38,36 @@ func (e QueryCompileError) Error() string {
 // values from an sql result set
 // Note: The row selector should be used in single-threaded context
 func CompileQuery(q *Query) (string, RowSelector, error) {
+	return compileQuery(q, false, SQLite)
+}
+
+// CompileQueryDialect compiles a query to sql in the specified dialect
+func CompileQueryDialect(q *Query, dialect SQLDialect) (string, RowSelector, error) {
+	return compileQuery(q, false, dialect)
+}
+
+// CompileCursorQuery compiles a cursor query to sql.
+// The returned row selector tracks the counter of the last scanned row.
+func CompileCursorQuery(q *Query) (string, *RowSelectCursor, error) {
+	return CompileCursorQueryDialect(q, SQLite)
+}
+
+// CompileCursorQueryDialect compiles a cursor query to sql in the specified
+// dialect
+func CompileCursorQueryDialect(q *Query, dialect SQLDialect) (string, *RowSelectCursor, error) {
+	if !q.IsCursorQuery() {
+		return "", nil, QueryCompileError("Not a cursor query")
+	}
+
+	sqlq, rsel, err := compileQuery(q, true, dialect)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return sqlq, &RowSelectCursor{rs: rsel}, nil
+}
+
+func compileQuery(q *Query, cursor bool, dialect SQLDialect) (string, RowSelector, error) {
 	var sqlq string
 	var join bool
 	switch {
@@ -41,26 +80,16 @@ func CompileQuery(q *Query) (string, RowSelector, error) {
 		join = true
 	}
 
-	if isIndexCriteria(q.criteria) {
-		tabs, err := indexCriteriaTables(q.criteria)
-		if err != nil {
-			return "", nil, err
-		}
-
-		for _, tab := range tabs {
-			sqlq = fmt.Sprintf("%s JOIN %s ON Envelope.id = %s.id", sqlq, tab, tab)
-		}
-
-		join = true
-	}
-
 	cols, err := compileQueryColumns(q, join)
 	if err != nil {
 		return "", nil, err
 	}
+	if cursor {
+		cols = fmt.Sprintf("%s, counter", cols)
+	}
 	sqlq = fmt.Sprintf(sqlq, cols)
 
-	crit, err := compileQueryCriteria(q, join)
+	crit, err := compileQueryCriteria(q, join, dialect)
 	if err != nil {
 		return "", nil, err
 	}
@@ -68,13 +97,25 @@ func CompileQuery(q *Query) (string, RowSelector, error) {
 		sqlq = fmt.Sprintf("%s WHERE %s", sqlq, crit)
 	}
 
-	order := compileQueryOrder(q, join)
+	if q.group != nil {
+		sqlq = fmt.Sprintf("%s GROUP BY %s", sqlq, strings.Join(q.group, ", "))
+	}
+
+	order := compileQueryOrder(q, join, dialect)
 	if order != "" {
 		sqlq = fmt.Sprintf("%s ORDER BY %s", sqlq, order)
 	}
 
-	if q.limit > 0 {
+	switch {
+	case q.limit > 0:
 		sqlq = fmt.Sprintf("%s LIMIT %d", sqlq, q.limit)
+	case q.offset > 0 && dialect == SQLite:
+		// sqlite requires a LIMIT clause for OFFSET
+		sqlq = fmt.Sprintf("%s LIMIT -1", sqlq)
+	}
+
+	if q.offset > 0 {
+		sqlq = fmt.Sprintf("%s OFFSET %d", sqlq, q.offset)
 	}
 
 	rsel, err := compileQueryRowSelector(q)
@@ -86,37 +127,79 @@ func CompileQuery(q *Query) (string, RowSelector, error) {
 }
 
 func compileQueryColumns(q *Query, join bool) (string, error) {
+	if q.group != nil && !isCompoundSelector(q.selector) {
+		return "", QueryCompileError("GROUP BY requires a compound selector")
+	}
+
 	switch sel := q.selector.(type) {
 	case SimpleSelector:
+		if q.distinct {
+			col := selectorColumn(sel, selectorColumnCompound)
+			return fmt.Sprintf("DISTINCT %s", disambigSelector(col, join)), nil
+		}
+
 		col := selectorColumn(sel, selectorColumnSimple)
 		return disambigSelector(col, join), nil
 
 	case CompoundSelector:
-		if len(sel) == 1 {
-			col := selectorColumn(sel[0], selectorColumnSimple)
-			return disambigSelector(col, join), nil
+		if !checkCompoundSelector(sel, q.group) {
+			return "", QueryCompileError("Illegal compound selector; simple selectors must be grouped when used with functions")
+		}
+
+		if len(sel) == 1 && !q.distinct {
+			ssel, ok := sel[0].(SimpleSelector)
+			if ok {
+				col := selectorColumn(ssel, selectorColumnSimple)
+				return disambigSelector(col, join), nil
+			}
 		}
 
 		cols := make([]string, len(sel))
 		for x := 0; x < len(sel); x++ {
-			col := selectorColumn(sel[x], selectorColumnCompound)
-			cols[x] = disambigSelector(col, join)
+			switch xsel := sel[x].(type) {
+			case SimpleSelector:
+				col := selectorColumn(xsel, selectorColumnCompound)
+				cols[x] = disambigSelector(col, join)
+
+			case *FunctionSelector:
+				col, err := compileFunctionColumn(xsel, join)
+				if err != nil {
+					return "", err
+				}
+				cols[x] = col
+
+			default:
+				return "", QueryCompileError(fmt.Sprintf("Unexpected selector type: %T", xsel))
+			}
 		}
-		return strings.Join(cols, ", "), nil
 
-	case *FunctionSelector:
-		if !checkFunctionSelector(sel) {
-			return "", QueryCompileError(fmt.Sprintf("Illegal selector: %s(%s)", sel.op, sel.sel))
+		if q.distinct {
+			return fmt.Sprintf("DISTINCT %s", strings.Join(cols, ", ")), nil
 		}
+		return strings.Join(cols, ", "), nil
 
-		col := selectorColumn(sel.sel, selectorColumnFun)
-		return fmt.Sprintf("%s(%s)", sel.op, disambigSelector(col, join)), nil
+	case *FunctionSelector:
+		return compileFunctionColumn(sel, join)
 
 	default:
 		return "", QueryCompileError(fmt.Sprintf("Unexpected selector type: %T", sel))
 	}
 }
 
+func compileFunctionColumn(sel *FunctionSelector, join bool) (string, error) {
+	if !checkFunctionSelector(sel) {
+		return "", QueryCompileError(fmt.Sprintf("Illegal selector: %s(%s)", sel.op, sel.sel))
+	}
+
+	if sel.distinct {
+		col := selectorColumn(sel.sel, selectorColumnCompound)
+		return fmt.Sprintf("%s(DISTINCT %s)", sel.op, disambigSelector(col, join)), nil
+	}
+
+	col := selectorColumn(sel.sel, selectorColumnFun)
+	return fmt.Sprintf("%s(%s)", sel.op, disambigSelector(col, join)), nil
+}
+
 // when we are JOINing, id is ambiguous because it is a column in both tables;
 // this funciton disambiguates
 func disambigSelector(col string, join bool) string {
@@ -156,13 +239,13 @@ var selectorColumnFun = map[string]string{
 	"publisher": "DISTINCT publisher",
 	"source":    "DISTINCT source"}
 
-func compileQueryCriteria(q *Query, join bool) (string, error) {
-	nscrit := compileNamespaceCriteria(q.namespace)
+func compileQueryCriteria(q *Query, join bool, dialect SQLDialect) (string, error) {
+	nscrit := compileNamespaceCriteria(q.namespace, dialect)
 	if q.criteria == nil {
 		return nscrit, nil
 	}
 
-	scrit, err := compileSelectorCriteria(q.criteria, join)
+	scrit, err := compileSelectorCriteria(q.criteria, join, dialect)
 	if err != nil {
 		return "", err
 	}
@@ -174,7 +257,15 @@ func compileQueryCriteria(q *Query, join bool) (string, error) {
 	return scrit, nil
 }
 
-func compileQueryOrder(q *Query, join bool) string {
+// text columns are ordered bytewise in postgres as in sqlite, regardless
+// of the database collation, so that id order is the same in all dbs
+var textOrderSelectorp = map[string]bool{
+	"id":        true,
+	"namespace": true,
+	"publisher": true,
+	"source":    true}
+
+func compileQueryOrder(q *Query, join bool, dialect SQLDialect) string {
 	if q.order == nil {
 		return ""
 	}
@@ -182,6 +273,9 @@ func compileQueryOrder(q *Query, join bool) string {
 	strs := make([]string, len(q.order))
 	for x, spec := range q.order {
 		str := disambigSelector(spec.sel, join)
+		if dialect == Postgres && textOrderSelectorp[spec.sel] {
+			str = fmt.Sprintf("%s COLLATE \"C\"", str)
+		}
 		if spec.dir != "" {
 			str = fmt.Sprintf("%s %s", str, spec.dir)
 		}
@@ -191,36 +285,102 @@ func compileQueryOrder(q *Query, join bool) string {
 	return strings.Join(strs, ", ")
 }
 
-func compileNamespaceCriteria(ns string) string {
+// namespace wildcards match the namespace and the namespaces under it,
+// as in the node's namespace rule checks; foo.* matches foo and foo.bar,
+// but not foobar. Prefixes are case sensitive; LIKE is case insensitive
+// in sqlite, so use GLOB.
+func compileNamespaceCriteria(ns string, dialect SQLDialect) string {
 	switch {
 	case ns == "*":
 		return ""
 	case ns[len(ns)-1] == '*':
 		pre := ns[:len(ns)-2]
-		return fmt.Sprintf("namespace LIKE '%s%%'", pre)
+		return fmt.Sprintf("(namespace = '%s' OR %s)", pre, compilePattern("namespace", "PREFIX", pre+".", dialect))
 	default:
 		return fmt.Sprintf("namespace = '%s'", ns)
 	}
 }
 
-func compileSelectorCriteria(c QueryCriteria, join bool) (string, error) {
+func compileSelectorCriteria(c QueryCriteria, join bool, dialect SQLDialect) (string, error) {
 	switch c := c.(type) {
 	case *ValueCriteria:
-		return fmt.Sprintf("%s %s '%s'", disambigSelector(c.sel, join), c.op, c.val), nil
+		switch c.op {
+		case "IN":
+			return compileValueIn(disambigSelector(c.sel, join), c.vals), nil
+		case "LIKE", "PREFIX":
+			return compilePattern(disambigSelector(c.sel, join), c.op, c.val, dialect), nil
+		default:
+			return fmt.Sprintf("%s %s '%s'", disambigSelector(c.sel, join), c.op, c.val), nil
+		}
 
 	case *RangeCriteria:
 		return fmt.Sprintf("%s %s %d", c.sel, c.op, c.val), nil
 
 	case *IndexCriteria:
-		return fmt.Sprintf("%s = '%s'", c.sel, c.val), nil
+		// index criteria are compiled to a subquery on the index table, as
+		// joining would produce a row for each matching index entry of a
+		// statement, and none for statements without entries
+		tab, ok := indexCriteriaTableNames[c.sel]
+		if !ok {
+			return "", QueryCompileError(fmt.Sprintf("Unexpected index selector: %s", c.sel))
+		}
+
+		var icrit string
+		switch c.op {
+		case "IN":
+			icrit = compileValueIn(c.sel, c.vals)
+		case "LIKE", "PREFIX":
+			icrit = compilePattern(c.sel, c.op, c.val, dialect)
+		default:
+			icrit = fmt.Sprintf("%s = '%s'", c.sel, c.val)
+		}
+		return fmt.Sprintf("%s IN (SELECT id FROM %s WHERE %s)", disambigSelector("id", join), tab, icrit), nil
+
+	case *FieldCriteria:
+		// field criteria are compiled to a subquery, so that they can be
+		// freely combined without joining the Fields table for each path
+		var vcrit string
+		switch c.op {
+		case "IN":
+			vcrit = compileValueIn("value", c.vals)
+		default:
+			vcrit = fmt.Sprintf("value = '%s'", c.val)
+		}
+		return fmt.Sprintf("%s IN (SELECT id FROM Fields WHERE path = '%s' AND %s)", disambigSelector("id", join), c.path, vcrit), nil
+
+	case *NamespaceCriteria:
+		crit := compileNamespaceCriteria(c.ns, dialect)
+		if crit == "" {
+			// the * wildcard matches everything
+			return "1", nil
+		}
+		return crit, nil
+
+	case *TextCriteria:
+		if dialect != SQLite {
+			return "", QueryCompileError("Text criteria require sqlite full-text search")
+		}
+		return fmt.Sprintf("%s IN (SELECT id FROM Text WHERE Text MATCH '%s')", disambigSelector("id", join), c.val), nil
+
+	case *SupersedeCriteria:
+		// the targets are joined with their envelopes in the subquery, so
+		// that it is independent of the outer query
+		var rcrit string
+		if c.op == "retracted" {
+			rcrit = " AND Supersede.retract"
+		}
+		return fmt.Sprintf("%s IN (SELECT Supersede.target FROM Supersede JOIN Envelope AS Target ON Supersede.target = Target.id WHERE Supersede.publisher = Target.publisher%s)", disambigSelector("id", join), rcrit), nil
+
+	case *DataCriteria:
+		return "", QueryCompileError("Data criteria can't be compiled; use a data query")
 
 	case *CompoundCriteria:
-		left, err := compileSelectorCriteria(c.left, join)
+		left, err := compileSelectorCriteria(c.left, join, dialect)
 		if err != nil {
 			return "", err
 		}
 
-		right, err := compileSelectorCriteria(c.right, join)
+		right, err := compileSelectorCriteria(c.right, join, dialect)
 		if err != nil {
 			return "", err
 		}
@@ -228,7 +388,7 @@ func compileSelectorCriteria(c QueryCriteria, join bool) (string, error) {
 		return fmt.Sprintf("(%s %s %s)", left, c.op, right), nil
 
 	case *NegatedCriteria:
-		expr, err := compileSelectorCriteria(c.e, join)
+		expr, err := compileSelectorCriteria(c.e, join, dialect)
 		if err != nil {
 			return "", err
 		}
@@ -240,6 +400,40 @@ func compileSelectorCriteria(c QueryCriteria, join bool) (string, error) {
 	}
 }
 
+// An empty value list is not valid sql in postgres, and matches nothing
+func compileValueIn(col string, vals []string) string {
+	if len(vals) == 0 {
+		return "0 = 1"
+	}
+	return fmt.Sprintf("%s IN (%s)", col, compileValueList(vals))
+}
+
+func compileValueList(vals []string) string {
+	strs := make([]string, len(vals))
+	for x, val := range vals {
+		strs[x] = fmt.Sprintf("'%s'", val)
+	}
+	return strings.Join(strs, ", ")
+}
+
+// Patterns are compiled to GLOB, which is case sensitive and allows sqlite
+// to use the column index for a range scan on the pattern prefix.
+// The pattern lexemes exclude the GLOB special characters.
+// Postgres has no GLOB, but its LIKE is case sensitive; the LIKE wildcards
+// are escaped in prefixes.
+func compilePattern(col, op, val string, dialect SQLDialect) string {
+	switch {
+	case dialect == Postgres && op == "PREFIX":
+		return fmt.Sprintf("%s LIKE '%s%%'", col, strings.NewReplacer("%", "\\%", "_", "\\_").Replace(val))
+	case dialect == Postgres:
+		return fmt.Sprintf("%s LIKE '%s'", col, val)
+	case op == "PREFIX":
+		return fmt.Sprintf("%s GLOB '%s*'", col, val)
+	default:
+		return fmt.Sprintf("%s GLOB '%s'", col, strings.NewReplacer("%", "*", "_", "?").Replace(val))
+	}
+}
+
 func compileQueryRowSelector(q *Query) (RowSelector, error) {
 	switch sel := q.selector.(type) {
 	case SimpleSelector:
@@ -251,18 +445,27 @@ func compileQueryRowSelector(q *Query) (RowSelector, error) {
 		return makef(), nil
 
 	case CompoundSelector:
+		keys := make([]string, len(sel))
 		srs := make([]SimpleRowSelector, len(sel))
 		ptrs := make([]interface{}, len(sel))
-		for x, ssel := range sel {
-			makef, ok := makeSimpleRowSelector[string(ssel)]
+		for x, xsel := range sel {
+			var makef MakeSimpleRowSelector
+			var ok bool
+			switch xsel := xsel.(type) {
+			case SimpleSelector:
+				makef, ok = makeSimpleRowSelector[string(xsel)]
+			case *FunctionSelector:
+				makef, ok = makeFunRowSelector[xsel.op]
+			}
 			if !ok {
-				return nil, QueryCompileError(fmt.Sprintf("Unexpected selector: %s", sel))
+				return nil, QueryCompileError(fmt.Sprintf("Unexpected selector: %s", selectorKey(xsel)))
 			}
+			keys[x] = selectorKey(xsel)
 			srs[x] = makef()
 			ptrs[x] = srs[x].ptr()
 		}
 
-		return &RowSelectCompound{sel, srs, ptrs}, nil
+		return &RowSelectCompound{keys, srs, ptrs}, nil
 
 	case *FunctionSelector:
 		makef, ok := makeFunRowSelector[sel.op]
@@ -286,7 +489,7 @@ type SimpleRowSelector interface {
 }
 
 type RowSelectCompound struct {
-	sel  []SimpleSelector
+	keys []string
 	srs  []SimpleRowSelector
 	ptrs []interface{}
 }
@@ -298,17 +501,42 @@ func (rs *RowSelectCompound) Scan(src RowScanner) (interface{}, error) {
 	}
 
 	obj := make(map[string]interface{})
-	for x, sel := range rs.sel {
+	for x, key := range rs.keys {
 		val, err := rs.srs[x].value()
 		if err != nil {
 			return nil, err
 		}
-		obj[string(sel)] = val
+		obj[key] = val
 	}
 
 	return obj, nil
 }
 
+// RowSelectCursor wraps the row selector of a cursor query, scanning
+// the trailing counter column.
+type RowSelectCursor struct {
+	rs      RowSelector
+	counter int64
+}
+
+func (rs *RowSelectCursor) Scan(src RowScanner) (interface{}, error) {
+	return rs.rs.Scan(&cursorRowScanner{src, &rs.counter})
+}
+
+// Cursor returns the counter of the last scanned row
+func (rs *RowSelectCursor) Cursor() int64 {
+	return rs.counter
+}
+
+type cursorRowScanner struct {
+	src     RowScanner
+	counter *int64
+}
+
+func (cs *cursorRowScanner) Scan(res ...interface{}) error {
+	return cs.src.Scan(append(res, cs.counter)...)
+}
+
 type RowSelectStatement struct {
 	val sql.RawBytes
 }
@@ -511,8 +739,8 @@ func selectorp(sel QuerySelector, tbl map[string]bool, funp bool) bool {
 		return tbl[string(sel)]
 
 	case CompoundSelector:
-		for _, ssel := range sel {
-			if !tbl[string(ssel)] {
+		for _, xsel := range sel {
+			if !selectorp(xsel, tbl, funp) {
 				return false
 			}
 		}
@@ -534,74 +762,47 @@ func isEnvelopeSelector(sel QuerySelector) bool {
 	return selectorp(sel, envelopeSelectorp, true)
 }
 
-func isStatementCriteria(c QueryCriteria) bool {
-	switch c := c.(type) {
-	case *ValueCriteria:
-		return c.sel == "id"
-
-	case *CompoundCriteria:
-		return isStatementCriteria(c.left) && isStatementCriteria(c.right)
-
-	case *NegatedCriteria:
-		return isStatementCriteria(c.e)
-
-	default:
-		return false
-	}
+func isCompoundSelector(sel QuerySelector) bool {
+	_, ok := sel.(CompoundSelector)
+	return ok
 }
 
-func isIndexCriteria(c QueryCriteria) bool {
-	switch c := c.(type) {
-	case *IndexCriteria:
-		return true
-
-	case *CompoundCriteria:
-		return isIndexCriteria(c.left) || isIndexCriteria(c.right)
+// cursor queries append the counter column, so the selector can't use
+// DISTINCT or aggregate functions
+func isCursorSelector(sel QuerySelector) bool {
+	switch sel := sel.(type) {
+	case SimpleSelector:
+		col := selectorColumn(sel, selectorColumnSimple)
+		return !strings.HasPrefix(col, "DISTINCT")
 
-	case *NegatedCriteria:
-		return isIndexCriteria(c.e)
+	case CompoundSelector:
+		if len(sel) == 1 {
+			return isCursorSelector(sel[0])
+		}
+		return !hasFunctionSelector(sel)
 
 	default:
 		return false
 	}
 }
 
-func indexCriteriaTables(c QueryCriteria) (map[string]string, error) {
-	tabs := make(map[string]string)
-
-	err := collectIndexCriteriaTables(tabs, c)
-	if err != nil {
-		return nil, err
-	}
-
-	return tabs, err
-}
-
-func collectIndexCriteriaTables(tabs map[string]string, c QueryCriteria) error {
+func isStatementCriteria(c QueryCriteria) bool {
 	switch c := c.(type) {
-	case *IndexCriteria:
-		tab, ok := indexCriteriaTableNames[c.sel]
-		if !ok {
-			return QueryCompileError(fmt.Sprintf("Unexpected index selector: %s", c.sel))
-		}
-
-		tabs[c.sel] = tab
-		return nil
+	case *ValueCriteria:
+		return c.sel == "id"
 
 	case *CompoundCriteria:
-		err := collectIndexCriteriaTables(tabs, c.left)
-		if err != nil {
-			return err
-		}
-		return collectIndexCriteriaTables(tabs, c.right)
+		return isStatementCriteria(c.left) && isStatementCriteria(c.right)
 
 	case *NegatedCriteria:
-		return collectIndexCriteriaTables(tabs, c.e)
+		return isStatementCriteria(c.e)
 
 	default:
-		return nil
+		return false
 	}
 }
 
 var indexCriteriaTableNames = map[string]string{
-	"wki": "Refs"}
+	"wki": "Refs",
+	"tag": "Tags",
+	"dep": "Deps"}
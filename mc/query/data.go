@@ -0,0 +1,432 @@
+package query
+
+import (
+	"fmt"
+	pb "github.com/mediachain/concat/proto"
+	"strings"
+)
+
+// Data queries select on the content of the metadata objects of statements,
+// which live in the datastore and can't be compiled to sql.
+// They are evaluated in two steps: first the statements matching the
+// envelope criteria are scanned in query order with the query returned by
+// DataQuery, and their objects are checked with the DataFilter. Then the
+// query is compiled with the data criteria replaced by batches of ids of
+// the matching statements, using WithDataIds; DataRows applies offsets,
+// limits and DISTINCT across the batches. Aggregate queries are instead
+// evaluated over the matching statements with a DataResultSet.
+// A statement matches if any of its objects passes the filter.
+// Data criteria can be combined with envelope criteria only with AND.
+type DataFilter func(obj interface{}) bool
+
+func (q *Query) IsDataQuery() bool {
+	return isDataCriteria(q.criteria)
+}
+
+// DataQuery returns the query selecting candidate statements for
+// a data query and the filter for their objects.
+func DataQuery(q *Query) (*Query, DataFilter, error) {
+	ecrit, dcrit, err := splitDataCriteria(q.criteria)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filter, err := makeDataFilter(dcrit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	xq := &Query{
+		Op:        OpSelect,
+		namespace: q.namespace,
+		selector:  SimpleSelector("*"),
+		criteria:  ecrit,
+		order:     q.order,
+	}
+
+	return xq, filter, nil
+}
+
+// WithDataIds returns the query for a batch of results, with the data
+// criteria replaced by the ids of statements matching them. The offset and
+// limit apply to the results of all batches, so they are dropped.
+func (q *Query) WithDataIds(ids []string) (*Query, error) {
+	ecrit, _, err := splitDataCriteria(q.criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	var crit QueryCriteria = &ValueCriteria{op: "IN", sel: "id", vals: ids}
+	if ecrit != nil {
+		crit = &CompoundCriteria{op: "AND", left: ecrit, right: crit}
+	}
+
+	xq := *q
+	xq.criteria = crit
+	xq.limit = 0
+	xq.offset = 0
+	return &xq, nil
+}
+
+// IsAggregateSelect returns true if the query applies functions to the
+// selected statements, with or without grouping.
+func (q *Query) IsAggregateSelect() bool {
+	if q.group != nil {
+		return true
+	}
+
+	switch sel := q.selector.(type) {
+	case *FunctionSelector:
+		return true
+	case CompoundSelector:
+		return hasFunctionSelector(sel)
+	default:
+		return false
+	}
+}
+
+// DataRows tracks the results of a data query across its batches.
+// Without DISTINCT, each matching statement selects a single row, so the
+// offset and limit are applied to the statement ids; with DISTINCT, they
+// are applied to the distinct rows.
+type DataRows struct {
+	offset int
+	limit  int
+	count  int
+	seen   map[string]bool
+}
+
+func NewDataRows(q *Query) *DataRows {
+	rows := &DataRows{offset: q.offset, limit: q.limit}
+	if isDistinctSelect(q) {
+		rows.seen = make(map[string]bool)
+	}
+	return rows
+}
+
+// AddId returns true if the results should include the statement
+func (rows *DataRows) AddId() bool {
+	if rows.seen != nil {
+		return true
+	}
+
+	return rows.add()
+}
+
+// AddRow returns true if the results should include the row
+func (rows *DataRows) AddRow(val interface{}) bool {
+	if rows.seen == nil {
+		return true
+	}
+
+	key := fmt.Sprintf("%v", val)
+	if rows.seen[key] {
+		return false
+	}
+	rows.seen[key] = true
+
+	return rows.add()
+}
+
+func (rows *DataRows) add() bool {
+	if rows.offset > 0 {
+		rows.offset--
+		return false
+	}
+
+	if rows.Done() {
+		return false
+	}
+
+	rows.count++
+	return true
+}
+
+// Done returns true once the limit has been reached
+func (rows *DataRows) Done() bool {
+	return rows.limit > 0 && rows.count >= rows.limit
+}
+
+func isDistinctSelect(q *Query) bool {
+	if q.distinct {
+		return true
+	}
+
+	sel, ok := q.selector.(SimpleSelector)
+	if ok {
+		return strings.HasPrefix(selectorColumn(sel, selectorColumnSimple), "DISTINCT")
+	}
+
+	return false
+}
+
+// DataResultSet evaluates an aggregate data query over the statements
+// matching its data criteria, as they are scanned.
+type DataResultSet struct {
+	rs     QueryResultSet
+	offset int
+}
+
+func NewDataResultSet(q *Query) (*DataResultSet, error) {
+	rs, err := makeResultSet(resultSetQuery(q))
+	if err != nil {
+		return nil, err
+	}
+
+	rs.begin(0)
+	return &DataResultSet{rs: rs, offset: q.offset}, nil
+}
+
+func (drs *DataResultSet) Add(stmt *pb.Statement) {
+	drs.rs.add(stmt)
+}
+
+func (drs *DataResultSet) Result() []interface{} {
+	drs.rs.end()
+	return resultOffset(drs.rs.result(), drs.offset)
+}
+
+func isDataCriteria(c QueryCriteria) bool {
+	switch c := c.(type) {
+	case *DataCriteria:
+		return true
+
+	case *CompoundCriteria:
+		return isDataCriteria(c.left) || isDataCriteria(c.right)
+
+	case *NegatedCriteria:
+		return isDataCriteria(c.e)
+
+	default:
+		return false
+	}
+}
+
+// splitDataCriteria splits the top level conjunction of the criteria into
+// its envelope and data parts
+func splitDataCriteria(c QueryCriteria) (ecrit QueryCriteria, dcrit QueryCriteria, err error) {
+	conj := make([]QueryCriteria, 0)
+	collectConjunction(c, &conj)
+
+	for _, xc := range conj {
+		switch {
+		case !isDataCriteria(xc):
+			ecrit = andCriteria(ecrit, xc)
+
+		case isEnvelopeCriteria(xc):
+			return nil, nil, QueryCompileError("Data criteria can only be combined with other criteria by AND")
+
+		default:
+			dcrit = andCriteria(dcrit, xc)
+		}
+	}
+
+	if dcrit == nil {
+		return nil, nil, QueryCompileError("Not a data query")
+	}
+
+	return ecrit, dcrit, nil
+}
+
+func collectConjunction(c QueryCriteria, conj *[]QueryCriteria) {
+	xc, ok := c.(*CompoundCriteria)
+	if ok && xc.op == "AND" {
+		collectConjunction(xc.left, conj)
+		collectConjunction(xc.right, conj)
+		return
+	}
+
+	if c != nil {
+		*conj = append(*conj, c)
+	}
+}
+
+func andCriteria(left, right QueryCriteria) QueryCriteria {
+	if left == nil {
+		return right
+	}
+	return &CompoundCriteria{op: "AND", left: left, right: right}
+}
+
+// isEnvelopeCriteria returns true if the criteria contain any non-data
+// criteria
+func isEnvelopeCriteria(c QueryCriteria) bool {
+	switch c := c.(type) {
+	case *DataCriteria:
+		return false
+
+	case *CompoundCriteria:
+		return isEnvelopeCriteria(c.left) || isEnvelopeCriteria(c.right)
+
+	case *NegatedCriteria:
+		return isEnvelopeCriteria(c.e)
+
+	default:
+		return true
+	}
+}
+
+func makeDataFilter(c QueryCriteria) (DataFilter, error) {
+	switch c := c.(type) {
+	case *DataCriteria:
+		switch c.op {
+		case "=":
+			return func(obj interface{}) bool {
+				return dataValueEQ(obj, c.path, c.val)
+			}, nil
+
+		case "!=":
+			return func(obj interface{}) bool {
+				return !dataValueEQ(obj, c.path, c.val)
+			}, nil
+
+		case "CONTAINS":
+			return func(obj interface{}) bool {
+				return dataValueContains(obj, c.path, c.val)
+			}, nil
+
+		default:
+			return nil, QueryEvalError(fmt.Sprintf("Unexpected data criteria operator: %s", c.op))
+		}
+
+	case *CompoundCriteria:
+		left, err := makeDataFilter(c.left)
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := makeDataFilter(c.right)
+		if err != nil {
+			return nil, err
+		}
+
+		switch c.op {
+		case "AND":
+			return func(obj interface{}) bool {
+				return left(obj) && right(obj)
+			}, nil
+
+		case "OR":
+			return func(obj interface{}) bool {
+				return left(obj) || right(obj)
+			}, nil
+
+		default:
+			return nil, QueryEvalError(fmt.Sprintf("Unexpected criteria combinator: %s", c.op))
+		}
+
+	case *NegatedCriteria:
+		filter, err := makeDataFilter(c.e)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(obj interface{}) bool {
+			return !filter(obj)
+		}, nil
+
+	default:
+		return nil, QueryEvalError(fmt.Sprintf("Unexpected criteria type: %T", c))
+	}
+}
+
+func dataValue(obj interface{}, path []string) (interface{}, bool) {
+	for _, key := range path {
+		switch xobj := obj.(type) {
+		case map[string]interface{}:
+			val, ok := xobj[key]
+			if !ok {
+				return nil, false
+			}
+			obj = val
+
+		case map[interface{}]interface{}:
+			val, ok := xobj[key]
+			if !ok {
+				return nil, false
+			}
+			obj = val
+
+		default:
+			return nil, false
+		}
+	}
+
+	return obj, true
+}
+
+// scalar values are compared by their string representation
+func dataValueString(val interface{}) (string, bool) {
+	switch val := val.(type) {
+	case string:
+		return val, true
+	case []byte:
+		return string(val), true
+	case map[string]interface{}, map[interface{}]interface{}, []interface{}, nil:
+		return "", false
+	default:
+		return fmt.Sprintf("%v", val), true
+	}
+}
+
+func dataValueEQ(obj interface{}, path []string, val string) bool {
+	xval, ok := dataValue(obj, path)
+	if !ok {
+		return false
+	}
+
+	str, ok := dataValueString(xval)
+	return ok && str == val
+}
+
+// CONTAINS matches substrings of strings and elements of arrays
+func dataValueContains(obj interface{}, path []string, val string) bool {
+	xval, ok := dataValue(obj, path)
+	if !ok {
+		return false
+	}
+
+	switch xval := xval.(type) {
+	case []interface{}:
+		for _, elt := range xval {
+			str, ok := dataValueString(elt)
+			if ok && str == val {
+				return true
+			}
+		}
+		return false
+
+	default:
+		str, ok := dataValueString(xval)
+		return ok && strings.Contains(str, val)
+	}
+}
+
+// DataValues returns the values of the field at path in a metadata object,
+// as indexed by field indexes: scalars have a single value, while arrays
+// have a value for each of their scalar elements.
+func DataValues(obj interface{}, path string) []string {
+	val, ok := dataValue(obj, strings.Split(path, "."))
+	if !ok {
+		return nil
+	}
+
+	switch val := val.(type) {
+	case []interface{}:
+		vals := make([]string, 0, len(val))
+		for _, elt := range val {
+			str, ok := dataValueString(elt)
+			if ok {
+				vals = append(vals, str)
+			}
+		}
+		return vals
+
+	default:
+		str, ok := dataValueString(val)
+		if ok {
+			return []string{str}
+		}
+		return nil
+	}
+}
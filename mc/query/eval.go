@@ -3,6 +3,7 @@ package query
 import (
 	"fmt"
 	pb "github.com/mediachain/concat/proto"
+	"regexp"
 	"strings"
 )
 
@@ -16,7 +17,7 @@ func EvalQuery(query *Query, stmts []*pb.Statement) ([]interface{}, error) {
 		return nil, err
 	}
 
-	rs, err := makeResultSet(query)
+	rs, err := makeResultSet(resultSetQuery(query))
 	if err != nil {
 		return nil, err
 	}
@@ -29,7 +30,27 @@ func EvalQuery(query *Query, stmts []*pb.Statement) ([]interface{}, error) {
 	}
 	rs.end()
 
-	return rs.result(), nil
+	return resultOffset(rs.result(), query.offset), nil
+}
+
+// the offset applies to the result rows, after grouping, DISTINCT and
+// aggregation, so the result set must be limited to offset+limit rows
+func resultSetQuery(query *Query) *Query {
+	if query.offset > 0 && query.limit > 0 {
+		return query.WithLimit(query.offset + query.limit)
+	}
+	return query
+}
+
+func resultOffset(res []interface{}, offset int) []interface{} {
+	switch {
+	case offset >= len(res):
+		return []interface{}{}
+	case offset > 0:
+		return res[offset:]
+	default:
+		return res
+	}
 }
 
 type QueryResultSet interface {
@@ -132,6 +153,14 @@ func wkiCriteriaFilter(stmt *pb.Statement) []string {
 	return StatementRefs(stmt).List()
 }
 
+func tagCriteriaFilter(stmt *pb.Statement) []string {
+	return StatementTags(stmt).List()
+}
+
+func depCriteriaFilter(stmt *pb.Statement) []string {
+	return StatementDeps(stmt).List()
+}
+
 func indexCriteriaContains(keys []string, val string) bool {
 	for _, key := range keys {
 		if key == val {
@@ -141,8 +170,54 @@ func indexCriteriaContains(keys []string, val string) bool {
 	return false
 }
 
+func indexCriteriaContainsAny(keys []string, vals map[string]bool) bool {
+	for _, key := range keys {
+		if vals[key] {
+			return true
+		}
+	}
+	return false
+}
+
+func indexCriteriaMatch(keys []string, match func(string) bool) bool {
+	for _, key := range keys {
+		if match(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// LIKE patterns are case sensitive, as they compile to GLOB
+func makePatternMatcher(op, val string) func(string) bool {
+	switch op {
+	case "PREFIX":
+		return func(s string) bool {
+			return strings.HasPrefix(s, val)
+		}
+
+	default:
+		parts := strings.Split(val, "%")
+		for x, part := range parts {
+			parts[x] = strings.Replace(regexp.QuoteMeta(part), "_", ".", -1)
+		}
+		rx := regexp.MustCompile(fmt.Sprintf("^(?s:%s)$", strings.Join(parts, ".*")))
+		return rx.MatchString
+	}
+}
+
+func makeValueSet(vals []string) map[string]bool {
+	set := make(map[string]bool)
+	for _, val := range vals {
+		set[val] = true
+	}
+	return set
+}
+
 var indexCriteriaFilterSelect = map[string]IndexCriteriaFilterSelect{
-	"wki": wkiCriteriaFilter}
+	"wki": wkiCriteriaFilter,
+	"tag": tagCriteriaFilter,
+	"dep": depCriteriaFilter}
 
 func compoundCriteriaAND(stmt *pb.Statement, left, right StatementFilter) bool {
 	return left(stmt) && right(stmt)
@@ -161,15 +236,19 @@ func emptyFilter(*pb.Statement) bool {
 }
 
 func makeNamespaceFilter(query *Query) StatementFilter {
-	ns := query.namespace
+	return namespaceFilter(query.namespace)
+}
+
+func namespaceFilter(ns string) StatementFilter {
 	switch {
 	case ns == "*":
 		return emptyFilter
 
 	case ns[len(ns)-1] == '*':
-		prefix := ns[:len(ns)-2]
+		pre := ns[:len(ns)-2]
+		prefix := ns[:len(ns)-1]
 		return func(stmt *pb.Statement) bool {
-			return strings.HasPrefix(stmt.Namespace, prefix)
+			return stmt.Namespace == pre || strings.HasPrefix(stmt.Namespace, prefix)
 		}
 
 	default:
@@ -196,6 +275,20 @@ func makeCriteriaFilterF(c QueryCriteria) (StatementFilter, error) {
 			return nil, QueryEvalError(fmt.Sprintf("Unexpected criteria selector: %s", c.sel))
 		}
 
+		switch c.op {
+		case "IN":
+			vals := makeValueSet(c.vals)
+			return func(stmt *pb.Statement) bool {
+				return vals[getf(stmt)]
+			}, nil
+
+		case "LIKE", "PREFIX":
+			match := makePatternMatcher(c.op, c.val)
+			return func(stmt *pb.Statement) bool {
+				return match(getf(stmt))
+			}, nil
+		}
+
 		cmpf, ok := valueCriteriaFilterCompare[c.op]
 		if !ok {
 			return nil, QueryEvalError(fmt.Sprintf("Unexpected criteria operator: %s", c.op))
@@ -226,10 +319,27 @@ func makeCriteriaFilterF(c QueryCriteria) (StatementFilter, error) {
 			return nil, QueryEvalError(fmt.Sprintf("Unexpected index selector: %s", c.sel))
 		}
 
+		switch c.op {
+		case "IN":
+			vals := makeValueSet(c.vals)
+			return func(stmt *pb.Statement) bool {
+				return indexCriteriaContainsAny(getf(stmt), vals)
+			}, nil
+
+		case "LIKE", "PREFIX":
+			match := makePatternMatcher(c.op, c.val)
+			return func(stmt *pb.Statement) bool {
+				return indexCriteriaMatch(getf(stmt), match)
+			}, nil
+		}
+
 		return func(stmt *pb.Statement) bool {
 			return indexCriteriaContains(getf(stmt), c.val)
 		}, nil
 
+	case *NamespaceCriteria:
+		return namespaceFilter(c.ns), nil
+
 	case *CompoundCriteria:
 		filter, ok := compoundCriteriaFilters[c.op]
 		if !ok {
@@ -376,6 +486,49 @@ func checkFunctionSelector(sel *FunctionSelector) bool {
 	return valid[string(sel.sel)]
 }
 
+// Simple selectors in compound selectors with functions must appear in
+// the GROUP BY clause, so that they have a single value per result
+func checkCompoundSelector(sel CompoundSelector, group []string) bool {
+	for _, xsel := range sel {
+		fsel, ok := xsel.(*FunctionSelector)
+		if ok && !checkFunctionSelector(fsel) {
+			return false
+		}
+	}
+
+	if group == nil && !hasFunctionSelector(sel) {
+		return true
+	}
+
+	for _, xsel := range sel {
+		ssel, ok := xsel.(SimpleSelector)
+		if ok && !groupContains(group, string(ssel)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func hasFunctionSelector(sel CompoundSelector) bool {
+	for _, xsel := range sel {
+		_, ok := xsel.(*FunctionSelector)
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func groupContains(group []string, sel string) bool {
+	for _, key := range group {
+		if key == sel {
+			return true
+		}
+	}
+	return false
+}
+
 // The difference between the types of result set:
 //  Simple selectors (SimpleResultSet) have unique (set) semantics.
 //  Compound selectors (CompoundResultSet) create objects with fields named by
@@ -395,6 +548,10 @@ func checkFunctionSelector(sel *FunctionSelector) bool {
 //  of distinct namespaces.
 func makeResultSet(query *Query) (QueryResultSet, error) {
 	sel := query.selector
+	if query.group != nil && !isCompoundSelector(sel) {
+		return nil, QueryEvalError("GROUP BY requires a compound selector")
+	}
+
 	switch sel := sel.(type) {
 	case SimpleSelector:
 		getf, ok := simpleSelectors[string(sel)]
@@ -405,37 +562,52 @@ func makeResultSet(query *Query) (QueryResultSet, error) {
 		return makeSimpleResultSet(getf, query.limit), nil
 
 	case CompoundSelector:
+		if !checkCompoundSelector(sel, query.group) {
+			return nil, QueryEvalError("Illegal compound selector; simple selectors must be grouped when used with functions")
+		}
+
+		if query.group != nil || hasFunctionSelector(sel) {
+			return makeGroupResultSet(sel, query.group, query.limit)
+		}
+
+		keys := make([]string, len(sel))
 		getfs := make([]StatementSelector, len(sel))
-		for x, key := range sel {
-			getf, ok := simpleSelectors[string(key)]
+		for x, xsel := range sel {
+			key := selectorKey(xsel)
+			getf, ok := simpleSelectors[key]
 			if !ok {
 				return nil, QueryEvalError(fmt.Sprintf("Unexpected selector: %s", key))
 			}
+			keys[x] = key
 			getfs[x] = getf
 		}
 
-		return makeCompoundResultSet(sel, getfs, query.limit), nil
+		return makeCompoundResultSet(keys, getfs, query.distinct, query.limit), nil
 
 	case *FunctionSelector:
-		if !checkFunctionSelector(sel) {
-			return nil, QueryEvalError(fmt.Sprintf("Illegal selector: %s(%s)", sel.op, sel.sel))
-		}
+		return makeFunctionSelectorResultSet(sel, query.limit)
 
-		fun, ok := functionSelectors[sel.op]
-		if !ok {
-			return nil, QueryEvalError(fmt.Sprintf("Unexpected selector: %s", sel.op))
-		}
+	default:
+		return nil, QueryEvalError(fmt.Sprintf("Unexpected selector type: %T", sel))
+	}
+}
 
-		getf, ok := simpleSelectors[string(sel.sel)]
-		if !ok {
-			return nil, QueryEvalError(fmt.Sprintf("Unexpected selector: %s", sel.sel))
-		}
+func makeFunctionSelectorResultSet(sel *FunctionSelector, limit int) (QueryResultSet, error) {
+	if !checkFunctionSelector(sel) {
+		return nil, QueryEvalError(fmt.Sprintf("Illegal selector: %s(%s)", sel.op, sel.sel))
+	}
 
-		return makeFunctionResultSet(fun, getf, query.limit), nil
+	fun, ok := functionSelectors[sel.op]
+	if !ok {
+		return nil, QueryEvalError(fmt.Sprintf("Unexpected selector: %s", sel.op))
+	}
 
-	default:
-		return nil, QueryEvalError(fmt.Sprintf("Unexpected selector type: %T", sel))
+	getf, ok := simpleSelectors[string(sel.sel)]
+	if !ok {
+		return nil, QueryEvalError(fmt.Sprintf("Unexpected selector: %s", sel.sel))
 	}
+
+	return makeFunctionResultSet(fun, getf, limit), nil
 }
 
 func makeSimpleResultSet(getf StatementSelector, limit int) QueryResultSet {
@@ -471,16 +643,21 @@ func (rs *SimpleResultSet) result() []interface{} {
 	return rs.res
 }
 
-func makeCompoundResultSet(sels []SimpleSelector, getfs []StatementSelector, limit int) QueryResultSet {
-	compf := makeCompoundStatementSelector(sels, getfs)
-	return &CompoundResultSet{getf: compf, limit: limit}
+func makeCompoundResultSet(keys []string, getfs []StatementSelector, distinct bool, limit int) QueryResultSet {
+	compf := makeCompoundStatementSelector(keys, getfs)
+	rs := &CompoundResultSet{getf: compf, limit: limit}
+	if distinct {
+		rs.keys = keys
+		rs.seen = make(map[string]bool)
+	}
+	return rs
 }
 
-func makeCompoundStatementSelector(sels []SimpleSelector, getfs []StatementSelector) StatementSelector {
+func makeCompoundStatementSelector(keys []string, getfs []StatementSelector) StatementSelector {
 	return func(stmt *pb.Statement) interface{} {
 		val := make(map[string]interface{})
-		for x, sel := range sels {
-			val[string(sel)] = getfs[x](stmt)
+		for x, key := range keys {
+			val[key] = getfs[x](stmt)
 		}
 		return val
 	}
@@ -490,6 +667,8 @@ type CompoundResultSet struct {
 	rset  []interface{}
 	getf  StatementSelector
 	limit int
+	keys  []string        // distinct compound selection
+	seen  map[string]bool // distinct values seen so far
 }
 
 func (rs *CompoundResultSet) begin(hint int) {
@@ -497,7 +676,23 @@ func (rs *CompoundResultSet) begin(hint int) {
 }
 
 func (rs *CompoundResultSet) add(stmt *pb.Statement) {
-	rs.rset = append(rs.rset, rs.getf(stmt))
+	val := rs.getf(stmt)
+	if rs.seen != nil {
+		key := rs.distinctKey(val.(map[string]interface{}))
+		if rs.seen[key] {
+			return
+		}
+		rs.seen[key] = true
+	}
+	rs.rset = append(rs.rset, val)
+}
+
+func (rs *CompoundResultSet) distinctKey(val map[string]interface{}) string {
+	strs := make([]string, len(rs.keys))
+	for x, key := range rs.keys {
+		strs[x] = fmt.Sprintf("%v", val[key])
+	}
+	return strings.Join(strs, "\x00")
 }
 
 func (rs *CompoundResultSet) end() {}
@@ -532,3 +727,127 @@ func (rs *FunctionResultSet) end() {
 func (rs *FunctionResultSet) result() []interface{} {
 	return rs.res
 }
+
+// Group result sets partition statements by the GROUP BY selectors and
+// evaluate each selector of a compound selector separately for every group.
+// Without GROUP BY, all statements fall in a single group.
+func makeGroupResultSet(sel CompoundSelector, group []string, limit int) (QueryResultSet, error) {
+	keys := make([]string, len(sel))
+	for x, xsel := range sel {
+		keys[x] = selectorKey(xsel)
+	}
+
+	getfs := make([]StatementSelector, len(group))
+	for x, key := range group {
+		getf, ok := simpleSelectors[key]
+		if !ok {
+			return nil, QueryEvalError(fmt.Sprintf("Unexpected group selector: %s", key))
+		}
+		getfs[x] = getf
+	}
+
+	makef := func() ([]QueryResultSet, error) {
+		rsets := make([]QueryResultSet, len(sel))
+		for x, xsel := range sel {
+			switch xsel := xsel.(type) {
+			case SimpleSelector:
+				getf, ok := simpleSelectors[string(xsel)]
+				if !ok {
+					return nil, QueryEvalError(fmt.Sprintf("Unexpected selector: %s", xsel))
+				}
+				rsets[x] = makeSimpleResultSet(getf, 0)
+
+			case *FunctionSelector:
+				rs, err := makeFunctionSelectorResultSet(xsel, 0)
+				if err != nil {
+					return nil, err
+				}
+				rsets[x] = rs
+
+			default:
+				return nil, QueryEvalError(fmt.Sprintf("Unexpected selector type: %T", xsel))
+			}
+		}
+		return rsets, nil
+	}
+
+	// check the selectors once, so that group creation can't fail
+	_, err := makef()
+	if err != nil {
+		return nil, err
+	}
+
+	return &GroupResultSet{keys: keys, getfs: getfs, makef: makef, limit: limit}, nil
+}
+
+type GroupResultSet struct {
+	keys   []string
+	getfs  []StatementSelector
+	makef  func() ([]QueryResultSet, error)
+	groups map[string][]QueryResultSet
+	order  []string
+	res    []interface{}
+	limit  int
+}
+
+func (rs *GroupResultSet) begin(hint int) {
+	rs.groups = make(map[string][]QueryResultSet)
+	rs.order = nil
+	if len(rs.getfs) == 0 {
+		// aggregate over the whole result set
+		rs.addGroup("")
+	}
+}
+
+func (rs *GroupResultSet) groupKey(stmt *pb.Statement) string {
+	vals := make([]string, len(rs.getfs))
+	for x, getf := range rs.getfs {
+		vals[x] = fmt.Sprintf("%v", getf(stmt))
+	}
+	return strings.Join(vals, "\x00")
+}
+
+func (rs *GroupResultSet) addGroup(key string) []QueryResultSet {
+	rsets, _ := rs.makef()
+	for _, xrs := range rsets {
+		xrs.begin(0)
+	}
+	rs.groups[key] = rsets
+	rs.order = append(rs.order, key)
+	return rsets
+}
+
+func (rs *GroupResultSet) add(stmt *pb.Statement) {
+	key := rs.groupKey(stmt)
+	rsets, ok := rs.groups[key]
+	if !ok {
+		if rs.limit > 0 && len(rs.order) >= rs.limit {
+			return
+		}
+		rsets = rs.addGroup(key)
+	}
+
+	for _, xrs := range rsets {
+		xrs.add(stmt)
+	}
+}
+
+func (rs *GroupResultSet) end() {
+	rs.res = make([]interface{}, len(rs.order))
+	for x, key := range rs.order {
+		obj := make(map[string]interface{})
+		for y, xrs := range rs.groups[key] {
+			xrs.end()
+			val := xrs.result()
+			if len(val) > 0 {
+				obj[rs.keys[y]] = val[0]
+			}
+		}
+		rs.res[x] = obj
+	}
+	rs.groups = nil
+}
+
+func (rs *GroupResultSet) result() []interface{} {
+	return rs.res
+}
@@ -0,0 +1,152 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// String formats the query in MCQL, so that rewritten queries can be sent
+// to remote peers. Namespace restrictions have no MCQL form; they are only
+// applied locally.
+func (q *Query) String() string {
+	switch q.Op {
+	case OpSelect:
+		return q.formatSelect()
+
+	case OpExplain:
+		return fmt.Sprintf("EXPLAIN %s", q.formatSelect())
+
+	case OpDelete:
+		str := fmt.Sprintf("DELETE FROM %s", q.namespace)
+		if q.criteria != nil {
+			str = fmt.Sprintf("%s WHERE %s", str, formatCriteria(q.criteria))
+		}
+		if q.limit > 0 {
+			str = fmt.Sprintf("%s LIMIT %d", str, q.limit)
+		}
+		return str
+
+	default:
+		return ""
+	}
+}
+
+func (q *Query) formatSelect() string {
+	var buf []string
+
+	buf = append(buf, "SELECT")
+	if q.distinct {
+		buf = append(buf, "DISTINCT")
+	}
+	buf = append(buf, formatSelector(q.selector), "FROM", q.namespace)
+
+	if q.criteria != nil {
+		buf = append(buf, "WHERE", formatCriteria(q.criteria))
+	}
+
+	if len(q.group) > 0 {
+		buf = append(buf, "GROUP BY", strings.Join(q.group, ", "))
+	}
+
+	if len(q.order) > 0 {
+		specs := make([]string, len(q.order))
+		for x, spec := range q.order {
+			if spec.dir != "" {
+				specs[x] = fmt.Sprintf("%s %s", spec.sel, spec.dir)
+			} else {
+				specs[x] = spec.sel
+			}
+		}
+		buf = append(buf, "ORDER BY", strings.Join(specs, ", "))
+	}
+
+	if q.limit > 0 {
+		buf = append(buf, fmt.Sprintf("LIMIT %d", q.limit))
+	}
+
+	if q.offset > 0 {
+		buf = append(buf, fmt.Sprintf("OFFSET %d", q.offset))
+	}
+
+	return strings.Join(buf, " ")
+}
+
+func formatSelector(sel QuerySelector) string {
+	switch sel := sel.(type) {
+	case CompoundSelector:
+		sels := make([]string, len(sel))
+		for x, xsel := range sel {
+			sels[x] = selectorKey(xsel)
+		}
+		return fmt.Sprintf("(%s)", strings.Join(sels, ", "))
+
+	default:
+		return selectorKey(sel)
+	}
+}
+
+func formatCriteria(c QueryCriteria) string {
+	switch c := c.(type) {
+	case *ValueCriteria:
+		return formatValueCriteria(c.sel, c.op, c.val, c.vals)
+
+	case *RangeCriteria:
+		return fmt.Sprintf("%s %s %d", c.sel, c.op, c.val)
+
+	case *IndexCriteria:
+		return formatValueCriteria(c.sel, c.op, c.val, c.vals)
+
+	case *DataCriteria:
+		return fmt.Sprintf("data.%s %s %s", strings.Join(c.path, "."), c.op, formatDataValue(c.val))
+
+	case *FieldCriteria:
+		if c.op == "IN" {
+			vals := make([]string, len(c.vals))
+			for x, val := range c.vals {
+				vals[x] = formatDataValue(val)
+			}
+			return fmt.Sprintf("field.%s IN (%s)", c.path, strings.Join(vals, ", "))
+		}
+		return fmt.Sprintf("field.%s %s %s", c.path, c.op, formatDataValue(c.val))
+
+	case *TextCriteria:
+		return fmt.Sprintf("text MATCH %s", formatDataValue(c.val))
+
+	case *SupersedeCriteria:
+		return c.op
+
+	case *NamespaceCriteria:
+		return fmt.Sprintf("namespace = %s", c.ns)
+
+	case *CompoundCriteria:
+		return fmt.Sprintf("%s %s %s", formatSubCriteria(c.left), c.op, formatSubCriteria(c.right))
+
+	case *NegatedCriteria:
+		return fmt.Sprintf("NOT %s", formatSubCriteria(c.e))
+
+	default:
+		return ""
+	}
+}
+
+// formatSubCriteria parenthesizes compound criteria, as boolean operators
+// have no precedence in MCQL
+func formatSubCriteria(c QueryCriteria) string {
+	switch c.(type) {
+	case *CompoundCriteria:
+		return fmt.Sprintf("(%s)", formatCriteria(c))
+	default:
+		return formatCriteria(c)
+	}
+}
+
+func formatValueCriteria(sel, op, val string, vals []string) string {
+	if op == "IN" {
+		return fmt.Sprintf("%s IN (%s)", sel, strings.Join(vals, ", "))
+	}
+	return fmt.Sprintf("%s %s %s", sel, op, val)
+}
+
+func formatDataValue(val string) string {
+	return fmt.Sprintf("'%s'", val)
+}
@@ -2,6 +2,7 @@ package query
 
 import (
 	"strconv"
+	"strings"
 )
 
 // query parsing
@@ -42,6 +43,10 @@ func (ps *ParseState) setDeleteOp() {
 	ps.query.selector = SimpleSelector("id")
 }
 
+func (ps *ParseState) setExplainOp() {
+	ps.query.Op = OpExplain
+}
+
 func (ps *ParseState) setSimpleSelector() {
 	// stack: simple-selector
 	sel := ps.pop().(string)
@@ -49,21 +54,43 @@ func (ps *ParseState) setSimpleSelector() {
 }
 
 func (ps *ParseState) setCompoundSelector() {
-	// stack: simple-selector ...
+	// stack: {simple-selector | function-selector} ...
 	count := ps.sklen()
-	sels := make([]SimpleSelector, count)
+	sels := make([]QuerySelector, count)
 	for x := 0; x < count; x++ {
-		sel := ps.pop().(string)
-		sels[count-x-1] = SimpleSelector(sel)
+		switch sel := ps.pop().(type) {
+		case string:
+			sels[count-x-1] = SimpleSelector(sel)
+		case *FunctionSelector:
+			sels[count-x-1] = sel
+		}
 	}
 	ps.query.selector = CompoundSelector(sels)
 }
 
 func (ps *ParseState) setFunctionSelector() {
-	// stack: simple-selector function
+	// stack: simple-selector [DISTINCT] function
+	ps.query.selector = ps.popFunctionSelector()
+}
+
+func (ps *ParseState) addFunctionSelector() {
+	// stack: simple-selector [DISTINCT] function ...
+	ps.push(ps.popFunctionSelector())
+}
+
+func (ps *ParseState) popFunctionSelector() *FunctionSelector {
 	sel := ps.pop().(string)
 	op := ps.pop().(string)
-	ps.query.selector = &FunctionSelector{op: op, sel: SimpleSelector(sel)}
+	distinct := false
+	if op == "DISTINCT" {
+		distinct = true
+		op = ps.pop().(string)
+	}
+	return &FunctionSelector{op: op, sel: SimpleSelector(sel), distinct: distinct}
+}
+
+func (ps *ParseState) setDistinct() {
+	ps.query.distinct = true
 }
 
 func (ps *ParseState) setNamespace(ns string) {
@@ -76,11 +103,18 @@ func (ps *ParseState) setCriteria() {
 }
 
 func (ps *ParseState) addValueCriteria() {
-	// stack: value op selector ...
-	val := ps.pop().(string)
-	op := ps.pop().(string)
-	sel := ps.pop().(string)
-	crit := &ValueCriteria{op: op, sel: sel, val: val}
+	// stack: {value | value-list} op selector ...
+	var crit *ValueCriteria
+	switch val := ps.pop().(type) {
+	case string:
+		op := ps.pop().(string)
+		sel := ps.pop().(string)
+		crit = &ValueCriteria{op: op, sel: sel, val: val}
+	case []string:
+		op := ps.pop().(string)
+		sel := ps.pop().(string)
+		crit = &ValueCriteria{op: op, sel: sel, vals: val}
+	}
 	ps.push(crit)
 }
 
@@ -99,13 +133,69 @@ func (ps *ParseState) addRangeCriteria() {
 }
 
 func (ps *ParseState) addIndexCriteria() {
-	// stack: val selector ...
+	// stack: {val | value-list} op selector ...
+	var crit *IndexCriteria
+	switch val := ps.pop().(type) {
+	case string:
+		op := ps.pop().(string)
+		sel := ps.pop().(string)
+		crit = &IndexCriteria{op: op, sel: sel, val: val}
+	case []string:
+		op := ps.pop().(string)
+		sel := ps.pop().(string)
+		crit = &IndexCriteria{op: op, sel: sel, vals: val}
+	}
+	ps.push(crit)
+}
+
+func (ps *ParseState) addDataCriteria() {
+	// stack: val op path ...
 	val := ps.pop().(string)
-	sel := ps.pop().(string)
-	crit := &IndexCriteria{sel: sel, val: val}
+	op := ps.pop().(string)
+	path := ps.pop().(string)
+	crit := &DataCriteria{op: op, path: strings.Split(path, "."), val: val}
 	ps.push(crit)
 }
 
+func (ps *ParseState) addFieldCriteria() {
+	// stack: {val | value-list} op path ...
+	var crit *FieldCriteria
+	switch val := ps.pop().(type) {
+	case string:
+		op := ps.pop().(string)
+		path := ps.pop().(string)
+		crit = &FieldCriteria{op: op, path: path, val: val}
+	case []string:
+		op := ps.pop().(string)
+		path := ps.pop().(string)
+		crit = &FieldCriteria{op: op, path: path, vals: val}
+	}
+	ps.push(crit)
+}
+
+func (ps *ParseState) addTextCriteria() {
+	// stack: val ...
+	val := ps.pop().(string)
+	ps.push(&TextCriteria{val: val})
+}
+
+func (ps *ParseState) addSupersedeCriteria() {
+	// stack: op ...
+	op := ps.pop().(string)
+	ps.push(&SupersedeCriteria{op: op})
+}
+
+func (ps *ParseState) pushValueList(op string) {
+	ps.push(op)
+	ps.push([]string{})
+}
+
+func (ps *ParseState) addListValue(val string) {
+	// stack: value-list ...
+	lst := ps.pop().([]string)
+	ps.push(append(lst, val))
+}
+
 func (ps *ParseState) addCompoundCriteria() {
 	// stack: criteria op criteria ...
 	right := ps.pop().(QueryCriteria)
@@ -122,6 +212,16 @@ func (ps *ParseState) addNegatedCriteria() {
 	ps.push(crit)
 }
 
+func (ps *ParseState) setGroup() {
+	// stack: selector ...
+	count := ps.sklen()
+	group := make([]string, count)
+	for x := 0; x < count; x++ {
+		group[count-x-1] = ps.pop().(string)
+	}
+	ps.query.group = group
+}
+
 func (ps *ParseState) setOrder() {
 	// stack: order-spec ...
 	count := ps.sklen()
@@ -155,6 +255,15 @@ func (ps *ParseState) setLimit(x string) {
 	ps.query.limit = lim
 }
 
+func (ps *ParseState) setOffset(x string) {
+	off, err := strconv.Atoi(x)
+	if err != nil {
+		ps.err = err
+		off = 0
+	}
+	ps.query.offset = off
+}
+
 func (ps *ParseState) push(val interface{}) {
 	cell := &ConsCell{car: val, cdr: ps.stack}
 	ps.stack = cell
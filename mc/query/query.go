@@ -1,23 +1,41 @@
 package query
 
-import ()
+import (
+	"fmt"
+)
 
 type Query struct {
 	Op        int
 	namespace string
+	distinct  bool
 	selector  QuerySelector
 	criteria  QueryCriteria
+	group     []string
 	order     QueryOrder
 	limit     int
+	offset    int
 }
 
 const (
 	OpSelect = iota
 	OpDelete
+	OpExplain
 )
 
+func (q *Query) Namespace() string {
+	return q.namespace
+}
+
+func (q *Query) WithNamespace(ns string) *Query {
+	xq := *q
+	xq.namespace = ns
+	return &xq
+}
+
 func (q *Query) WithLimit(limit int) *Query {
-	return &Query{q.Op, q.namespace, q.selector, q.criteria, q.order, limit}
+	xq := *q
+	xq.limit = limit
+	return &xq
 }
 
 func (q *Query) IsSimpleSelect(sel string) bool {
@@ -33,7 +51,79 @@ func (q *Query) IsSimpleSelect(sel string) bool {
 }
 
 func (q *Query) WithSimpleSelect(sel string) *Query {
-	return &Query{q.Op, q.namespace, SimpleSelector(sel), q.criteria, q.order, q.limit}
+	xq := *q
+	xq.selector = SimpleSelector(sel)
+	return &xq
+}
+
+// IsCursorQuery returns true if the query is in ascending counter order and
+// can be resumed with a counter > criterion from the last result.
+func (q *Query) IsCursorQuery() bool {
+	return q.Op == OpSelect &&
+		len(q.order) > 0 &&
+		q.order[0].sel == "counter" &&
+		q.order[0].dir != "DESC" &&
+		q.group == nil &&
+		!q.distinct &&
+		isCursorSelector(q.selector)
+}
+
+// IsStreamSelect returns true if the query selects statements without
+// grouping, ordering or limits, so that its results can be streamed
+// in counter order.
+func (q *Query) IsStreamSelect() bool {
+	return q.IsSimpleSelect("*") &&
+		!q.distinct &&
+		q.group == nil &&
+		len(q.order) == 0 &&
+		q.limit == 0 &&
+		q.offset == 0 &&
+		!q.IsDataQuery()
+}
+
+// WithCounterCursor returns the query for the next batch of results after
+// the statement with the given counter.
+func (q *Query) WithCounterCursor(counter int64, limit int) *Query {
+	var crit QueryCriteria = &RangeCriteria{op: ">", sel: "counter", val: counter}
+	if q.criteria != nil {
+		crit = &CompoundCriteria{op: "AND", left: q.criteria, right: crit}
+	}
+
+	xq := *q
+	xq.criteria = crit
+	xq.order = QueryOrder{&QueryOrderSpec{sel: "counter", dir: "ASC"}}
+	xq.limit = limit
+	return &xq
+}
+
+// WithNamespaceRestriction returns the query restricted to namespaces
+// that don't match any of the deny rules, unless they match an allow rule.
+// Rules are namespaces or namespace wildcards.
+func (q *Query) WithNamespaceRestriction(deny, allow []string) *Query {
+	if len(deny) == 0 {
+		return q
+	}
+
+	var crit QueryCriteria = &NegatedCriteria{namespaceRuleCriteria(deny)}
+	if len(allow) > 0 {
+		crit = &CompoundCriteria{op: "OR", left: crit, right: namespaceRuleCriteria(allow)}
+	}
+
+	if q.criteria != nil {
+		crit = &CompoundCriteria{op: "AND", left: q.criteria, right: crit}
+	}
+
+	xq := *q
+	xq.criteria = crit
+	return &xq
+}
+
+func namespaceRuleCriteria(rules []string) QueryCriteria {
+	var crit QueryCriteria = &NamespaceCriteria{rules[0]}
+	for _, rule := range rules[1:] {
+		crit = &CompoundCriteria{op: "OR", left: crit, right: &NamespaceCriteria{rule}}
+	}
+	return crit
 }
 
 type QuerySelector interface {
@@ -41,10 +131,11 @@ type QuerySelector interface {
 }
 
 type SimpleSelector string
-type CompoundSelector []SimpleSelector
+type CompoundSelector []QuerySelector // SimpleSelector or *FunctionSelector
 type FunctionSelector struct {
-	op  string
-	sel SimpleSelector
+	op       string
+	sel      SimpleSelector
+	distinct bool
 }
 
 func (s SimpleSelector) selectorType() string {
@@ -59,14 +150,31 @@ func (s *FunctionSelector) selectorType() string {
 	return "function"
 }
 
+// selectorKey returns the name of a selector as a field in compound
+// selector results
+func selectorKey(sel QuerySelector) string {
+	switch sel := sel.(type) {
+	case SimpleSelector:
+		return string(sel)
+	case *FunctionSelector:
+		if sel.distinct {
+			return fmt.Sprintf("%s(DISTINCT %s)", sel.op, sel.sel)
+		}
+		return fmt.Sprintf("%s(%s)", sel.op, sel.sel)
+	default:
+		return ""
+	}
+}
+
 type QueryCriteria interface {
 	criteriaType() string
 }
 
 type ValueCriteria struct {
-	op  string
-	sel string
-	val string
+	op   string
+	sel  string
+	val  string
+	vals []string // IN
 }
 
 type RangeCriteria struct {
@@ -76,10 +184,44 @@ type RangeCriteria struct {
 }
 
 type IndexCriteria struct {
-	sel string
+	op   string
+	sel  string
+	val  string
+	vals []string // IN
+}
+
+// DataCriteria select on the content of the metadata objects of statements
+type DataCriteria struct {
+	op   string
+	path []string
+	val  string
+}
+
+// FieldCriteria select on fields of metadata objects declared in field indexes
+type FieldCriteria struct {
+	op   string
+	path string
+	val  string
+	vals []string // IN
+}
+
+// TextCriteria select statements matching a full-text search query
+type TextCriteria struct {
 	val string
 }
 
+// SupersedeCriteria select statements superseded or retracted by later
+// statements of the same publisher
+type SupersedeCriteria struct {
+	op string
+}
+
+// NamespaceCriteria restrict the namespaces of a query; they are not part
+// of the grammar, but are added by the node to enforce read access rules.
+type NamespaceCriteria struct {
+	ns string
+}
+
 type CompoundCriteria struct {
 	op          string
 	left, right QueryCriteria
@@ -101,6 +243,26 @@ func (c *IndexCriteria) criteriaType() string {
 	return "index"
 }
 
+func (c *DataCriteria) criteriaType() string {
+	return "data"
+}
+
+func (c *FieldCriteria) criteriaType() string {
+	return "field"
+}
+
+func (c *TextCriteria) criteriaType() string {
+	return "text"
+}
+
+func (c *SupersedeCriteria) criteriaType() string {
+	return "supersede"
+}
+
+func (c *NamespaceCriteria) criteriaType() string {
+	return "namespace"
+}
+
 func (c *CompoundCriteria) criteriaType() string {
 	return "compound"
 }
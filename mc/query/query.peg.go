@@ -17,11 +17,15 @@ const (
 	ruleGrammar
 	ruleSelect
 	ruleDelete
+	ruleExplain
+	ruleDistinct
 	ruleSelector
 	ruleSimpleSelector
 	ruleSimpleSelectorOp
 	ruleCompoundSelector
+	ruleCompoundSelectorPart
 	ruleFunctionSelector
+	ruleFunctionDistinct
 	ruleFunction
 	ruleFunctionOp
 	ruleSource
@@ -38,6 +42,9 @@ const (
 	ruleSourceCriteria
 	ruleValueCompare
 	ruleValueCompareOp
+	ruleValueIn
+	ruleValueMatch
+	ruleValueMatchOp
 	ruleRangeCriteria
 	ruleRangeSelector
 	ruleRangeSelectorOp
@@ -47,6 +54,21 @@ const (
 	ruleComparisonOp
 	ruleIndexCriteria
 	ruleWKICriteria
+	ruleTagCriteria
+	ruleDepCriteria
+	ruleIndexCompare
+	ruleDataCriteria
+	ruleFieldCriteria
+	ruleTextCriteria
+	ruleSupersedeCriteria
+	ruleDataPath
+	ruleDataKey
+	ruleDataCompare
+	ruleDataCompareOp
+	ruleGroup
+	ruleGroupSpec
+	ruleGroupSelector
+	ruleGroupSelectorOp
 	ruleOrder
 	ruleOrderSpec
 	ruleOrderSelectorSpec
@@ -55,10 +77,23 @@ const (
 	ruleOrderDir
 	ruleOrderDirOp
 	ruleLimit
+	ruleOffset
 	ruleStatementId
 	rulePublisherId
 	ruleWKI
+	ruleTag
+	ruleObjectId
 	ruleUInt
+	ruleDataValue
+	ruleStatementIdPattern
+	ruleWKIPattern
+	ruleStatementIdList
+	rulePublisherIdList
+	ruleWKIList
+	ruleTagList
+	ruleObjectIdList
+	ruleDataValueList
+	ruleDataValueItem
 	ruleWS
 	ruleWSX
 	ruleWhiteSpace
@@ -69,9 +104,9 @@ const (
 	ruleAction2
 	ruleAction3
 	ruleAction4
-	rulePegText
 	ruleAction5
 	ruleAction6
+	rulePegText
 	ruleAction7
 	ruleAction8
 	ruleAction9
@@ -98,6 +133,44 @@ const (
 	ruleAction30
 	ruleAction31
 	ruleAction32
+	ruleAction33
+	ruleAction34
+	ruleAction35
+	ruleAction36
+	ruleAction37
+	ruleAction38
+	ruleAction39
+	ruleAction40
+	ruleAction41
+	ruleAction42
+	ruleAction43
+	ruleAction44
+	ruleAction45
+	ruleAction46
+	ruleAction47
+	ruleAction48
+	ruleAction49
+	ruleAction50
+	ruleAction51
+	ruleAction52
+	ruleAction53
+	ruleAction54
+	ruleAction55
+	ruleAction56
+	ruleAction57
+	ruleAction58
+	ruleAction59
+	ruleAction60
+	ruleAction61
+	ruleAction62
+	ruleAction63
+	ruleAction64
+	ruleAction65
+	ruleAction66
+	ruleAction67
+	ruleAction68
+	ruleAction69
+	ruleAction70
 
 	rulePre
 	ruleIn
@@ -109,11 +182,15 @@ var rul3s = [...]string{
 	"Grammar",
 	"Select",
 	"Delete",
+	"Explain",
+	"Distinct",
 	"Selector",
 	"SimpleSelector",
 	"SimpleSelectorOp",
 	"CompoundSelector",
+	"CompoundSelectorPart",
 	"FunctionSelector",
+	"FunctionDistinct",
 	"Function",
 	"FunctionOp",
 	"Source",
@@ -130,6 +207,9 @@ var rul3s = [...]string{
 	"SourceCriteria",
 	"ValueCompare",
 	"ValueCompareOp",
+	"ValueIn",
+	"ValueMatch",
+	"ValueMatchOp",
 	"RangeCriteria",
 	"RangeSelector",
 	"RangeSelectorOp",
@@ -139,6 +219,21 @@ var rul3s = [...]string{
 	"ComparisonOp",
 	"IndexCriteria",
 	"WKICriteria",
+	"TagCriteria",
+	"DepCriteria",
+	"IndexCompare",
+	"DataCriteria",
+	"FieldCriteria",
+	"TextCriteria",
+	"SupersedeCriteria",
+	"DataPath",
+	"DataKey",
+	"DataCompare",
+	"DataCompareOp",
+	"Group",
+	"GroupSpec",
+	"GroupSelector",
+	"GroupSelectorOp",
 	"Order",
 	"OrderSpec",
 	"OrderSelectorSpec",
@@ -147,10 +242,23 @@ var rul3s = [...]string{
 	"OrderDir",
 	"OrderDirOp",
 	"Limit",
+	"Offset",
 	"StatementId",
 	"PublisherId",
 	"WKI",
+	"Tag",
+	"ObjectId",
 	"UInt",
+	"DataValue",
+	"StatementIdPattern",
+	"WKIPattern",
+	"StatementIdList",
+	"PublisherIdList",
+	"WKIList",
+	"TagList",
+	"ObjectIdList",
+	"DataValueList",
+	"DataValueItem",
 	"WS",
 	"WSX",
 	"WhiteSpace",
@@ -161,9 +269,9 @@ var rul3s = [...]string{
 	"Action2",
 	"Action3",
 	"Action4",
-	"PegText",
 	"Action5",
 	"Action6",
+	"PegText",
 	"Action7",
 	"Action8",
 	"Action9",
@@ -190,6 +298,44 @@ var rul3s = [...]string{
 	"Action30",
 	"Action31",
 	"Action32",
+	"Action33",
+	"Action34",
+	"Action35",
+	"Action36",
+	"Action37",
+	"Action38",
+	"Action39",
+	"Action40",
+	"Action41",
+	"Action42",
+	"Action43",
+	"Action44",
+	"Action45",
+	"Action46",
+	"Action47",
+	"Action48",
+	"Action49",
+	"Action50",
+	"Action51",
+	"Action52",
+	"Action53",
+	"Action54",
+	"Action55",
+	"Action56",
+	"Action57",
+	"Action58",
+	"Action59",
+	"Action60",
+	"Action61",
+	"Action62",
+	"Action63",
+	"Action64",
+	"Action65",
+	"Action66",
+	"Action67",
+	"Action68",
+	"Action69",
+	"Action70",
 
 	"Pre_",
 	"_In_",
@@ -485,7 +631,7 @@ type QueryParser struct {
 
 	Buffer string
 	buffer []rune
-	rules  [85]func() bool
+	rules  [158]func() bool
 	Parse  func(rule ...int) error
 	Reset  func()
 	Pretty bool
@@ -574,45 +720,45 @@ func (p *QueryParser) Execute() {
 		case ruleAction1:
 			p.setDeleteOp()
 		case ruleAction2:
-			p.setSimpleSelector()
+			p.setExplainOp()
 		case ruleAction3:
-			p.setCompoundSelector()
+			p.setDistinct()
 		case ruleAction4:
-			p.setFunctionSelector()
+			p.setSimpleSelector()
 		case ruleAction5:
-			p.push(text)
+			p.setCompoundSelector()
 		case ruleAction6:
-			p.push(text)
+			p.setFunctionSelector()
 		case ruleAction7:
-			p.setNamespace(text)
+			p.push(text)
 		case ruleAction8:
-			p.setCriteria()
+			p.addFunctionSelector()
 		case ruleAction9:
-			p.addCompoundCriteria()
+			p.push(text)
 		case ruleAction10:
-			p.addNegatedCriteria()
+			p.push(text)
 		case ruleAction11:
-			p.addValueCriteria()
+			p.setNamespace(text)
 		case ruleAction12:
-			p.addRangeCriteria()
+			p.setCriteria()
 		case ruleAction13:
-			p.addIndexCriteria()
+			p.addCompoundCriteria()
 		case ruleAction14:
-			p.push(text)
+			p.addNegatedCriteria()
 		case ruleAction15:
-			p.push(text)
+			p.addValueCriteria()
 		case ruleAction16:
-			p.push(text)
+			p.addRangeCriteria()
 		case ruleAction17:
-			p.push(text)
+			p.addIndexCriteria()
 		case ruleAction18:
-			p.push(text)
+			p.addDataCriteria()
 		case ruleAction19:
-			p.push(text)
+			p.addFieldCriteria()
 		case ruleAction20:
-			p.push(text)
+			p.addTextCriteria()
 		case ruleAction21:
-			p.push(text)
+			p.addSupersedeCriteria()
 		case ruleAction22:
 			p.push(text)
 		case ruleAction23:
@@ -624,17 +770,93 @@ func (p *QueryParser) Execute() {
 		case ruleAction26:
 			p.push(text)
 		case ruleAction27:
-			p.setOrder()
+			p.push(text)
 		case ruleAction28:
-			p.addOrderSelector()
+			p.push(text)
 		case ruleAction29:
-			p.setOrderDir()
-		case ruleAction30:
 			p.push(text)
+		case ruleAction30:
+			p.pushValueList(text)
 		case ruleAction31:
 			p.push(text)
 		case ruleAction32:
+			p.push(text)
+		case ruleAction33:
+			p.push(text)
+		case ruleAction34:
+			p.push(text)
+		case ruleAction35:
+			p.push(text)
+		case ruleAction36:
+			p.push(text)
+		case ruleAction37:
+			p.push(text)
+		case ruleAction38:
+			p.push(text)
+		case ruleAction39:
+			p.push(text)
+		case ruleAction40:
+			p.push(text)
+		case ruleAction41:
+			p.push(text)
+		case ruleAction42:
+			p.push(text)
+		case ruleAction43:
+			p.push(text)
+		case ruleAction44:
+			p.push(text)
+		case ruleAction45:
+			p.push(text)
+		case ruleAction46:
+			p.push(text)
+		case ruleAction47:
+			p.push(text)
+		case ruleAction48:
+			p.setGroup()
+		case ruleAction49:
+			p.push(text)
+		case ruleAction50:
+			p.setOrder()
+		case ruleAction51:
+			p.addOrderSelector()
+		case ruleAction52:
+			p.setOrderDir()
+		case ruleAction53:
+			p.push(text)
+		case ruleAction54:
+			p.push(text)
+		case ruleAction55:
 			p.setLimit(text)
+		case ruleAction56:
+			p.setOffset(text)
+		case ruleAction57:
+			p.push(text)
+		case ruleAction58:
+			p.push(text)
+		case ruleAction59:
+			p.addListValue(text)
+		case ruleAction60:
+			p.addListValue(text)
+		case ruleAction61:
+			p.addListValue(text)
+		case ruleAction62:
+			p.addListValue(text)
+		case ruleAction63:
+			p.addListValue(text)
+		case ruleAction64:
+			p.addListValue(text)
+		case ruleAction65:
+			p.addListValue(text)
+		case ruleAction66:
+			p.addListValue(text)
+		case ruleAction67:
+			p.addListValue(text)
+		case ruleAction68:
+			p.addListValue(text)
+		case ruleAction69:
+			p.addListValue(text)
+		case ruleAction70:
+			p.addListValue(text)
 
 		}
 	}
@@ -704,627 +926,783 @@ func (p *QueryParser) Init() {
 
 	_rules = [...]func() bool{
 		nil,
-		/* 0 Grammar <- <((Select WSX EOF Action0) / (Delete WSX EOF Action1))> */
+		/* 0 Grammar <- <((&('E') (Explain WSX EOF Action2)) | (&('D') (Delete WSX EOF Action1)) | (&('S') (Select WSX EOF Action0)))> */
 		func() bool {
 			position0, tokenIndex0, depth0 := position, tokenIndex, depth
 			{
 				position1 := position
 				depth++
 				{
-					position2, tokenIndex2, depth2 := position, tokenIndex, depth
-					{
-						position4 := position
-						depth++
-						if buffer[position] != rune('S') {
-							goto l3
-						}
-						position++
-						if buffer[position] != rune('E') {
-							goto l3
-						}
-						position++
-						if buffer[position] != rune('L') {
-							goto l3
-						}
-						position++
-						if buffer[position] != rune('E') {
-							goto l3
-						}
-						position++
-						if buffer[position] != rune('C') {
-							goto l3
-						}
-						position++
-						if buffer[position] != rune('T') {
-							goto l3
-						}
-						position++
-						if !_rules[ruleWS]() {
-							goto l3
-						}
+					switch buffer[position] {
+					case 'E':
 						{
-							position5 := position
+							position3 := position
 							depth++
-							{
-								switch buffer[position] {
-								case 'C', 'M':
-									{
-										position7 := position
-										depth++
-										{
-											position8 := position
-											depth++
-											{
-												position9 := position
-												depth++
-												{
-													position10 := position
-													depth++
-													{
-														position11, tokenIndex11, depth11 := position, tokenIndex, depth
-														if buffer[position] != rune('C') {
-															goto l12
-														}
-														position++
-														if buffer[position] != rune('O') {
-															goto l12
-														}
-														position++
-														if buffer[position] != rune('U') {
-															goto l12
-														}
-														position++
-														if buffer[position] != rune('N') {
-															goto l12
-														}
-														position++
-														if buffer[position] != rune('T') {
-															goto l12
-														}
-														position++
-														goto l11
-													l12:
-														position, tokenIndex, depth = position11, tokenIndex11, depth11
-														if buffer[position] != rune('M') {
-															goto l13
-														}
-														position++
-														if buffer[position] != rune('I') {
-															goto l13
-														}
-														position++
-														if buffer[position] != rune('N') {
-															goto l13
-														}
-														position++
-														goto l11
-													l13:
-														position, tokenIndex, depth = position11, tokenIndex11, depth11
-														if buffer[position] != rune('M') {
-															goto l3
-														}
-														position++
-														if buffer[position] != rune('A') {
-															goto l3
-														}
-														position++
-														if buffer[position] != rune('X') {
-															goto l3
-														}
-														position++
-													}
-												l11:
-													depth--
-													add(ruleFunctionOp, position10)
-												}
-												depth--
-												add(rulePegText, position9)
-											}
-											{
-												add(ruleAction6, position)
-											}
-											depth--
-											add(ruleFunction, position8)
-										}
-										if buffer[position] != rune('(') {
-											goto l3
-										}
-										position++
-										if !_rules[ruleSimpleSelector]() {
-											goto l3
-										}
-										if buffer[position] != rune(')') {
-											goto l3
-										}
-										position++
-										depth--
-										add(ruleFunctionSelector, position7)
-									}
-									{
-										add(ruleAction4, position)
-									}
-									break
-								case '(':
-									{
-										position16 := position
-										depth++
-										if buffer[position] != rune('(') {
-											goto l3
-										}
-										position++
-										if !_rules[ruleSimpleSelector]() {
-											goto l3
-										}
-									l17:
-										{
-											position18, tokenIndex18, depth18 := position, tokenIndex, depth
-											if buffer[position] != rune(',') {
-												goto l18
-											}
-											position++
-											if !_rules[ruleWSX]() {
-												goto l18
-											}
-											if !_rules[ruleSimpleSelector]() {
-												goto l18
-											}
-											goto l17
-										l18:
-											position, tokenIndex, depth = position18, tokenIndex18, depth18
-										}
-										if buffer[position] != rune(')') {
-											goto l3
-										}
-										position++
-										depth--
-										add(ruleCompoundSelector, position16)
-									}
-									{
-										add(ruleAction3, position)
-									}
-									break
-								default:
-									if !_rules[ruleSimpleSelector]() {
-										goto l3
-									}
-									{
-										add(ruleAction2, position)
-									}
-									break
-								}
+							if buffer[position] != rune('E') {
+								goto l0
+							}
+							position++
+							if buffer[position] != rune('X') {
+								goto l0
+							}
+							position++
+							if buffer[position] != rune('P') {
+								goto l0
+							}
+							position++
+							if buffer[position] != rune('L') {
+								goto l0
+							}
+							position++
+							if buffer[position] != rune('A') {
+								goto l0
+							}
+							position++
+							if buffer[position] != rune('I') {
+								goto l0
+							}
+							position++
+							if buffer[position] != rune('N') {
+								goto l0
+							}
+							position++
+							if !_rules[ruleWS]() {
+								goto l0
+							}
+							if !_rules[ruleSelect]() {
+								goto l0
 							}
-
 							depth--
-							add(ruleSelector, position5)
+							add(ruleExplain, position3)
 						}
-						if !_rules[ruleWS]() {
-							goto l3
+						if !_rules[ruleWSX]() {
+							goto l0
 						}
-						if !_rules[ruleSource]() {
-							goto l3
+						if !_rules[ruleEOF]() {
+							goto l0
 						}
 						{
-							position21, tokenIndex21, depth21 := position, tokenIndex, depth
-							if !_rules[ruleWS]() {
-								goto l21
-							}
-							if !_rules[ruleCriteria]() {
-								goto l21
-							}
-							goto l22
-						l21:
-							position, tokenIndex, depth = position21, tokenIndex21, depth21
+							add(ruleAction2, position)
 						}
-					l22:
+						break
+					case 'D':
 						{
-							position23, tokenIndex23, depth23 := position, tokenIndex, depth
+							position5 := position
+							depth++
+							if buffer[position] != rune('D') {
+								goto l0
+							}
+							position++
+							if buffer[position] != rune('E') {
+								goto l0
+							}
+							position++
+							if buffer[position] != rune('L') {
+								goto l0
+							}
+							position++
+							if buffer[position] != rune('E') {
+								goto l0
+							}
+							position++
+							if buffer[position] != rune('T') {
+								goto l0
+							}
+							position++
+							if buffer[position] != rune('E') {
+								goto l0
+							}
+							position++
 							if !_rules[ruleWS]() {
-								goto l23
+								goto l0
+							}
+							if !_rules[ruleSource]() {
+								goto l0
 							}
 							{
-								position25 := position
-								depth++
-								if buffer[position] != rune('O') {
-									goto l23
-								}
-								position++
-								if buffer[position] != rune('R') {
-									goto l23
-								}
-								position++
-								if buffer[position] != rune('D') {
-									goto l23
-								}
-								position++
-								if buffer[position] != rune('E') {
-									goto l23
-								}
-								position++
-								if buffer[position] != rune('R') {
-									goto l23
-								}
-								position++
+								position6, tokenIndex6, depth6 := position, tokenIndex, depth
 								if !_rules[ruleWS]() {
-									goto l23
-								}
-								if buffer[position] != rune('B') {
-									goto l23
+									goto l6
 								}
-								position++
-								if buffer[position] != rune('Y') {
-									goto l23
+								if !_rules[ruleCriteria]() {
+									goto l6
 								}
-								position++
+								goto l7
+							l6:
+								position, tokenIndex, depth = position6, tokenIndex6, depth6
+							}
+						l7:
+							{
+								position8, tokenIndex8, depth8 := position, tokenIndex, depth
 								if !_rules[ruleWS]() {
-									goto l23
-								}
-								{
-									position26 := position
-									depth++
-									if !_rules[ruleOrderSelectorSpec]() {
-										goto l23
-									}
-								l27:
-									{
-										position28, tokenIndex28, depth28 := position, tokenIndex, depth
-										if buffer[position] != rune(',') {
-											goto l28
-										}
-										position++
-										if !_rules[ruleWSX]() {
-											goto l28
-										}
-										if !_rules[ruleOrderSelectorSpec]() {
-											goto l28
-										}
-										goto l27
-									l28:
-										position, tokenIndex, depth = position28, tokenIndex28, depth28
-									}
-									depth--
-									add(ruleOrderSpec, position26)
+									goto l8
 								}
-								{
-									add(ruleAction27, position)
+								if !_rules[ruleLimit]() {
+									goto l8
 								}
-								depth--
-								add(ruleOrder, position25)
+								goto l9
+							l8:
+								position, tokenIndex, depth = position8, tokenIndex8, depth8
 							}
-							goto l24
-						l23:
-							position, tokenIndex, depth = position23, tokenIndex23, depth23
+						l9:
+							depth--
+							add(ruleDelete, position5)
+						}
+						if !_rules[ruleWSX]() {
+							goto l0
+						}
+						if !_rules[ruleEOF]() {
+							goto l0
 						}
-					l24:
 						{
-							position30, tokenIndex30, depth30 := position, tokenIndex, depth
-							if !_rules[ruleWS]() {
-								goto l30
-							}
-							if !_rules[ruleLimit]() {
-								goto l30
-							}
-							goto l31
-						l30:
-							position, tokenIndex, depth = position30, tokenIndex30, depth30
+							add(ruleAction1, position)
 						}
-					l31:
-						depth--
-						add(ruleSelect, position4)
-					}
-					if !_rules[ruleWSX]() {
-						goto l3
-					}
-					if !_rules[ruleEOF]() {
-						goto l3
-					}
-					{
-						add(ruleAction0, position)
-					}
-					goto l2
-				l3:
-					position, tokenIndex, depth = position2, tokenIndex2, depth2
-					{
-						position33 := position
-						depth++
-						if buffer[position] != rune('D') {
+						break
+					default:
+						if !_rules[ruleSelect]() {
 							goto l0
 						}
-						position++
-						if buffer[position] != rune('E') {
+						if !_rules[ruleWSX]() {
 							goto l0
 						}
-						position++
-						if buffer[position] != rune('L') {
+						if !_rules[ruleEOF]() {
 							goto l0
 						}
+						{
+							add(ruleAction0, position)
+						}
+						break
+					}
+				}
+
+				depth--
+				add(ruleGrammar, position1)
+			}
+			return true
+		l0:
+			position, tokenIndex, depth = position0, tokenIndex0, depth0
+			return false
+		},
+		/* 1 Select <- <('S' 'E' 'L' 'E' 'C' 'T' WS (Distinct WS)? Selector WS Source (WS Criteria)? (WS Group)? (WS Order)? (WS Limit)? (WS Offset)?)> */
+		func() bool {
+			position12, tokenIndex12, depth12 := position, tokenIndex, depth
+			{
+				position13 := position
+				depth++
+				if buffer[position] != rune('S') {
+					goto l12
+				}
+				position++
+				if buffer[position] != rune('E') {
+					goto l12
+				}
+				position++
+				if buffer[position] != rune('L') {
+					goto l12
+				}
+				position++
+				if buffer[position] != rune('E') {
+					goto l12
+				}
+				position++
+				if buffer[position] != rune('C') {
+					goto l12
+				}
+				position++
+				if buffer[position] != rune('T') {
+					goto l12
+				}
+				position++
+				if !_rules[ruleWS]() {
+					goto l12
+				}
+				{
+					position14, tokenIndex14, depth14 := position, tokenIndex, depth
+					{
+						position16 := position
+						depth++
+						if buffer[position] != rune('D') {
+							goto l14
+						}
 						position++
-						if buffer[position] != rune('E') {
-							goto l0
+						if buffer[position] != rune('I') {
+							goto l14
+						}
+						position++
+						if buffer[position] != rune('S') {
+							goto l14
 						}
 						position++
 						if buffer[position] != rune('T') {
-							goto l0
+							goto l14
 						}
 						position++
-						if buffer[position] != rune('E') {
-							goto l0
+						if buffer[position] != rune('I') {
+							goto l14
+						}
+						position++
+						if buffer[position] != rune('N') {
+							goto l14
+						}
+						position++
+						if buffer[position] != rune('C') {
+							goto l14
+						}
+						position++
+						if buffer[position] != rune('T') {
+							goto l14
+						}
+						position++
+						{
+							add(ruleAction3, position)
+						}
+						depth--
+						add(ruleDistinct, position16)
+					}
+					if !_rules[ruleWS]() {
+						goto l14
+					}
+					goto l15
+				l14:
+					position, tokenIndex, depth = position14, tokenIndex14, depth14
+				}
+			l15:
+				{
+					position18 := position
+					depth++
+					{
+						switch buffer[position] {
+						case 'C', 'M':
+							if !_rules[ruleFunctionSelector]() {
+								goto l12
+							}
+							{
+								add(ruleAction6, position)
+							}
+							break
+						case '(':
+							{
+								position21 := position
+								depth++
+								if buffer[position] != rune('(') {
+									goto l12
+								}
+								position++
+								if !_rules[ruleCompoundSelectorPart]() {
+									goto l12
+								}
+							l22:
+								{
+									position23, tokenIndex23, depth23 := position, tokenIndex, depth
+									if buffer[position] != rune(',') {
+										goto l23
+									}
+									position++
+									if !_rules[ruleWSX]() {
+										goto l23
+									}
+									if !_rules[ruleCompoundSelectorPart]() {
+										goto l23
+									}
+									goto l22
+								l23:
+									position, tokenIndex, depth = position23, tokenIndex23, depth23
+								}
+								if buffer[position] != rune(')') {
+									goto l12
+								}
+								position++
+								depth--
+								add(ruleCompoundSelector, position21)
+							}
+							{
+								add(ruleAction5, position)
+							}
+							break
+						default:
+							if !_rules[ruleSimpleSelector]() {
+								goto l12
+							}
+							{
+								add(ruleAction4, position)
+							}
+							break
+						}
+					}
+
+					depth--
+					add(ruleSelector, position18)
+				}
+				if !_rules[ruleWS]() {
+					goto l12
+				}
+				if !_rules[ruleSource]() {
+					goto l12
+				}
+				{
+					position26, tokenIndex26, depth26 := position, tokenIndex, depth
+					if !_rules[ruleWS]() {
+						goto l26
+					}
+					if !_rules[ruleCriteria]() {
+						goto l26
+					}
+					goto l27
+				l26:
+					position, tokenIndex, depth = position26, tokenIndex26, depth26
+				}
+			l27:
+				{
+					position28, tokenIndex28, depth28 := position, tokenIndex, depth
+					if !_rules[ruleWS]() {
+						goto l28
+					}
+					{
+						position30 := position
+						depth++
+						if buffer[position] != rune('G') {
+							goto l28
+						}
+						position++
+						if buffer[position] != rune('R') {
+							goto l28
+						}
+						position++
+						if buffer[position] != rune('O') {
+							goto l28
+						}
+						position++
+						if buffer[position] != rune('U') {
+							goto l28
+						}
+						position++
+						if buffer[position] != rune('P') {
+							goto l28
 						}
 						position++
 						if !_rules[ruleWS]() {
-							goto l0
+							goto l28
 						}
-						if !_rules[ruleSource]() {
-							goto l0
+						if buffer[position] != rune('B') {
+							goto l28
+						}
+						position++
+						if buffer[position] != rune('Y') {
+							goto l28
+						}
+						position++
+						if !_rules[ruleWS]() {
+							goto l28
 						}
 						{
-							position34, tokenIndex34, depth34 := position, tokenIndex, depth
-							if !_rules[ruleWS]() {
-								goto l34
+							position31 := position
+							depth++
+							if !_rules[ruleGroupSelector]() {
+								goto l28
 							}
-							if !_rules[ruleCriteria]() {
-								goto l34
+						l32:
+							{
+								position33, tokenIndex33, depth33 := position, tokenIndex, depth
+								if buffer[position] != rune(',') {
+									goto l33
+								}
+								position++
+								if !_rules[ruleWSX]() {
+									goto l33
+								}
+								if !_rules[ruleGroupSelector]() {
+									goto l33
+								}
+								goto l32
+							l33:
+								position, tokenIndex, depth = position33, tokenIndex33, depth33
 							}
+							depth--
+							add(ruleGroupSpec, position31)
+						}
+						{
+							add(ruleAction48, position)
+						}
+						depth--
+						add(ruleGroup, position30)
+					}
+					goto l29
+				l28:
+					position, tokenIndex, depth = position28, tokenIndex28, depth28
+				}
+			l29:
+				{
+					position35, tokenIndex35, depth35 := position, tokenIndex, depth
+					if !_rules[ruleWS]() {
+						goto l35
+					}
+					{
+						position37 := position
+						depth++
+						if buffer[position] != rune('O') {
+							goto l35
+						}
+						position++
+						if buffer[position] != rune('R') {
+							goto l35
+						}
+						position++
+						if buffer[position] != rune('D') {
+							goto l35
+						}
+						position++
+						if buffer[position] != rune('E') {
+							goto l35
+						}
+						position++
+						if buffer[position] != rune('R') {
+							goto l35
+						}
+						position++
+						if !_rules[ruleWS]() {
+							goto l35
+						}
+						if buffer[position] != rune('B') {
+							goto l35
+						}
+						position++
+						if buffer[position] != rune('Y') {
+							goto l35
+						}
+						position++
+						if !_rules[ruleWS]() {
 							goto l35
-						l34:
-							position, tokenIndex, depth = position34, tokenIndex34, depth34
 						}
-					l35:
 						{
-							position36, tokenIndex36, depth36 := position, tokenIndex, depth
-							if !_rules[ruleWS]() {
-								goto l36
+							position38 := position
+							depth++
+							if !_rules[ruleOrderSelectorSpec]() {
+								goto l35
 							}
-							if !_rules[ruleLimit]() {
-								goto l36
+						l39:
+							{
+								position40, tokenIndex40, depth40 := position, tokenIndex, depth
+								if buffer[position] != rune(',') {
+									goto l40
+								}
+								position++
+								if !_rules[ruleWSX]() {
+									goto l40
+								}
+								if !_rules[ruleOrderSelectorSpec]() {
+									goto l40
+								}
+								goto l39
+							l40:
+								position, tokenIndex, depth = position40, tokenIndex40, depth40
 							}
-							goto l37
-						l36:
-							position, tokenIndex, depth = position36, tokenIndex36, depth36
+							depth--
+							add(ruleOrderSpec, position38)
+						}
+						{
+							add(ruleAction50, position)
 						}
-					l37:
 						depth--
-						add(ruleDelete, position33)
+						add(ruleOrder, position37)
 					}
-					if !_rules[ruleWSX]() {
-						goto l0
+					goto l36
+				l35:
+					position, tokenIndex, depth = position35, tokenIndex35, depth35
+				}
+			l36:
+				{
+					position42, tokenIndex42, depth42 := position, tokenIndex, depth
+					if !_rules[ruleWS]() {
+						goto l42
+					}
+					if !_rules[ruleLimit]() {
+						goto l42
 					}
-					if !_rules[ruleEOF]() {
-						goto l0
+					goto l43
+				l42:
+					position, tokenIndex, depth = position42, tokenIndex42, depth42
+				}
+			l43:
+				{
+					position44, tokenIndex44, depth44 := position, tokenIndex, depth
+					if !_rules[ruleWS]() {
+						goto l44
 					}
 					{
-						add(ruleAction1, position)
+						position46 := position
+						depth++
+						if buffer[position] != rune('O') {
+							goto l44
+						}
+						position++
+						if buffer[position] != rune('F') {
+							goto l44
+						}
+						position++
+						if buffer[position] != rune('F') {
+							goto l44
+						}
+						position++
+						if buffer[position] != rune('S') {
+							goto l44
+						}
+						position++
+						if buffer[position] != rune('E') {
+							goto l44
+						}
+						position++
+						if buffer[position] != rune('T') {
+							goto l44
+						}
+						position++
+						if !_rules[ruleWS]() {
+							goto l44
+						}
+						if !_rules[ruleUInt]() {
+							goto l44
+						}
+						{
+							add(ruleAction56, position)
+						}
+						depth--
+						add(ruleOffset, position46)
 					}
+					goto l45
+				l44:
+					position, tokenIndex, depth = position44, tokenIndex44, depth44
 				}
-			l2:
+			l45:
 				depth--
-				add(ruleGrammar, position1)
+				add(ruleSelect, position13)
 			}
 			return true
-		l0:
-			position, tokenIndex, depth = position0, tokenIndex0, depth0
+		l12:
+			position, tokenIndex, depth = position12, tokenIndex12, depth12
 			return false
 		},
-		/* 1 Select <- <('S' 'E' 'L' 'E' 'C' 'T' WS Selector WS Source (WS Criteria)? (WS Order)? (WS Limit)?)> */
-		nil,
 		/* 2 Delete <- <('D' 'E' 'L' 'E' 'T' 'E' WS Source (WS Criteria)? (WS Limit)?)> */
 		nil,
-		/* 3 Selector <- <((&('C' | 'M') (FunctionSelector Action4)) | (&('(') (CompoundSelector Action3)) | (&('*' | 'b' | 'c' | 'i' | 'n' | 'p' | 's' | 't') (SimpleSelector Action2)))> */
+		/* 3 Explain <- <('E' 'X' 'P' 'L' 'A' 'I' 'N' WS Select)> */
+		nil,
+		/* 4 Distinct <- <('D' 'I' 'S' 'T' 'I' 'N' 'C' 'T' Action3)> */
+		nil,
+		/* 5 Selector <- <((&('C' | 'M') (FunctionSelector Action6)) | (&('(') (CompoundSelector Action5)) | (&('*' | 'b' | 'c' | 'i' | 'n' | 'p' | 's' | 't') (SimpleSelector Action4)))> */
 		nil,
-		/* 4 SimpleSelector <- <(<SimpleSelectorOp> Action5)> */
+		/* 6 SimpleSelector <- <(<SimpleSelectorOp> Action7)> */
 		func() bool {
-			position42, tokenIndex42, depth42 := position, tokenIndex, depth
+			position52, tokenIndex52, depth52 := position, tokenIndex, depth
 			{
-				position43 := position
+				position53 := position
 				depth++
 				{
-					position44 := position
+					position54 := position
 					depth++
 					{
-						position45 := position
+						position55 := position
 						depth++
 						{
 							switch buffer[position] {
 							case 'c':
 								if buffer[position] != rune('c') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('o') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('u') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('n') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('t') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('e') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('r') {
-									goto l42
+									goto l52
 								}
 								position++
 								break
 							case 't':
 								if buffer[position] != rune('t') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('i') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('m') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('e') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('s') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('t') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('a') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('m') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('p') {
-									goto l42
+									goto l52
 								}
 								position++
 								break
 							case 's':
 								if buffer[position] != rune('s') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('o') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('u') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('r') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('c') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('e') {
-									goto l42
+									goto l52
 								}
 								position++
 								break
 							case 'n':
 								if buffer[position] != rune('n') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('a') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('m') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('e') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('s') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('p') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('a') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('c') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('e') {
-									goto l42
+									goto l52
 								}
 								position++
 								break
 							case 'p':
 								if buffer[position] != rune('p') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('u') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('b') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('l') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('i') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('s') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('h') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('e') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('r') {
-									goto l42
+									goto l52
 								}
 								position++
 								break
 							case 'i':
 								if buffer[position] != rune('i') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('d') {
-									goto l42
+									goto l52
 								}
 								position++
 								break
 							case 'b':
 								if buffer[position] != rune('b') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('o') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('d') {
-									goto l42
+									goto l52
 								}
 								position++
 								if buffer[position] != rune('y') {
-									goto l42
+									goto l52
 								}
 								position++
 								break
 							default:
 								if buffer[position] != rune('*') {
-									goto l42
+									goto l52
 								}
 								position++
 								break
@@ -1332,1786 +1710,4647 @@ func (p *QueryParser) Init() {
 						}
 
 						depth--
-						add(ruleSimpleSelectorOp, position45)
+						add(ruleSimpleSelectorOp, position55)
 					}
 					depth--
-					add(rulePegText, position44)
+					add(rulePegText, position54)
 				}
 				{
-					add(ruleAction5, position)
+					add(ruleAction7, position)
 				}
 				depth--
-				add(ruleSimpleSelector, position43)
+				add(ruleSimpleSelector, position53)
 			}
 			return true
-		l42:
-			position, tokenIndex, depth = position42, tokenIndex42, depth42
+		l52:
+			position, tokenIndex, depth = position52, tokenIndex52, depth52
 			return false
 		},
-		/* 5 SimpleSelectorOp <- <((&('c') ('c' 'o' 'u' 'n' 't' 'e' 'r')) | (&('t') ('t' 'i' 'm' 'e' 's' 't' 'a' 'm' 'p')) | (&('s') ('s' 'o' 'u' 'r' 'c' 'e')) | (&('n') ('n' 'a' 'm' 'e' 's' 'p' 'a' 'c' 'e')) | (&('p') ('p' 'u' 'b' 'l' 'i' 's' 'h' 'e' 'r')) | (&('i') ('i' 'd')) | (&('b') ('b' 'o' 'd' 'y')) | (&('*') '*'))> */
-		nil,
-		/* 6 CompoundSelector <- <('(' SimpleSelector (',' WSX SimpleSelector)* ')')> */
-		nil,
-		/* 7 FunctionSelector <- <(Function '(' SimpleSelector ')')> */
-		nil,
-		/* 8 Function <- <(<FunctionOp> Action6)> */
+		/* 7 SimpleSelectorOp <- <((&('c') ('c' 'o' 'u' 'n' 't' 'e' 'r')) | (&('t') ('t' 'i' 'm' 'e' 's' 't' 'a' 'm' 'p')) | (&('s') ('s' 'o' 'u' 'r' 'c' 'e')) | (&('n') ('n' 'a' 'm' 'e' 's' 'p' 'a' 'c' 'e')) | (&('p') ('p' 'u' 'b' 'l' 'i' 's' 'h' 'e' 'r')) | (&('i') ('i' 'd')) | (&('b') ('b' 'o' 'd' 'y')) | (&('*') '*'))> */
 		nil,
-		/* 9 FunctionOp <- <(('C' 'O' 'U' 'N' 'T') / ('M' 'I' 'N') / ('M' 'A' 'X'))> */
+		/* 8 CompoundSelector <- <('(' CompoundSelectorPart (',' WSX CompoundSelectorPart)* ')')> */
 		nil,
-		/* 10 Source <- <('F' 'R' 'O' 'M' WS Namespace Action7)> */
+		/* 9 CompoundSelectorPart <- <(SimpleSelector / (FunctionSelector Action8))> */
 		func() bool {
-			position53, tokenIndex53, depth53 := position, tokenIndex, depth
+			position60, tokenIndex60, depth60 := position, tokenIndex, depth
 			{
-				position54 := position
+				position61 := position
 				depth++
-				if buffer[position] != rune('F') {
-					goto l53
-				}
-				position++
-				if buffer[position] != rune('R') {
-					goto l53
-				}
-				position++
-				if buffer[position] != rune('O') {
-					goto l53
-				}
-				position++
-				if buffer[position] != rune('M') {
-					goto l53
-				}
-				position++
-				if !_rules[ruleWS]() {
-					goto l53
-				}
 				{
-					position55 := position
-					depth++
+					position62, tokenIndex62, depth62 := position, tokenIndex, depth
+					if !_rules[ruleSimpleSelector]() {
+						goto l63
+					}
+					goto l62
+				l63:
+					position, tokenIndex, depth = position62, tokenIndex62, depth62
+					if !_rules[ruleFunctionSelector]() {
+						goto l60
+					}
 					{
-						position56, tokenIndex56, depth56 := position, tokenIndex, depth
-						{
-							position58 := position
-							depth++
-							if !_rules[ruleNamespacePart]() {
-								goto l57
-							}
-						l59:
-							{
-								position60, tokenIndex60, depth60 := position, tokenIndex, depth
-								if buffer[position] != rune('.') {
-									goto l60
-								}
+						add(ruleAction8, position)
+					}
+				}
+			l62:
+				depth--
+				add(ruleCompoundSelectorPart, position61)
+			}
+			return true
+		l60:
+			position, tokenIndex, depth = position60, tokenIndex60, depth60
+			return false
+		},
+		/* 10 FunctionSelector <- <(Function '(' (FunctionDistinct WS)? SimpleSelector ')')> */
+		func() bool {
+			position65, tokenIndex65, depth65 := position, tokenIndex, depth
+			{
+				position66 := position
+				depth++
+				{
+					position67 := position
+					depth++
+					{
+						position68 := position
+						depth++
+						{
+							position69 := position
+							depth++
+							{
+								position70, tokenIndex70, depth70 := position, tokenIndex, depth
+								if buffer[position] != rune('C') {
+									goto l71
+								}
+								position++
+								if buffer[position] != rune('O') {
+									goto l71
+								}
+								position++
+								if buffer[position] != rune('U') {
+									goto l71
+								}
+								position++
+								if buffer[position] != rune('N') {
+									goto l71
+								}
+								position++
+								if buffer[position] != rune('T') {
+									goto l71
+								}
+								position++
+								goto l70
+							l71:
+								position, tokenIndex, depth = position70, tokenIndex70, depth70
+								if buffer[position] != rune('M') {
+									goto l72
+								}
+								position++
+								if buffer[position] != rune('I') {
+									goto l72
+								}
+								position++
+								if buffer[position] != rune('N') {
+									goto l72
+								}
+								position++
+								goto l70
+							l72:
+								position, tokenIndex, depth = position70, tokenIndex70, depth70
+								if buffer[position] != rune('M') {
+									goto l65
+								}
+								position++
+								if buffer[position] != rune('A') {
+									goto l65
+								}
+								position++
+								if buffer[position] != rune('X') {
+									goto l65
+								}
+								position++
+							}
+						l70:
+							depth--
+							add(ruleFunctionOp, position69)
+						}
+						depth--
+						add(rulePegText, position68)
+					}
+					{
+						add(ruleAction10, position)
+					}
+					depth--
+					add(ruleFunction, position67)
+				}
+				if buffer[position] != rune('(') {
+					goto l65
+				}
+				position++
+				{
+					position74, tokenIndex74, depth74 := position, tokenIndex, depth
+					{
+						position76 := position
+						depth++
+						{
+							position77 := position
+							depth++
+							if buffer[position] != rune('D') {
+								goto l74
+							}
+							position++
+							if buffer[position] != rune('I') {
+								goto l74
+							}
+							position++
+							if buffer[position] != rune('S') {
+								goto l74
+							}
+							position++
+							if buffer[position] != rune('T') {
+								goto l74
+							}
+							position++
+							if buffer[position] != rune('I') {
+								goto l74
+							}
+							position++
+							if buffer[position] != rune('N') {
+								goto l74
+							}
+							position++
+							if buffer[position] != rune('C') {
+								goto l74
+							}
+							position++
+							if buffer[position] != rune('T') {
+								goto l74
+							}
+							position++
+							depth--
+							add(rulePegText, position77)
+						}
+						{
+							add(ruleAction9, position)
+						}
+						depth--
+						add(ruleFunctionDistinct, position76)
+					}
+					if !_rules[ruleWS]() {
+						goto l74
+					}
+					goto l75
+				l74:
+					position, tokenIndex, depth = position74, tokenIndex74, depth74
+				}
+			l75:
+				if !_rules[ruleSimpleSelector]() {
+					goto l65
+				}
+				if buffer[position] != rune(')') {
+					goto l65
+				}
+				position++
+				depth--
+				add(ruleFunctionSelector, position66)
+			}
+			return true
+		l65:
+			position, tokenIndex, depth = position65, tokenIndex65, depth65
+			return false
+		},
+		/* 11 FunctionDistinct <- <(<('D' 'I' 'S' 'T' 'I' 'N' 'C' 'T')> Action9)> */
+		nil,
+		/* 12 Function <- <(<FunctionOp> Action10)> */
+		nil,
+		/* 13 FunctionOp <- <(('C' 'O' 'U' 'N' 'T') / ('M' 'I' 'N') / ('M' 'A' 'X'))> */
+		nil,
+		/* 14 Source <- <('F' 'R' 'O' 'M' WS Namespace Action11)> */
+		func() bool {
+			position82, tokenIndex82, depth82 := position, tokenIndex, depth
+			{
+				position83 := position
+				depth++
+				if buffer[position] != rune('F') {
+					goto l82
+				}
+				position++
+				if buffer[position] != rune('R') {
+					goto l82
+				}
+				position++
+				if buffer[position] != rune('O') {
+					goto l82
+				}
+				position++
+				if buffer[position] != rune('M') {
+					goto l82
+				}
+				position++
+				if !_rules[ruleWS]() {
+					goto l82
+				}
+				{
+					position84 := position
+					depth++
+					{
+						position85, tokenIndex85, depth85 := position, tokenIndex, depth
+						{
+							position87 := position
+							depth++
+							if !_rules[ruleNamespacePart]() {
+								goto l86
+							}
+						l88:
+							{
+								position89, tokenIndex89, depth89 := position, tokenIndex, depth
+								if buffer[position] != rune('.') {
+									goto l89
+								}
 								position++
 								if !_rules[ruleNamespacePart]() {
-									goto l60
+									goto l89
 								}
-								goto l59
-							l60:
-								position, tokenIndex, depth = position60, tokenIndex60, depth60
+								goto l88
+							l89:
+								position, tokenIndex, depth = position89, tokenIndex89, depth89
 							}
 							{
-								position61, tokenIndex61, depth61 := position, tokenIndex, depth
+								position90, tokenIndex90, depth90 := position, tokenIndex, depth
 								if buffer[position] != rune('.') {
-									goto l61
+									goto l90
 								}
 								position++
 								if !_rules[ruleWildcard]() {
-									goto l61
+									goto l90
 								}
-								goto l62
-							l61:
-								position, tokenIndex, depth = position61, tokenIndex61, depth61
+								goto l91
+							l90:
+								position, tokenIndex, depth = position90, tokenIndex90, depth90
 							}
-						l62:
+						l91:
 							depth--
-							add(rulePegText, position58)
+							add(rulePegText, position87)
 						}
-						goto l56
-					l57:
-						position, tokenIndex, depth = position56, tokenIndex56, depth56
+						goto l85
+					l86:
+						position, tokenIndex, depth = position85, tokenIndex85, depth85
 						{
-							position63 := position
+							position92 := position
 							depth++
 							if !_rules[ruleWildcard]() {
-								goto l53
+								goto l82
 							}
 							depth--
-							add(rulePegText, position63)
+							add(rulePegText, position92)
 						}
 					}
-				l56:
+				l85:
 					depth--
-					add(ruleNamespace, position55)
+					add(ruleNamespace, position84)
 				}
 				{
-					add(ruleAction7, position)
+					add(ruleAction11, position)
 				}
 				depth--
-				add(ruleSource, position54)
+				add(ruleSource, position83)
 			}
 			return true
-		l53:
-			position, tokenIndex, depth = position53, tokenIndex53, depth53
+		l82:
+			position, tokenIndex, depth = position82, tokenIndex82, depth82
 			return false
 		},
-		/* 11 Namespace <- <(<(NamespacePart ('.' NamespacePart)* ('.' Wildcard)?)> / <Wildcard>)> */
+		/* 15 Namespace <- <(<(NamespacePart ('.' NamespacePart)* ('.' Wildcard)?)> / <Wildcard>)> */
 		nil,
-		/* 12 NamespacePart <- <((&('-') '-') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))+> */
+		/* 16 NamespacePart <- <((&('-') '-') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))+> */
 		func() bool {
-			position66, tokenIndex66, depth66 := position, tokenIndex, depth
+			position95, tokenIndex95, depth95 := position, tokenIndex, depth
 			{
-				position67 := position
+				position96 := position
 				depth++
 				{
 					switch buffer[position] {
 					case '-':
 						if buffer[position] != rune('-') {
-							goto l66
+							goto l95
 						}
 						position++
 						break
 					case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
 						if c := buffer[position]; c < rune('0') || c > rune('9') {
-							goto l66
+							goto l95
 						}
 						position++
 						break
 					case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
 						if c := buffer[position]; c < rune('A') || c > rune('Z') {
-							goto l66
+							goto l95
 						}
 						position++
 						break
 					default:
 						if c := buffer[position]; c < rune('a') || c > rune('z') {
-							goto l66
+							goto l95
 						}
 						position++
 						break
 					}
 				}
 
-			l68:
+			l97:
 				{
-					position69, tokenIndex69, depth69 := position, tokenIndex, depth
+					position98, tokenIndex98, depth98 := position, tokenIndex, depth
 					{
 						switch buffer[position] {
 						case '-':
 							if buffer[position] != rune('-') {
-								goto l69
+								goto l98
 							}
 							position++
 							break
 						case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
 							if c := buffer[position]; c < rune('0') || c > rune('9') {
-								goto l69
+								goto l98
 							}
 							position++
 							break
 						case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
 							if c := buffer[position]; c < rune('A') || c > rune('Z') {
-								goto l69
+								goto l98
 							}
 							position++
 							break
 						default:
 							if c := buffer[position]; c < rune('a') || c > rune('z') {
-								goto l69
+								goto l98
 							}
 							position++
 							break
 						}
 					}
 
-					goto l68
-				l69:
-					position, tokenIndex, depth = position69, tokenIndex69, depth69
+					goto l97
+				l98:
+					position, tokenIndex, depth = position98, tokenIndex98, depth98
 				}
 				depth--
-				add(ruleNamespacePart, position67)
+				add(ruleNamespacePart, position96)
 			}
 			return true
-		l66:
-			position, tokenIndex, depth = position66, tokenIndex66, depth66
+		l95:
+			position, tokenIndex, depth = position95, tokenIndex95, depth95
 			return false
 		},
-		/* 13 Wildcard <- <'*'> */
+		/* 17 Wildcard <- <'*'> */
 		func() bool {
-			position72, tokenIndex72, depth72 := position, tokenIndex, depth
+			position101, tokenIndex101, depth101 := position, tokenIndex, depth
 			{
-				position73 := position
+				position102 := position
 				depth++
 				if buffer[position] != rune('*') {
-					goto l72
+					goto l101
 				}
 				position++
 				depth--
-				add(ruleWildcard, position73)
+				add(ruleWildcard, position102)
 			}
 			return true
-		l72:
-			position, tokenIndex, depth = position72, tokenIndex72, depth72
+		l101:
+			position, tokenIndex, depth = position101, tokenIndex101, depth101
 			return false
 		},
-		/* 14 Criteria <- <('W' 'H' 'E' 'R' 'E' WS MultiCriteria Action8)> */
+		/* 18 Criteria <- <('W' 'H' 'E' 'R' 'E' WS MultiCriteria Action12)> */
 		func() bool {
-			position74, tokenIndex74, depth74 := position, tokenIndex, depth
+			position103, tokenIndex103, depth103 := position, tokenIndex, depth
 			{
-				position75 := position
+				position104 := position
 				depth++
 				if buffer[position] != rune('W') {
-					goto l74
+					goto l103
 				}
 				position++
 				if buffer[position] != rune('H') {
-					goto l74
+					goto l103
 				}
 				position++
 				if buffer[position] != rune('E') {
-					goto l74
+					goto l103
 				}
 				position++
 				if buffer[position] != rune('R') {
-					goto l74
+					goto l103
 				}
 				position++
 				if buffer[position] != rune('E') {
-					goto l74
+					goto l103
 				}
 				position++
 				if !_rules[ruleWS]() {
-					goto l74
+					goto l103
 				}
 				if !_rules[ruleMultiCriteria]() {
-					goto l74
+					goto l103
 				}
 				{
-					add(ruleAction8, position)
+					add(ruleAction12, position)
 				}
 				depth--
-				add(ruleCriteria, position75)
+				add(ruleCriteria, position104)
 			}
 			return true
-		l74:
-			position, tokenIndex, depth = position74, tokenIndex74, depth74
+		l103:
+			position, tokenIndex, depth = position103, tokenIndex103, depth103
 			return false
 		},
-		/* 15 MultiCriteria <- <(CompoundCriteria (WS Boolean WS CompoundCriteria Action9)*)> */
+		/* 19 MultiCriteria <- <(CompoundCriteria (WS Boolean WS CompoundCriteria Action13)*)> */
 		func() bool {
-			position77, tokenIndex77, depth77 := position, tokenIndex, depth
+			position106, tokenIndex106, depth106 := position, tokenIndex, depth
 			{
-				position78 := position
+				position107 := position
 				depth++
 				if !_rules[ruleCompoundCriteria]() {
-					goto l77
+					goto l106
 				}
-			l79:
+			l108:
 				{
-					position80, tokenIndex80, depth80 := position, tokenIndex, depth
+					position109, tokenIndex109, depth109 := position, tokenIndex, depth
 					if !_rules[ruleWS]() {
-						goto l80
+						goto l109
 					}
 					{
-						position81 := position
+						position110 := position
 						depth++
 						{
-							position82 := position
+							position111 := position
 							depth++
 							{
-								position83 := position
+								position112 := position
 								depth++
 								{
-									position84, tokenIndex84, depth84 := position, tokenIndex, depth
+									position113, tokenIndex113, depth113 := position, tokenIndex, depth
 									if buffer[position] != rune('A') {
-										goto l85
+										goto l114
 									}
 									position++
 									if buffer[position] != rune('N') {
-										goto l85
+										goto l114
 									}
 									position++
 									if buffer[position] != rune('D') {
-										goto l85
+										goto l114
 									}
 									position++
-									goto l84
-								l85:
-									position, tokenIndex, depth = position84, tokenIndex84, depth84
+									goto l113
+								l114:
+									position, tokenIndex, depth = position113, tokenIndex113, depth113
 									if buffer[position] != rune('O') {
-										goto l80
+										goto l109
 									}
 									position++
 									if buffer[position] != rune('R') {
-										goto l80
+										goto l109
 									}
 									position++
 								}
-							l84:
+							l113:
 								depth--
-								add(ruleBooleanOp, position83)
+								add(ruleBooleanOp, position112)
 							}
 							depth--
-							add(rulePegText, position82)
+							add(rulePegText, position111)
 						}
 						{
-							add(ruleAction23, position)
+							add(ruleAction34, position)
 						}
 						depth--
-						add(ruleBoolean, position81)
+						add(ruleBoolean, position110)
 					}
 					if !_rules[ruleWS]() {
-						goto l80
+						goto l109
 					}
 					if !_rules[ruleCompoundCriteria]() {
-						goto l80
+						goto l109
 					}
 					{
-						add(ruleAction9, position)
+						add(ruleAction13, position)
 					}
-					goto l79
-				l80:
-					position, tokenIndex, depth = position80, tokenIndex80, depth80
+					goto l108
+				l109:
+					position, tokenIndex, depth = position109, tokenIndex109, depth109
 				}
 				depth--
-				add(ruleMultiCriteria, position78)
+				add(ruleMultiCriteria, position107)
 			}
 			return true
-		l77:
-			position, tokenIndex, depth = position77, tokenIndex77, depth77
+		l106:
+			position, tokenIndex, depth = position106, tokenIndex106, depth106
 			return false
 		},
-		/* 16 CompoundCriteria <- <((&('N') ('N' 'O' 'T' WS CompoundCriteria Action10)) | (&('(') ('(' MultiCriteria ')')) | (&('c' | 'i' | 'p' | 's' | 't' | 'w') SimpleCriteria))> */
+		/* 20 CompoundCriteria <- <((&('N') ('N' 'O' 'T' WS CompoundCriteria Action14)) | (&('(') ('(' MultiCriteria ')')) | (&('c' | 'd' | 'f' | 'i' | 'p' | 'r' | 's' | 't' | 'w') SimpleCriteria))> */
 		func() bool {
-			position88, tokenIndex88, depth88 := position, tokenIndex, depth
+			position117, tokenIndex117, depth117 := position, tokenIndex, depth
 			{
-				position89 := position
+				position118 := position
 				depth++
 				{
 					switch buffer[position] {
 					case 'N':
 						if buffer[position] != rune('N') {
-							goto l88
+							goto l117
 						}
 						position++
 						if buffer[position] != rune('O') {
-							goto l88
+							goto l117
 						}
 						position++
 						if buffer[position] != rune('T') {
-							goto l88
+							goto l117
 						}
 						position++
 						if !_rules[ruleWS]() {
-							goto l88
+							goto l117
 						}
 						if !_rules[ruleCompoundCriteria]() {
-							goto l88
+							goto l117
 						}
 						{
-							add(ruleAction10, position)
+							add(ruleAction14, position)
 						}
 						break
 					case '(':
 						if buffer[position] != rune('(') {
-							goto l88
+							goto l117
 						}
 						position++
 						if !_rules[ruleMultiCriteria]() {
-							goto l88
+							goto l117
 						}
 						if buffer[position] != rune(')') {
-							goto l88
+							goto l117
 						}
 						position++
 						break
 					default:
 						{
-							position92 := position
+							position121 := position
 							depth++
 							{
-								switch buffer[position] {
-								case 'w':
+								position122, tokenIndex122, depth122 := position, tokenIndex, depth
+								{
+									position124 := position
+									depth++
 									{
-										position94 := position
-										depth++
-										{
-											position95 := position
-											depth++
+										switch buffer[position] {
+										case 's':
 											{
-												position96 := position
+												position126 := position
 												depth++
-												if buffer[position] != rune('w') {
-													goto l88
+												{
+													position127 := position
+													depth++
+													if buffer[position] != rune('s') {
+														goto l123
+													}
+													position++
+													if buffer[position] != rune('o') {
+														goto l123
+													}
+													position++
+													if buffer[position] != rune('u') {
+														goto l123
+													}
+													position++
+													if buffer[position] != rune('r') {
+														goto l123
+													}
+													position++
+													if buffer[position] != rune('c') {
+														goto l123
+													}
+													position++
+													if buffer[position] != rune('e') {
+														goto l123
+													}
+													position++
+													depth--
+													add(rulePegText, position127)
 												}
-												position++
-												if buffer[position] != rune('k') {
-													goto l88
+												{
+													add(ruleAction27, position)
 												}
-												position++
-												if buffer[position] != rune('i') {
-													goto l88
+												if !_rules[ruleWSX]() {
+													goto l123
 												}
-												position++
+												{
+													position129, tokenIndex129, depth129 := position, tokenIndex, depth
+													if !_rules[ruleValueCompare]() {
+														goto l130
+													}
+													if !_rules[ruleWSX]() {
+														goto l130
+													}
+													if !_rules[rulePublisherId]() {
+														goto l130
+													}
+													{
+														add(ruleAction28, position)
+													}
+													goto l129
+												l130:
+													position, tokenIndex, depth = position129, tokenIndex129, depth129
+													if !_rules[ruleValueIn]() {
+														goto l123
+													}
+													if !_rules[ruleWSX]() {
+														goto l123
+													}
+													if buffer[position] != rune('(') {
+														goto l123
+													}
+													position++
+													if !_rules[ruleWSX]() {
+														goto l123
+													}
+													if !_rules[rulePublisherIdList]() {
+														goto l123
+													}
+													if !_rules[ruleWSX]() {
+														goto l123
+													}
+													if buffer[position] != rune(')') {
+														goto l123
+													}
+													position++
+												}
+											l129:
 												depth--
-												add(rulePegText, position96)
+												add(ruleSourceCriteria, position126)
 											}
+											break
+										case 'p':
 											{
-												add(ruleAction25, position)
-											}
-											if !_rules[ruleWSX]() {
-												goto l88
-											}
-											if buffer[position] != rune('=') {
-												goto l88
-											}
-											position++
-											if !_rules[ruleWSX]() {
-												goto l88
+												position132 := position
+												depth++
+												{
+													position133 := position
+													depth++
+													if buffer[position] != rune('p') {
+														goto l123
+													}
+													position++
+													if buffer[position] != rune('u') {
+														goto l123
+													}
+													position++
+													if buffer[position] != rune('b') {
+														goto l123
+													}
+													position++
+													if buffer[position] != rune('l') {
+														goto l123
+													}
+													position++
+													if buffer[position] != rune('i') {
+														goto l123
+													}
+													position++
+													if buffer[position] != rune('s') {
+														goto l123
+													}
+													position++
+													if buffer[position] != rune('h') {
+														goto l123
+													}
+													position++
+													if buffer[position] != rune('e') {
+														goto l123
+													}
+													position++
+													if buffer[position] != rune('r') {
+														goto l123
+													}
+													position++
+													depth--
+													add(rulePegText, position133)
+												}
+												{
+													add(ruleAction25, position)
+												}
+												if !_rules[ruleWSX]() {
+													goto l123
+												}
+												{
+													position135, tokenIndex135, depth135 := position, tokenIndex, depth
+													if !_rules[ruleValueCompare]() {
+														goto l136
+													}
+													if !_rules[ruleWSX]() {
+														goto l136
+													}
+													if !_rules[rulePublisherId]() {
+														goto l136
+													}
+													{
+														add(ruleAction26, position)
+													}
+													goto l135
+												l136:
+													position, tokenIndex, depth = position135, tokenIndex135, depth135
+													if !_rules[ruleValueIn]() {
+														goto l123
+													}
+													if !_rules[ruleWSX]() {
+														goto l123
+													}
+													if buffer[position] != rune('(') {
+														goto l123
+													}
+													position++
+													if !_rules[ruleWSX]() {
+														goto l123
+													}
+													if !_rules[rulePublisherIdList]() {
+														goto l123
+													}
+													if !_rules[ruleWSX]() {
+														goto l123
+													}
+													if buffer[position] != rune(')') {
+														goto l123
+													}
+													position++
+												}
+											l135:
+												depth--
+												add(rulePublisherCriteria, position132)
 											}
+											break
+										default:
 											{
-												position98 := position
+												position138 := position
 												depth++
 												{
-													position99 := position
+													position139 := position
 													depth++
-													{
-														switch buffer[position] {
-														case '.':
-															if buffer[position] != rune('.') {
-																goto l88
-															}
-															position++
-															break
-														case '/':
-															if buffer[position] != rune('/') {
-																goto l88
-															}
-															position++
-															break
-														case '_':
-															if buffer[position] != rune('_') {
-																goto l88
-															}
-															position++
-															break
-														case ':':
-															if buffer[position] != rune(':') {
-																goto l88
-															}
-															position++
-															break
-														case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
-															if c := buffer[position]; c < rune('0') || c > rune('9') {
-																goto l88
-															}
-															position++
-															break
-														case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
-															if c := buffer[position]; c < rune('A') || c > rune('Z') {
-																goto l88
-															}
-															position++
-															break
-														case '-':
-															if buffer[position] != rune('-') {
-																goto l88
-															}
-															position++
-															break
-														default:
-															if c := buffer[position]; c < rune('a') || c > rune('z') {
-																goto l88
-															}
-															position++
-															break
-														}
+													if buffer[position] != rune('i') {
+														goto l123
 													}
-
-												l100:
-													{
-														position101, tokenIndex101, depth101 := position, tokenIndex, depth
+													position++
+													if buffer[position] != rune('d') {
+														goto l123
+													}
+													position++
+													depth--
+													add(rulePegText, position139)
+												}
+												{
+													add(ruleAction22, position)
+												}
+												if !_rules[ruleWSX]() {
+													goto l123
+												}
+												{
+													switch buffer[position] {
+													case 'L', 'P':
+														if !_rules[ruleValueMatch]() {
+															goto l123
+														}
+														if !_rules[ruleWSX]() {
+															goto l123
+														}
 														{
-															switch buffer[position] {
-															case '.':
-																if buffer[position] != rune('.') {
-																	goto l101
+															position142 := position
+															depth++
+															{
+																position143, tokenIndex143, depth143 := position, tokenIndex, depth
+																if buffer[position] != rune('\'') {
+																	goto l144
 																}
 																position++
-																break
-															case '/':
-																if buffer[position] != rune('/') {
-																	goto l101
+																{
+																	position145 := position
+																	depth++
+																	{
+																		switch buffer[position] {
+																		case '_':
+																			if buffer[position] != rune('_') {
+																				goto l144
+																			}
+																			position++
+																			break
+																		case '%':
+																			if buffer[position] != rune('%') {
+																				goto l144
+																			}
+																			position++
+																			break
+																		case ':':
+																			if buffer[position] != rune(':') {
+																				goto l144
+																			}
+																			position++
+																			break
+																		case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+																			if c := buffer[position]; c < rune('0') || c > rune('9') {
+																				goto l144
+																			}
+																			position++
+																			break
+																		case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+																			if c := buffer[position]; c < rune('A') || c > rune('Z') {
+																				goto l144
+																			}
+																			position++
+																			break
+																		default:
+																			if c := buffer[position]; c < rune('a') || c > rune('z') {
+																				goto l144
+																			}
+																			position++
+																			break
+																		}
+																	}
+
+																l146:
+																	{
+																		position147, tokenIndex147, depth147 := position, tokenIndex, depth
+																		{
+																			switch buffer[position] {
+																			case '_':
+																				if buffer[position] != rune('_') {
+																					goto l147
+																				}
+																				position++
+																				break
+																			case '%':
+																				if buffer[position] != rune('%') {
+																					goto l147
+																				}
+																				position++
+																				break
+																			case ':':
+																				if buffer[position] != rune(':') {
+																					goto l147
+																				}
+																				position++
+																				break
+																			case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+																				if c := buffer[position]; c < rune('0') || c > rune('9') {
+																					goto l147
+																				}
+																				position++
+																				break
+																			case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+																				if c := buffer[position]; c < rune('A') || c > rune('Z') {
+																					goto l147
+																				}
+																				position++
+																				break
+																			default:
+																				if c := buffer[position]; c < rune('a') || c > rune('z') {
+																					goto l147
+																				}
+																				position++
+																				break
+																			}
+																		}
+
+																		goto l146
+																	l147:
+																		position, tokenIndex, depth = position147, tokenIndex147, depth147
+																	}
+																	depth--
+																	add(rulePegText, position145)
 																}
-																position++
-																break
-															case '_':
-																if buffer[position] != rune('_') {
-																	goto l101
+																if buffer[position] != rune('\'') {
+																	goto l144
 																}
 																position++
-																break
-															case ':':
-																if buffer[position] != rune(':') {
-																	goto l101
+																goto l143
+															l144:
+																position, tokenIndex, depth = position143, tokenIndex143, depth143
+																{
+																	position150 := position
+																	depth++
+																	{
+																		switch buffer[position] {
+																		case '_':
+																			if buffer[position] != rune('_') {
+																				goto l123
+																			}
+																			position++
+																			break
+																		case '%':
+																			if buffer[position] != rune('%') {
+																				goto l123
+																			}
+																			position++
+																			break
+																		case ':':
+																			if buffer[position] != rune(':') {
+																				goto l123
+																			}
+																			position++
+																			break
+																		case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+																			if c := buffer[position]; c < rune('0') || c > rune('9') {
+																				goto l123
+																			}
+																			position++
+																			break
+																		case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+																			if c := buffer[position]; c < rune('A') || c > rune('Z') {
+																				goto l123
+																			}
+																			position++
+																			break
+																		default:
+																			if c := buffer[position]; c < rune('a') || c > rune('z') {
+																				goto l123
+																			}
+																			position++
+																			break
+																		}
+																	}
+
+																l151:
+																	{
+																		position152, tokenIndex152, depth152 := position, tokenIndex, depth
+																		{
+																			switch buffer[position] {
+																			case '_':
+																				if buffer[position] != rune('_') {
+																					goto l152
+																				}
+																				position++
+																				break
+																			case '%':
+																				if buffer[position] != rune('%') {
+																					goto l152
+																				}
+																				position++
+																				break
+																			case ':':
+																				if buffer[position] != rune(':') {
+																					goto l152
+																				}
+																				position++
+																				break
+																			case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+																				if c := buffer[position]; c < rune('0') || c > rune('9') {
+																					goto l152
+																				}
+																				position++
+																				break
+																			case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+																				if c := buffer[position]; c < rune('A') || c > rune('Z') {
+																					goto l152
+																				}
+																				position++
+																				break
+																			default:
+																				if c := buffer[position]; c < rune('a') || c > rune('z') {
+																					goto l152
+																				}
+																				position++
+																				break
+																			}
+																		}
+
+																		goto l151
+																	l152:
+																		position, tokenIndex, depth = position152, tokenIndex152, depth152
+																	}
+																	depth--
+																	add(rulePegText, position150)
 																}
-																position++
-																break
-															case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
-																if c := buffer[position]; c < rune('0') || c > rune('9') {
-																	goto l101
+															}
+														l143:
+															depth--
+															add(ruleStatementIdPattern, position142)
+														}
+														{
+															add(ruleAction24, position)
+														}
+														break
+													case 'I':
+														if !_rules[ruleValueIn]() {
+															goto l123
+														}
+														if !_rules[ruleWSX]() {
+															goto l123
+														}
+														if buffer[position] != rune('(') {
+															goto l123
+														}
+														position++
+														if !_rules[ruleWSX]() {
+															goto l123
+														}
+														{
+															position156 := position
+															depth++
+															if !_rules[ruleStatementId]() {
+																goto l123
+															}
+															{
+																add(ruleAction59, position)
+															}
+														l158:
+															{
+																position159, tokenIndex159, depth159 := position, tokenIndex, depth
+																if !_rules[ruleWSX]() {
+																	goto l159
 																}
-																position++
-																break
-															case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
-																if c := buffer[position]; c < rune('A') || c > rune('Z') {
-																	goto l101
+																if buffer[position] != rune(',') {
+																	goto l159
 																}
 																position++
-																break
-															case '-':
-																if buffer[position] != rune('-') {
-																	goto l101
+																if !_rules[ruleWSX]() {
+																	goto l159
 																}
-																position++
-																break
-															default:
-																if c := buffer[position]; c < rune('a') || c > rune('z') {
-																	goto l101
+																if !_rules[ruleStatementId]() {
+																	goto l159
 																}
-																position++
-																break
+																{
+																	add(ruleAction60, position)
+																}
+																goto l158
+															l159:
+																position, tokenIndex, depth = position159, tokenIndex159, depth159
 															}
+															depth--
+															add(ruleStatementIdList, position156)
 														}
-
-														goto l100
-													l101:
-														position, tokenIndex, depth = position101, tokenIndex101, depth101
+														if !_rules[ruleWSX]() {
+															goto l123
+														}
+														if buffer[position] != rune(')') {
+															goto l123
+														}
+														position++
+														break
+													default:
+														if !_rules[ruleValueCompare]() {
+															goto l123
+														}
+														if !_rules[ruleWSX]() {
+															goto l123
+														}
+														if !_rules[ruleStatementId]() {
+															goto l123
+														}
+														{
+															add(ruleAction23, position)
+														}
+														break
 													}
-													depth--
-													add(rulePegText, position99)
 												}
+
 												depth--
-												add(ruleWKI, position98)
-											}
-											{
-												add(ruleAction26, position)
+												add(ruleIdCriteria, position138)
 											}
-											depth--
-											add(ruleWKICriteria, position95)
+											break
 										}
-										depth--
-										add(ruleIndexCriteria, position94)
-									}
-									{
-										add(ruleAction13, position)
 									}
-									break
-								case 'c', 't':
+
+									depth--
+									add(ruleValueCriteria, position124)
+								}
+								{
+									add(ruleAction15, position)
+								}
+								goto l122
+							l123:
+								position, tokenIndex, depth = position122, tokenIndex122, depth122
+								{
+									position164 := position
+									depth++
 									{
-										position106 := position
+										position165 := position
 										depth++
 										{
-											position107 := position
+											position166 := position
 											depth++
 											{
-												position108 := position
+												position167 := position
 												depth++
 												{
-													position109 := position
-													depth++
-													{
-														position110, tokenIndex110, depth110 := position, tokenIndex, depth
-														if buffer[position] != rune('t') {
-															goto l111
-														}
-														position++
-														if buffer[position] != rune('i') {
-															goto l111
-														}
-														position++
-														if buffer[position] != rune('m') {
-															goto l111
-														}
-														position++
-														if buffer[position] != rune('e') {
-															goto l111
-														}
-														position++
-														if buffer[position] != rune('s') {
-															goto l111
-														}
-														position++
-														if buffer[position] != rune('t') {
-															goto l111
-														}
-														position++
-														if buffer[position] != rune('a') {
-															goto l111
-														}
-														position++
-														if buffer[position] != rune('m') {
-															goto l111
-														}
-														position++
-														if buffer[position] != rune('p') {
-															goto l111
-														}
-														position++
-														goto l110
-													l111:
-														position, tokenIndex, depth = position110, tokenIndex110, depth110
-														if buffer[position] != rune('c') {
-															goto l88
-														}
-														position++
-														if buffer[position] != rune('o') {
-															goto l88
-														}
-														position++
-														if buffer[position] != rune('u') {
-															goto l88
-														}
-														position++
-														if buffer[position] != rune('n') {
-															goto l88
-														}
-														position++
-														if buffer[position] != rune('t') {
-															goto l88
-														}
-														position++
-														if buffer[position] != rune('e') {
-															goto l88
-														}
-														position++
-														if buffer[position] != rune('r') {
-															goto l88
-														}
-														position++
+													position168, tokenIndex168, depth168 := position, tokenIndex, depth
+													if buffer[position] != rune('t') {
+														goto l169
 													}
-												l110:
-													depth--
-													add(ruleRangeSelectorOp, position109)
+													position++
+													if buffer[position] != rune('i') {
+														goto l169
+													}
+													position++
+													if buffer[position] != rune('m') {
+														goto l169
+													}
+													position++
+													if buffer[position] != rune('e') {
+														goto l169
+													}
+													position++
+													if buffer[position] != rune('s') {
+														goto l169
+													}
+													position++
+													if buffer[position] != rune('t') {
+														goto l169
+													}
+													position++
+													if buffer[position] != rune('a') {
+														goto l169
+													}
+													position++
+													if buffer[position] != rune('m') {
+														goto l169
+													}
+													position++
+													if buffer[position] != rune('p') {
+														goto l169
+													}
+													position++
+													goto l168
+												l169:
+													position, tokenIndex, depth = position168, tokenIndex168, depth168
+													if buffer[position] != rune('c') {
+														goto l163
+													}
+													position++
+													if buffer[position] != rune('o') {
+														goto l163
+													}
+													position++
+													if buffer[position] != rune('u') {
+														goto l163
+													}
+													position++
+													if buffer[position] != rune('n') {
+														goto l163
+													}
+													position++
+													if buffer[position] != rune('t') {
+														goto l163
+													}
+													position++
+													if buffer[position] != rune('e') {
+														goto l163
+													}
+													position++
+													if buffer[position] != rune('r') {
+														goto l163
+													}
+													position++
 												}
+											l168:
 												depth--
-												add(rulePegText, position108)
-											}
-											{
-												add(ruleAction22, position)
+												add(ruleRangeSelectorOp, position167)
 											}
 											depth--
-											add(ruleRangeSelector, position107)
+											add(rulePegText, position166)
 										}
-										if !_rules[ruleWSX]() {
-											goto l88
+										{
+											add(ruleAction33, position)
 										}
+										depth--
+										add(ruleRangeSelector, position165)
+									}
+									if !_rules[ruleWSX]() {
+										goto l163
+									}
+									{
+										position171 := position
+										depth++
 										{
-											position113 := position
+											position172 := position
 											depth++
 											{
-												position114 := position
+												position173 := position
 												depth++
 												{
-													position115 := position
-													depth++
+													position174, tokenIndex174, depth174 := position, tokenIndex, depth
+													if buffer[position] != rune('<') {
+														goto l175
+													}
+													position++
+													if buffer[position] != rune('=') {
+														goto l175
+													}
+													position++
+													goto l174
+												l175:
+													position, tokenIndex, depth = position174, tokenIndex174, depth174
+													if buffer[position] != rune('>') {
+														goto l176
+													}
+													position++
+													if buffer[position] != rune('=') {
+														goto l176
+													}
+													position++
+													goto l174
+												l176:
+													position, tokenIndex, depth = position174, tokenIndex174, depth174
 													{
-														position116, tokenIndex116, depth116 := position, tokenIndex, depth
-														if buffer[position] != rune('<') {
-															goto l117
-														}
-														position++
-														if buffer[position] != rune('=') {
-															goto l117
-														}
-														position++
-														goto l116
-													l117:
-														position, tokenIndex, depth = position116, tokenIndex116, depth116
-														if buffer[position] != rune('>') {
-															goto l118
-														}
-														position++
-														if buffer[position] != rune('=') {
-															goto l118
-														}
-														position++
-														goto l116
-													l118:
-														position, tokenIndex, depth = position116, tokenIndex116, depth116
-														{
-															switch buffer[position] {
-															case '>':
-																if buffer[position] != rune('>') {
-																	goto l88
-																}
-																position++
-																break
-															case '!':
-																if buffer[position] != rune('!') {
-																	goto l88
-																}
-																position++
-																if buffer[position] != rune('=') {
-																	goto l88
-																}
-																position++
-																break
-															case '=':
-																if buffer[position] != rune('=') {
-																	goto l88
-																}
-																position++
-																break
-															default:
-																if buffer[position] != rune('<') {
-																	goto l88
-																}
-																position++
-																break
+														switch buffer[position] {
+														case '>':
+															if buffer[position] != rune('>') {
+																goto l163
+															}
+															position++
+															break
+														case '!':
+															if buffer[position] != rune('!') {
+																goto l163
 															}
+															position++
+															if buffer[position] != rune('=') {
+																goto l163
+															}
+															position++
+															break
+														case '=':
+															if buffer[position] != rune('=') {
+																goto l163
+															}
+															position++
+															break
+														default:
+															if buffer[position] != rune('<') {
+																goto l163
+															}
+															position++
+															break
 														}
-
 													}
-												l116:
-													depth--
-													add(ruleComparisonOp, position115)
+
 												}
+											l174:
 												depth--
-												add(rulePegText, position114)
-											}
-											{
-												add(ruleAction24, position)
+												add(ruleComparisonOp, position173)
 											}
 											depth--
-											add(ruleComparison, position113)
-										}
-										if !_rules[ruleWSX]() {
-											goto l88
-										}
-										if !_rules[ruleUInt]() {
-											goto l88
+											add(rulePegText, position172)
 										}
 										{
-											add(ruleAction21, position)
+											add(ruleAction35, position)
 										}
 										depth--
-										add(ruleRangeCriteria, position106)
+										add(ruleComparison, position171)
+									}
+									if !_rules[ruleWSX]() {
+										goto l163
+									}
+									if !_rules[ruleUInt]() {
+										goto l163
 									}
 									{
-										add(ruleAction12, position)
+										add(ruleAction32, position)
 									}
-									break
-								default:
+									depth--
+									add(ruleRangeCriteria, position164)
+								}
+								{
+									add(ruleAction16, position)
+								}
+								goto l122
+							l163:
+								position, tokenIndex, depth = position122, tokenIndex122, depth122
+								{
+									position182 := position
+									depth++
 									{
-										position123 := position
-										depth++
-										{
-											switch buffer[position] {
-											case 's':
+										switch buffer[position] {
+										case 'd':
+											{
+												position184 := position
+												depth++
 												{
-													position125 := position
+													position185 := position
 													depth++
-													{
-														position126 := position
-														depth++
-														if buffer[position] != rune('s') {
-															goto l88
-														}
-														position++
-														if buffer[position] != rune('o') {
-															goto l88
-														}
-														position++
-														if buffer[position] != rune('u') {
-															goto l88
-														}
-														position++
-														if buffer[position] != rune('r') {
-															goto l88
-														}
-														position++
-														if buffer[position] != rune('c') {
-															goto l88
-														}
-														position++
-														if buffer[position] != rune('e') {
-															goto l88
-														}
-														position++
-														depth--
-														add(rulePegText, position126)
+													if buffer[position] != rune('d') {
+														goto l181
 													}
-													{
-														add(ruleAction18, position)
+													position++
+													if buffer[position] != rune('e') {
+														goto l181
 													}
-													if !_rules[ruleWSX]() {
-														goto l88
+													position++
+													if buffer[position] != rune('p') {
+														goto l181
 													}
-													if !_rules[ruleValueCompare]() {
-														goto l88
+													position++
+													depth--
+													add(rulePegText, position185)
+												}
+												{
+													add(ruleAction41, position)
+												}
+												if !_rules[ruleWSX]() {
+													goto l181
+												}
+												{
+													position187, tokenIndex187, depth187 := position, tokenIndex, depth
+													if !_rules[ruleIndexCompare]() {
+														goto l188
 													}
 													if !_rules[ruleWSX]() {
-														goto l88
+														goto l188
 													}
-													if !_rules[rulePublisherId]() {
-														goto l88
+													if !_rules[ruleObjectId]() {
+														goto l188
 													}
 													{
-														add(ruleAction19, position)
+														add(ruleAction42, position)
+													}
+													goto l187
+												l188:
+													position, tokenIndex, depth = position187, tokenIndex187, depth187
+													if !_rules[ruleValueIn]() {
+														goto l181
+													}
+													if !_rules[ruleWSX]() {
+														goto l181
+													}
+													if buffer[position] != rune('(') {
+														goto l181
+													}
+													position++
+													if !_rules[ruleWSX]() {
+														goto l181
 													}
-													depth--
-													add(ruleSourceCriteria, position125)
-												}
-												break
-											case 'p':
-												{
-													position129 := position
-													depth++
 													{
-														position130 := position
+														position190 := position
 														depth++
-														if buffer[position] != rune('p') {
-															goto l88
-														}
-														position++
-														if buffer[position] != rune('u') {
-															goto l88
-														}
-														position++
-														if buffer[position] != rune('b') {
-															goto l88
-														}
-														position++
-														if buffer[position] != rune('l') {
-															goto l88
-														}
-														position++
-														if buffer[position] != rune('i') {
-															goto l88
-														}
-														position++
-														if buffer[position] != rune('s') {
-															goto l88
-														}
-														position++
-														if buffer[position] != rune('h') {
-															goto l88
+														if !_rules[ruleObjectId]() {
+															goto l181
 														}
-														position++
-														if buffer[position] != rune('e') {
-															goto l88
+														{
+															add(ruleAction67, position)
 														}
-														position++
-														if buffer[position] != rune('r') {
-															goto l88
+													l192:
+														{
+															position193, tokenIndex193, depth193 := position, tokenIndex, depth
+															if !_rules[ruleWSX]() {
+																goto l193
+															}
+															if buffer[position] != rune(',') {
+																goto l193
+															}
+															position++
+															if !_rules[ruleWSX]() {
+																goto l193
+															}
+															if !_rules[ruleObjectId]() {
+																goto l193
+															}
+															{
+																add(ruleAction68, position)
+															}
+															goto l192
+														l193:
+															position, tokenIndex, depth = position193, tokenIndex193, depth193
 														}
-														position++
 														depth--
-														add(rulePegText, position130)
-													}
-													{
-														add(ruleAction16, position)
+														add(ruleObjectIdList, position190)
 													}
 													if !_rules[ruleWSX]() {
-														goto l88
+														goto l181
 													}
-													if !_rules[ruleValueCompare]() {
-														goto l88
+													if buffer[position] != rune(')') {
+														goto l181
 													}
-													if !_rules[ruleWSX]() {
-														goto l88
+													position++
+												}
+											l187:
+												depth--
+												add(ruleDepCriteria, position184)
+											}
+											break
+										case 't':
+											{
+												position195 := position
+												depth++
+												{
+													position196 := position
+													depth++
+													if buffer[position] != rune('t') {
+														goto l181
 													}
-													if !_rules[rulePublisherId]() {
-														goto l88
+													position++
+													if buffer[position] != rune('a') {
+														goto l181
 													}
-													{
-														add(ruleAction17, position)
+													position++
+													if buffer[position] != rune('g') {
+														goto l181
 													}
+													position++
 													depth--
-													add(rulePublisherCriteria, position129)
+													add(rulePegText, position196)
 												}
-												break
-											default:
 												{
-													position133 := position
-													depth++
-													{
-														position134 := position
-														depth++
-														if buffer[position] != rune('i') {
-															goto l88
-														}
-														position++
-														if buffer[position] != rune('d') {
-															goto l88
-														}
-														position++
-														depth--
-														add(rulePegText, position134)
+													add(ruleAction39, position)
+												}
+												if !_rules[ruleWSX]() {
+													goto l181
+												}
+												{
+													position198, tokenIndex198, depth198 := position, tokenIndex, depth
+													if !_rules[ruleIndexCompare]() {
+														goto l199
+													}
+													if !_rules[ruleWSX]() {
+														goto l199
+													}
+													if !_rules[ruleTag]() {
+														goto l199
 													}
 													{
-														add(ruleAction14, position)
+														add(ruleAction40, position)
+													}
+													goto l198
+												l199:
+													position, tokenIndex, depth = position198, tokenIndex198, depth198
+													if !_rules[ruleValueIn]() {
+														goto l181
 													}
 													if !_rules[ruleWSX]() {
-														goto l88
+														goto l181
 													}
-													if !_rules[ruleValueCompare]() {
-														goto l88
+													if buffer[position] != rune('(') {
+														goto l181
 													}
+													position++
 													if !_rules[ruleWSX]() {
-														goto l88
+														goto l181
 													}
 													{
-														position136 := position
+														position201 := position
 														depth++
+														if !_rules[ruleTag]() {
+															goto l181
+														}
+														{
+															add(ruleAction65, position)
+														}
+													l203:
+														{
+															position204, tokenIndex204, depth204 := position, tokenIndex, depth
+															if !_rules[ruleWSX]() {
+																goto l204
+															}
+															if buffer[position] != rune(',') {
+																goto l204
+															}
+															position++
+															if !_rules[ruleWSX]() {
+																goto l204
+															}
+															if !_rules[ruleTag]() {
+																goto l204
+															}
+															{
+																add(ruleAction66, position)
+															}
+															goto l203
+														l204:
+															position, tokenIndex, depth = position204, tokenIndex204, depth204
+														}
+														depth--
+														add(ruleTagList, position201)
+													}
+													if !_rules[ruleWSX]() {
+														goto l181
+													}
+													if buffer[position] != rune(')') {
+														goto l181
+													}
+													position++
+												}
+											l198:
+												depth--
+												add(ruleTagCriteria, position195)
+											}
+											break
+										default:
+											{
+												position206 := position
+												depth++
+												{
+													position207 := position
+													depth++
+													if buffer[position] != rune('w') {
+														goto l181
+													}
+													position++
+													if buffer[position] != rune('k') {
+														goto l181
+													}
+													position++
+													if buffer[position] != rune('i') {
+														goto l181
+													}
+													position++
+													depth--
+													add(rulePegText, position207)
+												}
+												{
+													add(ruleAction36, position)
+												}
+												if !_rules[ruleWSX]() {
+													goto l181
+												}
+												{
+													switch buffer[position] {
+													case 'I':
+														if !_rules[ruleValueIn]() {
+															goto l181
+														}
+														if !_rules[ruleWSX]() {
+															goto l181
+														}
+														if buffer[position] != rune('(') {
+															goto l181
+														}
+														position++
+														if !_rules[ruleWSX]() {
+															goto l181
+														}
 														{
-															position137 := position
+															position210 := position
 															depth++
+															if !_rules[ruleWKI]() {
+																goto l181
+															}
 															{
-																switch buffer[position] {
-																case ':':
-																	if buffer[position] != rune(':') {
-																		goto l88
-																	}
-																	position++
-																	break
-																case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
-																	if c := buffer[position]; c < rune('0') || c > rune('9') {
-																		goto l88
-																	}
-																	position++
-																	break
-																case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
-																	if c := buffer[position]; c < rune('A') || c > rune('Z') {
-																		goto l88
-																	}
-																	position++
-																	break
-																default:
-																	if c := buffer[position]; c < rune('a') || c > rune('z') {
-																		goto l88
-																	}
-																	position++
-																	break
+																add(ruleAction63, position)
+															}
+														l212:
+															{
+																position213, tokenIndex213, depth213 := position, tokenIndex, depth
+																if !_rules[ruleWSX]() {
+																	goto l213
+																}
+																if buffer[position] != rune(',') {
+																	goto l213
 																}
+																position++
+																if !_rules[ruleWSX]() {
+																	goto l213
+																}
+																if !_rules[ruleWKI]() {
+																	goto l213
+																}
+																{
+																	add(ruleAction64, position)
+																}
+																goto l212
+															l213:
+																position, tokenIndex, depth = position213, tokenIndex213, depth213
 															}
-
-														l138:
+															depth--
+															add(ruleWKIList, position210)
+														}
+														if !_rules[ruleWSX]() {
+															goto l181
+														}
+														if buffer[position] != rune(')') {
+															goto l181
+														}
+														position++
+														break
+													case '=':
+														if !_rules[ruleIndexCompare]() {
+															goto l181
+														}
+														if !_rules[ruleWSX]() {
+															goto l181
+														}
+														if !_rules[ruleWKI]() {
+															goto l181
+														}
+														{
+															add(ruleAction37, position)
+														}
+														break
+													default:
+														if !_rules[ruleValueMatch]() {
+															goto l181
+														}
+														if !_rules[ruleWSX]() {
+															goto l181
+														}
+														{
+															position216 := position
+															depth++
 															{
-																position139, tokenIndex139, depth139 := position, tokenIndex, depth
+																position217, tokenIndex217, depth217 := position, tokenIndex, depth
+																if buffer[position] != rune('\'') {
+																	goto l218
+																}
+																position++
 																{
-																	switch buffer[position] {
-																	case ':':
-																		if buffer[position] != rune(':') {
-																			goto l139
+																	position219 := position
+																	depth++
+																	{
+																		switch buffer[position] {
+																		case '%':
+																			if buffer[position] != rune('%') {
+																				goto l218
+																			}
+																			position++
+																			break
+																		case '.':
+																			if buffer[position] != rune('.') {
+																				goto l218
+																			}
+																			position++
+																			break
+																		case '/':
+																			if buffer[position] != rune('/') {
+																				goto l218
+																			}
+																			position++
+																			break
+																		case '_':
+																			if buffer[position] != rune('_') {
+																				goto l218
+																			}
+																			position++
+																			break
+																		case ':':
+																			if buffer[position] != rune(':') {
+																				goto l218
+																			}
+																			position++
+																			break
+																		case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+																			if c := buffer[position]; c < rune('0') || c > rune('9') {
+																				goto l218
+																			}
+																			position++
+																			break
+																		case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+																			if c := buffer[position]; c < rune('A') || c > rune('Z') {
+																				goto l218
+																			}
+																			position++
+																			break
+																		case '-':
+																			if buffer[position] != rune('-') {
+																				goto l218
+																			}
+																			position++
+																			break
+																		default:
+																			if c := buffer[position]; c < rune('a') || c > rune('z') {
+																				goto l218
+																			}
+																			position++
+																			break
 																		}
-																		position++
-																		break
-																	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
-																		if c := buffer[position]; c < rune('0') || c > rune('9') {
-																			goto l139
+																	}
+
+																l220:
+																	{
+																		position221, tokenIndex221, depth221 := position, tokenIndex, depth
+																		{
+																			switch buffer[position] {
+																			case '%':
+																				if buffer[position] != rune('%') {
+																					goto l221
+																				}
+																				position++
+																				break
+																			case '.':
+																				if buffer[position] != rune('.') {
+																					goto l221
+																				}
+																				position++
+																				break
+																			case '/':
+																				if buffer[position] != rune('/') {
+																					goto l221
+																				}
+																				position++
+																				break
+																			case '_':
+																				if buffer[position] != rune('_') {
+																					goto l221
+																				}
+																				position++
+																				break
+																			case ':':
+																				if buffer[position] != rune(':') {
+																					goto l221
+																				}
+																				position++
+																				break
+																			case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+																				if c := buffer[position]; c < rune('0') || c > rune('9') {
+																					goto l221
+																				}
+																				position++
+																				break
+																			case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+																				if c := buffer[position]; c < rune('A') || c > rune('Z') {
+																					goto l221
+																				}
+																				position++
+																				break
+																			case '-':
+																				if buffer[position] != rune('-') {
+																					goto l221
+																				}
+																				position++
+																				break
+																			default:
+																				if c := buffer[position]; c < rune('a') || c > rune('z') {
+																					goto l221
+																				}
+																				position++
+																				break
+																			}
 																		}
-																		position++
-																		break
-																	case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
-																		if c := buffer[position]; c < rune('A') || c > rune('Z') {
-																			goto l139
+
+																		goto l220
+																	l221:
+																		position, tokenIndex, depth = position221, tokenIndex221, depth221
+																	}
+																	depth--
+																	add(rulePegText, position219)
+																}
+																if buffer[position] != rune('\'') {
+																	goto l218
+																}
+																position++
+																goto l217
+															l218:
+																position, tokenIndex, depth = position217, tokenIndex217, depth217
+																{
+																	position224 := position
+																	depth++
+																	{
+																		switch buffer[position] {
+																		case '%':
+																			if buffer[position] != rune('%') {
+																				goto l181
+																			}
+																			position++
+																			break
+																		case '.':
+																			if buffer[position] != rune('.') {
+																				goto l181
+																			}
+																			position++
+																			break
+																		case '/':
+																			if buffer[position] != rune('/') {
+																				goto l181
+																			}
+																			position++
+																			break
+																		case '_':
+																			if buffer[position] != rune('_') {
+																				goto l181
+																			}
+																			position++
+																			break
+																		case ':':
+																			if buffer[position] != rune(':') {
+																				goto l181
+																			}
+																			position++
+																			break
+																		case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+																			if c := buffer[position]; c < rune('0') || c > rune('9') {
+																				goto l181
+																			}
+																			position++
+																			break
+																		case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+																			if c := buffer[position]; c < rune('A') || c > rune('Z') {
+																				goto l181
+																			}
+																			position++
+																			break
+																		case '-':
+																			if buffer[position] != rune('-') {
+																				goto l181
+																			}
+																			position++
+																			break
+																		default:
+																			if c := buffer[position]; c < rune('a') || c > rune('z') {
+																				goto l181
+																			}
+																			position++
+																			break
 																		}
-																		position++
-																		break
-																	default:
-																		if c := buffer[position]; c < rune('a') || c > rune('z') {
-																			goto l139
+																	}
+
+																l225:
+																	{
+																		position226, tokenIndex226, depth226 := position, tokenIndex, depth
+																		{
+																			switch buffer[position] {
+																			case '%':
+																				if buffer[position] != rune('%') {
+																					goto l226
+																				}
+																				position++
+																				break
+																			case '.':
+																				if buffer[position] != rune('.') {
+																					goto l226
+																				}
+																				position++
+																				break
+																			case '/':
+																				if buffer[position] != rune('/') {
+																					goto l226
+																				}
+																				position++
+																				break
+																			case '_':
+																				if buffer[position] != rune('_') {
+																					goto l226
+																				}
+																				position++
+																				break
+																			case ':':
+																				if buffer[position] != rune(':') {
+																					goto l226
+																				}
+																				position++
+																				break
+																			case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+																				if c := buffer[position]; c < rune('0') || c > rune('9') {
+																					goto l226
+																				}
+																				position++
+																				break
+																			case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+																				if c := buffer[position]; c < rune('A') || c > rune('Z') {
+																					goto l226
+																				}
+																				position++
+																				break
+																			case '-':
+																				if buffer[position] != rune('-') {
+																					goto l226
+																				}
+																				position++
+																				break
+																			default:
+																				if c := buffer[position]; c < rune('a') || c > rune('z') {
+																					goto l226
+																				}
+																				position++
+																				break
+																			}
 																		}
-																		position++
-																		break
+
+																		goto l225
+																	l226:
+																		position, tokenIndex, depth = position226, tokenIndex226, depth226
 																	}
+																	depth--
+																	add(rulePegText, position224)
 																}
-
-																goto l138
-															l139:
-																position, tokenIndex, depth = position139, tokenIndex139, depth139
 															}
+														l217:
 															depth--
-															add(rulePegText, position137)
+															add(ruleWKIPattern, position216)
 														}
-														depth--
-														add(ruleStatementId, position136)
+														{
+															add(ruleAction38, position)
+														}
+														break
+													}
+												}
+
+												depth--
+												add(ruleWKICriteria, position206)
+											}
+											break
+										}
+									}
+
+									depth--
+									add(ruleIndexCriteria, position182)
+								}
+								{
+									add(ruleAction17, position)
+								}
+								goto l122
+							l181:
+								position, tokenIndex, depth = position122, tokenIndex122, depth122
+								{
+									switch buffer[position] {
+									case 't':
+										{
+											position232 := position
+											depth++
+											if buffer[position] != rune('t') {
+												goto l117
+											}
+											position++
+											if buffer[position] != rune('e') {
+												goto l117
+											}
+											position++
+											if buffer[position] != rune('x') {
+												goto l117
+											}
+											position++
+											if buffer[position] != rune('t') {
+												goto l117
+											}
+											position++
+											if !_rules[ruleWS]() {
+												goto l117
+											}
+											if buffer[position] != rune('M') {
+												goto l117
+											}
+											position++
+											if buffer[position] != rune('A') {
+												goto l117
+											}
+											position++
+											if buffer[position] != rune('T') {
+												goto l117
+											}
+											position++
+											if buffer[position] != rune('C') {
+												goto l117
+											}
+											position++
+											if buffer[position] != rune('H') {
+												goto l117
+											}
+											position++
+											if !_rules[ruleWS]() {
+												goto l117
+											}
+											if !_rules[ruleDataValue]() {
+												goto l117
+											}
+											depth--
+											add(ruleTextCriteria, position232)
+										}
+										{
+											add(ruleAction20, position)
+										}
+										break
+									case 'f':
+										{
+											position234 := position
+											depth++
+											if buffer[position] != rune('f') {
+												goto l117
+											}
+											position++
+											if buffer[position] != rune('i') {
+												goto l117
+											}
+											position++
+											if buffer[position] != rune('e') {
+												goto l117
+											}
+											position++
+											if buffer[position] != rune('l') {
+												goto l117
+											}
+											position++
+											if buffer[position] != rune('d') {
+												goto l117
+											}
+											position++
+											if buffer[position] != rune('.') {
+												goto l117
+											}
+											position++
+											{
+												position235 := position
+												depth++
+												if !_rules[ruleDataPath]() {
+													goto l117
+												}
+												depth--
+												add(rulePegText, position235)
+											}
+											{
+												add(ruleAction45, position)
+											}
+											if !_rules[ruleWSX]() {
+												goto l117
+											}
+											{
+												position237, tokenIndex237, depth237 := position, tokenIndex, depth
+												if !_rules[ruleIndexCompare]() {
+													goto l238
+												}
+												if !_rules[ruleWSX]() {
+													goto l238
+												}
+												if !_rules[ruleDataValue]() {
+													goto l238
+												}
+												goto l237
+											l238:
+												position, tokenIndex, depth = position237, tokenIndex237, depth237
+												if !_rules[ruleValueIn]() {
+													goto l117
+												}
+												if !_rules[ruleWSX]() {
+													goto l117
+												}
+												if buffer[position] != rune('(') {
+													goto l117
+												}
+												position++
+												if !_rules[ruleWSX]() {
+													goto l117
+												}
+												{
+													position239 := position
+													depth++
+													if !_rules[ruleDataValueItem]() {
+														goto l117
 													}
+												l240:
 													{
-														add(ruleAction15, position)
+														position241, tokenIndex241, depth241 := position, tokenIndex, depth
+														if !_rules[ruleWSX]() {
+															goto l241
+														}
+														if buffer[position] != rune(',') {
+															goto l241
+														}
+														position++
+														if !_rules[ruleWSX]() {
+															goto l241
+														}
+														if !_rules[ruleDataValueItem]() {
+															goto l241
+														}
+														goto l240
+													l241:
+														position, tokenIndex, depth = position241, tokenIndex241, depth241
 													}
 													depth--
-													add(ruleIdCriteria, position133)
+													add(ruleDataValueList, position239)
+												}
+												if !_rules[ruleWSX]() {
+													goto l117
 												}
-												break
+												if buffer[position] != rune(')') {
+													goto l117
+												}
+												position++
 											}
+										l237:
+											depth--
+											add(ruleFieldCriteria, position234)
+										}
+										{
+											add(ruleAction19, position)
 										}
+										break
+									case 'd':
+										{
+											position243 := position
+											depth++
+											if buffer[position] != rune('d') {
+												goto l117
+											}
+											position++
+											if buffer[position] != rune('a') {
+												goto l117
+											}
+											position++
+											if buffer[position] != rune('t') {
+												goto l117
+											}
+											position++
+											if buffer[position] != rune('a') {
+												goto l117
+											}
+											position++
+											if buffer[position] != rune('.') {
+												goto l117
+											}
+											position++
+											{
+												position244 := position
+												depth++
+												if !_rules[ruleDataPath]() {
+													goto l117
+												}
+												depth--
+												add(rulePegText, position244)
+											}
+											{
+												add(ruleAction44, position)
+											}
+											if !_rules[ruleWSX]() {
+												goto l117
+											}
+											{
+												position246 := position
+												depth++
+												{
+													position247 := position
+													depth++
+													{
+														position248 := position
+														depth++
+														{
+															switch buffer[position] {
+															case 'C':
+																if buffer[position] != rune('C') {
+																	goto l117
+																}
+																position++
+																if buffer[position] != rune('O') {
+																	goto l117
+																}
+																position++
+																if buffer[position] != rune('N') {
+																	goto l117
+																}
+																position++
+																if buffer[position] != rune('T') {
+																	goto l117
+																}
+																position++
+																if buffer[position] != rune('A') {
+																	goto l117
+																}
+																position++
+																if buffer[position] != rune('I') {
+																	goto l117
+																}
+																position++
+																if buffer[position] != rune('N') {
+																	goto l117
+																}
+																position++
+																if buffer[position] != rune('S') {
+																	goto l117
+																}
+																position++
+																break
+															case '!':
+																if buffer[position] != rune('!') {
+																	goto l117
+																}
+																position++
+																if buffer[position] != rune('=') {
+																	goto l117
+																}
+																position++
+																break
+															default:
+																if buffer[position] != rune('=') {
+																	goto l117
+																}
+																position++
+																break
+															}
+														}
+
+														depth--
+														add(ruleDataCompareOp, position248)
+													}
+													depth--
+													add(rulePegText, position247)
+												}
+												{
+													add(ruleAction47, position)
+												}
+												depth--
+												add(ruleDataCompare, position246)
+											}
+											if !_rules[ruleWSX]() {
+												goto l117
+											}
+											if !_rules[ruleDataValue]() {
+												goto l117
+											}
+											depth--
+											add(ruleDataCriteria, position243)
+										}
+										{
+											add(ruleAction18, position)
+										}
+										break
+									default:
+										{
+											position252 := position
+											depth++
+											{
+												position253 := position
+												depth++
+												{
+													position254, tokenIndex254, depth254 := position, tokenIndex, depth
+													if buffer[position] != rune('s') {
+														goto l255
+													}
+													position++
+													if buffer[position] != rune('u') {
+														goto l255
+													}
+													position++
+													if buffer[position] != rune('p') {
+														goto l255
+													}
+													position++
+													if buffer[position] != rune('e') {
+														goto l255
+													}
+													position++
+													if buffer[position] != rune('r') {
+														goto l255
+													}
+													position++
+													if buffer[position] != rune('s') {
+														goto l255
+													}
+													position++
+													if buffer[position] != rune('e') {
+														goto l255
+													}
+													position++
+													if buffer[position] != rune('d') {
+														goto l255
+													}
+													position++
+													if buffer[position] != rune('e') {
+														goto l255
+													}
+													position++
+													if buffer[position] != rune('d') {
+														goto l255
+													}
+													position++
+													goto l254
+												l255:
+													position, tokenIndex, depth = position254, tokenIndex254, depth254
+													if buffer[position] != rune('r') {
+														goto l117
+													}
+													position++
+													if buffer[position] != rune('e') {
+														goto l117
+													}
+													position++
+													if buffer[position] != rune('t') {
+														goto l117
+													}
+													position++
+													if buffer[position] != rune('r') {
+														goto l117
+													}
+													position++
+													if buffer[position] != rune('a') {
+														goto l117
+													}
+													position++
+													if buffer[position] != rune('c') {
+														goto l117
+													}
+													position++
+													if buffer[position] != rune('t') {
+														goto l117
+													}
+													position++
+													if buffer[position] != rune('e') {
+														goto l117
+													}
+													position++
+													if buffer[position] != rune('d') {
+														goto l117
+													}
+													position++
+												}
+											l254:
+												depth--
+												add(rulePegText, position253)
+											}
+											{
+												add(ruleAction46, position)
+											}
+											depth--
+											add(ruleSupersedeCriteria, position252)
+										}
+										{
+											add(ruleAction21, position)
+										}
+										break
+									}
+								}
+
+							}
+						l122:
+							depth--
+							add(ruleSimpleCriteria, position121)
+						}
+						break
+					}
+				}
+
+				depth--
+				add(ruleCompoundCriteria, position118)
+			}
+			return true
+		l117:
+			position, tokenIndex, depth = position117, tokenIndex117, depth117
+			return false
+		},
+		/* 21 SimpleCriteria <- <((ValueCriteria Action15) / (RangeCriteria Action16) / (IndexCriteria Action17) / ((&('t') (TextCriteria Action20)) | (&('f') (FieldCriteria Action19)) | (&('d') (DataCriteria Action18)) | (&('r' | 's') (SupersedeCriteria Action21))))> */
+		nil,
+		/* 22 ValueCriteria <- <((&('s') SourceCriteria) | (&('p') PublisherCriteria) | (&('i') IdCriteria))> */
+		nil,
+		/* 23 IdCriteria <- <(<('i' 'd')> Action22 WSX ((&('L' | 'P') (ValueMatch WSX StatementIdPattern Action24)) | (&('I') (ValueIn WSX '(' WSX StatementIdList WSX ')')) | (&('!' | '=') (ValueCompare WSX StatementId Action23))))> */
+		nil,
+		/* 24 PublisherCriteria <- <(<('p' 'u' 'b' 'l' 'i' 's' 'h' 'e' 'r')> Action25 WSX ((ValueCompare WSX PublisherId Action26) / (ValueIn WSX '(' WSX PublisherIdList WSX ')')))> */
+		nil,
+		/* 25 SourceCriteria <- <(<('s' 'o' 'u' 'r' 'c' 'e')> Action27 WSX ((ValueCompare WSX PublisherId Action28) / (ValueIn WSX '(' WSX PublisherIdList WSX ')')))> */
+		nil,
+		/* 26 ValueCompare <- <(<ValueCompareOp> Action29)> */
+		func() bool {
+			position263, tokenIndex263, depth263 := position, tokenIndex, depth
+			{
+				position264 := position
+				depth++
+				{
+					position265 := position
+					depth++
+					{
+						position266 := position
+						depth++
+						{
+							position267, tokenIndex267, depth267 := position, tokenIndex, depth
+							if buffer[position] != rune('=') {
+								goto l268
+							}
+							position++
+							goto l267
+						l268:
+							position, tokenIndex, depth = position267, tokenIndex267, depth267
+							if buffer[position] != rune('!') {
+								goto l263
+							}
+							position++
+							if buffer[position] != rune('=') {
+								goto l263
+							}
+							position++
+						}
+					l267:
+						depth--
+						add(ruleValueCompareOp, position266)
+					}
+					depth--
+					add(rulePegText, position265)
+				}
+				{
+					add(ruleAction29, position)
+				}
+				depth--
+				add(ruleValueCompare, position264)
+			}
+			return true
+		l263:
+			position, tokenIndex, depth = position263, tokenIndex263, depth263
+			return false
+		},
+		/* 27 ValueCompareOp <- <('=' / ('!' '='))> */
+		nil,
+		/* 28 ValueIn <- <(<('I' 'N')> Action30)> */
+		func() bool {
+			position271, tokenIndex271, depth271 := position, tokenIndex, depth
+			{
+				position272 := position
+				depth++
+				{
+					position273 := position
+					depth++
+					if buffer[position] != rune('I') {
+						goto l271
+					}
+					position++
+					if buffer[position] != rune('N') {
+						goto l271
+					}
+					position++
+					depth--
+					add(rulePegText, position273)
+				}
+				{
+					add(ruleAction30, position)
+				}
+				depth--
+				add(ruleValueIn, position272)
+			}
+			return true
+		l271:
+			position, tokenIndex, depth = position271, tokenIndex271, depth271
+			return false
+		},
+		/* 29 ValueMatch <- <(<ValueMatchOp> Action31)> */
+		func() bool {
+			position275, tokenIndex275, depth275 := position, tokenIndex, depth
+			{
+				position276 := position
+				depth++
+				{
+					position277 := position
+					depth++
+					{
+						position278 := position
+						depth++
+						{
+							position279, tokenIndex279, depth279 := position, tokenIndex, depth
+							if buffer[position] != rune('L') {
+								goto l280
+							}
+							position++
+							if buffer[position] != rune('I') {
+								goto l280
+							}
+							position++
+							if buffer[position] != rune('K') {
+								goto l280
+							}
+							position++
+							if buffer[position] != rune('E') {
+								goto l280
+							}
+							position++
+							goto l279
+						l280:
+							position, tokenIndex, depth = position279, tokenIndex279, depth279
+							if buffer[position] != rune('P') {
+								goto l275
+							}
+							position++
+							if buffer[position] != rune('R') {
+								goto l275
+							}
+							position++
+							if buffer[position] != rune('E') {
+								goto l275
+							}
+							position++
+							if buffer[position] != rune('F') {
+								goto l275
+							}
+							position++
+							if buffer[position] != rune('I') {
+								goto l275
+							}
+							position++
+							if buffer[position] != rune('X') {
+								goto l275
+							}
+							position++
+						}
+					l279:
+						depth--
+						add(ruleValueMatchOp, position278)
+					}
+					depth--
+					add(rulePegText, position277)
+				}
+				{
+					add(ruleAction31, position)
+				}
+				depth--
+				add(ruleValueMatch, position276)
+			}
+			return true
+		l275:
+			position, tokenIndex, depth = position275, tokenIndex275, depth275
+			return false
+		},
+		/* 30 ValueMatchOp <- <(('L' 'I' 'K' 'E') / ('P' 'R' 'E' 'F' 'I' 'X'))> */
+		nil,
+		/* 31 RangeCriteria <- <(RangeSelector WSX Comparison WSX UInt Action32)> */
+		nil,
+		/* 32 RangeSelector <- <(<RangeSelectorOp> Action33)> */
+		nil,
+		/* 33 RangeSelectorOp <- <(('t' 'i' 'm' 'e' 's' 't' 'a' 'm' 'p') / ('c' 'o' 'u' 'n' 't' 'e' 'r'))> */
+		nil,
+		/* 34 Boolean <- <(<BooleanOp> Action34)> */
+		nil,
+		/* 35 BooleanOp <- <(('A' 'N' 'D') / ('O' 'R'))> */
+		nil,
+		/* 36 Comparison <- <(<ComparisonOp> Action35)> */
+		nil,
+		/* 37 ComparisonOp <- <(('<' '=') / ('>' '=') / ((&('>') '>') | (&('!') ('!' '=')) | (&('=') '=') | (&('<') '<')))> */
+		nil,
+		/* 38 IndexCriteria <- <((&('d') DepCriteria) | (&('t') TagCriteria) | (&('w') WKICriteria))> */
+		nil,
+		/* 39 WKICriteria <- <(<('w' 'k' 'i')> Action36 WSX ((&('I') (ValueIn WSX '(' WSX WKIList WSX ')')) | (&('=') (IndexCompare WSX WKI Action37)) | (&('L' | 'P') (ValueMatch WSX WKIPattern Action38))))> */
+		nil,
+		/* 40 TagCriteria <- <(<('t' 'a' 'g')> Action39 WSX ((IndexCompare WSX Tag Action40) / (ValueIn WSX '(' WSX TagList WSX ')')))> */
+		nil,
+		/* 41 DepCriteria <- <(<('d' 'e' 'p')> Action41 WSX ((IndexCompare WSX ObjectId Action42) / (ValueIn WSX '(' WSX ObjectIdList WSX ')')))> */
+		nil,
+		/* 42 IndexCompare <- <(<'='> Action43)> */
+		func() bool {
+			position294, tokenIndex294, depth294 := position, tokenIndex, depth
+			{
+				position295 := position
+				depth++
+				{
+					position296 := position
+					depth++
+					if buffer[position] != rune('=') {
+						goto l294
+					}
+					position++
+					depth--
+					add(rulePegText, position296)
+				}
+				{
+					add(ruleAction43, position)
+				}
+				depth--
+				add(ruleIndexCompare, position295)
+			}
+			return true
+		l294:
+			position, tokenIndex, depth = position294, tokenIndex294, depth294
+			return false
+		},
+		/* 43 DataCriteria <- <('d' 'a' 't' 'a' '.' <DataPath> Action44 WSX DataCompare WSX DataValue)> */
+		nil,
+		/* 44 FieldCriteria <- <('f' 'i' 'e' 'l' 'd' '.' <DataPath> Action45 WSX ((IndexCompare WSX DataValue) / (ValueIn WSX '(' WSX DataValueList WSX ')')))> */
+		nil,
+		/* 45 TextCriteria <- <('t' 'e' 'x' 't' WS ('M' 'A' 'T' 'C' 'H') WS DataValue)> */
+		nil,
+		/* 46 SupersedeCriteria <- <(<(('s' 'u' 'p' 'e' 'r' 's' 'e' 'd' 'e' 'd') / ('r' 'e' 't' 'r' 'a' 'c' 't' 'e' 'd'))> Action46)> */
+		nil,
+		/* 47 DataPath <- <(DataKey ('.' DataKey)*)> */
+		func() bool {
+			position302, tokenIndex302, depth302 := position, tokenIndex, depth
+			{
+				position303 := position
+				depth++
+				if !_rules[ruleDataKey]() {
+					goto l302
+				}
+			l304:
+				{
+					position305, tokenIndex305, depth305 := position, tokenIndex, depth
+					if buffer[position] != rune('.') {
+						goto l305
+					}
+					position++
+					if !_rules[ruleDataKey]() {
+						goto l305
+					}
+					goto l304
+				l305:
+					position, tokenIndex, depth = position305, tokenIndex305, depth305
+				}
+				depth--
+				add(ruleDataPath, position303)
+			}
+			return true
+		l302:
+			position, tokenIndex, depth = position302, tokenIndex302, depth302
+			return false
+		},
+		/* 48 DataKey <- <((&('_') '_') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('-') '-') | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))+> */
+		func() bool {
+			position306, tokenIndex306, depth306 := position, tokenIndex, depth
+			{
+				position307 := position
+				depth++
+				{
+					switch buffer[position] {
+					case '_':
+						if buffer[position] != rune('_') {
+							goto l306
+						}
+						position++
+						break
+					case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+						if c := buffer[position]; c < rune('0') || c > rune('9') {
+							goto l306
+						}
+						position++
+						break
+					case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+						if c := buffer[position]; c < rune('A') || c > rune('Z') {
+							goto l306
+						}
+						position++
+						break
+					case '-':
+						if buffer[position] != rune('-') {
+							goto l306
+						}
+						position++
+						break
+					default:
+						if c := buffer[position]; c < rune('a') || c > rune('z') {
+							goto l306
+						}
+						position++
+						break
+					}
+				}
+
+			l308:
+				{
+					position309, tokenIndex309, depth309 := position, tokenIndex, depth
+					{
+						switch buffer[position] {
+						case '_':
+							if buffer[position] != rune('_') {
+								goto l309
+							}
+							position++
+							break
+						case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+							if c := buffer[position]; c < rune('0') || c > rune('9') {
+								goto l309
+							}
+							position++
+							break
+						case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+							if c := buffer[position]; c < rune('A') || c > rune('Z') {
+								goto l309
+							}
+							position++
+							break
+						case '-':
+							if buffer[position] != rune('-') {
+								goto l309
+							}
+							position++
+							break
+						default:
+							if c := buffer[position]; c < rune('a') || c > rune('z') {
+								goto l309
+							}
+							position++
+							break
+						}
+					}
+
+					goto l308
+				l309:
+					position, tokenIndex, depth = position309, tokenIndex309, depth309
+				}
+				depth--
+				add(ruleDataKey, position307)
+			}
+			return true
+		l306:
+			position, tokenIndex, depth = position306, tokenIndex306, depth306
+			return false
+		},
+		/* 49 DataCompare <- <(<DataCompareOp> Action47)> */
+		nil,
+		/* 50 DataCompareOp <- <((&('C') ('C' 'O' 'N' 'T' 'A' 'I' 'N' 'S')) | (&('!') ('!' '=')) | (&('=') '='))> */
+		nil,
+		/* 51 Group <- <('G' 'R' 'O' 'U' 'P' WS ('B' 'Y') WS GroupSpec Action48)> */
+		nil,
+		/* 52 GroupSpec <- <(GroupSelector (',' WSX GroupSelector)*)> */
+		nil,
+		/* 53 GroupSelector <- <(<GroupSelectorOp> Action49)> */
+		func() bool {
+			position316, tokenIndex316, depth316 := position, tokenIndex, depth
+			{
+				position317 := position
+				depth++
+				{
+					position318 := position
+					depth++
+					{
+						position319 := position
+						depth++
+						{
+							switch buffer[position] {
+							case 's':
+								if buffer[position] != rune('s') {
+									goto l316
+								}
+								position++
+								if buffer[position] != rune('o') {
+									goto l316
+								}
+								position++
+								if buffer[position] != rune('u') {
+									goto l316
+								}
+								position++
+								if buffer[position] != rune('r') {
+									goto l316
+								}
+								position++
+								if buffer[position] != rune('c') {
+									goto l316
+								}
+								position++
+								if buffer[position] != rune('e') {
+									goto l316
+								}
+								position++
+								break
+							case 'p':
+								if buffer[position] != rune('p') {
+									goto l316
+								}
+								position++
+								if buffer[position] != rune('u') {
+									goto l316
+								}
+								position++
+								if buffer[position] != rune('b') {
+									goto l316
+								}
+								position++
+								if buffer[position] != rune('l') {
+									goto l316
+								}
+								position++
+								if buffer[position] != rune('i') {
+									goto l316
+								}
+								position++
+								if buffer[position] != rune('s') {
+									goto l316
+								}
+								position++
+								if buffer[position] != rune('h') {
+									goto l316
+								}
+								position++
+								if buffer[position] != rune('e') {
+									goto l316
+								}
+								position++
+								if buffer[position] != rune('r') {
+									goto l316
+								}
+								position++
+								break
+							default:
+								if buffer[position] != rune('n') {
+									goto l316
+								}
+								position++
+								if buffer[position] != rune('a') {
+									goto l316
+								}
+								position++
+								if buffer[position] != rune('m') {
+									goto l316
+								}
+								position++
+								if buffer[position] != rune('e') {
+									goto l316
+								}
+								position++
+								if buffer[position] != rune('s') {
+									goto l316
+								}
+								position++
+								if buffer[position] != rune('p') {
+									goto l316
+								}
+								position++
+								if buffer[position] != rune('a') {
+									goto l316
+								}
+								position++
+								if buffer[position] != rune('c') {
+									goto l316
+								}
+								position++
+								if buffer[position] != rune('e') {
+									goto l316
+								}
+								position++
+								break
+							}
+						}
+
+						depth--
+						add(ruleGroupSelectorOp, position319)
+					}
+					depth--
+					add(rulePegText, position318)
+				}
+				{
+					add(ruleAction49, position)
+				}
+				depth--
+				add(ruleGroupSelector, position317)
+			}
+			return true
+		l316:
+			position, tokenIndex, depth = position316, tokenIndex316, depth316
+			return false
+		},
+		/* 54 GroupSelectorOp <- <((&('s') ('s' 'o' 'u' 'r' 'c' 'e')) | (&('p') ('p' 'u' 'b' 'l' 'i' 's' 'h' 'e' 'r')) | (&('n') ('n' 'a' 'm' 'e' 's' 'p' 'a' 'c' 'e')))> */
+		nil,
+		/* 55 Order <- <('O' 'R' 'D' 'E' 'R' WS ('B' 'Y') WS OrderSpec Action50)> */
+		nil,
+		/* 56 OrderSpec <- <(OrderSelectorSpec (',' WSX OrderSelectorSpec)*)> */
+		nil,
+		/* 57 OrderSelectorSpec <- <(OrderSelector Action51 (WS OrderDir Action52)?)> */
+		func() bool {
+			position325, tokenIndex325, depth325 := position, tokenIndex, depth
+			{
+				position326 := position
+				depth++
+				{
+					position327 := position
+					depth++
+					{
+						position328 := position
+						depth++
+						{
+							position329 := position
+							depth++
+							{
+								switch buffer[position] {
+								case 'c':
+									if buffer[position] != rune('c') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('o') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('u') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('n') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('t') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('e') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('r') {
+										goto l325
+									}
+									position++
+									break
+								case 't':
+									if buffer[position] != rune('t') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('i') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('m') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('e') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('s') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('t') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('a') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('m') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('p') {
+										goto l325
+									}
+									position++
+									break
+								case 's':
+									if buffer[position] != rune('s') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('o') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('u') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('r') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('c') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('e') {
+										goto l325
+									}
+									position++
+									break
+								case 'p':
+									if buffer[position] != rune('p') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('u') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('b') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('l') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('i') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('s') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('h') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('e') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('r') {
+										goto l325
+									}
+									position++
+									break
+								case 'n':
+									if buffer[position] != rune('n') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('a') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('m') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('e') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('s') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('p') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('a') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('c') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('e') {
+										goto l325
+									}
+									position++
+									break
+								default:
+									if buffer[position] != rune('i') {
+										goto l325
+									}
+									position++
+									if buffer[position] != rune('d') {
+										goto l325
+									}
+									position++
+									break
+								}
+							}
+
+							depth--
+							add(ruleOrderSelectorOp, position329)
+						}
+						depth--
+						add(rulePegText, position328)
+					}
+					{
+						add(ruleAction53, position)
+					}
+					depth--
+					add(ruleOrderSelector, position327)
+				}
+				{
+					add(ruleAction51, position)
+				}
+				{
+					position333, tokenIndex333, depth333 := position, tokenIndex, depth
+					if !_rules[ruleWS]() {
+						goto l333
+					}
+					{
+						position335 := position
+						depth++
+						{
+							position336 := position
+							depth++
+							{
+								position337 := position
+								depth++
+								{
+									position338, tokenIndex338, depth338 := position, tokenIndex, depth
+									if buffer[position] != rune('A') {
+										goto l339
+									}
+									position++
+									if buffer[position] != rune('S') {
+										goto l339
+									}
+									position++
+									if buffer[position] != rune('C') {
+										goto l339
+									}
+									position++
+									goto l338
+								l339:
+									position, tokenIndex, depth = position338, tokenIndex338, depth338
+									if buffer[position] != rune('D') {
+										goto l333
+									}
+									position++
+									if buffer[position] != rune('E') {
+										goto l333
+									}
+									position++
+									if buffer[position] != rune('S') {
+										goto l333
+									}
+									position++
+									if buffer[position] != rune('C') {
+										goto l333
+									}
+									position++
+								}
+							l338:
+								depth--
+								add(ruleOrderDirOp, position337)
+							}
+							depth--
+							add(rulePegText, position336)
+						}
+						{
+							add(ruleAction54, position)
+						}
+						depth--
+						add(ruleOrderDir, position335)
+					}
+					{
+						add(ruleAction52, position)
+					}
+					goto l334
+				l333:
+					position, tokenIndex, depth = position333, tokenIndex333, depth333
+				}
+			l334:
+				depth--
+				add(ruleOrderSelectorSpec, position326)
+			}
+			return true
+		l325:
+			position, tokenIndex, depth = position325, tokenIndex325, depth325
+			return false
+		},
+		/* 58 OrderSelector <- <(<OrderSelectorOp> Action53)> */
+		nil,
+		/* 59 OrderSelectorOp <- <((&('c') ('c' 'o' 'u' 'n' 't' 'e' 'r')) | (&('t') ('t' 'i' 'm' 'e' 's' 't' 'a' 'm' 'p')) | (&('s') ('s' 'o' 'u' 'r' 'c' 'e')) | (&('p') ('p' 'u' 'b' 'l' 'i' 's' 'h' 'e' 'r')) | (&('n') ('n' 'a' 'm' 'e' 's' 'p' 'a' 'c' 'e')) | (&('i') ('i' 'd')))> */
+		nil,
+		/* 60 OrderDir <- <(<OrderDirOp> Action54)> */
+		nil,
+		/* 61 OrderDirOp <- <(('A' 'S' 'C') / ('D' 'E' 'S' 'C'))> */
+		nil,
+		/* 62 Limit <- <('L' 'I' 'M' 'I' 'T' WS UInt Action55)> */
+		func() bool {
+			position346, tokenIndex346, depth346 := position, tokenIndex, depth
+			{
+				position347 := position
+				depth++
+				if buffer[position] != rune('L') {
+					goto l346
+				}
+				position++
+				if buffer[position] != rune('I') {
+					goto l346
+				}
+				position++
+				if buffer[position] != rune('M') {
+					goto l346
+				}
+				position++
+				if buffer[position] != rune('I') {
+					goto l346
+				}
+				position++
+				if buffer[position] != rune('T') {
+					goto l346
+				}
+				position++
+				if !_rules[ruleWS]() {
+					goto l346
+				}
+				if !_rules[ruleUInt]() {
+					goto l346
+				}
+				{
+					add(ruleAction55, position)
+				}
+				depth--
+				add(ruleLimit, position347)
+			}
+			return true
+		l346:
+			position, tokenIndex, depth = position346, tokenIndex346, depth346
+			return false
+		},
+		/* 63 Offset <- <('O' 'F' 'F' 'S' 'E' 'T' WS UInt Action56)> */
+		nil,
+		/* 64 StatementId <- <<((&(':') ':') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))+>> */
+		func() bool {
+			position350, tokenIndex350, depth350 := position, tokenIndex, depth
+			{
+				position351 := position
+				depth++
+				{
+					position352 := position
+					depth++
+					{
+						switch buffer[position] {
+						case ':':
+							if buffer[position] != rune(':') {
+								goto l350
+							}
+							position++
+							break
+						case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+							if c := buffer[position]; c < rune('0') || c > rune('9') {
+								goto l350
+							}
+							position++
+							break
+						case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+							if c := buffer[position]; c < rune('A') || c > rune('Z') {
+								goto l350
+							}
+							position++
+							break
+						default:
+							if c := buffer[position]; c < rune('a') || c > rune('z') {
+								goto l350
+							}
+							position++
+							break
+						}
+					}
 
-										depth--
-										add(ruleValueCriteria, position123)
-									}
-									{
-										add(ruleAction11, position)
-									}
-									break
+				l353:
+					{
+						position354, tokenIndex354, depth354 := position, tokenIndex, depth
+						{
+							switch buffer[position] {
+							case ':':
+								if buffer[position] != rune(':') {
+									goto l354
+								}
+								position++
+								break
+							case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+								if c := buffer[position]; c < rune('0') || c > rune('9') {
+									goto l354
 								}
+								position++
+								break
+							case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+								if c := buffer[position]; c < rune('A') || c > rune('Z') {
+									goto l354
+								}
+								position++
+								break
+							default:
+								if c := buffer[position]; c < rune('a') || c > rune('z') {
+									goto l354
+								}
+								position++
+								break
 							}
-
-							depth--
-							add(ruleSimpleCriteria, position92)
 						}
-						break
+
+						goto l353
+					l354:
+						position, tokenIndex, depth = position354, tokenIndex354, depth354
 					}
+					depth--
+					add(rulePegText, position352)
 				}
-
 				depth--
-				add(ruleCompoundCriteria, position89)
+				add(ruleStatementId, position351)
 			}
 			return true
-		l88:
-			position, tokenIndex, depth = position88, tokenIndex88, depth88
+		l350:
+			position, tokenIndex, depth = position350, tokenIndex350, depth350
 			return false
 		},
-		/* 17 SimpleCriteria <- <((&('w') (IndexCriteria Action13)) | (&('c' | 't') (RangeCriteria Action12)) | (&('i' | 'p' | 's') (ValueCriteria Action11)))> */
-		nil,
-		/* 18 ValueCriteria <- <((&('s') SourceCriteria) | (&('p') PublisherCriteria) | (&('i') IdCriteria))> */
-		nil,
-		/* 19 IdCriteria <- <(<('i' 'd')> Action14 WSX ValueCompare WSX StatementId Action15)> */
-		nil,
-		/* 20 PublisherCriteria <- <(<('p' 'u' 'b' 'l' 'i' 's' 'h' 'e' 'r')> Action16 WSX ValueCompare WSX PublisherId Action17)> */
-		nil,
-		/* 21 SourceCriteria <- <(<('s' 'o' 'u' 'r' 'c' 'e')> Action18 WSX ValueCompare WSX PublisherId Action19)> */
-		nil,
-		/* 22 ValueCompare <- <(<ValueCompareOp> Action20)> */
+		/* 65 PublisherId <- <<((&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))+>> */
 		func() bool {
-			position149, tokenIndex149, depth149 := position, tokenIndex, depth
+			position357, tokenIndex357, depth357 := position, tokenIndex, depth
 			{
-				position150 := position
+				position358 := position
 				depth++
 				{
-					position151 := position
+					position359 := position
 					depth++
 					{
-						position152 := position
-						depth++
-						{
-							position153, tokenIndex153, depth153 := position, tokenIndex, depth
-							if buffer[position] != rune('=') {
-								goto l154
+						switch buffer[position] {
+						case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+							if c := buffer[position]; c < rune('0') || c > rune('9') {
+								goto l357
 							}
 							position++
-							goto l153
-						l154:
-							position, tokenIndex, depth = position153, tokenIndex153, depth153
-							if buffer[position] != rune('!') {
-								goto l149
+							break
+						case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+							if c := buffer[position]; c < rune('A') || c > rune('Z') {
+								goto l357
 							}
 							position++
-							if buffer[position] != rune('=') {
-								goto l149
+							break
+						default:
+							if c := buffer[position]; c < rune('a') || c > rune('z') {
+								goto l357
 							}
 							position++
+							break
 						}
-					l153:
-						depth--
-						add(ruleValueCompareOp, position152)
+					}
+
+				l360:
+					{
+						position361, tokenIndex361, depth361 := position, tokenIndex, depth
+						{
+							switch buffer[position] {
+							case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+								if c := buffer[position]; c < rune('0') || c > rune('9') {
+									goto l361
+								}
+								position++
+								break
+							case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+								if c := buffer[position]; c < rune('A') || c > rune('Z') {
+									goto l361
+								}
+								position++
+								break
+							default:
+								if c := buffer[position]; c < rune('a') || c > rune('z') {
+									goto l361
+								}
+								position++
+								break
+							}
+						}
+
+						goto l360
+					l361:
+						position, tokenIndex, depth = position361, tokenIndex361, depth361
 					}
 					depth--
-					add(rulePegText, position151)
+					add(rulePegText, position359)
 				}
+				depth--
+				add(rulePublisherId, position358)
+			}
+			return true
+		l357:
+			position, tokenIndex, depth = position357, tokenIndex357, depth357
+			return false
+		},
+		/* 66 WKI <- <<((&('.') '.') | (&('/') '/') | (&('_') '_') | (&(':') ':') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('-') '-') | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))+>> */
+		func() bool {
+			position364, tokenIndex364, depth364 := position, tokenIndex, depth
+			{
+				position365 := position
+				depth++
 				{
-					add(ruleAction20, position)
+					position366 := position
+					depth++
+					{
+						switch buffer[position] {
+						case '.':
+							if buffer[position] != rune('.') {
+								goto l364
+							}
+							position++
+							break
+						case '/':
+							if buffer[position] != rune('/') {
+								goto l364
+							}
+							position++
+							break
+						case '_':
+							if buffer[position] != rune('_') {
+								goto l364
+							}
+							position++
+							break
+						case ':':
+							if buffer[position] != rune(':') {
+								goto l364
+							}
+							position++
+							break
+						case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+							if c := buffer[position]; c < rune('0') || c > rune('9') {
+								goto l364
+							}
+							position++
+							break
+						case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+							if c := buffer[position]; c < rune('A') || c > rune('Z') {
+								goto l364
+							}
+							position++
+							break
+						case '-':
+							if buffer[position] != rune('-') {
+								goto l364
+							}
+							position++
+							break
+						default:
+							if c := buffer[position]; c < rune('a') || c > rune('z') {
+								goto l364
+							}
+							position++
+							break
+						}
+					}
+
+				l367:
+					{
+						position368, tokenIndex368, depth368 := position, tokenIndex, depth
+						{
+							switch buffer[position] {
+							case '.':
+								if buffer[position] != rune('.') {
+									goto l368
+								}
+								position++
+								break
+							case '/':
+								if buffer[position] != rune('/') {
+									goto l368
+								}
+								position++
+								break
+							case '_':
+								if buffer[position] != rune('_') {
+									goto l368
+								}
+								position++
+								break
+							case ':':
+								if buffer[position] != rune(':') {
+									goto l368
+								}
+								position++
+								break
+							case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+								if c := buffer[position]; c < rune('0') || c > rune('9') {
+									goto l368
+								}
+								position++
+								break
+							case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+								if c := buffer[position]; c < rune('A') || c > rune('Z') {
+									goto l368
+								}
+								position++
+								break
+							case '-':
+								if buffer[position] != rune('-') {
+									goto l368
+								}
+								position++
+								break
+							default:
+								if c := buffer[position]; c < rune('a') || c > rune('z') {
+									goto l368
+								}
+								position++
+								break
+							}
+						}
+
+						goto l367
+					l368:
+						position, tokenIndex, depth = position368, tokenIndex368, depth368
+					}
+					depth--
+					add(rulePegText, position366)
 				}
 				depth--
-				add(ruleValueCompare, position150)
+				add(ruleWKI, position365)
 			}
 			return true
-		l149:
-			position, tokenIndex, depth = position149, tokenIndex149, depth149
+		l364:
+			position, tokenIndex, depth = position364, tokenIndex364, depth364
 			return false
 		},
-		/* 23 ValueCompareOp <- <('=' / ('!' '='))> */
-		nil,
-		/* 24 RangeCriteria <- <(RangeSelector WSX Comparison WSX UInt Action21)> */
-		nil,
-		/* 25 RangeSelector <- <(<RangeSelectorOp> Action22)> */
-		nil,
-		/* 26 RangeSelectorOp <- <(('t' 'i' 'm' 'e' 's' 't' 'a' 'm' 'p') / ('c' 'o' 'u' 'n' 't' 'e' 'r'))> */
-		nil,
-		/* 27 Boolean <- <(<BooleanOp> Action23)> */
-		nil,
-		/* 28 BooleanOp <- <(('A' 'N' 'D') / ('O' 'R'))> */
-		nil,
-		/* 29 Comparison <- <(<ComparisonOp> Action24)> */
-		nil,
-		/* 30 ComparisonOp <- <(('<' '=') / ('>' '=') / ((&('>') '>') | (&('!') ('!' '=')) | (&('=') '=') | (&('<') '<')))> */
-		nil,
-		/* 31 IndexCriteria <- <WKICriteria> */
-		nil,
-		/* 32 WKICriteria <- <(<('w' 'k' 'i')> Action25 WSX '=' WSX WKI Action26)> */
-		nil,
-		/* 33 Order <- <('O' 'R' 'D' 'E' 'R' WS ('B' 'Y') WS OrderSpec Action27)> */
-		nil,
-		/* 34 OrderSpec <- <(OrderSelectorSpec (',' WSX OrderSelectorSpec)*)> */
-		nil,
-		/* 35 OrderSelectorSpec <- <(OrderSelector Action28 (WS OrderDir Action29)?)> */
+		/* 67 Tag <- <(('\'' <((&('.') '.') | (&('/') '/') | (&('_') '_') | (&(':') ':') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('-') '-') | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))+> '\'') / <((&('.') '.') | (&('/') '/') | (&('_') '_') | (&(':') ':') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('-') '-') | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))+>)> */
 		func() bool {
-			position168, tokenIndex168, depth168 := position, tokenIndex, depth
+			position371, tokenIndex371, depth371 := position, tokenIndex, depth
 			{
-				position169 := position
+				position372 := position
 				depth++
 				{
-					position170 := position
-					depth++
+					position373, tokenIndex373, depth373 := position, tokenIndex, depth
+					if buffer[position] != rune('\'') {
+						goto l374
+					}
+					position++
 					{
-						position171 := position
+						position375 := position
 						depth++
 						{
-							position172 := position
-							depth++
-							{
-								switch buffer[position] {
-								case 'c':
-									if buffer[position] != rune('c') {
-										goto l168
-									}
-									position++
-									if buffer[position] != rune('o') {
-										goto l168
-									}
-									position++
-									if buffer[position] != rune('u') {
-										goto l168
-									}
-									position++
-									if buffer[position] != rune('n') {
-										goto l168
-									}
-									position++
-									if buffer[position] != rune('t') {
-										goto l168
-									}
-									position++
-									if buffer[position] != rune('e') {
-										goto l168
-									}
-									position++
-									if buffer[position] != rune('r') {
-										goto l168
+							switch buffer[position] {
+							case '.':
+								if buffer[position] != rune('.') {
+									goto l374
+								}
+								position++
+								break
+							case '/':
+								if buffer[position] != rune('/') {
+									goto l374
+								}
+								position++
+								break
+							case '_':
+								if buffer[position] != rune('_') {
+									goto l374
+								}
+								position++
+								break
+							case ':':
+								if buffer[position] != rune(':') {
+									goto l374
+								}
+								position++
+								break
+							case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+								if c := buffer[position]; c < rune('0') || c > rune('9') {
+									goto l374
+								}
+								position++
+								break
+							case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+								if c := buffer[position]; c < rune('A') || c > rune('Z') {
+									goto l374
+								}
+								position++
+								break
+							case '-':
+								if buffer[position] != rune('-') {
+									goto l374
+								}
+								position++
+								break
+							default:
+								if c := buffer[position]; c < rune('a') || c > rune('z') {
+									goto l374
+								}
+								position++
+								break
+							}
+						}
+
+					l376:
+						{
+							position377, tokenIndex377, depth377 := position, tokenIndex, depth
+							{
+								switch buffer[position] {
+								case '.':
+									if buffer[position] != rune('.') {
+										goto l377
 									}
 									position++
 									break
-								case 't':
-									if buffer[position] != rune('t') {
-										goto l168
-									}
-									position++
-									if buffer[position] != rune('i') {
-										goto l168
-									}
-									position++
-									if buffer[position] != rune('m') {
-										goto l168
-									}
-									position++
-									if buffer[position] != rune('e') {
-										goto l168
-									}
-									position++
-									if buffer[position] != rune('s') {
-										goto l168
-									}
-									position++
-									if buffer[position] != rune('t') {
-										goto l168
-									}
-									position++
-									if buffer[position] != rune('a') {
-										goto l168
-									}
-									position++
-									if buffer[position] != rune('m') {
-										goto l168
-									}
-									position++
-									if buffer[position] != rune('p') {
-										goto l168
+								case '/':
+									if buffer[position] != rune('/') {
+										goto l377
 									}
 									position++
 									break
-								case 's':
-									if buffer[position] != rune('s') {
-										goto l168
-									}
-									position++
-									if buffer[position] != rune('o') {
-										goto l168
-									}
-									position++
-									if buffer[position] != rune('u') {
-										goto l168
-									}
-									position++
-									if buffer[position] != rune('r') {
-										goto l168
-									}
-									position++
-									if buffer[position] != rune('c') {
-										goto l168
-									}
-									position++
-									if buffer[position] != rune('e') {
-										goto l168
+								case '_':
+									if buffer[position] != rune('_') {
+										goto l377
 									}
 									position++
 									break
-								case 'p':
-									if buffer[position] != rune('p') {
-										goto l168
+								case ':':
+									if buffer[position] != rune(':') {
+										goto l377
 									}
 									position++
-									if buffer[position] != rune('u') {
-										goto l168
+									break
+								case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+									if c := buffer[position]; c < rune('0') || c > rune('9') {
+										goto l377
 									}
 									position++
-									if buffer[position] != rune('b') {
-										goto l168
+									break
+								case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+									if c := buffer[position]; c < rune('A') || c > rune('Z') {
+										goto l377
 									}
 									position++
-									if buffer[position] != rune('l') {
-										goto l168
+									break
+								case '-':
+									if buffer[position] != rune('-') {
+										goto l377
 									}
 									position++
-									if buffer[position] != rune('i') {
-										goto l168
+									break
+								default:
+									if c := buffer[position]; c < rune('a') || c > rune('z') {
+										goto l377
 									}
 									position++
-									if buffer[position] != rune('s') {
-										goto l168
+									break
+								}
+							}
+
+							goto l376
+						l377:
+							position, tokenIndex, depth = position377, tokenIndex377, depth377
+						}
+						depth--
+						add(rulePegText, position375)
+					}
+					if buffer[position] != rune('\'') {
+						goto l374
+					}
+					position++
+					goto l373
+				l374:
+					position, tokenIndex, depth = position373, tokenIndex373, depth373
+					{
+						position380 := position
+						depth++
+						{
+							switch buffer[position] {
+							case '.':
+								if buffer[position] != rune('.') {
+									goto l371
+								}
+								position++
+								break
+							case '/':
+								if buffer[position] != rune('/') {
+									goto l371
+								}
+								position++
+								break
+							case '_':
+								if buffer[position] != rune('_') {
+									goto l371
+								}
+								position++
+								break
+							case ':':
+								if buffer[position] != rune(':') {
+									goto l371
+								}
+								position++
+								break
+							case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+								if c := buffer[position]; c < rune('0') || c > rune('9') {
+									goto l371
+								}
+								position++
+								break
+							case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+								if c := buffer[position]; c < rune('A') || c > rune('Z') {
+									goto l371
+								}
+								position++
+								break
+							case '-':
+								if buffer[position] != rune('-') {
+									goto l371
+								}
+								position++
+								break
+							default:
+								if c := buffer[position]; c < rune('a') || c > rune('z') {
+									goto l371
+								}
+								position++
+								break
+							}
+						}
+
+					l381:
+						{
+							position382, tokenIndex382, depth382 := position, tokenIndex, depth
+							{
+								switch buffer[position] {
+								case '.':
+									if buffer[position] != rune('.') {
+										goto l382
 									}
 									position++
-									if buffer[position] != rune('h') {
-										goto l168
+									break
+								case '/':
+									if buffer[position] != rune('/') {
+										goto l382
 									}
 									position++
-									if buffer[position] != rune('e') {
-										goto l168
+									break
+								case '_':
+									if buffer[position] != rune('_') {
+										goto l382
 									}
 									position++
-									if buffer[position] != rune('r') {
-										goto l168
+									break
+								case ':':
+									if buffer[position] != rune(':') {
+										goto l382
 									}
 									position++
 									break
-								case 'n':
-									if buffer[position] != rune('n') {
-										goto l168
+								case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+									if c := buffer[position]; c < rune('0') || c > rune('9') {
+										goto l382
 									}
 									position++
-									if buffer[position] != rune('a') {
-										goto l168
+									break
+								case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+									if c := buffer[position]; c < rune('A') || c > rune('Z') {
+										goto l382
 									}
 									position++
-									if buffer[position] != rune('m') {
-										goto l168
+									break
+								case '-':
+									if buffer[position] != rune('-') {
+										goto l382
 									}
 									position++
-									if buffer[position] != rune('e') {
-										goto l168
+									break
+								default:
+									if c := buffer[position]; c < rune('a') || c > rune('z') {
+										goto l382
 									}
 									position++
-									if buffer[position] != rune('s') {
-										goto l168
+									break
+								}
+							}
+
+							goto l381
+						l382:
+							position, tokenIndex, depth = position382, tokenIndex382, depth382
+						}
+						depth--
+						add(rulePegText, position380)
+					}
+				}
+			l373:
+				depth--
+				add(ruleTag, position372)
+			}
+			return true
+		l371:
+			position, tokenIndex, depth = position371, tokenIndex371, depth371
+			return false
+		},
+		/* 68 ObjectId <- <(('\'' <((&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))+> '\'') / <((&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))+>)> */
+		func() bool {
+			position385, tokenIndex385, depth385 := position, tokenIndex, depth
+			{
+				position386 := position
+				depth++
+				{
+					position387, tokenIndex387, depth387 := position, tokenIndex, depth
+					if buffer[position] != rune('\'') {
+						goto l388
+					}
+					position++
+					{
+						position389 := position
+						depth++
+						{
+							switch buffer[position] {
+							case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+								if c := buffer[position]; c < rune('0') || c > rune('9') {
+									goto l388
+								}
+								position++
+								break
+							case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+								if c := buffer[position]; c < rune('A') || c > rune('Z') {
+									goto l388
+								}
+								position++
+								break
+							default:
+								if c := buffer[position]; c < rune('a') || c > rune('z') {
+									goto l388
+								}
+								position++
+								break
+							}
+						}
+
+					l390:
+						{
+							position391, tokenIndex391, depth391 := position, tokenIndex, depth
+							{
+								switch buffer[position] {
+								case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+									if c := buffer[position]; c < rune('0') || c > rune('9') {
+										goto l391
 									}
 									position++
-									if buffer[position] != rune('p') {
-										goto l168
+									break
+								case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+									if c := buffer[position]; c < rune('A') || c > rune('Z') {
+										goto l391
 									}
 									position++
-									if buffer[position] != rune('a') {
-										goto l168
+									break
+								default:
+									if c := buffer[position]; c < rune('a') || c > rune('z') {
+										goto l391
 									}
 									position++
-									if buffer[position] != rune('c') {
-										goto l168
+									break
+								}
+							}
+
+							goto l390
+						l391:
+							position, tokenIndex, depth = position391, tokenIndex391, depth391
+						}
+						depth--
+						add(rulePegText, position389)
+					}
+					if buffer[position] != rune('\'') {
+						goto l388
+					}
+					position++
+					goto l387
+				l388:
+					position, tokenIndex, depth = position387, tokenIndex387, depth387
+					{
+						position394 := position
+						depth++
+						{
+							switch buffer[position] {
+							case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+								if c := buffer[position]; c < rune('0') || c > rune('9') {
+									goto l385
+								}
+								position++
+								break
+							case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+								if c := buffer[position]; c < rune('A') || c > rune('Z') {
+									goto l385
+								}
+								position++
+								break
+							default:
+								if c := buffer[position]; c < rune('a') || c > rune('z') {
+									goto l385
+								}
+								position++
+								break
+							}
+						}
+
+					l395:
+						{
+							position396, tokenIndex396, depth396 := position, tokenIndex, depth
+							{
+								switch buffer[position] {
+								case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+									if c := buffer[position]; c < rune('0') || c > rune('9') {
+										goto l396
 									}
 									position++
-									if buffer[position] != rune('e') {
-										goto l168
+									break
+								case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+									if c := buffer[position]; c < rune('A') || c > rune('Z') {
+										goto l396
 									}
 									position++
 									break
 								default:
-									if buffer[position] != rune('i') {
-										goto l168
-									}
-									position++
-									if buffer[position] != rune('d') {
-										goto l168
+									if c := buffer[position]; c < rune('a') || c > rune('z') {
+										goto l396
 									}
 									position++
 									break
 								}
 							}
-
-							depth--
-							add(ruleOrderSelectorOp, position172)
+
+							goto l395
+						l396:
+							position, tokenIndex, depth = position396, tokenIndex396, depth396
+						}
+						depth--
+						add(rulePegText, position394)
+					}
+				}
+			l387:
+				depth--
+				add(ruleObjectId, position386)
+			}
+			return true
+		l385:
+			position, tokenIndex, depth = position385, tokenIndex385, depth385
+			return false
+		},
+		/* 69 UInt <- <<[0-9]+>> */
+		func() bool {
+			position399, tokenIndex399, depth399 := position, tokenIndex, depth
+			{
+				position400 := position
+				depth++
+				{
+					position401 := position
+					depth++
+					if c := buffer[position]; c < rune('0') || c > rune('9') {
+						goto l399
+					}
+					position++
+				l402:
+					{
+						position403, tokenIndex403, depth403 := position, tokenIndex, depth
+						if c := buffer[position]; c < rune('0') || c > rune('9') {
+							goto l403
+						}
+						position++
+						goto l402
+					l403:
+						position, tokenIndex, depth = position403, tokenIndex403, depth403
+					}
+					depth--
+					add(rulePegText, position401)
+				}
+				depth--
+				add(ruleUInt, position400)
+			}
+			return true
+		l399:
+			position, tokenIndex, depth = position399, tokenIndex399, depth399
+			return false
+		},
+		/* 70 DataValue <- <(('\'' <(!'\'' .)*> '\'' Action57) / (<((&('.') '.') | (&('/') '/') | (&('_') '_') | (&(':') ':') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('-') '-') | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))+> Action58))> */
+		func() bool {
+			position404, tokenIndex404, depth404 := position, tokenIndex, depth
+			{
+				position405 := position
+				depth++
+				{
+					position406, tokenIndex406, depth406 := position, tokenIndex, depth
+					if buffer[position] != rune('\'') {
+						goto l407
+					}
+					position++
+					{
+						position408 := position
+						depth++
+					l409:
+						{
+							position410, tokenIndex410, depth410 := position, tokenIndex, depth
+							{
+								position411, tokenIndex411, depth411 := position, tokenIndex, depth
+								if buffer[position] != rune('\'') {
+									goto l411
+								}
+								position++
+								goto l410
+							l411:
+								position, tokenIndex, depth = position411, tokenIndex411, depth411
+							}
+							if !matchDot() {
+								goto l410
+							}
+							goto l409
+						l410:
+							position, tokenIndex, depth = position410, tokenIndex410, depth410
 						}
 						depth--
-						add(rulePegText, position171)
+						add(rulePegText, position408)
 					}
-					{
-						add(ruleAction30, position)
+					if buffer[position] != rune('\'') {
+						goto l407
 					}
-					depth--
-					add(ruleOrderSelector, position170)
-				}
-				{
-					add(ruleAction28, position)
-				}
-				{
-					position176, tokenIndex176, depth176 := position, tokenIndex, depth
-					if !_rules[ruleWS]() {
-						goto l176
+					position++
+					{
+						add(ruleAction57, position)
 					}
+					goto l406
+				l407:
+					position, tokenIndex, depth = position406, tokenIndex406, depth406
 					{
-						position178 := position
+						position413 := position
 						depth++
 						{
-							position179 := position
-							depth++
+							switch buffer[position] {
+							case '.':
+								if buffer[position] != rune('.') {
+									goto l404
+								}
+								position++
+								break
+							case '/':
+								if buffer[position] != rune('/') {
+									goto l404
+								}
+								position++
+								break
+							case '_':
+								if buffer[position] != rune('_') {
+									goto l404
+								}
+								position++
+								break
+							case ':':
+								if buffer[position] != rune(':') {
+									goto l404
+								}
+								position++
+								break
+							case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+								if c := buffer[position]; c < rune('0') || c > rune('9') {
+									goto l404
+								}
+								position++
+								break
+							case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+								if c := buffer[position]; c < rune('A') || c > rune('Z') {
+									goto l404
+								}
+								position++
+								break
+							case '-':
+								if buffer[position] != rune('-') {
+									goto l404
+								}
+								position++
+								break
+							default:
+								if c := buffer[position]; c < rune('a') || c > rune('z') {
+									goto l404
+								}
+								position++
+								break
+							}
+						}
+
+					l414:
+						{
+							position415, tokenIndex415, depth415 := position, tokenIndex, depth
 							{
-								position180 := position
-								depth++
-								{
-									position181, tokenIndex181, depth181 := position, tokenIndex, depth
-									if buffer[position] != rune('A') {
-										goto l182
+								switch buffer[position] {
+								case '.':
+									if buffer[position] != rune('.') {
+										goto l415
 									}
 									position++
-									if buffer[position] != rune('S') {
-										goto l182
+									break
+								case '/':
+									if buffer[position] != rune('/') {
+										goto l415
 									}
 									position++
-									if buffer[position] != rune('C') {
-										goto l182
+									break
+								case '_':
+									if buffer[position] != rune('_') {
+										goto l415
 									}
 									position++
-									goto l181
-								l182:
-									position, tokenIndex, depth = position181, tokenIndex181, depth181
-									if buffer[position] != rune('D') {
-										goto l176
+									break
+								case ':':
+									if buffer[position] != rune(':') {
+										goto l415
 									}
 									position++
-									if buffer[position] != rune('E') {
-										goto l176
+									break
+								case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+									if c := buffer[position]; c < rune('0') || c > rune('9') {
+										goto l415
 									}
 									position++
-									if buffer[position] != rune('S') {
-										goto l176
+									break
+								case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+									if c := buffer[position]; c < rune('A') || c > rune('Z') {
+										goto l415
 									}
 									position++
-									if buffer[position] != rune('C') {
-										goto l176
+									break
+								case '-':
+									if buffer[position] != rune('-') {
+										goto l415
+									}
+									position++
+									break
+								default:
+									if c := buffer[position]; c < rune('a') || c > rune('z') {
+										goto l415
 									}
 									position++
+									break
 								}
-							l181:
-								depth--
-								add(ruleOrderDirOp, position180)
 							}
-							depth--
-							add(rulePegText, position179)
-						}
-						{
-							add(ruleAction31, position)
+
+							goto l414
+						l415:
+							position, tokenIndex, depth = position415, tokenIndex415, depth415
 						}
 						depth--
-						add(ruleOrderDir, position178)
+						add(rulePegText, position413)
 					}
 					{
-						add(ruleAction29, position)
+						add(ruleAction58, position)
 					}
-					goto l177
-				l176:
-					position, tokenIndex, depth = position176, tokenIndex176, depth176
 				}
-			l177:
+			l406:
 				depth--
-				add(ruleOrderSelectorSpec, position169)
+				add(ruleDataValue, position405)
 			}
 			return true
-		l168:
-			position, tokenIndex, depth = position168, tokenIndex168, depth168
+		l404:
+			position, tokenIndex, depth = position404, tokenIndex404, depth404
 			return false
 		},
-		/* 36 OrderSelector <- <(<OrderSelectorOp> Action30)> */
-		nil,
-		/* 37 OrderSelectorOp <- <((&('c') ('c' 'o' 'u' 'n' 't' 'e' 'r')) | (&('t') ('t' 'i' 'm' 'e' 's' 't' 'a' 'm' 'p')) | (&('s') ('s' 'o' 'u' 'r' 'c' 'e')) | (&('p') ('p' 'u' 'b' 'l' 'i' 's' 'h' 'e' 'r')) | (&('n') ('n' 'a' 'm' 'e' 's' 'p' 'a' 'c' 'e')) | (&('i') ('i' 'd')))> */
+		/* 71 StatementIdPattern <- <(('\'' <((&('_') '_') | (&('%') '%') | (&(':') ':') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))+> '\'') / <((&('_') '_') | (&('%') '%') | (&(':') ':') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))+>)> */
 		nil,
-		/* 38 OrderDir <- <(<OrderDirOp> Action31)> */
+		/* 72 WKIPattern <- <(('\'' <((&('%') '%') | (&('.') '.') | (&('/') '/') | (&('_') '_') | (&(':') ':') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('-') '-') | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))+> '\'') / <((&('%') '%') | (&('.') '.') | (&('/') '/') | (&('_') '_') | (&(':') ':') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('-') '-') | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))+>)> */
 		nil,
-		/* 39 OrderDirOp <- <(('A' 'S' 'C') / ('D' 'E' 'S' 'C'))> */
+		/* 73 StatementIdList <- <(StatementId Action59 (WSX ',' WSX StatementId Action60)*)> */
 		nil,
-		/* 40 Limit <- <('L' 'I' 'M' 'I' 'T' WS UInt Action32)> */
+		/* 74 PublisherIdList <- <(PublisherId Action61 (WSX ',' WSX PublisherId Action62)*)> */
 		func() bool {
-			position189, tokenIndex189, depth189 := position, tokenIndex, depth
+			position422, tokenIndex422, depth422 := position, tokenIndex, depth
 			{
-				position190 := position
+				position423 := position
 				depth++
-				if buffer[position] != rune('L') {
-					goto l189
-				}
-				position++
-				if buffer[position] != rune('I') {
-					goto l189
-				}
-				position++
-				if buffer[position] != rune('M') {
-					goto l189
-				}
-				position++
-				if buffer[position] != rune('I') {
-					goto l189
-				}
-				position++
-				if buffer[position] != rune('T') {
-					goto l189
-				}
-				position++
-				if !_rules[ruleWS]() {
-					goto l189
+				if !_rules[rulePublisherId]() {
+					goto l422
 				}
-				if !_rules[ruleUInt]() {
-					goto l189
+				{
+					add(ruleAction61, position)
 				}
+			l425:
 				{
-					add(ruleAction32, position)
+					position426, tokenIndex426, depth426 := position, tokenIndex, depth
+					if !_rules[ruleWSX]() {
+						goto l426
+					}
+					if buffer[position] != rune(',') {
+						goto l426
+					}
+					position++
+					if !_rules[ruleWSX]() {
+						goto l426
+					}
+					if !_rules[rulePublisherId]() {
+						goto l426
+					}
+					{
+						add(ruleAction62, position)
+					}
+					goto l425
+				l426:
+					position, tokenIndex, depth = position426, tokenIndex426, depth426
 				}
 				depth--
-				add(ruleLimit, position190)
+				add(rulePublisherIdList, position423)
 			}
 			return true
-		l189:
-			position, tokenIndex, depth = position189, tokenIndex189, depth189
+		l422:
+			position, tokenIndex, depth = position422, tokenIndex422, depth422
 			return false
 		},
-		/* 41 StatementId <- <<((&(':') ':') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))+>> */
+		/* 75 WKIList <- <(WKI Action63 (WSX ',' WSX WKI Action64)*)> */
+		nil,
+		/* 76 TagList <- <(Tag Action65 (WSX ',' WSX Tag Action66)*)> */
+		nil,
+		/* 77 ObjectIdList <- <(ObjectId Action67 (WSX ',' WSX ObjectId Action68)*)> */
+		nil,
+		/* 78 DataValueList <- <(DataValueItem (WSX ',' WSX DataValueItem)*)> */
 		nil,
-		/* 42 PublisherId <- <<((&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))+>> */
+		/* 79 DataValueItem <- <(('\'' <(!'\'' .)*> '\'' Action69) / (<((&('.') '.') | (&('/') '/') | (&('_') '_') | (&(':') ':') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('-') '-') | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))+> Action70))> */
 		func() bool {
-			position193, tokenIndex193, depth193 := position, tokenIndex, depth
+			position432, tokenIndex432, depth432 := position, tokenIndex, depth
 			{
-				position194 := position
+				position433 := position
 				depth++
 				{
-					position195 := position
-					depth++
+					position434, tokenIndex434, depth434 := position, tokenIndex, depth
+					if buffer[position] != rune('\'') {
+						goto l435
+					}
+					position++
 					{
-						switch buffer[position] {
-						case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
-							if c := buffer[position]; c < rune('0') || c > rune('9') {
-								goto l193
-							}
-							position++
-							break
-						case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
-							if c := buffer[position]; c < rune('A') || c > rune('Z') {
-								goto l193
+						position436 := position
+						depth++
+					l437:
+						{
+							position438, tokenIndex438, depth438 := position, tokenIndex, depth
+							{
+								position439, tokenIndex439, depth439 := position, tokenIndex, depth
+								if buffer[position] != rune('\'') {
+									goto l439
+								}
+								position++
+								goto l438
+							l439:
+								position, tokenIndex, depth = position439, tokenIndex439, depth439
 							}
-							position++
-							break
-						default:
-							if c := buffer[position]; c < rune('a') || c > rune('z') {
-								goto l193
+							if !matchDot() {
+								goto l438
 							}
-							position++
-							break
+							goto l437
+						l438:
+							position, tokenIndex, depth = position438, tokenIndex438, depth438
 						}
+						depth--
+						add(rulePegText, position436)
 					}
-
-				l196:
+					if buffer[position] != rune('\'') {
+						goto l435
+					}
+					position++
+					{
+						add(ruleAction69, position)
+					}
+					goto l434
+				l435:
+					position, tokenIndex, depth = position434, tokenIndex434, depth434
 					{
-						position197, tokenIndex197, depth197 := position, tokenIndex, depth
+						position441 := position
+						depth++
 						{
 							switch buffer[position] {
+							case '.':
+								if buffer[position] != rune('.') {
+									goto l432
+								}
+								position++
+								break
+							case '/':
+								if buffer[position] != rune('/') {
+									goto l432
+								}
+								position++
+								break
+							case '_':
+								if buffer[position] != rune('_') {
+									goto l432
+								}
+								position++
+								break
+							case ':':
+								if buffer[position] != rune(':') {
+									goto l432
+								}
+								position++
+								break
 							case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
 								if c := buffer[position]; c < rune('0') || c > rune('9') {
-									goto l197
+									goto l432
 								}
 								position++
 								break
 							case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
 								if c := buffer[position]; c < rune('A') || c > rune('Z') {
-									goto l197
+									goto l432
+								}
+								position++
+								break
+							case '-':
+								if buffer[position] != rune('-') {
+									goto l432
 								}
 								position++
 								break
 							default:
 								if c := buffer[position]; c < rune('a') || c > rune('z') {
-									goto l197
+									goto l432
 								}
 								position++
 								break
 							}
 						}
 
-						goto l196
-					l197:
-						position, tokenIndex, depth = position197, tokenIndex197, depth197
-					}
-					depth--
-					add(rulePegText, position195)
-				}
-				depth--
-				add(rulePublisherId, position194)
-			}
-			return true
-		l193:
-			position, tokenIndex, depth = position193, tokenIndex193, depth193
-			return false
-		},
-		/* 43 WKI <- <<((&('.') '.') | (&('/') '/') | (&('_') '_') | (&(':') ':') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('-') '-') | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))+>> */
-		nil,
-		/* 44 UInt <- <<[0-9]+>> */
-		func() bool {
-			position201, tokenIndex201, depth201 := position, tokenIndex, depth
-			{
-				position202 := position
-				depth++
-				{
-					position203 := position
-					depth++
-					if c := buffer[position]; c < rune('0') || c > rune('9') {
-						goto l201
+					l442:
+						{
+							position443, tokenIndex443, depth443 := position, tokenIndex, depth
+							{
+								switch buffer[position] {
+								case '.':
+									if buffer[position] != rune('.') {
+										goto l443
+									}
+									position++
+									break
+								case '/':
+									if buffer[position] != rune('/') {
+										goto l443
+									}
+									position++
+									break
+								case '_':
+									if buffer[position] != rune('_') {
+										goto l443
+									}
+									position++
+									break
+								case ':':
+									if buffer[position] != rune(':') {
+										goto l443
+									}
+									position++
+									break
+								case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+									if c := buffer[position]; c < rune('0') || c > rune('9') {
+										goto l443
+									}
+									position++
+									break
+								case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+									if c := buffer[position]; c < rune('A') || c > rune('Z') {
+										goto l443
+									}
+									position++
+									break
+								case '-':
+									if buffer[position] != rune('-') {
+										goto l443
+									}
+									position++
+									break
+								default:
+									if c := buffer[position]; c < rune('a') || c > rune('z') {
+										goto l443
+									}
+									position++
+									break
+								}
+							}
+
+							goto l442
+						l443:
+							position, tokenIndex, depth = position443, tokenIndex443, depth443
+						}
+						depth--
+						add(rulePegText, position441)
 					}
-					position++
-				l204:
 					{
-						position205, tokenIndex205, depth205 := position, tokenIndex, depth
-						if c := buffer[position]; c < rune('0') || c > rune('9') {
-							goto l205
-						}
-						position++
-						goto l204
-					l205:
-						position, tokenIndex, depth = position205, tokenIndex205, depth205
+						add(ruleAction70, position)
 					}
-					depth--
-					add(rulePegText, position203)
 				}
+			l434:
 				depth--
-				add(ruleUInt, position202)
+				add(ruleDataValueItem, position433)
 			}
 			return true
-		l201:
-			position, tokenIndex, depth = position201, tokenIndex201, depth201
+		l432:
+			position, tokenIndex, depth = position432, tokenIndex432, depth432
 			return false
 		},
-		/* 45 WS <- <WhiteSpace+> */
+		/* 80 WS <- <WhiteSpace+> */
 		func() bool {
-			position206, tokenIndex206, depth206 := position, tokenIndex, depth
+			position447, tokenIndex447, depth447 := position, tokenIndex, depth
 			{
-				position207 := position
+				position448 := position
 				depth++
 				if !_rules[ruleWhiteSpace]() {
-					goto l206
+					goto l447
 				}
-			l208:
+			l449:
 				{
-					position209, tokenIndex209, depth209 := position, tokenIndex, depth
+					position450, tokenIndex450, depth450 := position, tokenIndex, depth
 					if !_rules[ruleWhiteSpace]() {
-						goto l209
+						goto l450
 					}
-					goto l208
-				l209:
-					position, tokenIndex, depth = position209, tokenIndex209, depth209
+					goto l449
+				l450:
+					position, tokenIndex, depth = position450, tokenIndex450, depth450
 				}
 				depth--
-				add(ruleWS, position207)
+				add(ruleWS, position448)
 			}
 			return true
-		l206:
-			position, tokenIndex, depth = position206, tokenIndex206, depth206
+		l447:
+			position, tokenIndex, depth = position447, tokenIndex447, depth447
 			return false
 		},
-		/* 46 WSX <- <WhiteSpace*> */
+		/* 81 WSX <- <WhiteSpace*> */
 		func() bool {
 			{
-				position211 := position
+				position452 := position
 				depth++
-			l212:
+			l453:
 				{
-					position213, tokenIndex213, depth213 := position, tokenIndex, depth
+					position454, tokenIndex454, depth454 := position, tokenIndex, depth
 					if !_rules[ruleWhiteSpace]() {
-						goto l213
+						goto l454
 					}
-					goto l212
-				l213:
-					position, tokenIndex, depth = position213, tokenIndex213, depth213
+					goto l453
+				l454:
+					position, tokenIndex, depth = position454, tokenIndex454, depth454
 				}
 				depth--
-				add(ruleWSX, position211)
+				add(ruleWSX, position452)
 			}
 			return true
 		},
-		/* 47 WhiteSpace <- <((&('\t') '\t') | (&(' ') ' ') | (&('\n' | '\r') EOL))> */
+		/* 82 WhiteSpace <- <((&('\t') '\t') | (&(' ') ' ') | (&('\n' | '\r') EOL))> */
 		func() bool {
-			position214, tokenIndex214, depth214 := position, tokenIndex, depth
+			position455, tokenIndex455, depth455 := position, tokenIndex, depth
 			{
-				position215 := position
+				position456 := position
 				depth++
 				{
 					switch buffer[position] {
 					case '\t':
 						if buffer[position] != rune('\t') {
-							goto l214
+							goto l455
 						}
 						position++
 						break
 					case ' ':
 						if buffer[position] != rune(' ') {
-							goto l214
+							goto l455
 						}
 						position++
 						break
 					default:
 						{
-							position217 := position
+							position458 := position
 							depth++
 							{
-								position218, tokenIndex218, depth218 := position, tokenIndex, depth
+								position459, tokenIndex459, depth459 := position, tokenIndex, depth
 								if buffer[position] != rune('\r') {
-									goto l219
+									goto l460
 								}
 								position++
 								if buffer[position] != rune('\n') {
-									goto l219
+									goto l460
 								}
 								position++
-								goto l218
-							l219:
-								position, tokenIndex, depth = position218, tokenIndex218, depth218
+								goto l459
+							l460:
+								position, tokenIndex, depth = position459, tokenIndex459, depth459
 								if buffer[position] != rune('\n') {
-									goto l220
+									goto l461
 								}
 								position++
-								goto l218
-							l220:
-								position, tokenIndex, depth = position218, tokenIndex218, depth218
+								goto l459
+							l461:
+								position, tokenIndex, depth = position459, tokenIndex459, depth459
 								if buffer[position] != rune('\r') {
-									goto l214
+									goto l455
 								}
 								position++
 							}
-						l218:
+						l459:
 							depth--
-							add(ruleEOL, position217)
+							add(ruleEOL, position458)
 						}
 						break
 					}
 				}
 
 				depth--
-				add(ruleWhiteSpace, position215)
+				add(ruleWhiteSpace, position456)
 			}
 			return true
-		l214:
-			position, tokenIndex, depth = position214, tokenIndex214, depth214
+		l455:
+			position, tokenIndex, depth = position455, tokenIndex455, depth455
 			return false
 		},
-		/* 48 EOL <- <(('\r' '\n') / '\n' / '\r')> */
+		/* 83 EOL <- <(('\r' '\n') / '\n' / '\r')> */
 		nil,
-		/* 49 EOF <- <!.> */
+		/* 84 EOF <- <!.> */
 		func() bool {
-			position222, tokenIndex222, depth222 := position, tokenIndex, depth
+			position463, tokenIndex463, depth463 := position, tokenIndex, depth
 			{
-				position223 := position
+				position464 := position
 				depth++
 				{
-					position224, tokenIndex224, depth224 := position, tokenIndex, depth
+					position465, tokenIndex465, depth465 := position, tokenIndex, depth
 					if !matchDot() {
-						goto l224
+						goto l465
 					}
-					goto l222
-				l224:
-					position, tokenIndex, depth = position224, tokenIndex224, depth224
+					goto l463
+				l465:
+					position, tokenIndex, depth = position465, tokenIndex465, depth465
 				}
 				depth--
-				add(ruleEOF, position223)
+				add(ruleEOF, position464)
 			}
 			return true
-		l222:
-			position, tokenIndex, depth = position222, tokenIndex222, depth222
+		l463:
+			position, tokenIndex, depth = position463, tokenIndex463, depth463
 			return false
 		},
-		/* 51 Action0 <- <{ p.setSelectOp() }> */
+		/* 86 Action0 <- <{ p.setSelectOp() }> */
+		nil,
+		/* 87 Action1 <- <{ p.setDeleteOp() }> */
+		nil,
+		/* 88 Action2 <- <{ p.setExplainOp() }> */
+		nil,
+		/* 89 Action3 <- <{ p.setDistinct() }> */
+		nil,
+		/* 90 Action4 <- <{ p.setSimpleSelector() }> */
+		nil,
+		/* 91 Action5 <- <{ p.setCompoundSelector() }> */
+		nil,
+		/* 92 Action6 <- <{ p.setFunctionSelector() }> */
+		nil,
+		nil,
+		/* 94 Action7 <- <{ p.push(text) }> */
+		nil,
+		/* 95 Action8 <- <{ p.addFunctionSelector() }> */
+		nil,
+		/* 96 Action9 <- <{ p.push(text) }> */
+		nil,
+		/* 97 Action10 <- <{ p.push(text) }> */
+		nil,
+		/* 98 Action11 <- <{ p.setNamespace(text) }> */
+		nil,
+		/* 99 Action12 <- <{ p.setCriteria() }> */
+		nil,
+		/* 100 Action13 <- <{ p.addCompoundCriteria() }> */
+		nil,
+		/* 101 Action14 <- <{ p.addNegatedCriteria() }> */
+		nil,
+		/* 102 Action15 <- <{ p.addValueCriteria() }> */
+		nil,
+		/* 103 Action16 <- <{ p.addRangeCriteria() }> */
+		nil,
+		/* 104 Action17 <- <{ p.addIndexCriteria() }> */
+		nil,
+		/* 105 Action18 <- <{ p.addDataCriteria() }> */
+		nil,
+		/* 106 Action19 <- <{ p.addFieldCriteria() }> */
+		nil,
+		/* 107 Action20 <- <{ p.addTextCriteria() }> */
+		nil,
+		/* 108 Action21 <- <{ p.addSupersedeCriteria() }> */
+		nil,
+		/* 109 Action22 <- <{ p.push(text) }> */
+		nil,
+		/* 110 Action23 <- <{ p.push(text) }> */
+		nil,
+		/* 111 Action24 <- <{ p.push(text) }> */
+		nil,
+		/* 112 Action25 <- <{ p.push(text) }> */
+		nil,
+		/* 113 Action26 <- <{ p.push(text) }> */
+		nil,
+		/* 114 Action27 <- <{ p.push(text) }> */
+		nil,
+		/* 115 Action28 <- <{ p.push(text) }> */
+		nil,
+		/* 116 Action29 <- <{ p.push(text) }> */
+		nil,
+		/* 117 Action30 <- <{ p.pushValueList(text) }> */
+		nil,
+		/* 118 Action31 <- <{ p.push(text) }> */
+		nil,
+		/* 119 Action32 <- <{ p.push(text) }> */
+		nil,
+		/* 120 Action33 <- <{ p.push(text) }> */
+		nil,
+		/* 121 Action34 <- <{ p.push(text) }> */
+		nil,
+		/* 122 Action35 <- <{ p.push(text) }> */
+		nil,
+		/* 123 Action36 <- <{ p.push(text) }> */
+		nil,
+		/* 124 Action37 <- <{ p.push(text) }> */
 		nil,
-		/* 52 Action1 <- <{ p.setDeleteOp() }> */
+		/* 125 Action38 <- <{ p.push(text) }> */
 		nil,
-		/* 53 Action2 <- <{ p.setSimpleSelector() }> */
+		/* 126 Action39 <- <{ p.push(text) }> */
 		nil,
-		/* 54 Action3 <- <{ p.setCompoundSelector() }> */
+		/* 127 Action40 <- <{ p.push(text) }> */
 		nil,
-		/* 55 Action4 <- <{ p.setFunctionSelector() }> */
+		/* 128 Action41 <- <{ p.push(text) }> */
 		nil,
+		/* 129 Action42 <- <{ p.push(text) }> */
 		nil,
-		/* 57 Action5 <- <{ p.push(text) }> */
+		/* 130 Action43 <- <{ p.push(text) }> */
 		nil,
-		/* 58 Action6 <- <{ p.push(text) }> */
+		/* 131 Action44 <- <{ p.push(text) }> */
 		nil,
-		/* 59 Action7 <- <{ p.setNamespace(text) }> */
+		/* 132 Action45 <- <{ p.push(text) }> */
 		nil,
-		/* 60 Action8 <- <{ p.setCriteria() }> */
+		/* 133 Action46 <- <{ p.push(text) }> */
 		nil,
-		/* 61 Action9 <- <{ p.addCompoundCriteria() }> */
+		/* 134 Action47 <- <{ p.push(text) }> */
 		nil,
-		/* 62 Action10 <- <{ p.addNegatedCriteria() }> */
+		/* 135 Action48 <- <{ p.setGroup() }> */
 		nil,
-		/* 63 Action11 <- <{ p.addValueCriteria() }> */
+		/* 136 Action49 <- <{ p.push(text) }> */
 		nil,
-		/* 64 Action12 <- <{ p.addRangeCriteria() }> */
+		/* 137 Action50 <- <{ p.setOrder() }> */
 		nil,
-		/* 65 Action13 <- <{ p.addIndexCriteria() }> */
+		/* 138 Action51 <- <{ p.addOrderSelector() }> */
 		nil,
-		/* 66 Action14 <- <{ p.push(text) }> */
+		/* 139 Action52 <- <{ p.setOrderDir() }> */
 		nil,
-		/* 67 Action15 <- <{ p.push(text) }> */
+		/* 140 Action53 <- <{ p.push(text) }> */
 		nil,
-		/* 68 Action16 <- <{ p.push(text) }> */
+		/* 141 Action54 <- <{ p.push(text) }> */
 		nil,
-		/* 69 Action17 <- <{ p.push(text) }> */
+		/* 142 Action55 <- <{ p.setLimit(text) }> */
 		nil,
-		/* 70 Action18 <- <{ p.push(text) }> */
+		/* 143 Action56 <- <{ p.setOffset(text) }> */
 		nil,
-		/* 71 Action19 <- <{ p.push(text) }> */
+		/* 144 Action57 <- <{ p.push(text) }> */
 		nil,
-		/* 72 Action20 <- <{ p.push(text) }> */
+		/* 145 Action58 <- <{ p.push(text) }> */
 		nil,
-		/* 73 Action21 <- <{ p.push(text) }> */
+		/* 146 Action59 <- <{ p.addListValue(text) }> */
 		nil,
-		/* 74 Action22 <- <{ p.push(text) }> */
+		/* 147 Action60 <- <{ p.addListValue(text) }> */
 		nil,
-		/* 75 Action23 <- <{ p.push(text) }> */
+		/* 148 Action61 <- <{ p.addListValue(text) }> */
 		nil,
-		/* 76 Action24 <- <{ p.push(text) }> */
+		/* 149 Action62 <- <{ p.addListValue(text) }> */
 		nil,
-		/* 77 Action25 <- <{ p.push(text) }> */
+		/* 150 Action63 <- <{ p.addListValue(text) }> */
 		nil,
-		/* 78 Action26 <- <{ p.push(text) }> */
+		/* 151 Action64 <- <{ p.addListValue(text) }> */
 		nil,
-		/* 79 Action27 <- <{ p.setOrder() }> */
+		/* 152 Action65 <- <{ p.addListValue(text) }> */
 		nil,
-		/* 80 Action28 <- <{ p.addOrderSelector() }> */
+		/* 153 Action66 <- <{ p.addListValue(text) }> */
 		nil,
-		/* 81 Action29 <- <{ p.setOrderDir() }> */
+		/* 154 Action67 <- <{ p.addListValue(text) }> */
 		nil,
-		/* 82 Action30 <- <{ p.push(text) }> */
+		/* 155 Action68 <- <{ p.addListValue(text) }> */
 		nil,
-		/* 83 Action31 <- <{ p.push(text) }> */
+		/* 156 Action69 <- <{ p.addListValue(text) }> */
 		nil,
-		/* 84 Action32 <- <{ p.setLimit(text) }> */
+		/* 157 Action70 <- <{ p.addListValue(text) }> */
 		nil,
 	}
 	p.rules = _rules
@@ -6,6 +6,7 @@ import (
 	_ "github.com/mattn/go-sqlite3"
 	pb "github.com/mediachain/concat/proto"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -33,6 +34,15 @@ var simpleq []string = []string{
 	"SELECT MIN(counter) FROM foo.bar",
 	"SELECT MAX(counter) FROM foo.bar",
 	"SELECT (id, namespace, publisher) FROM *",
+	"SELECT (COUNT(*), MAX(counter)) FROM foo.bar",
+	"SELECT DISTINCT publisher FROM foo.bar",
+	"SELECT DISTINCT (namespace, publisher) FROM *",
+	"SELECT COUNT(DISTINCT publisher) FROM *",
+	"SELECT (namespace, COUNT(DISTINCT publisher)) FROM * GROUP BY namespace",
+	"SELECT (namespace, COUNT(*)) FROM * GROUP BY namespace",
+	"SELECT (publisher, COUNT(*), MIN(timestamp), MAX(timestamp)) FROM foo.* GROUP BY publisher",
+	"SELECT (namespace, publisher, COUNT(id)) FROM * GROUP BY namespace, publisher",
+	"SELECT (source, COUNT(*)) FROM * WHERE timestamp > 1474000000 GROUP BY source ORDER BY source LIMIT 10",
 	"SELECT * FROM foo.bar.*",
 	"SELECT * FROM foo.bar-baz-with-dashes",
 	"SELECT * FROM foo.bar WHERE id = abc",
@@ -65,6 +75,31 @@ var simpleq []string = []string{
 	"SELECT * FROM foo.bar WHERE publisher = abc LIMIT 10",
 	"SELECT * FROM foo.bar WHERE wki = mywki:abc",
 	"SELECT * FROM foo.bar WHERE wki = mywki:abc-defg_123-ABC/xyz.XYZ",
+	"SELECT * FROM foo.bar WHERE tag = cc-by",
+	"SELECT * FROM foo.bar WHERE tag = license:cc-by_4.0/international",
+	"SELECT * FROM foo.bar WHERE dep = QmAAA",
+	"SELECT * FROM foo.bar WHERE tag = cc-by AND dep = QmAAA",
+	"SELECT * FROM foo.bar WHERE wki = mywki:abc OR tag = cc-by",
+	"SELECT * FROM foo.bar WHERE id IN (abc)",
+	"SELECT * FROM foo.bar WHERE id IN (abc, def,ghi)",
+	"SELECT * FROM foo.bar WHERE publisher IN ( abc, def )",
+	"SELECT * FROM foo.bar WHERE source IN (abc, def) AND timestamp > 1474000000",
+	"SELECT * FROM foo.bar WHERE wki IN (mywki:abc, mywki:abc-defg_123-ABC/xyz.XYZ)",
+	"SELECT * FROM foo.bar WHERE tag IN (cc-by, cc-0)",
+	"SELECT * FROM foo.bar WHERE dep IN (QmAAA, QmBBB)",
+	"SELECT * FROM foo.bar WHERE tag = 'cc-by'",
+	"SELECT * FROM foo.bar WHERE dep = 'QmAAA'",
+	"SELECT * FROM foo.bar WHERE tag IN ('cc-by', cc-0)",
+	"SELECT * FROM foo.bar WHERE dep IN ('QmAAA', 'QmBBB')",
+	"SELECT * FROM foo.bar WHERE NOT wki IN (abc, def)",
+	"SELECT * FROM foo.bar WHERE wki PREFIX dpla_",
+	"SELECT * FROM foo.bar WHERE wki LIKE dpla_%",
+	"SELECT * FROM foo.bar WHERE wki LIKE %:abc/%.XYZ",
+	"SELECT * FROM foo.bar WHERE wki PREFIX 'dpla_'",
+	"SELECT * FROM foo.bar WHERE wki LIKE 'dpla_%'",
+	"SELECT * FROM foo.bar WHERE id LIKE '4XTTM%'",
+	"SELECT * FROM foo.bar WHERE id PREFIX 4XTTM",
+	"SELECT * FROM foo.bar WHERE id LIKE 4XTTM%:_23",
 	"SELECT * FROM foo.bar LIMIT 10",
 	"SELECT * FROM * WHERE id = abc",
 	"SELECT * FROM * ORDER BY id",
@@ -82,6 +117,9 @@ var simpleq []string = []string{
 	"SELECT * FROM * WHERE timestamp > 1474000000 ORDER BY counter",
 	"SELECT * FROM * ORDER BY counter LIMIT 10",
 	"SELECT * FROM * WHERE timestamp > 1474000000 ORDER BY counter LIMIT 10",
+	"SELECT * FROM * ORDER BY counter LIMIT 10 OFFSET 20",
+	"SELECT * FROM * ORDER BY counter OFFSET 20",
+	"SELECT * FROM * WHERE counter > 100 ORDER BY counter LIMIT 10",
 }
 
 var delq []string = []string{
@@ -120,6 +158,33 @@ var delq []string = []string{
 	"DELETE FROM * WHERE id = abc LIMIT 10",
 }
 
+var fieldq []string = []string{
+	"SELECT * FROM * WHERE field.source_dataset = dpla",
+	"SELECT * FROM images.dpla WHERE field.source.name = 'DPLA Images'",
+	"SELECT id FROM images.dpla WHERE field.source_dataset IN (dpla, 'pd images')",
+	"SELECT COUNT(*) FROM images.dpla WHERE field.a = x AND (field.b = y OR NOT field.c = z)",
+	"SELECT * FROM images.dpla WHERE wki = abc AND field.a = x ORDER BY counter LIMIT 10",
+}
+
+var textq []string = []string{
+	"SELECT * FROM * WHERE text MATCH sunset",
+	"SELECT * FROM images.dpla WHERE text MATCH 'sunset beach'",
+	"SELECT id FROM images.dpla WHERE text MATCH '\"a page\" OR beach*'",
+	"SELECT COUNT(*) FROM images.dpla WHERE text MATCH sunset AND field.source = dpla",
+	"SELECT * FROM images.* WHERE NOT text MATCH sunset ORDER BY counter DESC LIMIT 10",
+}
+
+var dataq []string = []string{
+	"SELECT * FROM * WHERE data.title = foo",
+	"SELECT * FROM * WHERE data.source.name = 'dpla'",
+	"SELECT * FROM * WHERE data.title CONTAINS 'Page'",
+	"SELECT * FROM * WHERE data.title != 'A Page'",
+	"SELECT id FROM foo.* WHERE publisher = abc AND data.year = 1900",
+	"SELECT COUNT(*) FROM foo.bar WHERE data.keywords CONTAINS cat AND timestamp > 1474000000",
+	"SELECT * FROM foo.bar WHERE (data.a = x OR data.b = y) AND wki = abc ORDER BY counter LIMIT 10",
+	"SELECT * FROM foo.bar WHERE NOT data.a = x",
+}
+
 func checkError(t *testing.T, where string, err error) {
 	if err != nil {
 		t.Logf("QUERY: %s", where)
@@ -170,6 +235,36 @@ func TestQueryParse(t *testing.T) {
 	}
 }
 
+func TestQueryParsePattern(t *testing.T) {
+	// quoted patterns are equivalent to bare patterns
+	patq := [][2]string{
+		{"SELECT * FROM * WHERE wki LIKE 'dpla_%'", "SELECT * FROM * WHERE wki LIKE dpla_%"},
+		{"SELECT * FROM * WHERE wki PREFIX 'dpla_'", "SELECT * FROM * WHERE wki PREFIX dpla_"},
+		{"SELECT * FROM * WHERE id LIKE '4XTTM%:_23'", "SELECT * FROM * WHERE id LIKE 4XTTM%:_23"},
+		{"SELECT * FROM * WHERE id PREFIX '4XTTM'", "SELECT * FROM * WHERE id PREFIX 4XTTM"},
+	}
+
+	for _, pq := range patq {
+		q, err := ParseQuery(pq[0])
+		checkErrorNow(t, pq[0], err)
+		xq, err := ParseQuery(pq[1])
+		checkErrorNow(t, pq[1], err)
+		checkBool(t, pq[0], reflect.DeepEqual(q, xq))
+	}
+
+	// quoted patterns are still restricted to pattern characters
+	badq := []string{
+		"SELECT * FROM * WHERE wki LIKE 'dpla*'",
+		"SELECT * FROM * WHERE wki LIKE 'a' OR '1'",
+		"SELECT * FROM * WHERE wki PREFIX 'dpla",
+	}
+
+	for _, qs := range badq {
+		_, err := ParseQuery(qs)
+		checkBool(t, qs, err != nil)
+	}
+}
+
 func TestQueryParseDelete(t *testing.T) {
 	for _, qs := range delq {
 		q, err := ParseQuery(qs)
@@ -178,26 +273,324 @@ func TestQueryParseDelete(t *testing.T) {
 	}
 }
 
+func TestQueryParseExplain(t *testing.T) {
+	for _, qs := range simpleq {
+		q, err := ParseQuery(qs)
+		checkErrorNow(t, qs, err)
+		sqlq, _, err := CompileQuery(q)
+		checkErrorNow(t, qs, err)
+
+		xqs := "EXPLAIN " + qs
+		xq, err := ParseQuery(xqs)
+		checkErrorNow(t, xqs, err)
+		checkBool(t, xqs, xq.Op == OpExplain)
+		xsqlq, _, err := CompileQuery(xq)
+		checkErrorNow(t, xqs, err)
+		checkBool(t, xqs, sqlq == xsqlq)
+	}
+
+	for _, qs := range delq {
+		xqs := "EXPLAIN " + qs
+		_, err := ParseQuery(xqs)
+		checkBool(t, xqs, err != nil)
+	}
+}
+
+func TestQueryParseData(t *testing.T) {
+	for _, qs := range dataq {
+		q, err := ParseQuery(qs)
+		checkErrorNow(t, qs, err)
+		checkBool(t, qs, q.Op == OpSelect)
+		checkBool(t, qs, q.IsDataQuery())
+
+		// data queries don't compile directly
+		_, _, err = CompileQuery(q)
+		checkBool(t, qs, err != nil)
+
+		sq, _, err := DataQuery(q)
+		checkErrorNow(t, qs, err)
+		checkBool(t, qs, !sq.IsDataQuery())
+		_, _, err = CompileQuery(sq)
+		checkErrorNow(t, qs, err)
+
+		xq, err := q.WithDataIds([]string{"a", "b"})
+		checkErrorNow(t, qs, err)
+		checkBool(t, qs, !xq.IsDataQuery())
+		_, _, err = CompileQuery(xq)
+		checkErrorNow(t, qs, err)
+
+		// data queries without matching ids match nothing
+		xq, err = q.WithDataIds([]string{})
+		checkErrorNow(t, qs, err)
+		sqlq, _, err := CompileQueryDialect(xq, Postgres)
+		checkErrorNow(t, qs, err)
+		checkBool(t, qs, !strings.Contains(sqlq, "IN ()"))
+	}
+
+	for _, qs := range simpleq {
+		q, err := ParseQuery(qs)
+		checkErrorNow(t, qs, err)
+		checkBool(t, qs, !q.IsDataQuery())
+	}
+
+	// data criteria can only be conjoined with envelope criteria
+	qs := "SELECT * FROM * WHERE data.title = foo OR publisher = abc"
+	q, err := ParseQuery(qs)
+	checkErrorNow(t, qs, err)
+	_, _, err = DataQuery(q)
+	checkBool(t, qs, err != nil)
+
+	// statements are scanned in query order, and the limit applies across
+	// the batches of matching ids
+	qs = "SELECT * FROM foo.bar WHERE data.a = x ORDER BY counter LIMIT 10 OFFSET 5"
+	q, err = ParseQuery(qs)
+	checkErrorNow(t, qs, err)
+	sq, _, err := DataQuery(q)
+	checkErrorNow(t, qs, err)
+	sqlq, _, err := CompileQuery(sq)
+	checkErrorNow(t, qs, err)
+	checkBool(t, qs, strings.Contains(sqlq, "ORDER BY counter"))
+	checkBool(t, qs, !strings.Contains(sqlq, "LIMIT"))
+
+	xq, err := q.WithDataIds([]string{"a", "b"})
+	checkErrorNow(t, qs, err)
+	sqlq, _, err = CompileQuery(xq)
+	checkErrorNow(t, qs, err)
+	checkBool(t, qs, strings.Contains(sqlq, "ORDER BY counter"))
+	checkBool(t, qs, !strings.Contains(sqlq, "LIMIT"))
+	checkBool(t, qs, !strings.Contains(sqlq, "OFFSET"))
+	checkBool(t, qs, xq.IsCursorQuery())
+}
+
+func TestQueryDataRows(t *testing.T) {
+	// rows map to statements; the offset and limit apply to the ids
+	qs := "SELECT * FROM * WHERE data.a = x LIMIT 2 OFFSET 1"
+	q, err := ParseQuery(qs)
+	checkErrorNow(t, qs, err)
+	checkBool(t, qs, !q.IsAggregateSelect())
+
+	rows := NewDataRows(q)
+	checkBool(t, qs, !rows.AddId())
+	checkBool(t, qs, rows.AddId())
+	checkBool(t, qs, rows.AddRow("a"))
+	checkBool(t, qs, !rows.Done())
+	checkBool(t, qs, rows.AddId())
+	checkBool(t, qs, rows.AddRow("a"))
+	checkBool(t, qs, rows.Done())
+	checkBool(t, qs, !rows.AddId())
+
+	// distinct rows; the offset and limit apply to the rows
+	for _, qs := range []string{
+		"SELECT namespace FROM * WHERE data.a = x LIMIT 2 OFFSET 1",
+		"SELECT DISTINCT (namespace) FROM * WHERE data.a = x LIMIT 2 OFFSET 1"} {
+		q, err := ParseQuery(qs)
+		checkErrorNow(t, qs, err)
+		checkBool(t, qs, !q.IsAggregateSelect())
+
+		rows := NewDataRows(q)
+		checkBool(t, qs, rows.AddId())
+		checkBool(t, qs, !rows.AddRow("a"))
+		checkBool(t, qs, rows.AddId())
+		checkBool(t, qs, !rows.AddRow("a"))
+		checkBool(t, qs, rows.AddRow("b"))
+		checkBool(t, qs, !rows.AddRow("b"))
+		checkBool(t, qs, !rows.Done())
+		checkBool(t, qs, rows.AddRow("c"))
+		checkBool(t, qs, rows.Done())
+		checkBool(t, qs, !rows.AddRow("d"))
+	}
+}
+
+func TestQueryDataResultSet(t *testing.T) {
+	a := &pb.Statement{Id: "a", Publisher: "A", Namespace: "foo.a"}
+	b := &pb.Statement{Id: "b", Publisher: "B", Namespace: "foo.b"}
+	c := &pb.Statement{Id: "c", Publisher: "A", Namespace: "foo.c"}
+
+	qs := "SELECT COUNT(*) FROM * WHERE data.a = x"
+	q, err := ParseQuery(qs)
+	checkErrorNow(t, qs, err)
+	checkBool(t, qs, q.IsAggregateSelect())
+
+	rs, err := NewDataResultSet(q)
+	checkErrorNow(t, qs, err)
+	rs.Add(a)
+	rs.Add(b)
+	rs.Add(c)
+	res := rs.Result()
+	if checkResultLen(t, qs, res, 1) {
+		checkContains(t, qs, res, 3)
+	}
+
+	qs = "SELECT (publisher, COUNT(*)) FROM * WHERE data.a = x GROUP BY publisher"
+	q, err = ParseQuery(qs)
+	checkErrorNow(t, qs, err)
+	checkBool(t, qs, q.IsAggregateSelect())
+
+	rs, err = NewDataResultSet(q)
+	checkErrorNow(t, qs, err)
+	rs.Add(a)
+	rs.Add(b)
+	rs.Add(c)
+	res = rs.Result()
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, map[string]interface{}{"publisher": "A", "COUNT(*)": 2})
+		checkContains(t, qs, res, map[string]interface{}{"publisher": "B", "COUNT(*)": 1})
+	}
+}
+
+func TestQueryParseField(t *testing.T) {
+	for _, qs := range fieldq {
+		q, err := ParseQuery(qs)
+		checkErrorNow(t, qs, err)
+		checkBool(t, qs, q.Op == OpSelect)
+		checkBool(t, qs, !q.IsDataQuery())
+		sqlq, _, err := CompileQuery(q)
+		checkErrorNow(t, qs, err)
+		checkBool(t, qs, strings.Contains(sqlq, "IN (SELECT id FROM Fields WHERE path = "))
+	}
+
+	// field criteria are envelope criteria for data queries
+	qs := "SELECT * FROM * WHERE field.a = x AND data.b = y"
+	q, err := ParseQuery(qs)
+	checkErrorNow(t, qs, err)
+	sq, _, err := DataQuery(q)
+	checkErrorNow(t, qs, err)
+	_, _, err = CompileQuery(sq)
+	checkErrorNow(t, qs, err)
+}
+
+func TestQueryParseText(t *testing.T) {
+	for _, qs := range textq {
+		q, err := ParseQuery(qs)
+		checkErrorNow(t, qs, err)
+		checkBool(t, qs, q.Op == OpSelect)
+		sqlq, _, err := CompileQuery(q)
+		checkErrorNow(t, qs, err)
+		checkBool(t, qs, strings.Contains(sqlq, "IN (SELECT id FROM Text WHERE Text MATCH "))
+	}
+
+	// quotes are not allowed in text queries
+	qs := "SELECT * FROM * WHERE text MATCH 'it''s'"
+	_, err := ParseQuery(qs)
+	checkBool(t, qs, err != nil)
+}
+
+func TestQueryString(t *testing.T) {
+	qss := [][]string{simpleq, delq, fieldq, textq, dataq}
+	for _, qsl := range qss {
+		for _, qs := range qsl {
+			q, err := ParseQuery(qs)
+			checkErrorNow(t, qs, err)
+
+			xqs := q.String()
+			xq, err := ParseQuery(xqs)
+			checkErrorNow(t, xqs, err)
+			if !reflect.DeepEqual(q, xq) {
+				t.Errorf("Query doesn't round trip: %s => %s", qs, xqs)
+			}
+		}
+	}
+
+	for _, qs := range simpleq {
+		q, err := ParseQuery("EXPLAIN " + qs)
+		checkErrorNow(t, qs, err)
+
+		xqs := q.String()
+		xq, err := ParseQuery(xqs)
+		checkErrorNow(t, xqs, err)
+		checkBool(t, xqs, reflect.DeepEqual(q, xq))
+	}
+
+	qs := "SELECT * FROM foo.*"
+	q, err := ParseQuery(qs)
+	checkErrorNow(t, qs, err)
+
+	xqs := q.WithCounterCursor(10, 100).String()
+	checkBool(t, xqs, xqs == "SELECT * FROM foo.* WHERE counter > 10 ORDER BY counter ASC LIMIT 100")
+
+	xqs = q.WithNamespace("bar.baz").String()
+	checkBool(t, xqs, xqs == "SELECT * FROM bar.baz")
+}
+
+func TestQueryDataValues(t *testing.T) {
+	obj := map[string]interface{}{
+		"title":    "A Page of History",
+		"year":     uint64(1900),
+		"keywords": []interface{}{"cat", "dog", map[string]interface{}{}},
+		"source":   map[string]interface{}{"name": "dpla"},
+	}
+
+	checkValues := func(path string, xvals []string) {
+		vals := DataValues(obj, path)
+		checkBool(t, path, reflect.DeepEqual(vals, xvals))
+	}
+
+	checkValues("title", []string{"A Page of History"})
+	checkValues("year", []string{"1900"})
+	checkValues("keywords", []string{"cat", "dog"})
+	checkValues("source.name", []string{"dpla"})
+	checkValues("source", nil)
+	checkValues("missing", nil)
+}
+
+func TestQueryDataFilter(t *testing.T) {
+	obj := map[string]interface{}{
+		"title":    "A Page of History",
+		"year":     uint64(1900),
+		"keywords": []interface{}{"cat", "dog"},
+		"source": map[interface{}]interface{}{
+			"name": "dpla",
+			"url":  "http://dp.la/"},
+	}
+
+	checkFilter := func(qs string, xres bool) {
+		q, err := ParseQuery(qs)
+		checkErrorNow(t, qs, err)
+		_, filter, err := DataQuery(q)
+		checkErrorNow(t, qs, err)
+		checkBool(t, qs, filter(obj) == xres)
+	}
+
+	checkFilter("SELECT * FROM * WHERE data.title = 'A Page of History'", true)
+	checkFilter("SELECT * FROM * WHERE data.title = History", false)
+	checkFilter("SELECT * FROM * WHERE data.title CONTAINS 'Page'", true)
+	checkFilter("SELECT * FROM * WHERE data.title CONTAINS 'page'", false)
+	checkFilter("SELECT * FROM * WHERE data.title != 'A Page of History'", false)
+	checkFilter("SELECT * FROM * WHERE data.year = 1900", true)
+	checkFilter("SELECT * FROM * WHERE data.keywords CONTAINS cat", true)
+	checkFilter("SELECT * FROM * WHERE data.keywords CONTAINS ca", false)
+	checkFilter("SELECT * FROM * WHERE data.keywords = cat", false)
+	checkFilter("SELECT * FROM * WHERE data.source.name = dpla", true)
+	checkFilter("SELECT * FROM * WHERE data.source.name.first = dpla", false)
+	checkFilter("SELECT * FROM * WHERE data.source = dpla", false)
+	checkFilter("SELECT * FROM * WHERE data.missing = dpla", false)
+	checkFilter("SELECT * FROM * WHERE data.missing != dpla", true)
+	checkFilter("SELECT * FROM * WHERE data.year = 1900 AND data.source.name = dpla", true)
+	checkFilter("SELECT * FROM * WHERE data.year = 1901 OR data.source.name = dpla", true)
+	checkFilter("SELECT * FROM * WHERE NOT (data.year = 1901 OR data.source.name = dpla)", false)
+	checkFilter("SELECT * FROM * WHERE publisher = abc AND data.year = 1900", true)
+}
+
 func TestQueryEval(t *testing.T) {
 	a := &pb.Statement{
 		Id:        "a",
 		Publisher: "A",
 		Namespace: "foo.a",
-		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmAAA", Refs: []string{"aaa"}}}},
+		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmAAA", Refs: []string{"aaa"}, Tags: []string{"cc-by"}, Deps: []string{"QmDDD"}}}},
 		Timestamp: 100}
 
 	b := &pb.Statement{
 		Id:        "b",
 		Publisher: "B",
 		Namespace: "foo.b",
-		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmBBB", Refs: []string{"bbb"}}}},
+		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmBBB", Refs: []string{"bbb"}, Tags: []string{"cc-0"}}}},
 		Timestamp: 200}
 
 	c := &pb.Statement{
 		Id:        "c",
 		Publisher: "A",
 		Namespace: "bar.c",
-		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmCCC", Refs: []string{"ccc"}}}},
+		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmCCC", Refs: []string{"ccc"}, Tags: []string{"cc-by", "cc-sa"}, Deps: []string{"QmDDD", "QmEEE"}}}},
 		Timestamp: 300}
 
 	stmts := []*pb.Statement{a, b, c}
@@ -364,6 +757,84 @@ func TestQueryEval(t *testing.T) {
 		checkContains(t, qs, res, 1)
 	}
 
+	// check distinct
+	qs = "SELECT DISTINCT (publisher) FROM *"
+	res, err = parseEval(qs, stmts)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, map[string]interface{}{"publisher": "A"})
+		checkContains(t, qs, res, map[string]interface{}{"publisher": "B"})
+	}
+
+	qs = "SELECT DISTINCT publisher FROM *"
+	res, err = parseEval(qs, stmts)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, "A")
+		checkContains(t, qs, res, "B")
+	}
+
+	qs = "SELECT COUNT(DISTINCT publisher) FROM *"
+	res, err = parseEval(qs, stmts)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 1) {
+		checkContains(t, qs, res, 2)
+	}
+
+	// check offset -- order is unpredictable, so just check the count
+	qs = "SELECT (id) FROM * OFFSET 1"
+	res, err = parseEval(qs, stmts)
+	checkErrorNow(t, qs, err)
+	checkResultLen(t, qs, res, 2)
+
+	qs = "SELECT id FROM * LIMIT 1 OFFSET 1"
+	res, err = parseEval(qs, stmts)
+	checkErrorNow(t, qs, err)
+	checkResultLen(t, qs, res, 1)
+
+	// check grouping
+	qs = "SELECT (publisher, COUNT(*)) FROM * GROUP BY publisher"
+	res, err = parseEval(qs, stmts)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, map[string]interface{}{"publisher": "A", "COUNT(*)": 2})
+		checkContains(t, qs, res, map[string]interface{}{"publisher": "B", "COUNT(*)": 1})
+	}
+
+	qs = "SELECT (namespace, MIN(timestamp), MAX(timestamp)) FROM foo.* GROUP BY namespace"
+	res, err = parseEval(qs, stmts)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, map[string]interface{}{"namespace": "foo.a", "MIN(timestamp)": int64(100), "MAX(timestamp)": int64(100)})
+		checkContains(t, qs, res, map[string]interface{}{"namespace": "foo.b", "MIN(timestamp)": int64(200), "MAX(timestamp)": int64(200)})
+	}
+
+	qs = "SELECT (COUNT(*), MAX(timestamp)) FROM *"
+	res, err = parseEval(qs, stmts)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 1) {
+		checkContains(t, qs, res, map[string]interface{}{"COUNT(*)": 3, "MAX(timestamp)": int64(300)})
+	}
+
+	qs = "SELECT (publisher, COUNT(*)) FROM * GROUP BY publisher LIMIT 1"
+	res, err = parseEval(qs, stmts)
+	checkErrorNow(t, qs, err)
+	checkResultLen(t, qs, res, 1)
+
+	qs = "SELECT (id, COUNT(*)) FROM * GROUP BY publisher"
+	_, err = parseEval(qs, stmts)
+	checkBool(t, qs, err != nil)
+
+	qs = "SELECT COUNT(*) FROM * GROUP BY publisher"
+	_, err = parseEval(qs, stmts)
+	checkBool(t, qs, err != nil)
+
 	// check simple selection criteria
 	qs = "SELECT * FROM * WHERE id = a"
 	q, err = ParseQuery(qs)
@@ -590,71 +1061,225 @@ func TestQueryEval(t *testing.T) {
 		checkContains(t, qs, res, c)
 	}
 
-}
+	// check IN lists
+	qs = "SELECT * FROM * WHERE wki IN (aaa, ccc, zzz)"
+	res, err = parseEval(qs, stmts)
+	checkErrorNow(t, qs, err)
 
-func parseEval(qs string, stmts []*pb.Statement) ([]interface{}, error) {
-	q, err := ParseQuery(qs)
-	if err != nil {
-		return nil, err
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, a)
+		checkContains(t, qs, res, c)
 	}
 
-	res, err := EvalQuery(q, stmts)
-	return res, err
-}
+	qs = "SELECT * FROM * WHERE id IN (a, b)"
+	res, err = parseEval(qs, stmts)
+	checkErrorNow(t, qs, err)
 
-func TestQueryCompile(t *testing.T) {
-	for _, qs := range simpleq {
-		q, err := ParseQuery(qs)
-		checkErrorNow(t, qs, err)
-		_, _, err = CompileQuery(q)
-		checkErrorNow(t, qs, err)
-		//fmt.Printf("Compile %s -> %s\n", qs, sqlq)
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, a)
+		checkContains(t, qs, res, b)
 	}
-}
 
-func TestPBWTF(t *testing.T) {
-	a := &pb.Statement{
-		Id:        "a",
-		Publisher: "A",
-		Namespace: "foo.a",
-		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmAAA"}}},
-		Timestamp: 100}
+	qs = "SELECT * FROM * WHERE publisher IN (B, C)"
+	res, err = parseEval(qs, stmts)
+	checkErrorNow(t, qs, err)
 
-	bytes, err := ggproto.Marshal(a)
-	checkErrorNow(t, "Marshal", err)
+	if checkResultLen(t, qs, res, 1) {
+		checkContains(t, qs, res, b)
+	}
 
-	aa := new(pb.Statement)
-	err = ggproto.Unmarshal(bytes, aa)
-	checkErrorNow(t, "Unmarshal", err)
+	qs = "SELECT * FROM * WHERE NOT source IN (B)"
+	res, err = parseEval(qs, stmts)
+	checkErrorNow(t, qs, err)
 
-	if !reflect.DeepEqual(a, aa) {
-		t.Log("Marshal/Unmarshal is not idempotent!!!")
-		t.Logf("Expected: %v; Got: %v", a, aa)
-		t.FailNow()
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, a)
+		checkContains(t, qs, res, c)
 	}
-}
 
-func TestQueryCompileEval(t *testing.T) {
-	a := &pb.Statement{
-		Id:        "a",
-		Publisher: "A",
-		Namespace: "foo.a",
-		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmAAA", Refs: []string{"aaa"}}}},
-		Timestamp: 100}
+	// check patterns
+	qs = "SELECT * FROM * WHERE wki PREFIX a"
+	res, err = parseEval(qs, stmts)
+	checkErrorNow(t, qs, err)
 
-	b := &pb.Statement{
-		Id:        "b",
-		Publisher: "B",
-		Namespace: "foo.b",
-		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmBBB", Refs: []string{"bbb"}}}},
-		Timestamp: 200}
+	if checkResultLen(t, qs, res, 1) {
+		checkContains(t, qs, res, a)
+	}
 
-	c := &pb.Statement{
-		Id:        "c",
-		Publisher: "A",
-		Namespace: "bar.c",
-		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmCCC", Refs: []string{"ccc"}}}},
-		Timestamp: 300}
+	qs = "SELECT * FROM * WHERE wki LIKE _b_ OR wki LIKE %c"
+	res, err = parseEval(qs, stmts)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, b)
+		checkContains(t, qs, res, c)
+	}
+
+	qs = "SELECT * FROM * WHERE wki LIKE A%"
+	res, err = parseEval(qs, stmts)
+	checkErrorNow(t, qs, err)
+	checkResultLen(t, qs, res, 0)
+
+	qs = "SELECT * FROM * WHERE id LIKE %"
+	res, err = parseEval(qs, stmts)
+	checkErrorNow(t, qs, err)
+	checkResultLen(t, qs, res, 3)
+
+	qs = "SELECT * FROM * WHERE NOT id PREFIX b"
+	res, err = parseEval(qs, stmts)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, a)
+		checkContains(t, qs, res, c)
+	}
+
+	// check tag and dep selection
+	qs = "SELECT * FROM * WHERE tag = cc-by"
+	res, err = parseEval(qs, stmts)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, a)
+		checkContains(t, qs, res, c)
+	}
+
+	qs = "SELECT * FROM * WHERE tag = cc-0"
+	res, err = parseEval(qs, stmts)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 1) {
+		checkContains(t, qs, res, b)
+	}
+
+	qs = "SELECT * FROM * WHERE dep = QmEEE"
+	res, err = parseEval(qs, stmts)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 1) {
+		checkContains(t, qs, res, c)
+	}
+
+	qs = "SELECT * FROM * WHERE tag = cc-by AND dep = QmDDD"
+	res, err = parseEval(qs, stmts)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, a)
+		checkContains(t, qs, res, c)
+	}
+
+	qs = "SELECT * FROM * WHERE tag = cc-0 OR wki = aaa"
+	res, err = parseEval(qs, stmts)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, a)
+		checkContains(t, qs, res, b)
+	}
+
+}
+
+func parseEval(qs string, stmts []*pb.Statement) ([]interface{}, error) {
+	q, err := ParseQuery(qs)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := EvalQuery(q, stmts)
+	return res, err
+}
+
+func TestQueryCompile(t *testing.T) {
+	for _, qs := range simpleq {
+		q, err := ParseQuery(qs)
+		checkErrorNow(t, qs, err)
+		_, _, err = CompileQuery(q)
+		checkErrorNow(t, qs, err)
+		//fmt.Printf("Compile %s -> %s\n", qs, sqlq)
+	}
+}
+
+func TestQueryCompileDialect(t *testing.T) {
+	for _, qs := range simpleq {
+		q, err := ParseQuery(qs)
+		checkErrorNow(t, qs, err)
+		_, _, err = CompileQueryDialect(q, Postgres)
+		checkErrorNow(t, qs, err)
+	}
+
+	dialectq := []struct {
+		qs  string
+		sql string
+	}{
+		{"SELECT id FROM * WHERE wki PREFIX dpla_", "WHERE wki LIKE 'dpla\\_%')"},
+		{"SELECT id FROM * WHERE wki LIKE dpla_87%", "WHERE wki LIKE 'dpla_87%')"},
+		{"SELECT id FROM * WHERE id PREFIX 4XTTM", "WHERE id LIKE '4XTTM%'"},
+		{"SELECT id FROM foo.*", "WHERE (namespace = 'foo' OR namespace LIKE 'foo.%')"},
+		{"SELECT id FROM * ORDER BY counter OFFSET 10", "ORDER BY counter OFFSET 10"},
+		{"SELECT id FROM * ORDER BY id", "ORDER BY id COLLATE \"C\""},
+		{"SELECT * FROM * ORDER BY namespace DESC, counter", "ORDER BY namespace COLLATE \"C\" DESC, counter"},
+	}
+
+	for _, dq := range dialectq {
+		q, err := ParseQuery(dq.qs)
+		checkErrorNow(t, dq.qs, err)
+		sqlq, _, err := CompileQueryDialect(q, Postgres)
+		checkErrorNow(t, dq.qs, err)
+		checkBool(t, dq.qs, strings.HasSuffix(sqlq, dq.sql))
+	}
+
+	for _, qs := range textq {
+		q, err := ParseQuery(qs)
+		checkErrorNow(t, qs, err)
+		_, _, err = CompileQueryDialect(q, Postgres)
+		checkBool(t, qs, err != nil)
+	}
+}
+
+func TestPBWTF(t *testing.T) {
+	a := &pb.Statement{
+		Id:        "a",
+		Publisher: "A",
+		Namespace: "foo.a",
+		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmAAA"}}},
+		Timestamp: 100}
+
+	bytes, err := ggproto.Marshal(a)
+	checkErrorNow(t, "Marshal", err)
+
+	aa := new(pb.Statement)
+	err = ggproto.Unmarshal(bytes, aa)
+	checkErrorNow(t, "Unmarshal", err)
+
+	if !reflect.DeepEqual(a, aa) {
+		t.Log("Marshal/Unmarshal is not idempotent!!!")
+		t.Logf("Expected: %v; Got: %v", a, aa)
+		t.FailNow()
+	}
+}
+
+func TestQueryCompileEval(t *testing.T) {
+	a := &pb.Statement{
+		Id:        "a",
+		Publisher: "A",
+		Namespace: "foo.a",
+		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmAAA", Refs: []string{"aaa"}, Tags: []string{"cc-by"}, Deps: []string{"QmDDD"}}}},
+		Timestamp: 100}
+
+	b := &pb.Statement{
+		Id:        "b",
+		Publisher: "B",
+		Namespace: "foo.b",
+		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmBBB", Refs: []string{"bbb"}, Tags: []string{"cc-0"}}}},
+		Timestamp: 200}
+
+	c := &pb.Statement{
+		Id:        "c",
+		Publisher: "A",
+		Namespace: "bar.c",
+		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmCCC", Refs: []string{"ccc"}, Tags: []string{"cc-by", "cc-sa"}, Deps: []string{"QmDDD", "QmEEE"}}}},
+		Timestamp: 300}
 
 	stmts := []*pb.Statement{a, b, c}
 
@@ -807,6 +1432,45 @@ func TestQueryCompileEval(t *testing.T) {
 		checkContains(t, qs, res, int64(3))
 	}
 
+	// check grouping
+	qs = "SELECT (publisher, COUNT(*)) FROM * GROUP BY publisher"
+	res, err = parseCompileEval(db, qs)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, map[string]interface{}{"publisher": "A", "COUNT(*)": 2})
+		checkContains(t, qs, res, map[string]interface{}{"publisher": "B", "COUNT(*)": 1})
+	}
+
+	qs = "SELECT (namespace, MIN(timestamp), MAX(timestamp)) FROM foo.* GROUP BY namespace"
+	res, err = parseCompileEval(db, qs)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, map[string]interface{}{"namespace": "foo.a", "MIN(timestamp)": int64(100), "MAX(timestamp)": int64(100)})
+		checkContains(t, qs, res, map[string]interface{}{"namespace": "foo.b", "MIN(timestamp)": int64(200), "MAX(timestamp)": int64(200)})
+	}
+
+	qs = "SELECT (COUNT(*), MAX(timestamp)) FROM *"
+	res, err = parseCompileEval(db, qs)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 1) {
+		checkContains(t, qs, res, map[string]interface{}{"COUNT(*)": 3, "MAX(timestamp)": int64(300)})
+	}
+
+	qs = "SELECT (publisher, MAX(counter)) FROM * GROUP BY publisher ORDER BY publisher LIMIT 1"
+	res, err = parseCompileEval(db, qs)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 1) {
+		checkContains(t, qs, res, map[string]interface{}{"publisher": "A", "MAX(counter)": int64(3)})
+	}
+
+	qs = "SELECT (id, COUNT(*)) FROM * GROUP BY publisher"
+	_, err = parseCompileEval(db, qs)
+	checkBool(t, qs, err != nil)
+
 	// all simple selectors
 	qs = "SELECT body FROM foo.*"
 	res, err = parseCompileEval(db, qs)
@@ -1180,109 +1844,839 @@ func TestQueryCompileEval(t *testing.T) {
 		checkContains(t, qs, res, a)
 	}
 
-	// check wki
-	qs = "SELECT * FROM * WHERE wki = aaa"
+	// check distinct
+	qs = "SELECT DISTINCT (publisher) FROM *"
 	res, err = parseCompileEval(db, qs)
 	checkErrorNow(t, qs, err)
 
-	if checkResultLen(t, qs, res, 1) {
-		checkContains(t, qs, res, a)
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, map[string]interface{}{"publisher": "A"})
+		checkContains(t, qs, res, map[string]interface{}{"publisher": "B"})
 	}
 
-	qs = "SELECT * FROM * WHERE wki = bbb OR wki = ccc"
+	qs = "SELECT DISTINCT (publisher, source) FROM *"
 	res, err = parseCompileEval(db, qs)
 	checkErrorNow(t, qs, err)
 
 	if checkResultLen(t, qs, res, 2) {
-		checkContains(t, qs, res, b)
-		checkContains(t, qs, res, c)
+		checkContains(t, qs, res, map[string]interface{}{"publisher": "A", "source": "A"})
+		checkContains(t, qs, res, map[string]interface{}{"publisher": "B", "source": "B"})
 	}
 
-	qs = "SELECT * FROM * WHERE wki = aaa OR timestamp > 200"
+	qs = "SELECT DISTINCT publisher FROM *"
 	res, err = parseCompileEval(db, qs)
 	checkErrorNow(t, qs, err)
 
 	if checkResultLen(t, qs, res, 2) {
-		checkContains(t, qs, res, a)
-		checkContains(t, qs, res, c)
+		checkContains(t, qs, res, "A")
+		checkContains(t, qs, res, "B")
 	}
-}
 
-func makeStmtDb() (*sql.DB, error) {
-	db, err := sql.Open("sqlite3", ":memory:")
-	if err != nil {
-		return nil, err
-	}
+	qs = "SELECT COUNT(DISTINCT publisher) FROM *"
+	res, err = parseCompileEval(db, qs)
+	checkErrorNow(t, qs, err)
 
-	_, err = db.Exec("CREATE TABLE Statement (id VARCHAR(32) PRIMARY KEY, data VARBINARY)")
-	if err != nil {
-		return nil, err
+	if checkResultLen(t, qs, res, 1) {
+		checkContains(t, qs, res, 2)
 	}
 
-	_, err = db.Exec("CREATE TABLE Envelope (counter INTEGER PRIMARY KEY AUTOINCREMENT, id VARCHAR(32), namespace VARCHAR, publisher VARCHAR, source VARCHAR, timestamp INTEGER)")
-	if err != nil {
-		return nil, err
-	}
+	qs = "SELECT (namespace, COUNT(DISTINCT publisher)) FROM foo.* GROUP BY namespace"
+	res, err = parseCompileEval(db, qs)
+	checkErrorNow(t, qs, err)
 
-	_, err = db.Exec("CREATE TABLE Refs (id VARCHAR(32), wki VARCHAR)")
-	if err != nil {
-		return nil, err
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, map[string]interface{}{"namespace": "foo.a", "COUNT(DISTINCT publisher)": 1})
+		checkContains(t, qs, res, map[string]interface{}{"namespace": "foo.b", "COUNT(DISTINCT publisher)": 1})
 	}
 
-	return db, nil
-}
-
-func insertStmt(db *sql.DB, stmt *pb.Statement) error {
-	bytes, err := ggproto.Marshal(stmt)
-	if err != nil {
-		return err
-	}
+	// check offset
+	qs = "SELECT * FROM * ORDER BY counter LIMIT 1 OFFSET 1"
+	res, err = parseCompileEval(db, qs)
+	checkErrorNow(t, qs, err)
 
-	// a real index should use a transaction for both inserts...
-	_, err = db.Exec("INSERT INTO Statement VALUES (?, ?)", stmt.Id, bytes)
-	if err != nil {
-		return err
+	if checkResultLen(t, qs, res, 1) {
+		checkContains(t, qs, res, b)
 	}
 
-	// source = publisher only for simple statements
-	_, err = db.Exec("INSERT INTO Envelope VALUES (NULL,?, ?, ?, ?, ?)", stmt.Id, stmt.Namespace, stmt.Publisher, stmt.Publisher, stmt.Timestamp)
+	qs = "SELECT id FROM * ORDER BY counter OFFSET 1"
+	res, err = parseCompileEval(db, qs)
+	checkErrorNow(t, qs, err)
 
-	for wki, _ := range StatementRefs(stmt) {
-		_, err = db.Exec("INSERT INTO Refs VALUES (?, ?)", stmt.Id, wki)
-		if err != nil {
-			return err
-		}
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, "b")
+		checkContains(t, qs, res, "c")
 	}
 
-	return nil
-}
+	// check cursors
+	qs = "SELECT * FROM * WHERE counter > 1 ORDER BY counter LIMIT 1"
+	res, cursor, err := parseCompileEvalCursor(db, qs)
+	checkErrorNow(t, qs, err)
 
-func parseCompileEval(db *sql.DB, qs string) ([]interface{}, error) {
-	q, err := ParseQuery(qs)
-	if err != nil {
-		return nil, err
+	if checkResultLen(t, qs, res, 1) {
+		checkContains(t, qs, res, b)
 	}
+	checkBool(t, qs, cursor == 2)
 
-	sqlq, rsel, err := CompileQuery(q)
-	if err != nil {
-		return nil, err
-	}
+	qs = "SELECT (id, timestamp) FROM * WHERE counter > 2 ORDER BY counter"
+	res, cursor, err = parseCompileEvalCursor(db, qs)
+	checkErrorNow(t, qs, err)
 
-	rows, err := db.Query(sqlq)
-	if err != nil {
-		return nil, err
+	if checkResultLen(t, qs, res, 1) {
+		checkContains(t, qs, res, map[string]interface{}{"id": "c", "timestamp": int64(300)})
 	}
+	checkBool(t, qs, cursor == 3)
 
-	res := make([]interface{}, 0)
+	qs = "SELECT namespace FROM * ORDER BY counter"
+	_, _, err = parseCompileEvalCursor(db, qs)
+	checkBool(t, qs, err != nil)
 
-	defer rows.Close()
-	for rows.Next() {
-		obj, err := rsel.Scan(rows)
-		if err != nil {
-			return nil, err
-		}
-		res = append(res, obj)
-	}
+	// descending order can't be resumed with counter > cursor
+	qs = "SELECT * FROM * ORDER BY counter DESC"
+	_, _, err = parseCompileEvalCursor(db, qs)
+	checkBool(t, qs, err != nil)
 
-	return res, nil
+	qs = "SELECT * FROM * ORDER BY counter ASC LIMIT 1"
+	_, cursor, err = parseCompileEvalCursor(db, qs)
+	checkErrorNow(t, qs, err)
+	checkBool(t, qs, cursor == 1)
+
+	// check wki
+	qs = "SELECT * FROM * WHERE wki = aaa"
+	res, err = parseCompileEval(db, qs)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 1) {
+		checkContains(t, qs, res, a)
+	}
+
+	qs = "SELECT * FROM * WHERE wki = bbb OR wki = ccc"
+	res, err = parseCompileEval(db, qs)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, b)
+		checkContains(t, qs, res, c)
+	}
+
+	qs = "SELECT * FROM * WHERE wki = aaa OR timestamp > 200"
+	res, err = parseCompileEval(db, qs)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, a)
+		checkContains(t, qs, res, c)
+	}
+	// check IN lists
+	qs = "SELECT * FROM * WHERE wki IN (aaa, ccc, zzz)"
+	res, err = parseCompileEval(db, qs)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, a)
+		checkContains(t, qs, res, c)
+	}
+
+	qs = "SELECT * FROM * WHERE id IN (a, b)"
+	res, err = parseCompileEval(db, qs)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, a)
+		checkContains(t, qs, res, b)
+	}
+
+	qs = "SELECT id FROM * WHERE publisher IN (B, C)"
+	res, err = parseCompileEval(db, qs)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 1) {
+		checkContains(t, qs, res, "b")
+	}
+
+	qs = "SELECT id FROM * WHERE NOT source IN (B)"
+	res, err = parseCompileEval(db, qs)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, "a")
+		checkContains(t, qs, res, "c")
+	}
+
+	qs = "SELECT id FROM * WHERE tag IN (cc-0, cc-sa)"
+	res, err = parseCompileEval(db, qs)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, "b")
+		checkContains(t, qs, res, "c")
+	}
+
+	// check patterns
+	qs = "SELECT * FROM * WHERE wki PREFIX a"
+	res, err = parseCompileEval(db, qs)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 1) {
+		checkContains(t, qs, res, a)
+	}
+
+	qs = "SELECT id FROM * WHERE wki LIKE _b_ OR wki LIKE %c"
+	res, err = parseCompileEval(db, qs)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, "b")
+		checkContains(t, qs, res, "c")
+	}
+
+	qs = "SELECT * FROM * WHERE wki LIKE A%"
+	res, err = parseCompileEval(db, qs)
+	checkErrorNow(t, qs, err)
+	checkResultLen(t, qs, res, 0)
+
+	qs = "SELECT * FROM * WHERE id LIKE %"
+	res, err = parseCompileEval(db, qs)
+	checkErrorNow(t, qs, err)
+	checkResultLen(t, qs, res, 3)
+
+	qs = "SELECT id FROM foo.* WHERE NOT id PREFIX b"
+	res, err = parseCompileEval(db, qs)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 1) {
+		checkContains(t, qs, res, "a")
+	}
+
+	// check tag and dep
+	qs = "SELECT * FROM * WHERE tag = cc-by"
+	res, err = parseCompileEval(db, qs)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, a)
+		checkContains(t, qs, res, c)
+	}
+
+	qs = "SELECT * FROM * WHERE dep = QmEEE"
+	res, err = parseCompileEval(db, qs)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 1) {
+		checkContains(t, qs, res, c)
+	}
+
+	qs = "SELECT id FROM * WHERE tag = cc-sa AND dep = QmDDD"
+	res, err = parseCompileEval(db, qs)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 1) {
+		checkContains(t, qs, res, "c")
+	}
+
+	qs = "SELECT id FROM * WHERE tag = cc-0 OR wki = aaa"
+	res, err = parseCompileEval(db, qs)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, "a")
+		checkContains(t, qs, res, "b")
+	}
+
+	// quoted tags and deps
+	qs = "SELECT id FROM * WHERE tag = 'cc-by'"
+	res, err = parseCompileEval(db, qs)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, "a")
+		checkContains(t, qs, res, "c")
+	}
+
+	qs = "SELECT id FROM * WHERE dep = 'QmEEE'"
+	res, err = parseCompileEval(db, qs)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 1) {
+		checkContains(t, qs, res, "c")
+	}
+
+	qs = "SELECT id FROM * WHERE tag IN ('cc-0', cc-sa)"
+	res, err = parseCompileEval(db, qs)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, "b")
+		checkContains(t, qs, res, "c")
+	}
+
+	qs = "SELECT id FROM * WHERE dep IN ('QmEEE', 'QmFFF')"
+	res, err = parseCompileEval(db, qs)
+	checkErrorNow(t, qs, err)
+
+	if checkResultLen(t, qs, res, 1) {
+		checkContains(t, qs, res, "c")
+	}
+}
+
+// TestQueryCompileEvalParity checks that compiled queries agree with
+// EvalQuery, on statements with multiple index entries.
+func TestQueryCompileEvalParity(t *testing.T) {
+	a := &pb.Statement{
+		Id:        "a",
+		Publisher: "A",
+		Namespace: "foo.a",
+		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmAAA", Refs: []string{"aaa", "abc"}, Tags: []string{"cc-by", "cc-sa"}, Deps: []string{"QmDDD", "QmEEE"}}}},
+		Timestamp: 100}
+
+	b := &pb.Statement{
+		Id:        "b",
+		Publisher: "B",
+		Namespace: "foo.b",
+		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmBBB", Refs: []string{"bbb"}}}},
+		Timestamp: 200}
+
+	c := &pb.Statement{
+		Id:        "c",
+		Publisher: "A",
+		Namespace: "bar.c",
+		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmCCC", Tags: []string{"cc-0"}, Deps: []string{"QmDDD"}}}},
+		Timestamp: 300}
+
+	stmts := []*pb.Statement{a, b, c}
+
+	db, err := makeStmtDb()
+	checkErrorNow(t, "makeStmtDb", err)
+
+	for _, stmt := range stmts {
+		err = insertStmt(db, stmt)
+		checkErrorNow(t, "insertStmt", err)
+	}
+
+	queries := []string{
+		"SELECT id FROM * WHERE tag = cc-by",
+		"SELECT id FROM * WHERE tag = cc-by OR dep = QmDDD",
+		"SELECT COUNT(*) FROM * WHERE tag = cc-by OR dep = QmDDD",
+		"SELECT id FROM * WHERE tag = cc-by OR tag = cc-sa",
+		"SELECT id FROM * WHERE tag = cc-by AND dep = QmEEE",
+		"SELECT id FROM * WHERE NOT tag = x",
+		"SELECT id FROM * WHERE NOT tag = cc-by",
+		"SELECT id FROM * WHERE NOT dep = QmDDD",
+		"SELECT COUNT(*) FROM * WHERE NOT (tag = cc-0 OR wki = bbb)",
+		"SELECT id FROM * WHERE tag = cc-0 OR wki = aaa",
+		"SELECT id FROM foo.* WHERE NOT wki = aaa AND NOT tag = cc-0",
+		"SELECT (namespace, COUNT(*)) FROM * WHERE dep = QmDDD OR dep = QmEEE GROUP BY namespace",
+		"SELECT id FROM * WHERE wki IN (aaa, abc)",
+		"SELECT COUNT(*) FROM * WHERE wki IN (aaa, abc, bbb)",
+		"SELECT id FROM * WHERE NOT wki IN (aaa, xxx)",
+		"SELECT id FROM * WHERE wki IN (aaa, abc) OR tag IN (cc-by, cc-0)",
+		"SELECT DISTINCT publisher FROM * WHERE wki IN (aaa, abc, bbb)",
+		"SELECT id FROM * WHERE wki PREFIX a",
+		"SELECT COUNT(*) FROM * WHERE wki PREFIX 'a'",
+		"SELECT id FROM * WHERE wki LIKE 'a%'",
+		"SELECT COUNT(*) FROM * WHERE wki LIKE _b_ OR wki LIKE a%",
+		"SELECT id FROM * WHERE NOT wki LIKE '%c'",
+		// offsets apply to result rows
+		"SELECT COUNT(*) FROM * OFFSET 1",
+		"SELECT COUNT(*) FROM * LIMIT 1 OFFSET 1",
+		"SELECT (COUNT(*)) FROM * GROUP BY namespace OFFSET 1",
+		"SELECT (COUNT(*)) FROM * GROUP BY namespace LIMIT 1 OFFSET 2",
+		"SELECT (COUNT(*)) FROM * GROUP BY namespace OFFSET 3",
+		"SELECT (publisher, COUNT(*)) FROM * WHERE tag = cc-by OR tag = cc-0 GROUP BY publisher OFFSET 1",
+		"SELECT DISTINCT publisher FROM * OFFSET 2",
+		"SELECT COUNT(DISTINCT publisher) FROM * OFFSET 1",
+		"SELECT DISTINCT (publisher) FROM * WHERE NOT wki = bbb OFFSET 1",
+	}
+
+	for _, qs := range queries {
+		checkCompileEvalParity(t, db, stmts, qs)
+	}
+
+	// data queries without matching ids match nothing
+	q, err := ParseQuery("SELECT COUNT(*) FROM * WHERE data.title = foo")
+	checkErrorNow(t, "ParseQuery", err)
+	xq, err := q.WithDataIds([]string{})
+	checkErrorNow(t, "WithDataIds", err)
+	res, err := compileEval(db, xq)
+	checkErrorNow(t, "compileEval", err)
+	checkBool(t, "empty data ids", len(res) == 1 && res[0] == 0)
+}
+
+// checkCompileEvalParity checks that a query returns the same results when
+// compiled to sql and when evaluated, in any order.
+func checkCompileEvalParity(t *testing.T, db *sql.DB, stmts []*pb.Statement, qs string) {
+	q, err := ParseQuery(qs)
+	checkErrorNow(t, qs, err)
+
+	eres, err := EvalQuery(q, stmts)
+	checkErrorNow(t, qs, err)
+
+	sres, err := compileEval(db, q)
+	checkErrorNow(t, qs, err)
+
+	match := len(eres) == len(sres)
+	used := make([]bool, len(sres))
+	for _, eval := range eres {
+		if !match {
+			break
+		}
+
+		match = false
+		for x, sval := range sres {
+			if !used[x] && reflect.DeepEqual(eval, sval) {
+				used[x] = true
+				match = true
+				break
+			}
+		}
+	}
+
+	if !match {
+		t.Errorf("%s: eval %v, sql %v", qs, eres, sres)
+	}
+}
+
+func TestQueryNamespaceRestriction(t *testing.T) {
+	a := &pb.Statement{
+		Id:        "a",
+		Publisher: "A",
+		Namespace: "foo.a",
+		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmAAA"}}},
+		Timestamp: 100}
+
+	b := &pb.Statement{
+		Id:        "b",
+		Publisher: "B",
+		Namespace: "foo.b",
+		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmBBB"}}},
+		Timestamp: 200}
+
+	c := &pb.Statement{
+		Id:        "c",
+		Publisher: "A",
+		Namespace: "bar.c",
+		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmCCC"}}},
+		Timestamp: 300}
+
+	d := &pb.Statement{
+		Id:        "d",
+		Publisher: "B",
+		Namespace: "FOO.d",
+		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmDDD"}}},
+		Timestamp: 400}
+
+	e := &pb.Statement{
+		Id:        "e",
+		Publisher: "B",
+		Namespace: "foobar.e",
+		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmEEE"}}},
+		Timestamp: 500}
+
+	f := &pb.Statement{
+		Id:        "f",
+		Publisher: "B",
+		Namespace: "foo",
+		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmFFF"}}},
+		Timestamp: 600}
+
+	stmts := []*pb.Statement{a, b, c, d, e, f}
+
+	db, err := makeStmtDb()
+	checkErrorNow(t, "makeStmtDb", err)
+
+	for _, stmt := range stmts {
+		err = insertStmt(db, stmt)
+		checkErrorNow(t, "insertStmt", err)
+	}
+
+	restrictions := []struct {
+		qs    string
+		deny  []string
+		allow []string
+		res   []string
+	}{
+		{"SELECT id FROM *", nil, nil, []string{"a", "b", "c", "d", "e", "f"}},
+		{"SELECT id FROM *", []string{"foo.*"}, nil, []string{"c", "d", "e"}},
+		{"SELECT id FROM *", []string{"foo.*"}, []string{"foo.b"}, []string{"b", "c", "d", "e"}},
+		{"SELECT id FROM *", []string{"*"}, []string{"foo.a"}, []string{"a"}},
+		{"SELECT id FROM *", []string{"*"}, []string{"FOO.*"}, []string{"d"}},
+		{"SELECT id FROM *", []string{"foo.a", "bar.c"}, nil, []string{"b", "d", "e", "f"}},
+		{"SELECT id FROM foo.* WHERE publisher = A", []string{"bar.*"}, nil, []string{"a"}},
+		{"SELECT id FROM * WHERE publisher = A", []string{"foo.a"}, nil, []string{"c"}},
+		// wildcards match whole namespace components; foo.* is not foobar.*
+		{"SELECT id FROM *", []string{"foobar.*"}, []string{"foo.*"}, []string{"a", "b", "c", "d", "f"}},
+		{"SELECT id FROM *", []string{"*"}, []string{"foo.*"}, []string{"a", "b", "f"}},
+		{"SELECT id FROM foo.*", nil, nil, []string{"a", "b", "f"}},
+	}
+
+	for _, r := range restrictions {
+		q, err := ParseQuery(r.qs)
+		checkErrorNow(t, r.qs, err)
+		q = q.WithNamespaceRestriction(r.deny, r.allow)
+
+		res, err := EvalQuery(q, stmts)
+		checkErrorNow(t, r.qs, err)
+		if checkResultLen(t, r.qs, res, len(r.res)) {
+			for _, id := range r.res {
+				checkContains(t, r.qs, res, id)
+			}
+		}
+
+		res, err = compileEval(db, q)
+		checkErrorNow(t, r.qs, err)
+		if checkResultLen(t, r.qs, res, len(r.res)) {
+			for _, id := range r.res {
+				checkContains(t, r.qs, res, id)
+			}
+		}
+	}
+}
+
+func TestQueryCounterCursor(t *testing.T) {
+	streamq := []string{
+		"SELECT * FROM foo",
+		"SELECT * FROM foo.* WHERE publisher = A",
+		"SELECT * FROM * WHERE wki = aaa OR tag = cc-by",
+	}
+
+	for _, qs := range streamq {
+		q, err := ParseQuery(qs)
+		checkErrorNow(t, qs, err)
+		checkBool(t, qs, q.IsStreamSelect())
+	}
+
+	nstreamq := []string{
+		"SELECT id FROM foo",
+		"SELECT DISTINCT * FROM foo",
+		"SELECT * FROM foo ORDER BY counter",
+		"SELECT * FROM foo LIMIT 10",
+		"SELECT * FROM foo WHERE data.title = bar",
+		"DELETE FROM foo",
+	}
+
+	for _, qs := range nstreamq {
+		q, err := ParseQuery(qs)
+		checkErrorNow(t, qs, err)
+		checkBool(t, qs, !q.IsStreamSelect())
+	}
+
+	a := &pb.Statement{
+		Id:        "a",
+		Publisher: "A",
+		Namespace: "foo.a",
+		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmAAA"}}},
+		Timestamp: 100}
+
+	b := &pb.Statement{
+		Id:        "b",
+		Publisher: "B",
+		Namespace: "foo.b",
+		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmBBB"}}},
+		Timestamp: 200}
+
+	c := &pb.Statement{
+		Id:        "c",
+		Publisher: "A",
+		Namespace: "bar.c",
+		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmCCC"}}},
+		Timestamp: 300}
+
+	d := &pb.Statement{
+		Id:        "d",
+		Publisher: "A",
+		Namespace: "foo.d",
+		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmDDD"}}},
+		Timestamp: 400}
+
+	db, err := makeStmtDb()
+	checkErrorNow(t, "makeStmtDb", err)
+
+	for _, stmt := range []*pb.Statement{a, b, c, d} {
+		err = insertStmt(db, stmt)
+		checkErrorNow(t, "insertStmt", err)
+	}
+
+	qs := "SELECT * FROM foo.*"
+	q, err := ParseQuery(qs)
+	checkErrorNow(t, qs, err)
+
+	res, cursor, err := compileEvalCursor(db, q.WithCounterCursor(0, 2))
+	checkErrorNow(t, qs, err)
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, a)
+		checkContains(t, qs, res, b)
+	}
+	checkBool(t, qs, cursor == 2)
+
+	res, cursor, err = compileEvalCursor(db, q.WithCounterCursor(cursor, 2))
+	checkErrorNow(t, qs, err)
+	if checkResultLen(t, qs, res, 1) {
+		checkContains(t, qs, res, d)
+	}
+	checkBool(t, qs, cursor == 4)
+
+	res, _, err = compileEvalCursor(db, q.WithCounterCursor(cursor, 2))
+	checkErrorNow(t, qs, err)
+	checkResultLen(t, qs, res, 0)
+
+	qs = "SELECT * FROM * WHERE publisher = A"
+	q, err = ParseQuery(qs)
+	checkErrorNow(t, qs, err)
+
+	res, cursor, err = compileEvalCursor(db, q.WithCounterCursor(1, 10))
+	checkErrorNow(t, qs, err)
+	if checkResultLen(t, qs, res, 2) {
+		checkContains(t, qs, res, c)
+		checkContains(t, qs, res, d)
+	}
+	checkBool(t, qs, cursor == 4)
+}
+
+func TestQuerySupersede(t *testing.T) {
+	a := &pb.Statement{
+		Id:        "a",
+		Publisher: "A",
+		Namespace: "foo.a",
+		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmAAA"}}},
+		Timestamp: 100}
+
+	b := &pb.Statement{
+		Id:        "b",
+		Publisher: "B",
+		Namespace: "foo.b",
+		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmBBB"}}},
+		Timestamp: 200}
+
+	c := &pb.Statement{
+		Id:        "c",
+		Publisher: "A",
+		Namespace: "foo.c",
+		Body:      &pb.StatementBody{&pb.StatementBody_Simple{&pb.SimpleStatement{Object: "QmCCC"}}},
+		Timestamp: 300}
+
+	// the retraction of b is not honored, as it has a different publisher
+	r := &pb.Statement{
+		Id:        "r",
+		Publisher: "A",
+		Namespace: "foo.r",
+		Body:      &pb.StatementBody{&pb.StatementBody_Retract{&pb.RetractStatement{Targets: []string{"a", "b"}}}},
+		Timestamp: 400}
+
+	s := &pb.Statement{
+		Id:        "s",
+		Publisher: "A",
+		Namespace: "foo.c",
+		Body:      &pb.StatementBody{&pb.StatementBody_Supersede{&pb.SupersedeStatement{Targets: []string{"c"}, Body: &pb.SimpleStatement{Object: "QmCCD"}}}},
+		Timestamp: 500}
+
+	x := &pb.Statement{
+		Id:        "x",
+		Publisher: "B",
+		Namespace: "foo.a",
+		Body:      &pb.StatementBody{&pb.StatementBody_Supersede{&pb.SupersedeStatement{Targets: []string{"a", "c"}}}},
+		Timestamp: 600}
+
+	db, err := makeStmtDb()
+	checkErrorNow(t, "makeStmtDb", err)
+
+	for _, stmt := range []*pb.Statement{a, b, c, r, s, x} {
+		err = insertStmt(db, stmt)
+		checkErrorNow(t, "insertStmt", err)
+	}
+
+	objs := StatementObjects(s)
+	checkBool(t, "StatementObjects", len(objs) == 1 && objs["QmCCD"])
+
+	queries := []struct {
+		qs  string
+		res []interface{}
+	}{
+		{"SELECT id FROM * WHERE superseded", []interface{}{"a", "c"}},
+		{"SELECT id FROM * WHERE retracted", []interface{}{"a"}},
+		{"SELECT id FROM * WHERE NOT superseded", []interface{}{"b", "r", "s", "x"}},
+		{"SELECT id FROM foo.c WHERE NOT superseded", []interface{}{"s"}},
+		{"SELECT * FROM foo.* WHERE NOT (superseded OR publisher = B)", []interface{}{r, s}},
+		{"SELECT COUNT(*) FROM * WHERE NOT retracted", []interface{}{5}},
+	}
+
+	for _, query := range queries {
+		q, err := ParseQuery(query.qs)
+		checkErrorNow(t, query.qs, err)
+		checkBool(t, query.qs, q.String() == query.qs)
+
+		res, err := compileEval(db, q)
+		checkErrorNow(t, query.qs, err)
+		if checkResultLen(t, query.qs, res, len(query.res)) {
+			for _, val := range query.res {
+				checkContains(t, query.qs, res, val)
+			}
+		}
+
+		_, err = EvalQuery(q, []*pb.Statement{a})
+		checkBool(t, query.qs, err != nil)
+	}
+
+	qs := "DELETE FROM * WHERE retracted"
+	q, err := ParseQuery(qs)
+	checkErrorNow(t, qs, err)
+	checkBool(t, qs, q.Op == OpDelete)
+	checkBool(t, qs, q.String() == qs)
+	res, err := compileEval(db, q)
+	checkErrorNow(t, qs, err)
+	if checkResultLen(t, qs, res, 1) {
+		checkContains(t, qs, res, "a")
+	}
+}
+
+func makeStmtDb() (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Exec("CREATE TABLE Statement (id VARCHAR(32) PRIMARY KEY, data VARBINARY)")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Exec("CREATE TABLE Envelope (counter INTEGER PRIMARY KEY AUTOINCREMENT, id VARCHAR(32), namespace VARCHAR, publisher VARCHAR, source VARCHAR, timestamp INTEGER)")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Exec("CREATE TABLE Refs (id VARCHAR(32), wki VARCHAR)")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Exec("CREATE TABLE Tags (id VARCHAR(32), tag VARCHAR)")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Exec("CREATE TABLE Deps (id VARCHAR(32), dep VARCHAR)")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Exec("CREATE TABLE Supersede (id VARCHAR(32), publisher VARCHAR, target VARCHAR(32), retract BOOLEAN)")
+	if err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func insertStmt(db *sql.DB, stmt *pb.Statement) error {
+	bytes, err := ggproto.Marshal(stmt)
+	if err != nil {
+		return err
+	}
+
+	// a real index should use a transaction for both inserts...
+	_, err = db.Exec("INSERT INTO Statement VALUES (?, ?)", stmt.Id, bytes)
+	if err != nil {
+		return err
+	}
+
+	// source = publisher only for simple statements
+	_, err = db.Exec("INSERT INTO Envelope VALUES (NULL,?, ?, ?, ?, ?)", stmt.Id, stmt.Namespace, stmt.Publisher, stmt.Publisher, stmt.Timestamp)
+
+	for wki, _ := range StatementRefs(stmt) {
+		_, err = db.Exec("INSERT INTO Refs VALUES (?, ?)", stmt.Id, wki)
+		if err != nil {
+			return err
+		}
+	}
+
+	for tag, _ := range StatementTags(stmt) {
+		_, err = db.Exec("INSERT INTO Tags VALUES (?, ?)", stmt.Id, tag)
+		if err != nil {
+			return err
+		}
+	}
+
+	for dep, _ := range StatementDeps(stmt) {
+		_, err = db.Exec("INSERT INTO Deps VALUES (?, ?)", stmt.Id, dep)
+		if err != nil {
+			return err
+		}
+	}
+
+	targets, retract := StatementTargets(stmt)
+	for _, target := range targets {
+		_, err = db.Exec("INSERT INTO Supersede VALUES (?, ?, ?, ?)", stmt.Id, stmt.Publisher, target, retract)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func parseCompileEval(db *sql.DB, qs string) ([]interface{}, error) {
+	q, err := ParseQuery(qs)
+	if err != nil {
+		return nil, err
+	}
+
+	return compileEval(db, q)
+}
+
+func compileEval(db *sql.DB, q *Query) ([]interface{}, error) {
+	sqlq, rsel, err := CompileQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(sqlq)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]interface{}, 0)
+
+	defer rows.Close()
+	for rows.Next() {
+		obj, err := rsel.Scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, obj)
+	}
+
+	return res, nil
+}
+
+func parseCompileEvalCursor(db *sql.DB, qs string) ([]interface{}, int64, error) {
+	q, err := ParseQuery(qs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return compileEvalCursor(db, q)
+}
+
+func compileEvalCursor(db *sql.DB, q *Query) ([]interface{}, int64, error) {
+	sqlq, rsel, err := CompileCursorQuery(q)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := db.Query(sqlq)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	res := make([]interface{}, 0)
+
+	defer rows.Close()
+	for rows.Next() {
+		obj, err := rsel.Scan(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		res = append(res, obj)
+	}
+
+	return res, rsel.Cursor(), nil
 }
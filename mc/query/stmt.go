@@ -5,8 +5,57 @@ import (
 )
 
 func StatementRefs(stmt *pb.Statement) StatementRefSet {
+	return statementKeys(stmt, simpleStatementRefs)
+}
+
+func StatementTags(stmt *pb.Statement) StatementRefSet {
+	return statementKeys(stmt, simpleStatementTags)
+}
+
+func StatementDeps(stmt *pb.Statement) StatementRefSet {
+	return statementKeys(stmt, simpleStatementDeps)
+}
+
+func StatementObjects(stmt *pb.Statement) StatementRefSet {
+	return statementKeys(stmt, simpleStatementObjects)
+}
+
+// StatementTargets returns the ids of the statements retracted or superseded
+// by a statement, and whether it is a retraction.
+func StatementTargets(stmt *pb.Statement) ([]string, bool) {
+	switch body := stmt.Body.Body.(type) {
+	case *pb.StatementBody_Retract:
+		return body.Retract.Targets, true
+
+	case *pb.StatementBody_Supersede:
+		return body.Supersede.Targets, false
+
+	default:
+		return nil, false
+	}
+}
+
+type SimpleStatementKeys func(*pb.SimpleStatement) []string
+
+func simpleStatementRefs(stmt *pb.SimpleStatement) []string {
+	return stmt.Refs
+}
+
+func simpleStatementTags(stmt *pb.SimpleStatement) []string {
+	return stmt.Tags
+}
+
+func simpleStatementDeps(stmt *pb.SimpleStatement) []string {
+	return stmt.Deps
+}
+
+func simpleStatementObjects(stmt *pb.SimpleStatement) []string {
+	return []string{stmt.Object}
+}
+
+func statementKeys(stmt *pb.Statement, getf SimpleStatementKeys) StatementRefSet {
 	refs := makeStatementRefSet()
-	refs.mergeStatement(stmt)
+	refs.mergeStatement(stmt, getf)
 	return refs
 }
 
@@ -16,34 +65,39 @@ func makeStatementRefSet() StatementRefSet {
 	return StatementRefSet(make(map[string]bool))
 }
 
-func (refs StatementRefSet) mergeStatement(stmt *pb.Statement) {
+func (refs StatementRefSet) mergeStatement(stmt *pb.Statement, getf SimpleStatementKeys) {
 	switch body := stmt.Body.Body.(type) {
 	case *pb.StatementBody_Simple:
-		refs.mergeSimple(body.Simple)
+		refs.mergeSimple(body.Simple, getf)
 
 	case *pb.StatementBody_Compound:
-		refs.mergeCompound(body.Compound)
+		refs.mergeCompound(body.Compound, getf)
 
 	case *pb.StatementBody_Envelope:
-		refs.mergeEnvelope(body.Envelope)
+		refs.mergeEnvelope(body.Envelope, getf)
+
+	case *pb.StatementBody_Supersede:
+		if body.Supersede.Body != nil {
+			refs.mergeSimple(body.Supersede.Body, getf)
+		}
 	}
 }
 
-func (refs StatementRefSet) mergeSimple(stmt *pb.SimpleStatement) {
-	for _, wki := range stmt.Refs {
-		refs[wki] = true
+func (refs StatementRefSet) mergeSimple(stmt *pb.SimpleStatement, getf SimpleStatementKeys) {
+	for _, key := range getf(stmt) {
+		refs[key] = true
 	}
 }
 
-func (refs StatementRefSet) mergeCompound(stmt *pb.CompoundStatement) {
+func (refs StatementRefSet) mergeCompound(stmt *pb.CompoundStatement, getf SimpleStatementKeys) {
 	for _, xstmt := range stmt.Body {
-		refs.mergeSimple(xstmt)
+		refs.mergeSimple(xstmt, getf)
 	}
 }
 
-func (refs StatementRefSet) mergeEnvelope(stmt *pb.EnvelopeStatement) {
+func (refs StatementRefSet) mergeEnvelope(stmt *pb.EnvelopeStatement, getf SimpleStatementKeys) {
 	for _, xstmt := range stmt.Body {
-		refs.mergeStatement(xstmt)
+		refs.mergeStatement(xstmt, getf)
 	}
 }
 
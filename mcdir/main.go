@@ -4,8 +4,8 @@ import (
 	"context"
 	"flag"
 	"fmt"
-	p2p_peer "github.com/libp2p/go-libp2p-peer"
 	mc "github.com/mediachain/concat/mc"
+	mcdir "github.com/mediachain/concat/mc/dir"
 	homedir "github.com/mitchellh/go-homedir"
 	"log"
 	"os"
@@ -55,12 +55,7 @@ func main() {
 		log.Fatal(err)
 	}
 
-	dir := &Directory{PeerIdentity: id, host: host, peers: make(map[p2p_peer.ID]PeerRecord), mfs: NewManifestStore()}
-	host.SetStreamHandler("/mediachain/dir/register", dir.registerHandler)
-	host.SetStreamHandler("/mediachain/dir/lookup", dir.lookupHandler)
-	host.SetStreamHandler("/mediachain/dir/list", dir.listHandler)
-	host.SetStreamHandler("/mediachain/dir/listns", dir.listnsHandler)
-	host.SetStreamHandler("/mediachain/dir/listmf", dir.listmfHandler)
+	mcdir.NewDirectory(id, host)
 
 	for _, addr := range host.Addrs() {
 		if !mc.IsLinkLocalAddr(addr) {
@@ -17,6 +17,7 @@ import (
 	"io/ioutil"
 	"log"
 	"net/http"
+	"os"
 	"regexp"
 	"strconv"
 	"strings"
@@ -423,14 +424,147 @@ loop:
 	}
 }
 
-// POST /import
+// POST /retract/{namespace}
+// DATA: statement ids, one per line
+// Publishes a retraction of statements published by the node in the namespace;
+// nodes that merge the retraction delete the statements.
+// Returns the retraction statement id.
+func (node *Node) httpRetract(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	ns := vars["namespace"]
+
+	if !nsrx.Match([]byte(ns)) {
+		apiError(w, http.StatusBadRequest, BadNamespace)
+		return
+	}
+
+	targets := make([]string, 0)
+	scanner := bufio.NewScanner(r.Body)
+	for scanner.Scan() {
+		id := strings.TrimSpace(scanner.Text())
+		if id != "" {
+			targets = append(targets, id)
+		}
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		apiError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	sid, err := node.doRetract(ns, targets)
+	switch {
+	case err == BadTarget:
+		apiError(w, http.StatusBadRequest, err)
+		return
+
+	case err != nil:
+		apiError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	fmt.Fprintln(w, sid)
+}
+
+// POST /supersede/{namespace}
+// DATA: json-encoded pb.SupersedeStatement
+// Publishes a statement superseding statements published by the node in
+// the namespace.
+// Returns the statement id.
+func (node *Node) httpSupersede(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	ns := vars["namespace"]
+
+	if !nsrx.Match([]byte(ns)) {
+		apiError(w, http.StatusBadRequest, BadNamespace)
+		return
+	}
+
+	body := new(pb.SupersedeStatement)
+	err := json.NewDecoder(r.Body).Decode(body)
+	if err != nil {
+		apiError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	sid, err := node.doSupersede(ns, body)
+	switch {
+	case err == BadTarget || err == BadStatementBody:
+		apiError(w, http.StatusBadRequest, err)
+		return
+
+	case err != nil:
+		apiError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	fmt.Fprintln(w, sid)
+}
+
+// POST /archive/{namespace}
+// Produces a signed archive of the namespace as a tarball with the
+// statements and their metadata objects.
+func (node *Node) httpArchive(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	ns := vars["namespace"]
+
+	if !nsrx.Match([]byte(ns)) {
+		apiError(w, http.StatusBadRequest, BadNamespace)
+		return
+	}
+
+	// the archive is produced before the response is written, so that
+	// errors can be reported to the client
+	tmp, err := ioutil.TempFile("", "mcarchive")
+	if err != nil {
+		apiError(w, http.StatusInternalServerError, err)
+		return
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	_, _, err = node.doArchive(r.Context(), ns, tmp)
+	switch {
+	case err == NoResult:
+		apiError(w, http.StatusNotFound, err)
+		return
+
+	case err != nil:
+		apiError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	_, err = tmp.Seek(0, io.SeekStart)
+	if err != nil {
+		apiError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-tar")
+	_, err = io.Copy(w, tmp)
+	if err != nil {
+		log.Printf("Error writing response body: %s", err.Error())
+	}
+}
+
+// POST /import?archive=true
 // DATA: A stream of json-encoded pb.Statements
 // Merges a stream of pre-signed statements
-// Returns the number of statements merged
+// With archive=true, the data is an archive produced by /archive; the
+// archive statement is verified before the statements and objects are imported.
+// Returns the number of statements merged and the number of statements
+// rejected by publisher trust
 func (node *Node) httpImport(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("archive") == "true" {
+		node.httpImportArchive(w, r)
+		return
+	}
+
 	const batch = 1024
 	var err error
 	count := 0
+	rejects := 0
 
 	dec := json.NewDecoder(r.Body)
 	pkcache := make(map[string]p2p_crypto.PubKey)
@@ -449,9 +583,10 @@ loop:
 
 		stmts = append(stmts, stmt)
 		if len(stmts) >= batch {
-			var xcount int
-			xcount, err = node.doImport(stmts, pkcache)
+			var xcount, xrejects int
+			xcount, xrejects, err = node.doImport(stmts, pkcache)
 			count += xcount
+			rejects += xrejects
 			if err != nil {
 				goto import_error
 			}
@@ -460,15 +595,17 @@ loop:
 	}
 
 	if len(stmts) > 0 {
-		var xcount int
-		xcount, err = node.doImport(stmts, pkcache)
+		var xcount, xrejects int
+		xcount, xrejects, err = node.doImport(stmts, pkcache)
 		count += xcount
+		rejects += xrejects
 		if err != nil {
 			goto import_error
 		}
 	}
 
 	fmt.Fprintln(w, count)
+	fmt.Fprintln(w, rejects)
 	return
 
 import_error:
@@ -478,6 +615,20 @@ import_error:
 	}
 }
 
+func (node *Node) httpImportArchive(w http.ResponseWriter, r *http.Request) {
+	count, _, rejects, err := node.doImportArchive(r.Body)
+	if err != nil {
+		apiError(w, http.StatusBadRequest, err)
+		if count > 0 {
+			fmt.Fprintf(w, "Partial import: %d statements merged\n", count)
+		}
+		return
+	}
+
+	fmt.Fprintln(w, count)
+	fmt.Fprintln(w, rejects)
+}
+
 // GET /stmt/{statementId}
 // Retrieves a statement by id
 func (node *Node) httpStatement(w http.ResponseWriter, r *http.Request) {
@@ -503,9 +654,14 @@ func (node *Node) httpStatement(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// POST /query
-// DATA: MCQL SELECT query
+// POST /query?cursor=true
+// DATA: MCQL SELECT or EXPLAIN SELECT query
 // Queries the statement database and return the result set in ndjson
+// With cursor=true, queries ordered by counter are terminated with
+// the counter of the last result: {"cursor": counter}
+// EXPLAIN queries return the compiled SQL followed by the query plan:
+// {"sql": sql} {"plan": detail} ...
+// Queries with data criteria are subject to LocalDataQueryCost
 func (node *Node) httpQuery(w http.ResponseWriter, r *http.Request) {
 	body, err := ioutil.ReadAll(r.Body)
 	if err != nil {
@@ -519,7 +675,14 @@ func (node *Node) httpQuery(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if q.Op != mcq.OpSelect {
+	q = node.resolveAlias(q)
+
+	switch q.Op {
+	case mcq.OpSelect:
+	case mcq.OpExplain:
+		node.httpExplain(w, q)
+		return
+	default:
 		apiError(w, http.StatusBadRequest, BadQuery)
 		return
 	}
@@ -527,7 +690,7 @@ func (node *Node) httpQuery(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithCancel(r.Context())
 	defer cancel()
 
-	ch, err := node.db.QueryStream(ctx, q)
+	ch, err := node.doQuery(ctx, q, apiCursorQuery(r), LocalDataQueryCost)
 	if err != nil {
 		apiError(w, http.StatusInternalServerError, err)
 		return
@@ -543,9 +706,32 @@ func (node *Node) httpQuery(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// POST /query/{peerId}
-// DATA: MCQL SELECT query
+func (node *Node) httpExplain(w http.ResponseWriter, q *mcq.Query) {
+	res, err := node.db.Explain(q)
+	if err != nil {
+		apiError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	enc := json.NewEncoder(w)
+	for _, obj := range res {
+		err = enc.Encode(obj)
+		if err != nil {
+			log.Printf("Error encoding query result: %s", err.Error())
+			return
+		}
+	}
+}
+
+func apiCursorQuery(r *http.Request) bool {
+	return r.URL.Query().Get("cursor") == "true"
+}
+
+// POST /query/{peerId}?cursor=true
+// DATA: MCQL SELECT or EXPLAIN SELECT query
 // Queries a remote peer and returns the result set in ndjson
+// With cursor=true, cursor queries are terminated as in /query
+// EXPLAIN queries are only answered by peers whose policy allows them
 func (node *Node) httpRemoteQuery(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	peerId := vars["peerId"]
@@ -564,7 +750,7 @@ func (node *Node) httpRemoteQuery(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if qq.Op != mcq.OpSelect {
+	if qq.Op != mcq.OpSelect && qq.Op != mcq.OpExplain {
 		apiError(w, http.StatusBadRequest, BadQuery)
 		return
 	}
@@ -578,7 +764,12 @@ func (node *Node) httpRemoteQuery(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithCancel(r.Context())
 	defer cancel()
 
-	ch, err := node.doRemoteQuery(ctx, pid, q)
+	var ch <-chan interface{}
+	if apiCursorQuery(r) {
+		ch, err = node.doRemoteQueryCursor(ctx, pid, q)
+	} else {
+		ch, err = node.doRemoteQuery(ctx, pid, q)
+	}
 	if err != nil {
 		apiNetError(w, err)
 		return
@@ -594,11 +785,20 @@ func (node *Node) httpRemoteQuery(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// POST /merge/{peerId}
+// POST /merge/{peerId}?dryRun=true
 // DATA: MCQL SELECT query
 // Queries a remote peer and merges the resulting statements into the local
-// db; returns the number of statements and objects merged
+// db; returns the number of statements and objects merged, and the number
+// of statements rejected by publisher trust.
+// The merge runs as a background job, which continues if the client
+// disconnects.
+// With dryRun=true, nothing is merged and the diff is returned as in /diff
 func (node *Node) httpMerge(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("dryRun") == "true" {
+		node.httpDiff(w, r)
+		return
+	}
+
 	vars := mux.Vars(r)
 	peerId := vars["peerId"]
 
@@ -627,31 +827,152 @@ func (node *Node) httpMerge(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx, cancel := context.WithCancel(r.Context())
-	defer cancel()
-
-	count, ocount, err := node.doMerge(ctx, pid, q)
+	job, err := node.doMergeJob(pid, q)
 	if err != nil {
 		apiNetError(w, err)
-		if count > 0 {
-			fmt.Fprintf(w, "Partial merge: %d statements merged\n", count)
+		return
+	}
+
+	node.httpMergeJobWait(w, r, job)
+}
+
+func (node *Node) httpMergeJobWait(w http.ResponseWriter, r *http.Request, job *MergeJob) {
+	ctx := r.Context()
+	xjob, err := node.waitMergeJob(ctx, job)
+	switch {
+	case err != nil && err == ctx.Err():
+		// the client disconnected; the job continues in the background
+		return
+
+	case err != nil:
+		apiNetError(w, err)
+		if xjob.Statements > 0 {
+			fmt.Fprintf(w, "Partial merge: %d statements merged\n", xjob.Statements)
 		}
-		if ocount > 0 {
-			fmt.Fprintf(w, "Partial merge: %d objects merged\n", ocount)
+		if xjob.Objects > 0 {
+			fmt.Fprintf(w, "Partial merge: %d objects merged\n", xjob.Objects)
 		}
 
+	default:
+		fmt.Fprintln(w, xjob.Statements)
+		fmt.Fprintln(w, xjob.Objects)
+		fmt.Fprintln(w, xjob.Rejected)
+	}
+}
+
+// POST /diff/{peerId}
+// DATA: MCQL SELECT query
+// Queries a remote peer and reports what merging the result set would bring
+// in, without writing anything: the number of new and already present
+// statements, the namespaces and publishers involved, and the number of
+// referenced objects missing from the local datastore. Returns json.
+func (node *Node) httpDiff(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	peerId := vars["peerId"]
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("http/diff: Error reading request body: %s", err.Error())
 		return
 	}
 
-	fmt.Fprintln(w, count)
-	fmt.Fprintln(w, ocount)
+	q := string(body)
+
+	qq, err := mcq.ParseQuery(q)
+	if err != nil {
+		apiError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if !qq.IsSimpleSelect("*") {
+		apiError(w, http.StatusBadRequest, BadQuery)
+		return
+	}
+
+	pid, err := p2p_peer.IDB58Decode(peerId)
+	if err != nil {
+		apiError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	diff, err := node.doDiff(r.Context(), pid, q)
+	if err != nil {
+		apiNetError(w, err)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(diff)
+	if err != nil {
+		log.Printf("Error writing response body: %s", err.Error())
+	}
+}
+
+// GET /merge/jobs
+// Lists the node's merge jobs in json
+func (node *Node) httpMergeJobs(w http.ResponseWriter, r *http.Request) {
+	enc := json.NewEncoder(w)
+	for _, job := range node.getMergeJobs() {
+		err := enc.Encode(job)
+		if err != nil {
+			log.Printf("Error writing response body: %s", err.Error())
+			return
+		}
+	}
+}
+
+// POST /merge/jobs/{id}/resume
+// Resumes a merge job from its last checkpoint; returns the total number
+// of statements and objects merged by the job
+func (node *Node) httpMergeJobResume(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	job, err := node.doResumeMergeJob(id)
+	switch err {
+	case nil:
+		node.httpMergeJobWait(w, r, job)
+
+	case UnknownMergeJob:
+		apiError(w, http.StatusNotFound, err)
+
+	case MergeJobActive:
+		apiError(w, http.StatusConflict, err)
+
+	default:
+		apiNetError(w, err)
+	}
+}
+
+// DELETE /merge/jobs/{id}
+// Cancels a merge job if it is running and removes it
+func (node *Node) httpMergeJobDelete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		apiError(w, http.StatusBadRequest, BadMethod)
+		return
+	}
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	err := node.doDeleteMergeJob(id)
+	switch err {
+	case nil:
+		fmt.Fprintln(w, "OK")
+
+	case UnknownMergeJob:
+		apiError(w, http.StatusNotFound, err)
+
+	default:
+		apiError(w, http.StatusInternalServerError, err)
+	}
 }
 
 // POST /push/{peerId}
 // DATA: MCQL SELECT query
 // Pushes statements matching the query to peerId for merge; must be
 // authorized for push by the peer
-// returns the number of statements and objects merged
+// returns the number of statements and objects merged, and the number of
+// statements rejected by publisher trust
 func (node *Node) httpPush(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	peerId := vars["peerId"]
@@ -684,7 +1005,7 @@ func (node *Node) httpPush(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithCancel(r.Context())
 	defer cancel()
 
-	count, ocount, err := node.doPush(ctx, pid, qq)
+	count, ocount, rejects, err := node.doPush(ctx, pid, node.resolveAlias(qq))
 	if err != nil {
 		apiNetError(w, err)
 		if count > 0 {
@@ -700,8 +1021,186 @@ func (node *Node) httpPush(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	fmt.Fprintln(w, count)
-	fmt.Fprintln(w, ocount)
+	fmt.Fprintln(w, count)
+	fmt.Fprintln(w, ocount)
+	fmt.Fprintln(w, rejects)
+}
+
+// POST /sync/{peerId}/{namespace}
+// Reconciles the namespace with a peer: merges the statements the node is
+// missing and pushes the statements the peer is missing.
+// Returns statement and object counts in json.
+func (node *Node) httpSync(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	peerId := vars["peerId"]
+	ns := vars["namespace"]
+
+	pid, err := p2p_peer.IDB58Decode(peerId)
+	if err != nil {
+		apiError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	stats, err := node.doSync(ctx, pid, ns)
+	if err != nil {
+		apiNetError(w, err)
+		if stats.Merged > 0 {
+			fmt.Fprintf(w, "Partial sync: %d statements merged\n", stats.Merged)
+		}
+		if stats.MergedObjects > 0 {
+			fmt.Fprintf(w, "Partial sync: %d objects merged\n", stats.MergedObjects)
+		}
+		if stats.Pushed > 0 {
+			fmt.Fprintf(w, "Partial sync: %d statements pushed\n", stats.Pushed)
+		}
+		if stats.PushedObjects > 0 {
+			fmt.Fprintf(w, "Partial sync: %d objects pushed\n", stats.PushedObjects)
+		}
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(stats)
+	if err != nil {
+		log.Printf("Error writing response body: %s", err.Error())
+	}
+}
+
+// GET /subscribe
+// lists the node's subscriptions in json
+func (node *Node) httpSubscriptions(w http.ResponseWriter, r *http.Request) {
+	enc := json.NewEncoder(w)
+	for _, sub := range node.getSubscriptions() {
+		err := enc.Encode(sub)
+		if err != nil {
+			log.Printf("Error writing response body: %s", err.Error())
+			return
+		}
+	}
+}
+
+// GET  /subscribe/{peerId}
+// POST /subscribe/{peerId}
+// DATA: MCQL SELECT * query
+// gets/sets the subscription to a peer; statements matching the query
+// are merged as they are published by the peer.
+// An empty query cancels the subscription.
+func (node *Node) httpSubscribe(w http.ResponseWriter, r *http.Request) {
+	apiConfigMethod(w, r, node.httpSubscribeGet, node.httpSubscribeSet)
+}
+
+func (node *Node) httpSubscribeGet(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	peerId := vars["peerId"]
+
+	pid, err := p2p_peer.IDB58Decode(peerId)
+	if err != nil {
+		apiError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	sub, ok := node.getSubscription(pid)
+	if !ok {
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(sub)
+	if err != nil {
+		log.Printf("Error writing response body: %s", err.Error())
+	}
+}
+
+func (node *Node) httpSubscribeSet(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	peerId := vars["peerId"]
+
+	pid, err := p2p_peer.IDB58Decode(peerId)
+	if err != nil {
+		apiError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("http/subscribe: Error reading request body: %s", err.Error())
+		return
+	}
+
+	q := strings.TrimSpace(string(body))
+	if q != "" {
+		qq, err := mcq.ParseQuery(q)
+		if err != nil {
+			apiError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if !qq.IsStreamSelect() {
+			apiError(w, http.StatusBadRequest, BadQuery)
+			return
+		}
+	}
+
+	err = node.doSubscribe(pid, q)
+	if err != nil {
+		apiError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = node.saveConfig()
+	if err != nil {
+		apiError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	fmt.Fprintln(w, "OK")
+}
+
+// GET  /gossip
+// POST /gossip
+// DATA: list of namespaces, one per line
+// gets/sets the namespaces followed through gossip; statements announced
+// by their publishers in these namespaces are fetched and merged.
+func (node *Node) httpGossip(w http.ResponseWriter, r *http.Request) {
+	apiConfigMethod(w, r, node.httpGossipGet, node.httpGossipSet)
+}
+
+func (node *Node) httpGossipGet(w http.ResponseWriter, r *http.Request) {
+	for _, ns := range node.getGossip() {
+		fmt.Fprintln(w, ns)
+	}
+}
+
+func (node *Node) httpGossipSet(w http.ResponseWriter, r *http.Request) {
+	nss := make([]string, 0)
+	scanner := bufio.NewScanner(r.Body)
+	for scanner.Scan() {
+		ns := strings.TrimSpace(scanner.Text())
+		if ns != "" {
+			nss = append(nss, ns)
+		}
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		apiError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = node.doGossip(nss)
+	if err != nil {
+		apiError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = node.saveConfig()
+	if err != nil {
+		apiError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	fmt.Fprintln(w, "OK")
 }
 
 // POST /delete
@@ -726,6 +1225,8 @@ func (node *Node) httpDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	q = node.resolveAlias(q)
+
 	count, err := node.db.Delete(q)
 	if err != nil {
 		apiError(w, http.StatusInternalServerError, err)
@@ -762,6 +1263,156 @@ func (node *Node) httpVacuumFull(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprintln(w, "OK")
 }
 
+// GET /index
+// Lists the field and text indexes in ndjson
+func (node *Node) httpFieldIndexes(w http.ResponseWriter, r *http.Request) {
+	idxs, err := node.db.FieldIndexes()
+	if err != nil {
+		apiError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	tidxs, err := node.db.TextIndexes()
+	if err != nil {
+		apiError(w, http.StatusInternalServerError, err)
+		return
+	}
+	idxs = append(idxs, tidxs...)
+
+	enc := json.NewEncoder(w)
+	for _, idx := range idxs {
+		err = enc.Encode(idx)
+		if err != nil {
+			log.Printf("Error encoding field index: %s", err.Error())
+			return
+		}
+	}
+}
+
+// POST /index/{namespace}?text=true
+// DATA: field path in the metadata objects, eg source.name
+// Creates a field index for the namespace and backfills it with the
+// existing statements; the field is queried in MCQL as field.path
+// With text=true, creates a full-text index instead, queried in MCQL with
+// text MATCH and with /search
+func (node *Node) httpCreateFieldIndex(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	ns := vars["namespace"]
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("http/index: Error reading request body: %s", err.Error())
+		return
+	}
+
+	path := strings.TrimSpace(string(body))
+
+	if apiTextIndex(r) {
+		err = node.doCreateTextIndex(r.Context(), ns, path)
+	} else {
+		err = node.doCreateFieldIndex(r.Context(), ns, path)
+	}
+
+	switch err {
+	case nil:
+		fmt.Fprintln(w, "OK")
+
+	case BadNamespace, BadFieldPath, NoTextSearch:
+		apiError(w, http.StatusBadRequest, err)
+
+	default:
+		apiError(w, http.StatusInternalServerError, err)
+	}
+}
+
+// POST /index/{namespace}/drop?text=true
+// DATA: field path
+// Drops a field (or text) index and its entries
+func (node *Node) httpDropFieldIndex(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	ns := vars["namespace"]
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("http/index: Error reading request body: %s", err.Error())
+		return
+	}
+
+	path := strings.TrimSpace(string(body))
+
+	if apiTextIndex(r) {
+		err = node.doDropTextIndex(ns, path)
+	} else {
+		err = node.doDropFieldIndex(ns, path)
+	}
+
+	switch err {
+	case nil:
+		fmt.Fprintln(w, "OK")
+
+	case UnknownIndex:
+		apiError(w, http.StatusNotFound, err)
+
+	default:
+		apiError(w, http.StatusInternalServerError, err)
+	}
+}
+
+func apiTextIndex(r *http.Request) bool {
+	return r.URL.Query().Get("text") == "true"
+}
+
+// GET /search/{namespace}?q=query&limit=N
+// Searches the text indexes in the namespace with an FTS5 query and returns
+// the matching statements with their score in ndjson, best match first.
+// The namespace can be a wildcard as in MCQL; the default limit is 100.
+// Malformed queries, and searches without text indexes, fail with 400.
+func (node *Node) httpSearch(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	ns := vars["namespace"]
+
+	if ns != "*" && !nsrx.Match([]byte(strings.TrimSuffix(ns, ".*"))) {
+		apiError(w, http.StatusBadRequest, BadNamespace)
+		return
+	}
+
+	q := r.URL.Query().Get("q")
+	if q == "" {
+		apiError(w, http.StatusBadRequest, BadQuery)
+		return
+	}
+
+	limit := 100
+	if lim := r.URL.Query().Get("limit"); lim != "" {
+		xlim, err := strconv.Atoi(lim)
+		if err != nil || xlim <= 0 {
+			apiError(w, http.StatusBadRequest, BadQuery)
+			return
+		}
+		limit = xlim
+	}
+
+	res, err := node.db.Search(ns, q, limit)
+	if err != nil {
+		_, badq := err.(SearchQueryError)
+		if badq || err == NoTextSearch {
+			apiError(w, http.StatusBadRequest, err)
+		} else {
+			apiError(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	enc := json.NewEncoder(w)
+	for _, obj := range res {
+		err = enc.Encode(obj)
+		if err != nil {
+			log.Printf("Error encoding search result: %s", err.Error())
+			return
+		}
+	}
+}
+
 // datastore interface
 type DataObject struct {
 	Data []byte `json:"data"`
@@ -950,15 +1601,15 @@ func (node *Node) httpSyncData(w http.ResponseWriter, r *http.Request) {
 }
 
 func (node *Node) httpDataKeys(w http.ResponseWriter, r *http.Request) {
-	keys, err := node.ds.IterKeys(r.Context())
-	if err != nil {
-		apiError(w, http.StatusInternalServerError, err)
-		return
-	}
-
+	keys, errch := node.ds.IterKeys(r.Context())
 	for key := range keys {
 		fmt.Fprintln(w, multihash.Multihash(key).B58String())
 	}
+
+	err := <-errch
+	if err != nil {
+		log.Printf("Error iterating datastore keys: %s", err.Error())
+	}
 }
 
 // GET /status
@@ -1038,7 +1689,9 @@ func (node *Node) httpConfigDirSet(w http.ResponseWriter, r *http.Request) {
 		dirs = append(dirs, pinfo)
 	}
 
+	node.mx.Lock()
 	node.dir = dirs
+	node.mx.Unlock()
 
 	err := node.saveConfig()
 	if err != nil {
@@ -1074,7 +1727,9 @@ func (node *Node) httpConfigNATSet(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	node.mx.Lock()
 	node.natCfg = cfg
+	node.mx.Unlock()
 
 	err = node.saveConfig()
 	if err != nil {
@@ -1103,7 +1758,99 @@ func (node *Node) httpConfigInfoSet(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	node.mx.Lock()
 	node.info = strings.TrimSpace(string(body))
+	node.mx.Unlock()
+
+	err = node.saveConfig()
+	if err != nil {
+		apiError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	fmt.Fprintln(w, "OK")
+}
+
+// GET  /config/explain
+// POST /config/explain
+// retrieve/set the policy for remote EXPLAIN queries: allow or deny
+func (node *Node) httpConfigExplain(w http.ResponseWriter, r *http.Request) {
+	apiConfigMethod(w, r, node.httpConfigExplainGet, node.httpConfigExplainSet)
+}
+
+func (node *Node) httpConfigExplainGet(w http.ResponseWriter, r *http.Request) {
+	node.mx.Lock()
+	explain := node.explain
+	node.mx.Unlock()
+
+	if explain {
+		fmt.Fprintln(w, "allow")
+	} else {
+		fmt.Fprintln(w, "deny")
+	}
+}
+
+func (node *Node) httpConfigExplainSet(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("http/config/explain: Error reading request body: %s", err.Error())
+		return
+	}
+
+	var explain bool
+	switch strings.TrimSpace(string(body)) {
+	case "allow":
+		explain = true
+	case "deny":
+		explain = false
+	default:
+		apiError(w, http.StatusBadRequest, BadPolicy)
+		return
+	}
+
+	node.mx.Lock()
+	node.explain = explain
+	node.mx.Unlock()
+
+	err = node.saveConfig()
+	if err != nil {
+		apiError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	fmt.Fprintln(w, "OK")
+}
+
+// GET  /config/policy
+// POST /config/policy
+// DATA: json-encoded MergePolicy
+// retrieves/sets the merge policy and namespace aliases
+func (node *Node) httpConfigPolicy(w http.ResponseWriter, r *http.Request) {
+	apiConfigMethod(w, r, node.httpConfigPolicyGet, node.httpConfigPolicySet)
+}
+
+func (node *Node) httpConfigPolicyGet(w http.ResponseWriter, r *http.Request) {
+	err := json.NewEncoder(w).Encode(node.getPolicy())
+	if err != nil {
+		log.Printf("Error writing response body: %s", err.Error())
+	}
+}
+
+func (node *Node) httpConfigPolicySet(w http.ResponseWriter, r *http.Request) {
+	var policy MergePolicy
+	err := json.NewDecoder(r.Body).Decode(&policy)
+	if err != nil {
+		apiError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = policy.validate()
+	if err != nil {
+		apiError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	node.setPolicy(&policy)
 
 	err = node.saveConfig()
 	if err != nil {
@@ -1114,6 +1861,72 @@ func (node *Node) httpConfigInfoSet(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprintln(w, "OK")
 }
 
+// GET  /config/trust
+// POST /config/trust
+// DATA: json-encoded TrustPolicy
+// retrieves/sets the publisher trust policy; setting the policy refreshes
+// the publishers bound to trusted entities in the background.
+func (node *Node) httpConfigTrust(w http.ResponseWriter, r *http.Request) {
+	apiConfigMethod(w, r, node.httpConfigTrustGet, node.httpConfigTrustSet)
+}
+
+func (node *Node) httpConfigTrustGet(w http.ResponseWriter, r *http.Request) {
+	err := json.NewEncoder(w).Encode(node.trust.getPolicy())
+	if err != nil {
+		log.Printf("Error writing response body: %s", err.Error())
+	}
+}
+
+func (node *Node) httpConfigTrustSet(w http.ResponseWriter, r *http.Request) {
+	var policy TrustPolicy
+	err := json.NewDecoder(r.Body).Decode(&policy)
+	if err != nil {
+		apiError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = policy.validate()
+	if err != nil {
+		apiError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	node.trust.setPolicy(policy)
+
+	err = node.saveConfig()
+	if err != nil {
+		apiError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	node.refreshTrust()
+
+	fmt.Fprintln(w, "OK")
+}
+
+// GET /trust
+// Returns the trusted publishers, the publishers bound to trusted entities,
+// and the number of statements rejected per untrusted publisher in json
+func (node *Node) httpTrust(w http.ResponseWriter, r *http.Request) {
+	err := json.NewEncoder(w).Encode(node.trust.report())
+	if err != nil {
+		log.Printf("Error writing response body: %s", err.Error())
+	}
+}
+
+// POST /trust/refresh
+// Retrieves and verifies the manifests of the trusted entities from the
+// directory; returns the number of publishers bound to trusted entities
+func (node *Node) httpTrustRefresh(w http.ResponseWriter, r *http.Request) {
+	count, err := node.doRefreshTrust(r.Context())
+	if err != nil {
+		apiNetError(w, err)
+		return
+	}
+
+	fmt.Fprintln(w, count)
+}
+
 // GET /auth
 // retrieves all peer authorization rules in json
 func (node *Node) httpAuth(w http.ResponseWriter, r *http.Request) {
@@ -1134,6 +1947,41 @@ func (node *Node) httpAuthPeer(w http.ResponseWriter, r *http.Request) {
 }
 
 func (node *Node) httpAuthPeerGet(w http.ResponseWriter, r *http.Request) {
+	httpAuthRulesGet(w, r, &node.auth)
+}
+
+func (node *Node) httpAuthPeerSet(w http.ResponseWriter, r *http.Request) {
+	node.httpAuthRulesSet(w, r, &node.auth)
+}
+
+// GET /auth/read
+// retrieves all peer read authorization rules in json
+func (node *Node) httpAuthRead(w http.ResponseWriter, r *http.Request) {
+	rules := node.rauth.toJSON()
+
+	err := json.NewEncoder(w).Encode(rules)
+	if err != nil {
+		log.Printf("Error writing response body: %s", err.Error())
+	}
+}
+
+// GET  /auth/read/{peerId}
+// POST /auth/read/{peerId}
+// gets/sets read auth rules for peerId, granting access to private namespaces
+// rules are specified as a comma separated list of namespaces (or ns wildcards)
+func (node *Node) httpAuthReadPeer(w http.ResponseWriter, r *http.Request) {
+	apiConfigMethod(w, r, node.httpAuthReadPeerGet, node.httpAuthReadPeerSet)
+}
+
+func (node *Node) httpAuthReadPeerGet(w http.ResponseWriter, r *http.Request) {
+	httpAuthRulesGet(w, r, &node.rauth.PeerAuth)
+}
+
+func (node *Node) httpAuthReadPeerSet(w http.ResponseWriter, r *http.Request) {
+	node.httpAuthRulesSet(w, r, &node.rauth.PeerAuth)
+}
+
+func httpAuthRulesGet(w http.ResponseWriter, r *http.Request, auth *PeerAuth) {
 	vars := mux.Vars(r)
 	peerId := vars["peerId"]
 
@@ -1143,13 +1991,13 @@ func (node *Node) httpAuthPeerGet(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rules := node.auth.getRules(pid)
+	rules := auth.getRules(pid)
 	if len(rules) > 0 {
 		fmt.Fprintln(w, strings.Join(rules, ","))
 	}
 }
 
-func (node *Node) httpAuthPeerSet(w http.ResponseWriter, r *http.Request) {
+func (node *Node) httpAuthRulesSet(w http.ResponseWriter, r *http.Request, auth *PeerAuth) {
 	vars := mux.Vars(r)
 	peerId := vars["peerId"]
 
@@ -1167,10 +2015,58 @@ func (node *Node) httpAuthPeerSet(w http.ResponseWriter, r *http.Request) {
 
 	rbody := strings.TrimSpace(string(body))
 	if rbody == "" {
-		node.auth.clearRules(pid)
+		auth.clearRules(pid)
+	} else {
+		rules, err := parseNamespaceRules(rbody)
+		if err != nil {
+			apiError(w, http.StatusBadRequest, err)
+			return
+		}
+		auth.setRules(pid, rules)
+	}
+
+	err = node.saveConfig()
+	if err != nil {
+		apiError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	fmt.Fprintln(w, "OK")
+}
+
+// GET  /auth/private
+// POST /auth/private
+// gets/sets the private namespaces, which are only readable by remote peers
+// granted read access; all other namespaces are public.
+// namespaces are specified as a comma separated list of namespaces (or ns wildcards)
+func (node *Node) httpAuthPrivate(w http.ResponseWriter, r *http.Request) {
+	apiConfigMethod(w, r, node.httpAuthPrivateGet, node.httpAuthPrivateSet)
+}
+
+func (node *Node) httpAuthPrivateGet(w http.ResponseWriter, r *http.Request) {
+	rules := node.rauth.getPrivate()
+	if len(rules) > 0 {
+		fmt.Fprintln(w, strings.Join(rules, ","))
+	}
+}
+
+func (node *Node) httpAuthPrivateSet(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("http/auth: Error reading request body: %s", err.Error())
+		return
+	}
+
+	rbody := strings.TrimSpace(string(body))
+	if rbody == "" {
+		node.rauth.setPrivate(nil)
 	} else {
-		rules := strings.Split(rbody, ",")
-		node.auth.setRules(pid, rules)
+		rules, err := parseNamespaceRules(rbody)
+		if err != nil {
+			apiError(w, http.StatusBadRequest, err)
+			return
+		}
+		node.rauth.setPrivate(rules)
 	}
 
 	err = node.saveConfig()
@@ -1219,7 +2115,9 @@ loop:
 		}
 	}
 
+	node.mx.Lock()
 	node.mfs = mfs
+	node.mx.Unlock()
 
 	err := node.saveConfig()
 	if err != nil {
@@ -0,0 +1,489 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	ggproto "github.com/gogo/protobuf/proto"
+	p2p_crypto "github.com/libp2p/go-libp2p-crypto"
+	mc "github.com/mediachain/concat/mc"
+	mcq "github.com/mediachain/concat/mc/query"
+	pb "github.com/mediachain/concat/proto"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Archives package a namespace snapshot for offline distribution as a tarball
+// with the following entries:
+// archive.json is the archive statement, signed by the node that produced
+// the archive; it is not stored in the statement db.
+// data/{objectId} are the metadata objects of the statements.
+// stmt.ndjson are the statements in id order.
+// The archive statement records the number of statements, the first and
+// last statement ids and the Merkle root of the statement hashes, so that
+// an archive can be verified before it is imported.
+const (
+	archiveStatementEntry = "archive.json"
+	archiveDataPrefix     = "data/"
+	archiveStmtEntry      = "stmt.ndjson"
+)
+
+// archiveHasher accumulates the statement hashes of an archive; statements
+// must be added in id order.
+type archiveHasher struct {
+	count  int64
+	first  string
+	last   string
+	leaves [][]byte
+}
+
+func (h *archiveHasher) add(stmt *pb.Statement) error {
+	if h.count > 0 && stmt.Id <= h.last {
+		return BadArchive
+	}
+
+	bytes, err := ggproto.Marshal(stmt)
+	if err != nil {
+		return err
+	}
+
+	if h.count == 0 {
+		h.first = stmt.Id
+	}
+	h.last = stmt.Id
+	h.count++
+	h.leaves = append(h.leaves, merkleHash(0, bytes))
+	return nil
+}
+
+func (h *archiveHasher) archive() *pb.ArchiveStatement {
+	return &pb.ArchiveStatement{
+		Count: h.count,
+		First: h.first,
+		Last:  h.last,
+		Root:  merkleRoot(h.leaves),
+	}
+}
+
+// merkleHash hashes a tree node; leaves and interior nodes are hashed
+// with a different prefix so that they can't be confused.
+func merkleHash(prefix byte, data ...[]byte) []byte {
+	hash := sha256.New()
+	hash.Write([]byte{prefix})
+	for _, xdata := range data {
+		hash.Write(xdata)
+	}
+	return hash.Sum(nil)
+}
+
+// merkleRoot computes the root of the binary tree over the leaves;
+// the last node of an odd level is promoted to the next level.
+func merkleRoot(leaves [][]byte) []byte {
+	if len(leaves) == 0 {
+		return nil
+	}
+
+	level := leaves
+	for len(level) > 1 {
+		next := make([][]byte, 0, (len(level)+1)/2)
+		for x := 0; x < len(level); x += 2 {
+			if x+1 < len(level) {
+				next = append(next, merkleHash(1, level[x], level[x+1]))
+			} else {
+				next = append(next, level[x])
+			}
+		}
+		level = next
+	}
+
+	return level[0]
+}
+
+// doArchive writes an archive of a namespace; it returns the archive
+// statement and the number of objects archived.
+func (node *Node) doArchive(ctx context.Context, ns string, w io.Writer) (*pb.Statement, int, error) {
+	// ids are ordered bytewise regardless of the db collation, as the
+	// hasher requires
+	q, err := mcq.ParseQuery("SELECT * FROM " + ns + " ORDER BY id")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// statements are spooled so that the archive statement can be written first
+	tmp, err := ioutil.TempFile("", "mcarchive")
+	if err != nil {
+		return nil, 0, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ch, err := node.db.QueryStream(ctx, q)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var hasher archiveHasher
+	keys := make(map[string]Key)
+	enc := json.NewEncoder(tmp)
+	for val := range ch {
+		switch val := val.(type) {
+		case *pb.Statement:
+			err = hasher.add(val)
+			if err != nil {
+				return nil, 0, err
+			}
+
+			err = node.mergeStatementKeys(val, keys)
+			if err != nil {
+				return nil, 0, err
+			}
+
+			err = enc.Encode(val)
+			if err != nil {
+				return nil, 0, err
+			}
+
+		case StreamError:
+			return nil, 0, val
+
+		default:
+			return nil, 0, BadResult
+		}
+	}
+
+	if hasher.count == 0 {
+		return nil, 0, NoResult
+	}
+
+	stmt, err := node.makeStatement(ns, hasher.archive())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	tw := tar.NewWriter(w)
+	ts := time.Unix(stmt.Timestamp, 0)
+
+	sbytes, err := json.Marshal(stmt)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = writeArchiveEntry(tw, archiveStatementEntry, int64(len(sbytes)), ts, bytes.NewReader(sbytes))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	key58s := make([]string, 0, len(keys))
+	for key58, _ := range keys {
+		key58s = append(key58s, key58)
+	}
+	sort.Strings(key58s)
+
+	ocount := 0
+	for _, key58 := range key58s {
+		data, err := node.ds.Get(keys[key58])
+		if err != nil {
+			return nil, ocount, err
+		}
+
+		if data == nil {
+			continue
+		}
+
+		err = writeArchiveEntry(tw, archiveDataPrefix+key58, int64(len(data)), ts, bytes.NewReader(data))
+		if err != nil {
+			return nil, ocount, err
+		}
+		ocount++
+	}
+
+	if ocount < len(keys) {
+		log.Printf("Archive of %s is missing %d objects", ns, len(keys)-ocount)
+	}
+
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, ocount, err
+	}
+
+	_, err = tmp.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, ocount, err
+	}
+
+	err = writeArchiveEntry(tw, archiveStmtEntry, size, ts, tmp)
+	if err != nil {
+		return nil, ocount, err
+	}
+
+	return stmt, ocount, tw.Close()
+}
+
+func writeArchiveEntry(tw *tar.Writer, name string, size int64, ts time.Time, r io.Reader) error {
+	hdr := &tar.Header{
+		Name:     name,
+		Mode:     0644,
+		Size:     size,
+		ModTime:  ts,
+		Typeflag: tar.TypeReg,
+	}
+
+	err := tw.WriteHeader(hdr)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.CopyN(tw, r, size)
+	return err
+}
+
+// doImportArchive verifies an archive and imports its objects and statements;
+// nothing is imported unless the whole archive verifies.
+// Returns the number of statements and objects imported, and the number of
+// statements rejected by publisher trust.
+func (node *Node) doImportArchive(r io.Reader) (int, int, int, error) {
+	tmp, err := ioutil.TempFile("", "mcarchive")
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	_, err = io.Copy(tmp, r)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	_, err = tmp.Seek(0, io.SeekStart)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	keys, err := node.verifyArchive(tmp)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	_, err = tmp.Seek(0, io.SeekStart)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	return node.loadArchive(tmp, keys)
+}
+
+// verifyArchive checks the archive statement signature and publisher trust,
+// the statements against it and their own signatures, and the data objects
+// against their keys; returns the object keys of the statements.
+func (node *Node) verifyArchive(r io.Reader) (map[string]Key, error) {
+	tr := tar.NewReader(r)
+
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	if hdr.Name != archiveStatementEntry {
+		return nil, BadArchive
+	}
+
+	astmt := new(pb.Statement)
+	err = json.NewDecoder(tr).Decode(astmt)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := astmt.GetBody().GetArchive()
+	if archive == nil || !node.checkStatement(astmt) {
+		return nil, BadArchive
+	}
+
+	verify, err := node.verifyStatement(astmt)
+	if err != nil {
+		return nil, err
+	}
+
+	if !verify {
+		return nil, BadArchive
+	}
+
+	if !node.trustPublisher(astmt.Publisher) {
+		return nil, UntrustedArchive
+	}
+
+	var hasher archiveHasher
+	pkcache := make(map[string]p2p_crypto.PubKey)
+	keys := make(map[string]Key)
+	dkeys := make([]string, 0)
+	for {
+		hdr, err = tr.Next()
+		switch {
+		case err == io.EOF:
+			xarchive := hasher.archive()
+			if xarchive.Count != archive.Count ||
+				xarchive.First != archive.First ||
+				xarchive.Last != archive.Last ||
+				!bytes.Equal(xarchive.Root, archive.Root) {
+				return nil, BadArchive
+			}
+
+			// data objects must be referenced by the statements
+			for _, key58 := range dkeys {
+				_, ok := keys[key58]
+				if !ok {
+					return nil, UnexpectedData
+				}
+			}
+
+			return keys, nil
+
+		case err != nil:
+			return nil, err
+
+		case hdr.Name == archiveStmtEntry:
+			dec := json.NewDecoder(tr)
+			for {
+				stmt := new(pb.Statement)
+				err = dec.Decode(stmt)
+				if err == io.EOF {
+					break
+				}
+
+				if err != nil {
+					return nil, err
+				}
+
+				if stmt.Namespace != astmt.Namespace {
+					return nil, BadArchive
+				}
+
+				if !node.checkStatement(stmt) {
+					return nil, BadStatement
+				}
+
+				verify, err := node.verifyStatementCacheKeys(stmt, pkcache)
+				if err != nil {
+					return nil, err
+				}
+
+				if !verify {
+					return nil, BadStatement
+				}
+
+				err = hasher.add(stmt)
+				if err != nil {
+					return nil, err
+				}
+
+				err = node.mergeStatementKeys(stmt, keys)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+		case strings.HasPrefix(hdr.Name, archiveDataPrefix):
+			key58 := hdr.Name[len(archiveDataPrefix):]
+			data, err := ioutil.ReadAll(tr)
+			if err != nil {
+				return nil, err
+			}
+
+			if mc.Hash(data).B58String() != key58 {
+				return nil, BadData
+			}
+			dkeys = append(dkeys, key58)
+
+		default:
+			return nil, BadArchive
+		}
+	}
+}
+
+// loadArchive imports the objects and statements of a verified archive;
+// objects must be referenced by the statements and match their key.
+func (node *Node) loadArchive(r io.Reader, keys map[string]Key) (int, int, int, error) {
+	const batch = 1024
+
+	tr := tar.NewReader(r)
+	pkcache := make(map[string]p2p_crypto.PubKey)
+	count := 0
+	ocount := 0
+	rejects := 0
+
+	for {
+		hdr, err := tr.Next()
+		switch {
+		case err == io.EOF:
+			return count, ocount, rejects, nil
+
+		case err != nil:
+			return count, ocount, rejects, err
+
+		case strings.HasPrefix(hdr.Name, archiveDataPrefix):
+			key, ok := keys[hdr.Name[len(archiveDataPrefix):]]
+			if !ok {
+				return count, ocount, rejects, UnexpectedData
+			}
+
+			data, err := ioutil.ReadAll(tr)
+			if err != nil {
+				return count, ocount, rejects, err
+			}
+
+			if !bytes.Equal([]byte(key), []byte(mc.Hash(data))) {
+				return count, ocount, rejects, BadData
+			}
+
+			_, err = node.ds.Put(data)
+			if err != nil {
+				return count, ocount, rejects, err
+			}
+			ocount++
+
+		case hdr.Name == archiveStmtEntry:
+			dec := json.NewDecoder(tr)
+			stmts := make([]*pb.Statement, 0, batch)
+			for {
+				stmt := new(pb.Statement)
+				err = dec.Decode(stmt)
+				if err == io.EOF {
+					break
+				}
+
+				if err != nil {
+					return count, ocount, rejects, err
+				}
+
+				stmts = append(stmts, stmt)
+				if len(stmts) >= batch {
+					xcount, xrejects, err := node.doImport(stmts, pkcache)
+					count += xcount
+					rejects += xrejects
+					if err != nil {
+						return count, ocount, rejects, err
+					}
+					stmts = stmts[:0]
+				}
+			}
+
+			if len(stmts) > 0 {
+				xcount, xrejects, err := node.doImport(stmts, pkcache)
+				count += xcount
+				rejects += xrejects
+				if err != nil {
+					return count, ocount, rejects, err
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	pb "github.com/mediachain/concat/proto"
+	multihash "github.com/multiformats/go-multihash"
+	"testing"
+)
+
+func TestArchive(t *testing.T) {
+	tn := newTestNet(t)
+	defer tn.Close()
+
+	a := tn.addNode()
+	b := tn.addNode()
+	c := tn.addNode()
+
+	ids, keys := publishTestObjects(t, a, "test.a", 10)
+
+	ctx := context.Background()
+	var buf bytes.Buffer
+	_, ocount, err := a.doArchive(ctx, "test.a", &buf)
+	checkErrorNow(t, "doArchive", err)
+	checkBool(t, "doArchive objects", ocount == 10)
+	archive := buf.Bytes()
+
+	count, ocount, _, err := b.doImportArchive(bytes.NewReader(archive))
+	checkErrorNow(t, "doImportArchive", err)
+	checkBool(t, "doImportArchive statements", count == 10)
+	checkBool(t, "doImportArchive objects", ocount == 10)
+	checkStatementIds(t, b, "SELECT id FROM *", ids)
+	checkObjects(t, b, keys, true)
+
+	// archives by untrusted publishers are rejected
+	c.trust.setPolicy(TrustPolicy{Publishers: []string{b.publisher.ID58}})
+	_, _, _, err = c.doImportArchive(bytes.NewReader(archive))
+	checkBool(t, "doImportArchive untrusted", err == UntrustedArchive)
+	c.trust.setPolicy(TrustPolicy{})
+
+	// a statement modified after signing fails the whole archive before
+	// anything is imported, even if the archive statement is signed
+	stmt, err := a.makeStatement("test.a", &pb.SimpleStatement{Object: multihash.Multihash(keys[0]).B58String()})
+	checkErrorNow(t, "makeStatement", err)
+	stmt.Body.GetSimple().Refs = []string{"test:forged"}
+	err = a.db.Put(stmt)
+	checkErrorNow(t, "Put", err)
+
+	buf.Reset()
+	_, _, err = a.doArchive(ctx, "test.a", &buf)
+	checkErrorNow(t, "doArchive forged", err)
+
+	count, ocount, _, err = c.doImportArchive(&buf)
+	checkBool(t, "doImportArchive forged", err == BadStatement)
+	checkBool(t, "doImportArchive forged statements", count == 0)
+	checkBool(t, "doImportArchive forged objects", ocount == 0)
+	checkStatementIds(t, c, "SELECT id FROM *", nil)
+	checkObjects(t, c, keys, false)
+}
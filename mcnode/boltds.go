@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	mc "github.com/mediachain/concat/mc"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Pure-Go datastore backend on bolt, for platforms where rocksdb doesn't
+// build. Objects are stored in a single bucket keyed like RocksDS, by the
+// hash digest without the multihash prefix.
+type BoltDS struct {
+	db *bolt.DB
+}
+
+var boltDataBucket = []byte("data")
+
+// keys are iterated in chunks, each in its own read transaction, so that
+// long iterations don't hold a transaction open; writers block on remapping
+// the db file while there are open read transactions.
+const boltIterChunk = 1024
+
+func (ds *BoltDS) Open(home string) error {
+	db, err := bolt.Open(datastorePath(home, DatastoreBolt), 0644, nil)
+	if err != nil {
+		return err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltDataBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return err
+	}
+
+	ds.db = db
+	return nil
+}
+
+func (ds *BoltDS) Put(data []byte) (Key, error) {
+	key := mc.Hash(data)
+	err := ds.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltDataBucket).Put(key[2:], data)
+	})
+	return Key(key), err
+}
+
+func (ds *BoltDS) PutBatch(batch [][]byte) ([]Key, error) {
+	keys := make([]Key, len(batch))
+	err := ds.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltDataBucket)
+		for x, data := range batch {
+			key := mc.Hash(data)
+			err := bucket.Put(key[2:], data)
+			if err != nil {
+				return err
+			}
+			keys[x] = Key(key)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (ds *BoltDS) Has(key Key) (bool, error) {
+	var have bool
+	err := ds.db.View(func(tx *bolt.Tx) error {
+		have = tx.Bucket(boltDataBucket).Get(key[2:]) != nil
+		return nil
+	})
+	return have, err
+}
+
+func (ds *BoltDS) Get(key Key) ([]byte, error) {
+	var data []byte
+	err := ds.db.View(func(tx *bolt.Tx) error {
+		// values are only valid for the life of the transaction
+		val := tx.Bucket(boltDataBucket).Get(key[2:])
+		if val != nil {
+			data = make([]byte, len(val))
+			copy(data, val)
+		}
+		return nil
+	})
+	return data, err
+}
+
+func (ds *BoltDS) Delete(key Key) error {
+	return ds.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltDataBucket).Delete(key[2:])
+	})
+}
+
+// Sync is a noop, as bolt syncs every write transaction
+func (ds *BoltDS) Sync() error {
+	return nil
+}
+
+func (ds *BoltDS) IterKeys(ctx context.Context) (<-chan Key, <-chan error) {
+	ch := make(chan Key)
+	errch := make(chan error, 1)
+	go func() {
+		defer close(ch)
+
+		var last []byte
+		for {
+			keys, err := ds.iterChunk(last)
+			if err != nil {
+				errch <- err
+				return
+			}
+
+			if len(keys) == 0 {
+				errch <- nil
+				return
+			}
+
+			for _, key := range keys {
+				select {
+				case ch <- Key(mc.HashFromBytes(key)):
+				case <-ctx.Done():
+					errch <- nil
+					return
+				}
+			}
+
+			last = keys[len(keys)-1]
+		}
+	}()
+	return ch, errch
+}
+
+// iterChunk returns the next chunk of keys after the last key
+func (ds *BoltDS) iterChunk(last []byte) ([][]byte, error) {
+	keys := make([][]byte, 0, boltIterChunk)
+	err := ds.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(boltDataBucket).Cursor()
+
+		var key []byte
+		if last == nil {
+			key, _ = cursor.First()
+		} else {
+			key, _ = cursor.Seek(last)
+			if key != nil && bytes.Equal(key, last) {
+				key, _ = cursor.Next()
+			}
+		}
+
+		for ; key != nil && len(keys) < boltIterChunk; key, _ = cursor.Next() {
+			xkey := make([]byte, len(key))
+			copy(xkey, key)
+			keys = append(keys, xkey)
+		}
+
+		return nil
+	})
+	return keys, err
+}
+
+// Compact is a noop; bolt reuses the pages freed by deletes, but doesn't
+// shrink the db file.
+func (ds *BoltDS) Compact() {}
+
+func (ds *BoltDS) Close() {
+	ds.db.Close()
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"context"
+	"errors"
+	mcq "github.com/mediachain/concat/mc/query"
+	pb "github.com/mediachain/concat/proto"
+	multihash "github.com/multiformats/go-multihash"
+	codec "github.com/ugorji/go/codec"
+	"reflect"
+)
+
+// Data queries resolve the metadata objects of the statements matching
+// their envelope criteria from the datastore. The cost of a data query is the
+// number of objects it needs to retrieve; queries exceeding the cost limit
+// fail and should be narrowed with envelope criteria.
+// The results are selected in batches of DataQueryBatch matching statements.
+const (
+	LocalDataQueryCost  = 1000000
+	RemoteDataQueryCost = 10000
+	DataQueryBatch      = 1024
+)
+
+var (
+	DataQueryCostExceeded = errors.New("Data query exceeds cost limit; narrow the query with envelope criteria")
+)
+
+var cborHandle *codec.CborHandle
+
+func init() {
+	cborHandle = new(codec.CborHandle)
+	cborHandle.MapType = reflect.TypeOf(map[string]interface{}(nil))
+}
+
+func (node *Node) doQuery(ctx context.Context, q *mcq.Query, cursor bool, cost int) (<-chan interface{}, error) {
+	switch {
+	case q.IsDataQuery():
+		return node.doDataQuery(ctx, q, cursor, cost)
+	case cursor:
+		return node.db.QueryStreamCursor(ctx, q)
+	default:
+		return node.db.QueryStream(ctx, q)
+	}
+}
+
+func (node *Node) doDataQuery(ctx context.Context, q *mcq.Query, cursor bool, cost int) (<-chan interface{}, error) {
+	sq, filter, err := mcq.DataQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.IsAggregateSelect() {
+		return node.doDataQueryAggregate(ctx, q, sq, filter, cost)
+	}
+
+	return node.doDataQueryRows(ctx, q, sq, filter, cursor, cost)
+}
+
+// doDataQueryRows selects the results of a data query in batches of
+// DataQueryBatch matching statements, as the statements are scanned.
+func (node *Node) doDataQueryRows(ctx context.Context, q *mcq.Query, sq *mcq.Query, filter mcq.DataFilter, cursor bool, cost int) (<-chan interface{}, error) {
+	ch := make(chan interface{})
+	go func() {
+		defer close(ch)
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		rows := mcq.NewDataRows(q)
+		batch := make([]string, 0, DataQueryBatch)
+		var last interface{}
+
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+
+			xq, err := q.WithDataIds(batch)
+			if err != nil {
+				return err
+			}
+			batch = batch[:0]
+
+			var rch <-chan interface{}
+			if cursor {
+				rch, err = node.db.QueryStreamCursor(ctx, xq)
+			} else {
+				rch, err = node.db.QueryStream(ctx, xq)
+			}
+			if err != nil {
+				return err
+			}
+
+			for val := range rch {
+				switch val := val.(type) {
+				case StreamError:
+					return val
+
+				case QueryCursor:
+					// only the cursor of the last batch ends the stream
+					last = val
+					continue
+				}
+
+				if !rows.AddRow(val) {
+					continue
+				}
+
+				select {
+				case ch <- val:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+
+			return ctx.Err()
+		}
+
+		err := node.dataQueryScan(ctx, sq, filter, cost, func(stmt *pb.Statement) error {
+			if !rows.AddId() {
+				return nil
+			}
+
+			batch = append(batch, stmt.Id)
+			if len(batch) == DataQueryBatch {
+				return flush()
+			}
+
+			return nil
+		}, rows.Done)
+
+		if err == nil {
+			err = flush()
+		}
+
+		switch {
+		case err == context.Canceled:
+			return
+		case err != nil:
+			sendStreamError(ctx, ch, err.Error())
+		case last != nil:
+			select {
+			case ch <- last:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// doDataQueryAggregate evaluates an aggregate data query over the matching
+// statements, as they are scanned.
+func (node *Node) doDataQueryAggregate(ctx context.Context, q *mcq.Query, sq *mcq.Query, filter mcq.DataFilter, cost int) (<-chan interface{}, error) {
+	rs, err := mcq.NewDataResultSet(q)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan interface{})
+	go func() {
+		defer close(ch)
+
+		err := node.dataQueryScan(ctx, sq, filter, cost, func(stmt *pb.Statement) error {
+			rs.Add(stmt)
+			return nil
+		}, nil)
+
+		switch {
+		case err == context.Canceled:
+			return
+		case err != nil:
+			sendStreamError(ctx, ch, err.Error())
+			return
+		}
+
+		for _, val := range rs.Result() {
+			select {
+			case ch <- val:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// dataQueryScan streams the statements selected by the scan query sq and
+// passes those whose objects match the filter to match, until the scan
+// ends or done returns true.
+func (node *Node) dataQueryScan(ctx context.Context, sq *mcq.Query, filter mcq.DataFilter, cost int, match func(*pb.Statement) error, done func() bool) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ch, err := node.db.QueryStream(ctx, sq)
+	if err != nil {
+		return err
+	}
+
+	for val := range ch {
+		switch val := val.(type) {
+		case *pb.Statement:
+			for key, _ := range mcq.StatementObjects(val) {
+				if cost == 0 {
+					return DataQueryCostExceeded
+				}
+				cost--
+
+				obj, err := node.getDataObject(key)
+				if err != nil {
+					return err
+				}
+
+				if obj != nil && filter(obj) {
+					err = match(val)
+					if err != nil {
+						return err
+					}
+					break
+				}
+			}
+
+			if done != nil && done() {
+				return nil
+			}
+
+		case StreamError:
+			return val
+
+		default:
+			return BadResult
+		}
+	}
+
+	return ctx.Err()
+}
+
+// getDataObject retrieves and decodes a metadata object; missing objects
+// and objects that are not valid CBOR have no content and return nil.
+func (node *Node) getDataObject(key58 string) (interface{}, error) {
+	mhash, err := multihash.FromB58String(key58)
+	if err != nil {
+		return nil, nil
+	}
+
+	data, err := node.ds.Get(Key(mhash))
+	if err != nil {
+		return nil, err
+	}
+
+	if data == nil {
+		return nil, nil
+	}
+
+	var obj interface{}
+	err = codec.NewDecoderBytes(data, cborHandle).Decode(&obj)
+	if err != nil {
+		return nil, nil
+	}
+
+	return obj, nil
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"fmt"
@@ -10,7 +11,9 @@ import (
 	pb "github.com/mediachain/concat/proto"
 	"os"
 	"path"
+	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 type SQLDB struct {
@@ -18,11 +21,21 @@ type SQLDB struct {
 	insertStmtData     *sql.Stmt
 	insertStmtEnvelope *sql.Stmt
 	insertStmtRefs     *sql.Stmt
+	insertStmtTags     *sql.Stmt
+	insertStmtDeps     *sql.Stmt
+	insertStmtObjects  *sql.Stmt
+	insertStmtTargets  *sql.Stmt
 	selectStmtData     *sql.Stmt
 	deleteStmtData     *sql.Stmt
 	deleteStmtEnvelope *sql.Stmt
 	deleteStmtRefs     *sql.Stmt
-	wlock              sync.Mutex
+	deleteStmtTags     *sql.Stmt
+	deleteStmtDeps     *sql.Stmt
+	deleteStmtObjects  *sql.Stmt
+	deleteStmtFields   *sql.Stmt
+	deleteStmtTargets  *sql.Stmt
+	wlock              sync.Locker
+	dialect            mcq.SQLDialect
 }
 
 func (sdb *SQLDB) Put(stmt *pb.Statement) error {
@@ -46,20 +59,40 @@ func (sdb *SQLDB) Put(stmt *pb.Statement) error {
 		return err
 	}
 
-	xstmt = tx.Stmt(sdb.insertStmtEnvelope)
-	_, err = xstmt.Exec(stmt.Id, stmt.Namespace, stmt.Publisher, mcq.StatementSource(stmt), stmt.Timestamp)
+	err = insertStatementIndex(tx.Stmt(sdb.insertStmtRefs), stmt.Id, mcq.StatementRefs(stmt))
 	if err != nil {
 		tx.Rollback()
 		return err
 	}
 
-	xstmt = tx.Stmt(sdb.insertStmtRefs)
-	for wki, _ := range mcq.StatementRefs(stmt) {
-		_, err = xstmt.Exec(stmt.Id, wki)
-		if err != nil {
-			tx.Rollback()
-			return err
-		}
+	err = insertStatementIndex(tx.Stmt(sdb.insertStmtTags), stmt.Id, mcq.StatementTags(stmt))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = insertStatementIndex(tx.Stmt(sdb.insertStmtDeps), stmt.Id, mcq.StatementDeps(stmt))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = insertStatementIndex(tx.Stmt(sdb.insertStmtObjects), stmt.Id, mcq.StatementObjects(stmt))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = insertStatementTargets(tx.Stmt(sdb.insertStmtTargets), stmt)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = sdb.insertEnvelopes(tx, tx.Stmt(sdb.insertStmtEnvelope), []*pb.Statement{stmt})
+	if err != nil {
+		tx.Rollback()
+		return err
 	}
 
 	return tx.Commit()
@@ -75,8 +108,11 @@ func (sdb *SQLDB) PutBatch(stmts []*pb.Statement) error {
 	}
 
 	insertData := tx.Stmt(sdb.insertStmtData)
-	insertEnvelope := tx.Stmt(sdb.insertStmtEnvelope)
 	insertRefs := tx.Stmt(sdb.insertStmtRefs)
+	insertTags := tx.Stmt(sdb.insertStmtTags)
+	insertDeps := tx.Stmt(sdb.insertStmtDeps)
+	insertObjects := tx.Stmt(sdb.insertStmtObjects)
+	insertTargets := tx.Stmt(sdb.insertStmtTargets)
 
 	for _, stmt := range stmts {
 		bytes, err := ggproto.Marshal(stmt)
@@ -91,24 +127,119 @@ func (sdb *SQLDB) PutBatch(stmts []*pb.Statement) error {
 			return err
 		}
 
-		_, err = insertEnvelope.Exec(stmt.Id, stmt.Namespace, stmt.Publisher, mcq.StatementSource(stmt), stmt.Timestamp)
+		err = insertStatementIndex(insertRefs, stmt.Id, mcq.StatementRefs(stmt))
 		if err != nil {
 			tx.Rollback()
 			return err
 		}
 
-		for wki, _ := range mcq.StatementRefs(stmt) {
-			_, err = insertRefs.Exec(stmt.Id, wki)
-			if err != nil {
-				tx.Rollback()
-				return err
-			}
+		err = insertStatementIndex(insertTags, stmt.Id, mcq.StatementTags(stmt))
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		err = insertStatementIndex(insertDeps, stmt.Id, mcq.StatementDeps(stmt))
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		err = insertStatementIndex(insertObjects, stmt.Id, mcq.StatementObjects(stmt))
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		err = insertStatementTargets(insertTargets, stmt)
+		if err != nil {
+			tx.Rollback()
+			return err
 		}
 	}
 
+	err = sdb.insertEnvelopes(tx, tx.Stmt(sdb.insertStmtEnvelope), stmts)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	return tx.Commit()
 }
 
+// insertEnvelopes inserts the envelopes of the statements written by a
+// transaction, as the last step before it commits.
+// Envelope counters must be assigned in commit order, as counter cursors
+// would otherwise skip statements committed after a later counter was
+// seen. Sqlite writes are serialized by wlock; postgres transactions take
+// an advisory lock here, so that it is only held while the counters are
+// assigned and the transaction commits, and concurrent writes otherwise
+// overlap.
+func (sdb *SQLDB) insertEnvelopes(tx *sql.Tx, xstmt *sql.Stmt, stmts []*pb.Statement) error {
+	if len(stmts) == 0 {
+		return nil
+	}
+
+	if sdb.dialect == mcq.Postgres {
+		_, err := tx.Exec("SELECT pg_advisory_xact_lock($1)", envelopeLockKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, stmt := range stmts {
+		_, err := xstmt.Exec(stmt.Id, stmt.Namespace, stmt.Publisher, mcq.StatementSource(stmt), stmt.Timestamp)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bind rewrites the ? placeholders of a statement for the sql dialect;
+// postgres uses numbered placeholders.
+func (sdb *SQLDB) bind(q string) string {
+	if sdb.dialect != mcq.Postgres {
+		return q
+	}
+
+	var buf bytes.Buffer
+	n := 0
+	for _, c := range q {
+		if c == '?' {
+			n++
+			fmt.Fprintf(&buf, "$%d", n)
+		} else {
+			buf.WriteRune(c)
+		}
+	}
+	return buf.String()
+}
+
+func insertStatementIndex(xstmt *sql.Stmt, id string, keys mcq.StatementRefSet) error {
+	for key, _ := range keys {
+		_, err := xstmt.Exec(id, key)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insertStatementTargets records the statements retracted or superseded by
+// a statement; targets are only honored for statements by the same publisher.
+func insertStatementTargets(xstmt *sql.Stmt, stmt *pb.Statement) error {
+	targets, retract := mcq.StatementTargets(stmt)
+	for _, target := range targets {
+		_, err := xstmt.Exec(stmt.Id, stmt.Publisher, target, retract)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (sdb *SQLDB) Get(id string) (*pb.Statement, error) {
 	row := sdb.selectStmtData.QueryRow(id)
 
@@ -132,7 +263,7 @@ func (sdb *SQLDB) Get(id string) (*pb.Statement, error) {
 }
 
 func (sdb *SQLDB) Query(q *mcq.Query) ([]interface{}, error) {
-	sq, rsel, err := mcq.CompileQuery(q)
+	sq, rsel, err := mcq.CompileQueryDialect(q, sdb.dialect)
 	if err != nil {
 		return nil, err
 	}
@@ -156,11 +287,30 @@ func (sdb *SQLDB) Query(q *mcq.Query) ([]interface{}, error) {
 }
 
 func (sdb *SQLDB) QueryStream(ctx context.Context, q *mcq.Query) (<-chan interface{}, error) {
-	sq, rsel, err := mcq.CompileQuery(q)
+	sq, rsel, err := mcq.CompileQueryDialect(q, sdb.dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	return sdb.queryStream(ctx, sq, rsel, nil)
+}
+
+// QueryStreamCursor is like QueryStream, but the result stream of cursor
+// queries is terminated with a QueryCursor
+func (sdb *SQLDB) QueryStreamCursor(ctx context.Context, q *mcq.Query) (<-chan interface{}, error) {
+	if !q.IsCursorQuery() {
+		return sdb.QueryStream(ctx, q)
+	}
+
+	sq, rsel, err := mcq.CompileCursorQueryDialect(q, sdb.dialect)
 	if err != nil {
 		return nil, err
 	}
 
+	return sdb.queryStream(ctx, sq, rsel, rsel)
+}
+
+func (sdb *SQLDB) queryStream(ctx context.Context, sq string, rsel mcq.RowSelector, cursor *mcq.RowSelectCursor) (<-chan interface{}, error) {
 	rows, err := sdb.db.Query(sq)
 	if err != nil {
 		return nil, err
@@ -171,6 +321,7 @@ func (sdb *SQLDB) QueryStream(ctx context.Context, q *mcq.Query) (<-chan interfa
 		defer close(ch)
 		defer rows.Close()
 
+		count := 0
 		for rows.Next() {
 			obj, err := rsel.Scan(rows)
 			if err != nil {
@@ -180,18 +331,77 @@ func (sdb *SQLDB) QueryStream(ctx context.Context, q *mcq.Query) (<-chan interfa
 
 			select {
 			case ch <- obj:
+				count += 1
 				continue
 			case <-ctx.Done():
 				return
 			}
 		}
+
+		if cursor != nil && count > 0 {
+			select {
+			case ch <- QueryCursor{cursor.Cursor()}:
+			case <-ctx.Done():
+			}
+		}
 	}()
 
 	return ch, nil
 }
 
+// Explain returns the compiled SQL for an EXPLAIN query, followed by the
+// steps of the query plan: {"sql": sql} {"plan": detail} ...
+func (sdb *SQLDB) Explain(q *mcq.Query) ([]interface{}, error) {
+	sq, _, err := mcq.CompileQueryDialect(q, sdb.dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	explain := "EXPLAIN QUERY PLAN "
+	if sdb.dialect == mcq.Postgres {
+		explain = "EXPLAIN "
+	}
+
+	rows, err := sdb.db.Query(explain + sq)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// the plan columns differ between sqlite versions, but the detail
+	// is always the last column; postgres has a single column
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	vals := make([]interface{}, len(cols))
+	for x := range vals {
+		vals[x] = new(interface{})
+	}
+
+	res := make([]interface{}, 0)
+	res = append(res, map[string]interface{}{"sql": sq})
+	for rows.Next() {
+		err = rows.Scan(vals...)
+		if err != nil {
+			return nil, err
+		}
+
+		detail := *(vals[len(vals)-1].(*interface{}))
+		switch detail := detail.(type) {
+		case []byte:
+			res = append(res, map[string]interface{}{"plan": string(detail)})
+		default:
+			res = append(res, map[string]interface{}{"plan": fmt.Sprintf("%v", detail)})
+		}
+	}
+
+	return res, rows.Err()
+}
+
 func (sdb *SQLDB) QueryOne(q *mcq.Query) (interface{}, error) {
-	sq, rsel, err := mcq.CompileQuery(q)
+	sq, rsel, err := mcq.CompileQueryDialect(q, sdb.dialect)
 	if err != nil {
 		return nil, err
 	}
@@ -232,6 +442,20 @@ func (sdb *SQLDB) Delete(q *mcq.Query) (count int, err error) {
 	delData := tx.Stmt(sdb.deleteStmtData)
 	delEnvelope := tx.Stmt(sdb.deleteStmtEnvelope)
 	delRefs := tx.Stmt(sdb.deleteStmtRefs)
+	delTags := tx.Stmt(sdb.deleteStmtTags)
+	delDeps := tx.Stmt(sdb.deleteStmtDeps)
+	delObjects := tx.Stmt(sdb.deleteStmtObjects)
+	delFields := tx.Stmt(sdb.deleteStmtFields)
+	delTargets := tx.Stmt(sdb.deleteStmtTargets)
+
+	var delText *sql.Stmt
+	if sdb.hasText() {
+		delText, err = tx.Prepare(sdb.bind("DELETE FROM Text WHERE id = ?"))
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
 
 	for val := range ch {
 		switch id := val.(type) {
@@ -254,6 +478,44 @@ func (sdb *SQLDB) Delete(q *mcq.Query) (count int, err error) {
 				return 0, err
 			}
 
+			_, err = delTags.Exec(id)
+			if err != nil {
+				tx.Rollback()
+				return 0, err
+			}
+
+			_, err = delDeps.Exec(id)
+			if err != nil {
+				tx.Rollback()
+				return 0, err
+			}
+
+			_, err = delObjects.Exec(id)
+			if err != nil {
+				tx.Rollback()
+				return 0, err
+			}
+
+			_, err = delFields.Exec(id)
+			if err != nil {
+				tx.Rollback()
+				return 0, err
+			}
+
+			_, err = delTargets.Exec(id)
+			if err != nil {
+				tx.Rollback()
+				return 0, err
+			}
+
+			if delText != nil {
+				_, err = delText.Exec(id)
+				if err != nil {
+					tx.Rollback()
+					return 0, err
+				}
+			}
+
 			count += 1
 
 		case StreamError:
@@ -274,88 +536,647 @@ func (sdb *SQLDB) Delete(q *mcq.Query) (count int, err error) {
 	return count, nil
 }
 
-func (sdb *SQLDB) Close() error {
-	return sdb.db.Close()
+// ObjectNamespaces returns the namespaces of the statements referencing
+// an object, either as a statement object or as a dependency.
+func (sdb *SQLDB) ObjectNamespaces(key string) ([]string, error) {
+	rows, err := sdb.db.Query(sdb.bind("SELECT DISTINCT namespace FROM Envelope WHERE id IN (SELECT id FROM Objects WHERE object = ? UNION SELECT id FROM Deps WHERE dep = ?)"), key, key)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	nss := make([]string, 0)
+	for rows.Next() {
+		var ns string
+		err = rows.Scan(&ns)
+		if err != nil {
+			return nil, err
+		}
+		nss = append(nss, ns)
+	}
+
+	return nss, rows.Err()
 }
 
-func (sdb *SQLDB) createTables() error {
-	_, err := sdb.db.Exec("CREATE TABLE Statement (id VARCHAR(128) PRIMARY KEY, data VARBINARY)")
-	if err != nil {
-		return err
+// Retracted returns the ids of the statements retracted by a retraction
+// of their publisher in the db; the retraction targets are looked up in
+// batches, to stay within the sql variable limits.
+func (sdb *SQLDB) Retracted(stmts []*pb.Statement) (map[string]bool, error) {
+	const batch = 256
+
+	retracted := make(map[string]bool)
+	for len(stmts) > 0 {
+		xstmts := stmts
+		if len(xstmts) > batch {
+			xstmts = xstmts[:batch]
+		}
+		stmts = stmts[len(xstmts):]
+
+		pubs := make(map[string]string)
+		args := make([]interface{}, len(xstmts))
+		for x, stmt := range xstmts {
+			pubs[stmt.Id] = stmt.Publisher
+			args[x] = stmt.Id
+		}
+
+		params := strings.Repeat("?, ", len(xstmts)-1) + "?"
+		rows, err := sdb.db.Query(sdb.bind(fmt.Sprintf("SELECT target, publisher FROM Supersede WHERE retract AND target IN (%s)", params)), args...)
+		if err != nil {
+			return nil, err
+		}
+
+		for rows.Next() {
+			var target, pub string
+			err = rows.Scan(&target, &pub)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+
+			if pubs[target] == pub {
+				retracted[target] = true
+			}
+		}
+
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	_, err = sdb.db.Exec("CREATE TABLE Envelope (counter INTEGER PRIMARY KEY AUTOINCREMENT, id VARCHAR(128), namespace VARCHAR, publisher VARCHAR, source VARCHAR, timestamp INTEGER)")
+	return retracted, nil
+}
+
+// Field and text indexes share the same structure: a declaration table
+// tracking the indexing progress with the counter of the last statement
+// indexed, and a value table with (id, namespace, path, value) entries.
+// The value table of text indexes is an FTS5 table, which is created when
+// the first text index is declared, as sqlite may be built without FTS5.
+func (sdb *SQLDB) PutFieldIndex(ns, path string) error {
+	return sdb.putIndex("FieldIndex", ns, path)
+}
+
+func (sdb *SQLDB) DropFieldIndex(ns, path string) error {
+	return sdb.dropIndex("FieldIndex", "Fields", ns, path)
+}
+
+func (sdb *SQLDB) FieldIndexes() ([]FieldIndex, error) {
+	return sdb.listIndexes("FieldIndex", "Fields")
+}
+
+// PutFields adds field values to an index and advances the index counter
+// to the last statement examined; values for dropped indexes are discarded.
+func (sdb *SQLDB) PutFields(ns, path string, counter int64, fields []FieldValue) error {
+	return sdb.putIndexValues("FieldIndex", "Fields", ns, path, counter, fields)
+}
+
+func (sdb *SQLDB) PutTextIndex(ns, path string) error {
+	err := sdb.createTextTables()
 	if err != nil {
 		return err
 	}
 
-	_, err = sdb.db.Exec("CREATE UNIQUE INDEX EnvelopeId ON Envelope (id)")
-	if err != nil {
-		return err
+	return sdb.putIndex("TextIndex", ns, path)
+}
+
+func (sdb *SQLDB) DropTextIndex(ns, path string) error {
+	if !sdb.hasText() {
+		return UnknownIndex
 	}
 
-	_, err = sdb.db.Exec("CREATE INDEX EnvelopeNS ON Envelope (namespace)")
-	if err != nil {
-		return err
+	return sdb.dropIndex("TextIndex", "Text", ns, path)
+}
+
+func (sdb *SQLDB) TextIndexes() ([]FieldIndex, error) {
+	if !sdb.hasText() {
+		return []FieldIndex{}, nil
 	}
 
-	_, err = sdb.db.Exec("CREATE TABLE Refs (id VARCHAR(128), wki VARCHAR)")
+	idxs, err := sdb.listIndexes("TextIndex", "Text")
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	_, err = sdb.db.Exec("CREATE INDEX RefsId ON Refs (id)")
-	if err != nil {
-		return err
+	for x := range idxs {
+		idxs[x].Text = true
 	}
 
-	_, err = sdb.db.Exec("CREATE INDEX RefsWki ON Refs (wki)")
-	return err
+	return idxs, nil
 }
 
-func (sdb *SQLDB) prepareStatements() error {
-	stmt, err := sdb.db.Prepare("INSERT INTO Statement VALUES (?, ?)")
-	if err != nil {
-		return err
+func (sdb *SQLDB) PutText(ns, path string, counter int64, fields []FieldValue) error {
+	return sdb.putIndexValues("TextIndex", "Text", ns, path, counter, fields)
+}
+
+// Search returns the statements in the namespace whose text index entries
+// match the FTS5 query, in order of decreasing score.
+// Malformed queries fail with a SearchQueryError.
+func (sdb *SQLDB) Search(ns string, q string, limit int) ([]SearchResult, error) {
+	if !sdb.hasText() {
+		return nil, NoTextSearch
 	}
-	sdb.insertStmtData = stmt
 
-	stmt, err = sdb.db.Prepare("INSERT INTO Envelope VALUES (NULL, ?, ?, ?, ?, ?)")
-	if err != nil {
-		return err
+	var nscrit string
+	switch {
+	case ns == "*":
+		nscrit = ""
+	case ns[len(ns)-1] == '*':
+		pre := ns[:len(ns)-2]
+		nscrit = fmt.Sprintf("AND (namespace = '%s' OR namespace GLOB '%s.*')", pre, pre)
+	default:
+		nscrit = fmt.Sprintf("AND namespace = '%s'", ns)
 	}
-	sdb.insertStmtEnvelope = stmt
 
-	stmt, err = sdb.db.Prepare("INSERT INTO Refs VALUES (?, ?)")
+	sq := fmt.Sprintf("SELECT Statement.data, Matches.score FROM (SELECT id, -MIN(rank) AS score FROM Text WHERE Text MATCH ? %s GROUP BY id ORDER BY score DESC LIMIT ?) AS Matches JOIN Statement ON Statement.id = Matches.id ORDER BY Matches.score DESC", nscrit)
+	rows, err := sdb.db.Query(sdb.bind(sq), q, limit)
 	if err != nil {
-		return err
+		return nil, searchQueryError(err)
 	}
-	sdb.insertStmtRefs = stmt
+	defer rows.Close()
 
-	stmt, err = sdb.db.Prepare("SELECT data FROM Statement WHERE id = ?")
-	if err != nil {
-		return err
+	res := make([]SearchResult, 0)
+	for rows.Next() {
+		var bytes []byte
+		var score float64
+		err = rows.Scan(&bytes, &score)
+		if err != nil {
+			return nil, err
+		}
+
+		stmt := new(pb.Statement)
+		err = ggproto.Unmarshal(bytes, stmt)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, SearchResult{stmt, score})
 	}
-	sdb.selectStmtData = stmt
 
-	stmt, err = sdb.db.Prepare("DELETE FROM Statement WHERE id = ?")
+	err = rows.Err()
 	if err != nil {
-		return err
+		return nil, searchQueryError(err)
 	}
-	sdb.deleteStmtData = stmt
 
-	stmt, err = sdb.db.Prepare("DELETE FROM Envelope WHERE id = ?")
-	if err != nil {
+	return res, nil
+}
+
+// searchQueryError distinguishes the errors of malformed FTS5 queries
+// from db failures.
+func searchQueryError(err error) error {
+	msg := err.Error()
+	switch {
+	case strings.HasPrefix(msg, "fts5:"):
+		return SearchQueryError{msg}
+	case strings.HasPrefix(msg, "no such column"):
+		return SearchQueryError{msg}
+	case strings.HasPrefix(msg, "unknown special query"):
+		return SearchQueryError{msg}
+	default:
 		return err
 	}
-	sdb.deleteStmtEnvelope = stmt
+}
 
-	stmt, err = sdb.db.Prepare("DELETE FROM Refs WHERE id = ?")
+func (sdb *SQLDB) putIndex(decl string, ns, path string) error {
+	sdb.wlock.Lock()
+	defer sdb.wlock.Unlock()
+
+	insert := "INSERT OR IGNORE INTO %s VALUES (?, ?, 0)"
+	if sdb.dialect == mcq.Postgres {
+		insert = "INSERT INTO %s VALUES (?, ?, 0) ON CONFLICT DO NOTHING"
+	}
+
+	_, err := sdb.db.Exec(sdb.bind(fmt.Sprintf(insert, decl)), ns, path)
+	return err
+}
+
+func (sdb *SQLDB) dropIndex(decl, tab string, ns, path string) error {
+	sdb.wlock.Lock()
+	defer sdb.wlock.Unlock()
+
+	tx, err := sdb.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.Exec(sdb.bind(fmt.Sprintf("DELETE FROM %s WHERE namespace = ? AND path = ?", decl)), ns, path)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if count == 0 {
+		tx.Rollback()
+		return UnknownIndex
+	}
+
+	_, err = tx.Exec(sdb.bind(fmt.Sprintf("DELETE FROM %s WHERE namespace = ? AND path = ?", tab)), ns, path)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (sdb *SQLDB) listIndexes(decl, tab string) ([]FieldIndex, error) {
+	rows, err := sdb.db.Query(fmt.Sprintf("SELECT namespace, path, counter, (SELECT COUNT(*) FROM %s WHERE %s.namespace = %s.namespace AND %s.path = %s.path) FROM %s ORDER BY namespace, path", tab, tab, decl, tab, decl, decl))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]FieldIndex, 0)
+	for rows.Next() {
+		var idx FieldIndex
+		err = rows.Scan(&idx.Namespace, &idx.Path, &idx.Counter, &idx.Count)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, idx)
+	}
+
+	return res, rows.Err()
+}
+
+func (sdb *SQLDB) putIndexValues(decl, tab string, ns, path string, counter int64, fields []FieldValue) error {
+	sdb.wlock.Lock()
+	defer sdb.wlock.Unlock()
+
+	tx, err := sdb.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.Exec(sdb.bind(fmt.Sprintf("UPDATE %s SET counter = ? WHERE namespace = ? AND path = ?", decl)), counter, ns, path)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if count == 0 {
+		tx.Rollback()
+		return nil
+	}
+
+	insertValue, err := tx.Prepare(sdb.bind(fmt.Sprintf("INSERT INTO %s VALUES (?, ?, ?, ?)", tab)))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for _, field := range fields {
+		_, err = insertValue.Exec(field.Id, ns, path, field.Value)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (sdb *SQLDB) Close() error {
+	return sdb.db.Close()
+}
+
+func (sdb *SQLDB) createStatementTables(tx *sql.Tx) error {
+	_, err := tx.Exec("CREATE TABLE Statement (id VARCHAR(128) PRIMARY KEY, data VARBINARY)")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("CREATE TABLE Envelope (counter INTEGER PRIMARY KEY AUTOINCREMENT, id VARCHAR(128), namespace VARCHAR, publisher VARCHAR, source VARCHAR, timestamp INTEGER)")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("CREATE UNIQUE INDEX EnvelopeId ON Envelope (id)")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("CREATE INDEX EnvelopeNS ON Envelope (namespace)")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("CREATE TABLE Refs (id VARCHAR(128), wki VARCHAR)")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("CREATE INDEX RefsId ON Refs (id)")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("CREATE INDEX RefsWki ON Refs (wki)")
+	return err
+}
+
+func (sdb *SQLDB) createTagTables(tx *sql.Tx) error {
+	_, err := tx.Exec("CREATE TABLE Tags (id VARCHAR(128), tag VARCHAR)")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("CREATE INDEX TagsId ON Tags (id)")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("CREATE INDEX TagsTag ON Tags (tag)")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("CREATE TABLE Deps (id VARCHAR(128), dep VARCHAR)")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("CREATE INDEX DepsId ON Deps (id)")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("CREATE INDEX DepsDep ON Deps (dep)")
+	if err != nil {
+		return err
+	}
+
+	return sdb.backfillStatementIndexes(tx, []statementIndex{
+		{"INSERT INTO Tags VALUES (?, ?)", mcq.StatementTags},
+		{"INSERT INTO Deps VALUES (?, ?)", mcq.StatementDeps},
+	})
+}
+
+func (sdb *SQLDB) createObjectTables(tx *sql.Tx) error {
+	_, err := tx.Exec("CREATE TABLE Objects (id VARCHAR(128), object VARCHAR)")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("CREATE INDEX ObjectsId ON Objects (id)")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("CREATE INDEX ObjectsObject ON Objects (object)")
+	if err != nil {
+		return err
+	}
+
+	return sdb.backfillStatementIndexes(tx, []statementIndex{
+		{"INSERT INTO Objects VALUES (?, ?)", mcq.StatementObjects},
+	})
+}
+
+func (sdb *SQLDB) createFieldTables(tx *sql.Tx) error {
+	_, err := tx.Exec("CREATE TABLE FieldIndex (namespace VARCHAR, path VARCHAR, counter INTEGER, PRIMARY KEY (namespace, path))")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("CREATE TABLE Fields (id VARCHAR(128), namespace VARCHAR, path VARCHAR, value VARCHAR)")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("CREATE INDEX FieldsId ON Fields (id)")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("CREATE INDEX FieldsPathValue ON Fields (path, value)")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("CREATE INDEX FieldsNSPath ON Fields (namespace, path)")
+	return err
+}
+
+// there are no retraction or supersession statements to backfill in
+// databases created before the table was introduced
+func (sdb *SQLDB) createSupersedeTables(tx *sql.Tx) error {
+	_, err := tx.Exec("CREATE TABLE Supersede (id VARCHAR(128), publisher VARCHAR, target VARCHAR(128), retract BOOLEAN)")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("CREATE INDEX SupersedeId ON Supersede (id)")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("CREATE INDEX SupersedeTarget ON Supersede (target)")
+	return err
+}
+
+func (sdb *SQLDB) createTextTables() error {
+	sdb.wlock.Lock()
+	defer sdb.wlock.Unlock()
+
+	if sdb.hasText() {
+		return nil
+	}
+
+	if sdb.dialect != mcq.SQLite {
+		return NoTextSearch
+	}
+
+	_, err := sdb.db.Exec("CREATE VIRTUAL TABLE Text USING fts5(id UNINDEXED, namespace UNINDEXED, path UNINDEXED, value)")
+	if err != nil {
+		if strings.Contains(err.Error(), "no such module") {
+			return NoTextSearch
+		}
+		return err
+	}
+
+	_, err = sdb.db.Exec("CREATE TABLE TextIndex (namespace VARCHAR, path VARCHAR, counter INTEGER, PRIMARY KEY (namespace, path))")
+	return err
+}
+
+func (sdb *SQLDB) hasText() bool {
+	have, err := sdb.hasTable("TextIndex")
+	return err == nil && have
+}
+
+func (sdb *SQLDB) hasTable(name string) (bool, error) {
+	query := "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?"
+	if sdb.dialect == mcq.Postgres {
+		// postgres folds unquoted table names to lower case
+		query = "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = lower(?)"
+	}
+
+	var count int
+	row := sdb.db.QueryRow(sdb.bind(query), name)
+	err := row.Scan(&count)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+type statementIndex struct {
+	insert string
+	keys   func(*pb.Statement) mcq.StatementRefSet
+}
+
+// backfillStatementIndexes indexes the existing statements in tables
+// introduced after the database was created
+func (sdb *SQLDB) backfillStatementIndexes(tx *sql.Tx, idxs []statementIndex) error {
+	var err error
+	inserts := make([]*sql.Stmt, len(idxs))
+	for x, idx := range idxs {
+		inserts[x], err = tx.Prepare(sdb.bind(idx.insert))
+		if err != nil {
+			return err
+		}
+	}
+
+	rows, err := tx.Query("SELECT data FROM Statement")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bytes []byte
+		err = rows.Scan(&bytes)
+		if err != nil {
+			return err
+		}
+
+		stmt := new(pb.Statement)
+		err = ggproto.Unmarshal(bytes, stmt)
+		if err != nil {
+			return err
+		}
+
+		for x, idx := range idxs {
+			err = insertStatementIndex(inserts[x], stmt.Id, idx.keys(stmt))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return rows.Err()
+}
+
+func (sdb *SQLDB) prepareStatements() error {
+	stmt, err := sdb.db.Prepare(sdb.bind("INSERT INTO Statement VALUES (?, ?)"))
+	if err != nil {
+		return err
+	}
+	sdb.insertStmtData = stmt
+
+	stmt, err = sdb.db.Prepare(sdb.bind("INSERT INTO Envelope (id, namespace, publisher, source, timestamp) VALUES (?, ?, ?, ?, ?)"))
+	if err != nil {
+		return err
+	}
+	sdb.insertStmtEnvelope = stmt
+
+	stmt, err = sdb.db.Prepare(sdb.bind("INSERT INTO Refs VALUES (?, ?)"))
+	if err != nil {
+		return err
+	}
+	sdb.insertStmtRefs = stmt
+
+	stmt, err = sdb.db.Prepare(sdb.bind("INSERT INTO Tags VALUES (?, ?)"))
+	if err != nil {
+		return err
+	}
+	sdb.insertStmtTags = stmt
+
+	stmt, err = sdb.db.Prepare(sdb.bind("INSERT INTO Deps VALUES (?, ?)"))
+	if err != nil {
+		return err
+	}
+	sdb.insertStmtDeps = stmt
+
+	stmt, err = sdb.db.Prepare(sdb.bind("INSERT INTO Objects VALUES (?, ?)"))
+	if err != nil {
+		return err
+	}
+	sdb.insertStmtObjects = stmt
+
+	stmt, err = sdb.db.Prepare(sdb.bind("INSERT INTO Supersede VALUES (?, ?, ?, ?)"))
+	if err != nil {
+		return err
+	}
+	sdb.insertStmtTargets = stmt
+
+	stmt, err = sdb.db.Prepare(sdb.bind("SELECT data FROM Statement WHERE id = ?"))
+	if err != nil {
+		return err
+	}
+	sdb.selectStmtData = stmt
+
+	stmt, err = sdb.db.Prepare(sdb.bind("DELETE FROM Statement WHERE id = ?"))
+	if err != nil {
+		return err
+	}
+	sdb.deleteStmtData = stmt
+
+	stmt, err = sdb.db.Prepare(sdb.bind("DELETE FROM Envelope WHERE id = ?"))
+	if err != nil {
+		return err
+	}
+	sdb.deleteStmtEnvelope = stmt
+
+	stmt, err = sdb.db.Prepare(sdb.bind("DELETE FROM Refs WHERE id = ?"))
 	if err != nil {
 		return err
 	}
 	sdb.deleteStmtRefs = stmt
 
+	stmt, err = sdb.db.Prepare(sdb.bind("DELETE FROM Tags WHERE id = ?"))
+	if err != nil {
+		return err
+	}
+	sdb.deleteStmtTags = stmt
+
+	stmt, err = sdb.db.Prepare(sdb.bind("DELETE FROM Deps WHERE id = ?"))
+	if err != nil {
+		return err
+	}
+	sdb.deleteStmtDeps = stmt
+
+	stmt, err = sdb.db.Prepare(sdb.bind("DELETE FROM Objects WHERE id = ?"))
+	if err != nil {
+		return err
+	}
+	sdb.deleteStmtObjects = stmt
+
+	stmt, err = sdb.db.Prepare(sdb.bind("DELETE FROM Fields WHERE id = ?"))
+	if err != nil {
+		return err
+	}
+	sdb.deleteStmtFields = stmt
+
+	stmt, err = sdb.db.Prepare(sdb.bind("DELETE FROM Supersede WHERE id = ?"))
+	if err != nil {
+		return err
+	}
+	sdb.deleteStmtTargets = stmt
+
 	return nil
 }
 
@@ -364,13 +1185,33 @@ type SQLiteDB struct {
 	SQLDB
 }
 
+var memdbCounter int64
+
 func (sdb *SQLiteDB) Open(home string) error {
+	err := sdb.openSchema(home)
+	if err != nil {
+		return err
+	}
+
+	err = sdb.migrate(false)
+	if err != nil {
+		return err
+	}
+
+	return sdb.prepareStatements()
+}
+
+// openSchema opens the database without migrating it; the schema of new
+// databases is created by the migrations.
+func (sdb *SQLiteDB) openSchema(home string) error {
 	var dbpath string
-	var mktables bool
+	var mkdb bool
 
 	if home == ":memory:" { // allow testing
-		dbpath = home
-		mktables = true
+		// connections share a named in-memory db, as each connection to
+		// :memory: has its own db
+		dbpath = fmt.Sprintf("file:/mcnode%d?vfs=memdb", atomic.AddInt64(&memdbCounter, 1))
+		mkdb = true
 	} else {
 		dbdir := path.Join(home, "stmt")
 		err := os.MkdirAll(dbdir, 0755)
@@ -382,7 +1223,7 @@ func (sdb *SQLiteDB) Open(home string) error {
 		_, err = os.Stat(dbpath)
 		switch {
 		case os.IsNotExist(err):
-			mktables = true
+			mkdb = true
 		case err != nil:
 			return err
 		}
@@ -393,19 +1234,11 @@ func (sdb *SQLiteDB) Open(home string) error {
 		return err
 	}
 
-	if mktables {
-		err = sdb.tuneDB()
-		if err != nil {
-			return err
-		}
-
-		err = sdb.createTables()
-		if err != nil {
-			return err
-		}
+	if mkdb {
+		return sdb.tuneDB()
 	}
 
-	return sdb.prepareStatements()
+	return nil
 }
 
 func (sdb *SQLiteDB) openDB(dbpath string) error {
@@ -414,7 +1247,9 @@ func (sdb *SQLiteDB) openDB(dbpath string) error {
 		return err
 	}
 
+	// sqlite has a single writer
 	sdb.db = db
+	sdb.wlock = new(sync.Mutex)
 	return nil
 }
 
@@ -452,6 +1287,10 @@ func (sdb *SQLiteDB) MergeBatch(stmts []*pb.Statement) (count int, err error) {
 	insertData := tx.Stmt(sdb.insertStmtData)
 	insertEnvelope := tx.Stmt(sdb.insertStmtEnvelope)
 	insertRefs := tx.Stmt(sdb.insertStmtRefs)
+	insertTags := tx.Stmt(sdb.insertStmtTags)
+	insertDeps := tx.Stmt(sdb.insertStmtDeps)
+	insertObjects := tx.Stmt(sdb.insertStmtObjects)
+	insertTargets := tx.Stmt(sdb.insertStmtTargets)
 
 	for _, stmt := range stmts {
 		bytes, err := ggproto.Marshal(stmt)
@@ -476,12 +1315,34 @@ func (sdb *SQLiteDB) MergeBatch(stmts []*pb.Statement) (count int, err error) {
 			return 0, err
 		}
 
-		for wki, _ := range mcq.StatementRefs(stmt) {
-			_, err = insertRefs.Exec(stmt.Id, wki)
-			if err != nil {
-				tx.Rollback()
-				return 0, err
-			}
+		err = insertStatementIndex(insertRefs, stmt.Id, mcq.StatementRefs(stmt))
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+
+		err = insertStatementIndex(insertTags, stmt.Id, mcq.StatementTags(stmt))
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+
+		err = insertStatementIndex(insertDeps, stmt.Id, mcq.StatementDeps(stmt))
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+
+		err = insertStatementIndex(insertObjects, stmt.Id, mcq.StatementObjects(stmt))
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+
+		err = insertStatementTargets(insertTargets, stmt)
+		if err != nil {
+			tx.Rollback()
+			return 0, err
 		}
 
 		count += 1
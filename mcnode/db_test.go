@@ -0,0 +1,717 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	ggproto "github.com/gogo/protobuf/proto"
+	mcq "github.com/mediachain/concat/mc/query"
+	pb "github.com/mediachain/concat/proto"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// The StatementDB tests run against sqlite in a temporary directory and in
+// memory, and against postgres when MCNODE_TEST_POSTGRES holds a connection
+// URL; each postgres run uses a fresh schema that is dropped afterwards.
+// eg MCNODE_TEST_POSTGRES=postgres://postgres@localhost/mctest?sslmode=disable
+
+func TestSQLiteDB(t *testing.T) {
+	home, err := ioutil.TempDir("", "mcnode")
+	checkErrorNow(t, "TempDir", err)
+	defer os.RemoveAll(home)
+
+	db := &SQLiteDB{}
+	err = db.Open(home)
+	checkErrorNow(t, "Open", err)
+	defer db.Close()
+
+	testStatementDB(t, db)
+	testCursorMerge(t, db)
+}
+
+func TestSQLiteDBMemory(t *testing.T) {
+	db := &SQLiteDB{}
+	err := db.Open(":memory:")
+	checkErrorNow(t, "Open", err)
+	defer db.Close()
+
+	testStatementDB(t, db)
+
+	// in-memory dbs are independent
+	xdb := &SQLiteDB{}
+	err = xdb.Open(":memory:")
+	checkErrorNow(t, "Open", err)
+	defer xdb.Close()
+	checkQuery(t, xdb, "SELECT COUNT(*) FROM *", 0)
+}
+
+func TestPostgresDB(t *testing.T) {
+	url := os.Getenv("MCNODE_TEST_POSTGRES")
+	if url == "" {
+		t.Skip("MCNODE_TEST_POSTGRES is not set")
+	}
+
+	schema := fmt.Sprintf("mctest%d", time.Now().UnixNano())
+	admin, err := sql.Open("postgres", url)
+	checkErrorNow(t, "sql.Open", err)
+	defer admin.Close()
+
+	_, err = admin.Exec("CREATE SCHEMA " + schema)
+	checkErrorNow(t, "CREATE SCHEMA", err)
+	defer admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+
+	sep := "?"
+	if strings.Contains(url, "?") {
+		sep = "&"
+	}
+
+	db := &PostgresDB{url: url + sep + "search_path=" + schema}
+	err = db.Open("")
+	checkErrorNow(t, "Open", err)
+	defer db.Close()
+
+	testStatementDB(t, db)
+	testCursorMerge(t, db)
+	testMergeOverlap(t, db)
+
+	// reopening upgrades the existing schema
+	xdb := &PostgresDB{url: db.url}
+	err = xdb.Open("")
+	checkErrorNow(t, "Reopen", err)
+	xdb.Close()
+}
+
+func testStatementDB(t *testing.T, db StatementDB) {
+	a := makeTestStatement("a", "A", "foo.a", "QmAAA", "dpla:a", 100)
+	b := makeTestStatement("b", "A", "foo.b", "QmBBB", "dpla:b", 200)
+	c := makeTestStatement("c", "B", "foo.b", "QmCCC", "mywki:c", 300)
+
+	err := db.Put(a)
+	checkErrorNow(t, "Put", err)
+
+	err = db.PutBatch([]*pb.Statement{b, c})
+	checkErrorNow(t, "PutBatch", err)
+
+	stmt, err := db.Get("a")
+	checkErrorNow(t, "Get", err)
+	checkBool(t, "Get", reflect.DeepEqual(stmt, a))
+
+	_, err = db.Get("z")
+	checkBool(t, "Get unknown", err == UnknownStatement)
+
+	checkQuery(t, db, "SELECT * FROM foo.b ORDER BY id", b, c)
+	checkQuery(t, db, "SELECT id FROM foo.* WHERE publisher = A ORDER BY id", "a", "b")
+	checkQuery(t, db, "SELECT id FROM * WHERE wki PREFIX dpla: ORDER BY id", "a", "b")
+	checkQuery(t, db, "SELECT id FROM * WHERE wki LIKE %:c", "c")
+	checkQuery(t, db, "SELECT id FROM * WHERE timestamp > 150 ORDER BY timestamp DESC LIMIT 1", "c")
+	checkQuery(t, db, "SELECT id FROM * ORDER BY counter LIMIT 1", "a")
+	checkQuery(t, db, "SELECT (namespace, COUNT(*)) FROM * GROUP BY namespace ORDER BY namespace",
+		map[string]interface{}{"namespace": "foo.a", "COUNT(*)": 1},
+		map[string]interface{}{"namespace": "foo.b", "COUNT(*)": 2})
+
+	q := parseQueryNow(t, "SELECT COUNT(*) FROM *")
+	res, err := db.QueryOne(q)
+	checkErrorNow(t, "QueryOne", err)
+	checkBool(t, "QueryOne", res == 3)
+
+	q = parseQueryNow(t, "SELECT id FROM * ORDER BY id")
+	ch, err := db.QueryStream(context.Background(), q)
+	checkErrorNow(t, "QueryStream", err)
+	ids := make([]interface{}, 0)
+	for val := range ch {
+		ids = append(ids, val)
+	}
+	checkBool(t, "QueryStream", reflect.DeepEqual(ids, []interface{}{"a", "b", "c"}))
+
+	_, err = db.Explain(parseQueryNow(t, "SELECT * FROM foo.b WHERE wki = dpla:b"))
+	checkError(t, "Explain", err)
+
+	nss, err := db.ObjectNamespaces("QmBBB")
+	checkErrorNow(t, "ObjectNamespaces", err)
+	checkBool(t, "ObjectNamespaces", reflect.DeepEqual(nss, []string{"foo.b"}))
+
+	// merging
+	d := makeTestStatement("d", "B", "foo.d", "QmDDD", "mywki:d", 400)
+	e := makeTestStatement("e", "B", "foo.d", "QmEEE", "mywki:e", 500)
+
+	ok, err := db.Merge(a)
+	checkErrorNow(t, "Merge", err)
+	checkBool(t, "Merge duplicate", !ok)
+
+	ok, err = db.Merge(d)
+	checkErrorNow(t, "Merge", err)
+	checkBool(t, "Merge", ok)
+
+	count, err := db.MergeBatch([]*pb.Statement{e, a, d})
+	checkErrorNow(t, "MergeBatch", err)
+	checkBool(t, "MergeBatch", count == 1)
+	checkQuery(t, db, "SELECT id FROM foo.d WHERE wki = mywki:e", "e")
+
+	// concurrent merges of overlapping batches merge each statement once
+	batch := make([]*pb.Statement, 100)
+	for x := range batch {
+		batch[x] = makeTestStatement(fmt.Sprintf("m%03d", x), "C", "foo.m", fmt.Sprintf("QmM%03d", x), "", int64(1000+x))
+	}
+
+	var wg sync.WaitGroup
+	counts := make([]int, 4)
+	errs := make([]error, 4)
+	for x := range counts {
+		xbatch := make([]*pb.Statement, len(batch))
+		for y := range batch {
+			xbatch[y] = batch[(y+x*25)%len(batch)]
+		}
+
+		wg.Add(1)
+		go func(x int) {
+			defer wg.Done()
+			counts[x], errs[x] = db.MergeBatch(xbatch)
+		}(x)
+	}
+	wg.Wait()
+
+	total := 0
+	for x := range counts {
+		checkError(t, "MergeBatch concurrent", errs[x])
+		total += counts[x]
+	}
+	checkBool(t, "MergeBatch concurrent", total == len(batch))
+	checkQuery(t, db, "SELECT COUNT(*) FROM foo.m", len(batch))
+
+	// streams stop when the context is cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err = db.QueryStream(ctx, parseQueryNow(t, "SELECT id FROM foo.m"))
+	checkErrorNow(t, "QueryStream", err)
+	<-ch
+	cancel()
+	count = 0
+	for _ = range ch {
+		count++
+	}
+	checkBool(t, "QueryStream cancel", count < len(batch)-1)
+
+	// field indexes
+	err = db.PutFieldIndex("foo.b", "source")
+	checkErrorNow(t, "PutFieldIndex", err)
+
+	err = db.PutFieldIndex("foo.b", "source")
+	checkErrorNow(t, "PutFieldIndex duplicate", err)
+
+	err = db.PutFields("foo.b", "source", 3, []FieldValue{{"b", "dpla"}, {"c", "met"}})
+	checkErrorNow(t, "PutFields", err)
+
+	idxs, err := db.FieldIndexes()
+	checkErrorNow(t, "FieldIndexes", err)
+	checkBool(t, "FieldIndexes", reflect.DeepEqual(idxs, []FieldIndex{{Namespace: "foo.b", Path: "source", Counter: 3, Count: 2}}))
+	checkQuery(t, db, "SELECT id FROM foo.b WHERE field.source = met", "c")
+
+	err = db.DropFieldIndex("foo.b", "source")
+	checkErrorNow(t, "DropFieldIndex", err)
+
+	err = db.DropFieldIndex("foo.b", "source")
+	checkBool(t, "DropFieldIndex unknown", err == UnknownIndex)
+
+	// text indexes; full-text search requires sqlite with FTS5
+	_, err = db.Search("*", "painting", 10)
+	checkBool(t, "Search without text index", err == NoTextSearch)
+
+	err = db.PutTextIndex("foo.b", "title")
+	if err == NoTextSearch {
+		t.Log("Skipping text index tests; no full-text search")
+	} else {
+		checkErrorNow(t, "PutTextIndex", err)
+
+		err = db.PutTextIndex("foo.a", "title")
+		checkErrorNow(t, "PutTextIndex", err)
+
+		err = db.PutText("foo.b", "title", 3, []FieldValue{{"b", "A painting of a ship"}, {"c", "A photograph of a ship"}})
+		checkErrorNow(t, "PutText", err)
+
+		err = db.PutText("foo.a", "title", 1, []FieldValue{{"a", "A painting of the sea"}})
+		checkErrorNow(t, "PutText", err)
+
+		// matches are scoped to the namespace
+		checkSearch(t, db, "foo.b", "painting", "b")
+		checkSearch(t, db, "foo.b", "ship", "b", "c")
+		checkSearch(t, db, "foo.a", "ship")
+		checkSearch(t, db, "foo.*", "painting", "a", "b")
+		checkSearch(t, db, "*", "painting OR photograph", "a", "b", "c")
+
+		// malformed queries are distinguished from db failures
+		_, err = db.Search("*", "painting AND", 10)
+		_, ok := err.(SearchQueryError)
+		checkBool(t, "Search syntax error", ok)
+
+		err = db.DropTextIndex("foo.a", "title")
+		checkErrorNow(t, "DropTextIndex", err)
+		checkSearch(t, db, "*", "painting", "b")
+
+		err = db.DropTextIndex("foo.b", "title")
+		checkErrorNow(t, "DropTextIndex", err)
+	}
+
+	// retractions are only honored for the same publisher
+	r := &pb.Statement{
+		Id:        "r",
+		Publisher: "A",
+		Namespace: "foo.b",
+		Body:      &pb.StatementBody{&pb.StatementBody_Retract{&pb.RetractStatement{Targets: []string{"b", "c"}}}},
+		Timestamp: 600}
+
+	err = db.Put(r)
+	checkErrorNow(t, "Put", err)
+	checkQuery(t, db, "SELECT id FROM * WHERE retracted", "b")
+
+	retracted, err := db.Retracted([]*pb.Statement{a, b, c})
+	checkErrorNow(t, "Retracted", err)
+	checkBool(t, "Retracted", reflect.DeepEqual(retracted, map[string]bool{"b": true}))
+
+	q = parseQueryNow(t, "DELETE FROM * WHERE retracted")
+	count, err = db.Delete(q)
+	checkErrorNow(t, "Delete", err)
+	checkBool(t, "Delete", count == 1)
+
+	_, err = db.Get("b")
+	checkBool(t, "Get deleted", err == UnknownStatement)
+	checkQuery(t, db, "SELECT id FROM foo.b ORDER BY id", "c", "r")
+
+	// sqlite databases need a full vacuum before incremental vacuums
+	err = db.Vacuum(true)
+	checkError(t, "Vacuum full", err)
+
+	err = db.Vacuum(false)
+	checkError(t, "Vacuum", err)
+}
+
+// testCursorMerge follows a counter cursor while batches are merged
+// concurrently; counters must be assigned in commit order, so that the
+// cursor sees every statement exactly once.
+func testCursorMerge(t *testing.T, db StatementDB) {
+	const writers = 4
+	const batches = 10
+	const size = 20
+
+	var wg sync.WaitGroup
+	errs := make([]error, writers)
+	for x := 0; x < writers; x++ {
+		wg.Add(1)
+		go func(x int) {
+			defer wg.Done()
+			for y := 0; y < batches; y++ {
+				batch := make([]*pb.Statement, size)
+				for z := range batch {
+					id := fmt.Sprintf("cm%d.%03d.%03d", x, y, z)
+					batch[z] = makeTestStatement(id, "C", "foo.cursor", "Qm"+id, "", int64(z))
+				}
+
+				_, err := db.MergeBatch(batch)
+				if err != nil {
+					errs[x] = err
+					return
+				}
+			}
+		}(x)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	seen := make(map[string]int)
+	q := parseQueryNow(t, "SELECT id FROM foo.cursor")
+	var counter int64
+	for {
+		// a fetch that starts after the writers are done sees all statements
+		var finished bool
+		select {
+		case <-done:
+			finished = true
+		default:
+		}
+
+		ch, err := db.QueryStreamCursor(context.Background(), q.WithCounterCursor(counter, 50))
+		checkErrorNow(t, "QueryStreamCursor", err)
+
+		count := 0
+		for val := range ch {
+			switch val := val.(type) {
+			case string:
+				seen[val]++
+				count++
+			case QueryCursor:
+				counter = val.Counter
+			case StreamError:
+				t.Fatal(val.Error())
+			}
+		}
+
+		if finished && count == 0 {
+			break
+		}
+	}
+
+	for x := range errs {
+		checkError(t, "MergeBatch concurrent", errs[x])
+	}
+
+	checkBool(t, "cursor merge statements", len(seen) == writers*batches*size)
+	for id, count := range seen {
+		if count != 1 {
+			t.Errorf("Statement %s seen %d times", id, count)
+		}
+	}
+}
+
+// testMergeOverlap checks that concurrent postgres merges write their
+// statements while another transaction holds the envelope lock; only the
+// counter assignment and the commit are serialized.
+func testMergeOverlap(t *testing.T, db *PostgresDB) {
+	tx, err := db.db.Begin()
+	checkErrorNow(t, "Begin", err)
+	defer tx.Rollback()
+
+	_, err = tx.Exec("SELECT pg_advisory_xact_lock($1)", envelopeLockKey)
+	checkErrorNow(t, "pg_advisory_xact_lock", err)
+
+	const writers = 2
+	var wg sync.WaitGroup
+	errs := make([]error, writers)
+	for x := 0; x < writers; x++ {
+		batch := make([]*pb.Statement, 10)
+		for y := range batch {
+			id := fmt.Sprintf("ov%d.%03d", x, y)
+			batch[y] = makeTestStatement(id, "C", "foo.overlap", "Qm"+id, "", int64(y))
+		}
+
+		wg.Add(1)
+		go func(x int) {
+			defer wg.Done()
+			_, errs[x] = db.MergeBatch(batch)
+		}(x)
+	}
+
+	// both merges have written their statements and wait for the lock
+	waitFor(t, "overlapping merges", func() bool {
+		var waiting int
+		err := tx.QueryRow("SELECT COUNT(DISTINCT pid) FROM pg_locks WHERE locktype = 'advisory' AND NOT granted AND pid IN (SELECT pid FROM pg_locks WHERE relation = 'statement'::regclass AND mode = 'RowExclusiveLock')").Scan(&waiting)
+		checkErrorNow(t, "pg_locks", err)
+		return waiting == writers
+	})
+
+	tx.Rollback()
+	wg.Wait()
+
+	for x := range errs {
+		checkError(t, "MergeBatch overlap", errs[x])
+	}
+	checkQuery(t, db, "SELECT COUNT(*) FROM foo.overlap", 2*10)
+}
+
+func makeTestStatement(id, publisher, ns, object, wki string, ts int64) *pb.Statement {
+	body := &pb.SimpleStatement{Object: object}
+	if wki != "" {
+		body.Refs = []string{wki}
+	}
+
+	return &pb.Statement{
+		Id:        id,
+		Publisher: publisher,
+		Namespace: ns,
+		Body:      &pb.StatementBody{&pb.StatementBody_Simple{body}},
+		Timestamp: ts}
+}
+
+func parseQueryNow(t *testing.T, qs string) *mcq.Query {
+	q, err := mcq.ParseQuery(qs)
+	checkErrorNow(t, qs, err)
+	return q
+}
+
+// checkQuery checks the result of a query, in order
+func checkQuery(t *testing.T, db StatementDB, qs string, xres ...interface{}) {
+	res, err := db.Query(parseQueryNow(t, qs))
+	checkErrorNow(t, qs, err)
+	if !reflect.DeepEqual(res, xres) {
+		t.Logf("QUERY: %s", qs)
+		t.Errorf("Bad result: expected %v, but got %v", xres, res)
+	}
+}
+
+// checkSearch checks that the search matches exactly the statement ids
+func checkSearch(t *testing.T, db StatementDB, ns string, q string, xids ...string) {
+	res, err := db.Search(ns, q, 10)
+	checkErrorNow(t, q, err)
+
+	ids := make([]string, len(res))
+	for x, r := range res {
+		ids[x] = r.Statement.Id
+	}
+	sort.Strings(ids)
+
+	if !reflect.DeepEqual(ids, append([]string{}, xids...)) {
+		t.Logf("SEARCH: %s %s", ns, q)
+		t.Errorf("Bad result: expected %v, but got %v", xids, ids)
+	}
+}
+
+func checkError(t *testing.T, where string, err error) {
+	if err != nil {
+		t.Logf("%s", where)
+		t.Error(err)
+	}
+}
+
+func checkErrorNow(t *testing.T, where string, err error) {
+	if err != nil {
+		t.Logf("%s", where)
+		t.Log(err)
+		t.FailNow()
+	}
+}
+
+func checkBool(t *testing.T, where string, e bool) {
+	if !e {
+		t.Logf("%s", where)
+		t.Errorf("boolean condition failed")
+	}
+}
+
+// legacySchema is the schema of the statement db before versioning, by
+// the migration version that creates it; fixture databases are built
+// from it.
+var legacySchema = [][]string{
+	{"CREATE TABLE Statement (id VARCHAR(128) PRIMARY KEY, data VARBINARY)",
+		"CREATE TABLE Envelope (counter INTEGER PRIMARY KEY AUTOINCREMENT, id VARCHAR(128), namespace VARCHAR, publisher VARCHAR, source VARCHAR, timestamp INTEGER)",
+		"CREATE UNIQUE INDEX EnvelopeId ON Envelope (id)",
+		"CREATE INDEX EnvelopeNS ON Envelope (namespace)",
+		"CREATE TABLE Refs (id VARCHAR(128), wki VARCHAR)",
+		"CREATE INDEX RefsId ON Refs (id)",
+		"CREATE INDEX RefsWki ON Refs (wki)"},
+	{"CREATE TABLE Tags (id VARCHAR(128), tag VARCHAR)",
+		"CREATE INDEX TagsId ON Tags (id)",
+		"CREATE INDEX TagsTag ON Tags (tag)",
+		"CREATE TABLE Deps (id VARCHAR(128), dep VARCHAR)",
+		"CREATE INDEX DepsId ON Deps (id)",
+		"CREATE INDEX DepsDep ON Deps (dep)"},
+	{"CREATE TABLE Objects (id VARCHAR(128), object VARCHAR)",
+		"CREATE INDEX ObjectsId ON Objects (id)",
+		"CREATE INDEX ObjectsObject ON Objects (object)"},
+	{"CREATE TABLE FieldIndex (namespace VARCHAR, path VARCHAR, counter INTEGER, PRIMARY KEY (namespace, path))",
+		"CREATE TABLE Fields (id VARCHAR(128), namespace VARCHAR, path VARCHAR, value VARCHAR)",
+		"CREATE INDEX FieldsId ON Fields (id)",
+		"CREATE INDEX FieldsPathValue ON Fields (path, value)",
+		"CREATE INDEX FieldsNSPath ON Fields (namespace, path)"},
+	{"CREATE TABLE Supersede (id VARCHAR(128), publisher VARCHAR, target VARCHAR(128), retract BOOLEAN)",
+		"CREATE INDEX SupersedeId ON Supersede (id)",
+		"CREATE INDEX SupersedeTarget ON Supersede (target)"},
+}
+
+func TestSQLiteMigrate(t *testing.T) {
+	a := makeTestStatement("a", "A", "foo.a", "QmAAA", "dpla:a", 100)
+	a.Body.GetSimple().Tags = []string{"cc-by"}
+	b := makeTestStatement("b", "A", "foo.b", "QmBBB", "dpla:b", 200)
+	b.Body.GetSimple().Deps = []string{"QmDDD"}
+
+	legacy := []struct {
+		versions []int
+		version  int
+	}{
+		{[]int{1}, 1},
+		{[]int{1, 2}, 2},
+		{[]int{1, 2, 3}, 3},
+		{[]int{1, 2, 3, 4}, 4},
+		{[]int{1, 2, 3, 4, 5}, 5},
+		// field indexes were introduced before object indexes
+		{[]int{1, 2, 4}, 2},
+		{[]int{1, 2, 4, 3}, 4},
+	}
+
+	for _, l := range legacy {
+		version := l.version
+		where := fmt.Sprintf("legacy tables %v", l.versions)
+
+		home, err := ioutil.TempDir("", "mcnode")
+		checkErrorNow(t, where, err)
+		defer os.RemoveAll(home)
+
+		err = makeLegacyDB(home, l.versions, a, b)
+		checkErrorNow(t, where, err)
+
+		// dry runs report the pending migrations without applying them
+		db := &SQLiteDB{}
+		err = db.openSchema(home)
+		checkErrorNow(t, where, err)
+
+		xversion, err := db.schemaVersion()
+		checkErrorNow(t, where, err)
+		checkBool(t, where, xversion == version)
+
+		pending, err := db.pendingMigrations()
+		checkErrorNow(t, where, err)
+		checkBool(t, where, len(pending) == latestSchemaVersion()-version)
+		checkBool(t, where, len(pending) > 0 && pending[0].version == version+1)
+
+		have, err := db.hasTable("SchemaVersion")
+		checkErrorNow(t, where, err)
+		checkBool(t, where, !have)
+		db.Close()
+
+		// opening defers the offline vacuum migration
+		db = &SQLiteDB{}
+		err = db.Open(home)
+		checkErrorNow(t, where, err)
+
+		xversion, err = db.schemaVersion()
+		checkErrorNow(t, where, err)
+		checkBool(t, where, xversion == latestSchemaVersion()-1)
+
+		err = db.Vacuum(false)
+		checkBool(t, where, err != nil)
+
+		// which is applied in offline mode, as by mcnode migrate
+		err = db.migrate(true)
+		checkErrorNow(t, where, err)
+
+		xversion, err = db.schemaVersion()
+		checkErrorNow(t, where, err)
+		checkBool(t, where, xversion == latestSchemaVersion())
+
+		pending, err = db.pendingMigrations()
+		checkErrorNow(t, where, err)
+		checkBool(t, where, len(pending) == 0)
+
+		// indexes introduced by the migrations are backfilled
+		checkQuery(t, db, "SELECT id FROM * WHERE tag = cc-by", "a")
+		checkQuery(t, db, "SELECT id FROM * WHERE dep = QmDDD", "b")
+		nss, err := db.ObjectNamespaces("QmAAA")
+		checkErrorNow(t, where, err)
+		checkBool(t, where, reflect.DeepEqual(nss, []string{"foo.a"}))
+
+		// incremental vacuum no longer requires a full vacuum first
+		err = db.Vacuum(false)
+		checkError(t, where, err)
+
+		// the tables introduced by the migrations are writable
+		r := &pb.Statement{
+			Id:        "r",
+			Publisher: "A",
+			Namespace: "foo.a",
+			Body:      &pb.StatementBody{&pb.StatementBody_Retract{&pb.RetractStatement{Targets: []string{"a"}}}},
+			Timestamp: 300}
+
+		err = db.Put(r)
+		checkErrorNow(t, where, err)
+		checkQuery(t, db, "SELECT id FROM * WHERE retracted", "a")
+
+		err = db.PutFieldIndex("foo.a", "source")
+		checkError(t, where, err)
+		db.Close()
+
+		// reopening doesn't migrate again
+		db = &SQLiteDB{}
+		err = db.Open(home)
+		checkErrorNow(t, where, err)
+		checkQuery(t, db, "SELECT COUNT(*) FROM *", 3)
+		db.Close()
+	}
+}
+
+func TestSQLiteMigrateNewer(t *testing.T) {
+	home, err := ioutil.TempDir("", "mcnode")
+	checkErrorNow(t, "TempDir", err)
+	defer os.RemoveAll(home)
+
+	db := &SQLiteDB{}
+	err = db.Open(home)
+	checkErrorNow(t, "Open", err)
+
+	_, err = db.db.Exec("UPDATE SchemaVersion SET version = ?", latestSchemaVersion()+1)
+	checkErrorNow(t, "UPDATE SchemaVersion", err)
+	db.Close()
+
+	// databases migrated by a newer node are not opened
+	db = &SQLiteDB{}
+	err = db.Open(home)
+	checkBool(t, "Open newer", err != nil)
+	db.Close()
+}
+
+// makeLegacyDB creates a statement db with the legacy schema of the
+// migration versions, with the statements indexed as they were then.
+func makeLegacyDB(home string, versions []int, stmts ...*pb.Statement) error {
+	dbdir := path.Join(home, "stmt")
+	err := os.MkdirAll(dbdir, 0755)
+	if err != nil {
+		return err
+	}
+
+	db, err := sql.Open("sqlite3", path.Join(dbdir, "stmt.db"))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	have := make(map[int]bool)
+	for _, version := range versions {
+		have[version] = true
+		for _, decl := range legacySchema[version-1] {
+			_, err = db.Exec(decl)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, stmt := range stmts {
+		bytes, err := ggproto.Marshal(stmt)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.Exec("INSERT INTO Statement VALUES (?, ?)", stmt.Id, bytes)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.Exec("INSERT INTO Envelope (id, namespace, publisher, source, timestamp) VALUES (?, ?, ?, ?, ?)", stmt.Id, stmt.Namespace, stmt.Publisher, mcq.StatementSource(stmt), stmt.Timestamp)
+		if err != nil {
+			return err
+		}
+
+		idxs := []struct {
+			version int
+			insert  string
+			keys    mcq.StatementRefSet
+		}{
+			{1, "INSERT INTO Refs VALUES (?, ?)", mcq.StatementRefs(stmt)},
+			{2, "INSERT INTO Tags VALUES (?, ?)", mcq.StatementTags(stmt)},
+			{2, "INSERT INTO Deps VALUES (?, ?)", mcq.StatementDeps(stmt)},
+			{3, "INSERT INTO Objects VALUES (?, ?)", mcq.StatementObjects(stmt)},
+		}
+
+		for _, idx := range idxs {
+			if !have[idx.version] {
+				continue
+			}
+
+			for key, _ := range idx.keys {
+				_, err = db.Exec(idx.insert, stmt.Id, key)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
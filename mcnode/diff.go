@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	p2p_crypto "github.com/libp2p/go-libp2p-crypto"
+	p2p_peer "github.com/libp2p/go-libp2p-peer"
+	pb "github.com/mediachain/concat/proto"
+)
+
+// MergeDiff reports what a merge would bring in from a peer, without
+// writing anything to the statement db or the datastore.
+// Namespaces and publishers are mapped to the number of statements in the
+// result set. Invalid statements fail verification and would abort the merge.
+// Filtered statements are not accepted by the merge policy, and rejected
+// statements are by untrusted publishers; both would be skipped.
+// Objects counts the distinct objects referenced by the statements, and
+// MissingObjects those not in the local datastore.
+type MergeDiff struct {
+	Statements     int            `json:"statements"`
+	New            int            `json:"new"`
+	Present        int            `json:"present"`
+	Invalid        int            `json:"invalid"`
+	Filtered       int            `json:"filtered"`
+	Rejected       int            `json:"rejected"`
+	Namespaces     map[string]int `json:"namespaces"`
+	Publishers     map[string]int `json:"publishers"`
+	Objects        int            `json:"objects"`
+	MissingObjects int            `json:"missingObjects"`
+}
+
+func (node *Node) doDiff(ctx context.Context, pid p2p_peer.ID, q string) (diff MergeDiff, err error) {
+	diff.Namespaces = make(map[string]int)
+	diff.Publishers = make(map[string]int)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ch, err := node.doRemoteQuery(ctx, pid, q)
+	if err != nil {
+		return diff, err
+	}
+
+	policy := node.getPolicy()
+	pkcache := make(map[string]p2p_crypto.PubKey)
+	keys := make(map[string]Key)
+
+	for val := range ch {
+		switch val := val.(type) {
+		case *pb.Statement:
+			diff.Statements++
+			diff.Namespaces[val.Namespace]++
+			diff.Publishers[val.Publisher]++
+
+			// statements are checked in the same order as in doMergeStream
+			if !node.checkStatement(val) {
+				diff.Invalid++
+				continue
+			}
+
+			if !policy.accept(val) {
+				diff.Filtered++
+				continue
+			}
+
+			if !node.checkTrustPublisher(val.Publisher) {
+				diff.Rejected++
+				continue
+			}
+
+			if !node.diffVerifyStatement(val, pkcache, keys) {
+				diff.Invalid++
+				continue
+			}
+
+			_, err = node.db.Get(val.Id)
+			switch {
+			case err == UnknownStatement:
+				diff.New++
+			case err != nil:
+				return diff, err
+			default:
+				diff.Present++
+			}
+
+		case StreamError:
+			return diff, val
+
+		default:
+			return diff, BadResult
+		}
+	}
+
+	err = ctx.Err()
+	if err != nil {
+		return diff, err
+	}
+
+	diff.Objects = len(keys)
+	for _, key := range keys {
+		has, err := node.ds.Has(key)
+		if err != nil {
+			return diff, err
+		}
+
+		if !has {
+			diff.MissingObjects++
+		}
+	}
+
+	return diff, nil
+}
+
+// diffVerifyStatement verifies the signature of a statement as doMergeStream
+// does, and collects its object keys if it is valid
+func (node *Node) diffVerifyStatement(stmt *pb.Statement, pkcache map[string]p2p_crypto.PubKey, keys map[string]Key) bool {
+	verify, err := node.verifyStatementCacheKeys(stmt, pkcache)
+	if err != nil || !verify {
+		return false
+	}
+
+	skeys := make(map[string]Key)
+	err = node.mergeStatementKeys(stmt, skeys)
+	if err != nil {
+		return false
+	}
+
+	for key58, key := range skeys {
+		keys[key58] = key
+	}
+
+	return true
+}
@@ -1,3 +1,6 @@
+//go:build !norocksdb
+// +build !norocksdb
+
 package main
 
 import (
@@ -6,7 +9,6 @@ import (
 	rocksdb "github.com/mediachain/gorocksdb"
 	"log"
 	"os"
-	"path"
 	"runtime"
 	"strconv"
 )
@@ -18,8 +20,12 @@ type RocksDS struct {
 	fo *rocksdb.FlushOptions
 }
 
+func newRocksDS() (Datastore, error) {
+	return &RocksDS{}, nil
+}
+
 func (ds *RocksDS) Open(home string) error {
-	dbpath := path.Join(home, "data")
+	dbpath := datastorePath(home, DatastoreRocksDB)
 	// options
 	opts := rocksdb.NewDefaultOptions()
 	opts.SetCreateIfMissing(true)
@@ -113,20 +119,21 @@ func (ds *RocksDS) Sync() error {
 	return ds.db.Flush(ds.fo)
 }
 
-func (ds *RocksDS) IterKeys(ctx context.Context) (<-chan Key, error) {
-	err := ds.Sync()
-	if err != nil {
-		return nil, err
-	}
-
+func (ds *RocksDS) IterKeys(ctx context.Context) (<-chan Key, <-chan error) {
 	ch := make(chan Key)
+	errch := make(chan error, 1)
 	go func() {
 		defer close(ch)
 
+		err := ds.Sync()
+		if err != nil {
+			errch <- err
+			return
+		}
+
 		it := ds.db.NewIterator(ds.ro)
 		defer it.Close()
 
-	loop:
 		for it.SeekToFirst(); it.Valid(); it.Next() {
 			kslice := it.Key()
 			key := mc.HashFromBytes(kslice.Data())
@@ -134,11 +141,14 @@ func (ds *RocksDS) IterKeys(ctx context.Context) (<-chan Key, error) {
 			select {
 			case ch <- Key(key):
 			case <-ctx.Done():
-				break loop
+				errch <- nil
+				return
 			}
 		}
+
+		errch <- it.Err()
 	}()
-	return ch, nil
+	return ch, errch
 }
 
 func (ds *RocksDS) Compact() {
@@ -0,0 +1,11 @@
+//go:build norocksdb
+// +build norocksdb
+
+package main
+
+// Builds with the norocksdb tag leave out the rocksdb datastore, so that
+// the node builds without the rocksdb C++ library; nodes must use the bolt
+// datastore.
+func newRocksDS() (Datastore, error) {
+	return nil, NoRocksDB
+}
@@ -0,0 +1,61 @@
+//go:build !norocksdb
+// +build !norocksdb
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+// Tests that need the rocksdb datastore; go test -tags norocksdb skips them
+
+func TestRocksDS(t *testing.T) {
+	testDatastoreBackend(t, DatastoreRocksDB)
+}
+
+func TestConvertDS(t *testing.T) {
+	home, err := ioutil.TempDir("", "mcnode")
+	checkErrorNow(t, "TempDir", err)
+	defer os.RemoveAll(home)
+
+	node := &Node{home: home}
+	ds, err := newDatastore(node.dskind)
+	checkErrorNow(t, "newDatastore", err)
+
+	err = ds.Open(home)
+	checkErrorNow(t, "Open", err)
+
+	data := makeTestObjects(100)
+	keys, err := ds.PutBatch(data)
+	checkErrorNow(t, "PutBatch", err)
+	ds.Close()
+
+	// existing datastores can't be switched without conversion
+	err = node.setDatastore(DatastoreBolt)
+	checkBool(t, "setDatastore", err != nil)
+
+	count, err := node.doConvertDS(DatastoreBolt)
+	checkErrorNow(t, "doConvertDS", err)
+	checkBool(t, "doConvertDS", count == len(data))
+	checkBool(t, "doConvertDS", node.dskind == DatastoreBolt)
+
+	_, err = node.doConvertDS(DatastoreBolt)
+	checkBool(t, "doConvertDS same backend", err != nil)
+
+	err = node.loadConfig()
+	checkErrorNow(t, "loadConfig", err)
+	checkBool(t, "loadConfig", node.dskind == DatastoreBolt)
+
+	err = node.openDS()
+	checkErrorNow(t, "openDS", err)
+	defer node.ds.Close()
+
+	for x, key := range keys {
+		val, err := node.ds.Get(key)
+		checkErrorNow(t, "Get", err)
+		checkBool(t, "Get", bytes.Equal(val, data[x]))
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	mc "github.com/mediachain/concat/mc"
+	multihash "github.com/multiformats/go-multihash"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestBoltDS(t *testing.T) {
+	testDatastoreBackend(t, DatastoreBolt)
+}
+
+func TestMemDS(t *testing.T) {
+	ds := &MemDS{}
+	err := ds.Open("")
+	checkErrorNow(t, "Open", err)
+	defer ds.Close()
+
+	testDatastore(t, ds)
+}
+
+// testDatastoreBackend checks a persistent datastore backend
+func testDatastoreBackend(t *testing.T, kind string) {
+	home, err := ioutil.TempDir("", "mcnode")
+	checkErrorNow(t, "TempDir", err)
+	defer os.RemoveAll(home)
+
+	ds, err := newDatastore(kind)
+	checkErrorNow(t, "newDatastore", err)
+
+	err = ds.Open(home)
+	checkErrorNow(t, "Open", err)
+
+	keys := testDatastore(t, ds)
+	ds.Close()
+
+	ds, err = newDatastore(kind)
+	checkErrorNow(t, "newDatastore", err)
+
+	err = ds.Open(home)
+	checkErrorNow(t, "Reopen", err)
+	defer ds.Close()
+
+	checkDatastoreKeys(t, ds, keys)
+}
+
+// testDatastore is the conformance suite for Datastore implementations;
+// it checks an empty open datastore, and returns the keys it leaves in it.
+func testDatastore(t *testing.T, ds Datastore) []Key {
+	obj := []byte("hello world")
+	key, err := ds.Put(obj)
+	checkErrorNow(t, "Put", err)
+	checkBool(t, "Put key", bytes.Equal(key, mc.Hash(obj)))
+
+	have, err := ds.Has(key)
+	checkErrorNow(t, "Has", err)
+	checkBool(t, "Has", have)
+
+	val, err := ds.Get(key)
+	checkErrorNow(t, "Get", err)
+	checkBool(t, "Get", bytes.Equal(val, obj))
+
+	// the datastore doesn't share memory with its callers
+	obj[0] = 'j'
+	val[1] = 'a'
+	val, err = ds.Get(key)
+	checkErrorNow(t, "Get", err)
+	checkBool(t, "Get copy", string(val) == "hello world")
+
+	unknown := Key(mc.Hash([]byte("unknown")))
+	have, err = ds.Has(unknown)
+	checkErrorNow(t, "Has unknown", err)
+	checkBool(t, "Has unknown", !have)
+
+	val, err = ds.Get(unknown)
+	checkErrorNow(t, "Get unknown", err)
+	checkBool(t, "Get unknown", val == nil)
+
+	// more objects than a bolt iteration chunk
+	data := makeTestObjects(2500)
+	keys, err := ds.PutBatch(data)
+	checkErrorNow(t, "PutBatch", err)
+	checkBool(t, "PutBatch", len(keys) == len(data))
+
+	for x, key := range keys {
+		checkBool(t, "PutBatch key", bytes.Equal(key, mc.Hash(data[x])))
+	}
+
+	val, err = ds.Get(keys[1234])
+	checkErrorNow(t, "Get", err)
+	checkBool(t, "Get", bytes.Equal(val, data[1234]))
+
+	// puts are idempotent
+	_, err = ds.PutBatch(data[:10])
+	checkErrorNow(t, "PutBatch again", err)
+
+	err = ds.Delete(key)
+	checkErrorNow(t, "Delete", err)
+
+	have, err = ds.Has(key)
+	checkErrorNow(t, "Has deleted", err)
+	checkBool(t, "Has deleted", !have)
+
+	val, err = ds.Get(key)
+	checkErrorNow(t, "Get deleted", err)
+	checkBool(t, "Get deleted", val == nil)
+
+	err = ds.Delete(unknown)
+	checkError(t, "Delete unknown", err)
+
+	err = ds.Sync()
+	checkErrorNow(t, "Sync", err)
+	ds.Compact()
+
+	checkDatastoreKeys(t, ds, keys)
+
+	// objects can be deleted while iterating, as in gc
+	ch, errch := ds.IterKeys(context.Background())
+	count := 0
+	for key := range ch {
+		if count%2 == 0 {
+			err = ds.Delete(key)
+			checkErrorNow(t, "Delete", err)
+		}
+		count++
+	}
+	checkError(t, "IterKeys with deletes", <-errch)
+	checkBool(t, "IterKeys with deletes", count == len(keys))
+
+	// iteration stops when the context is cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, errch = ds.IterKeys(ctx)
+	<-ch
+	cancel()
+	count = 0
+	for _ = range ch {
+		count++
+	}
+	checkError(t, "IterKeys cancel", <-errch)
+	checkBool(t, "IterKeys cancel", count < len(keys)/2)
+
+	ch, errch = ds.IterKeys(context.Background())
+	rest := make([]Key, 0)
+	for key := range ch {
+		rest = append(rest, key)
+	}
+	checkError(t, "IterKeys after deletes", <-errch)
+	checkBool(t, "IterKeys after deletes", len(rest) == len(keys)/2)
+
+	return rest
+}
+
+// checkDatastoreKeys checks that the datastore contains exactly the keys
+func checkDatastoreKeys(t *testing.T, ds Datastore, keys []Key) {
+	ch, errch := ds.IterKeys(context.Background())
+
+	kset := make(map[string]bool)
+	for _, key := range keys {
+		kset[string(key)] = true
+	}
+
+	count := 0
+	for key := range ch {
+		if !kset[string(key)] {
+			t.Errorf("Unexpected key %s", multihash.Multihash(key).B58String())
+		}
+		count++
+	}
+	checkError(t, "IterKeys", <-errch)
+	checkBool(t, "IterKeys", count == len(keys))
+}
+
+func makeTestObjects(count int) [][]byte {
+	data := make([][]byte, count)
+	for x := range data {
+		data[x] = []byte(fmt.Sprintf("object %d", x))
+	}
+	return data
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path"
+)
+
+func datastoreKind(kind string) string {
+	if kind == "" {
+		return DatastoreRocksDB
+	}
+	return kind
+}
+
+func datastorePath(home, kind string) string {
+	switch datastoreKind(kind) {
+	case DatastoreBolt:
+		return path.Join(home, "data.bolt")
+	default:
+		return path.Join(home, "data")
+	}
+}
+
+func datastoreExists(home, kind string) (bool, error) {
+	_, err := os.Stat(datastorePath(home, kind))
+	switch {
+	case os.IsNotExist(err):
+		return false, nil
+	case err != nil:
+		return false, err
+	default:
+		return true, nil
+	}
+}
+
+// setDatastore selects the datastore backend of a node; existing
+// datastores must be converted instead.
+func (node *Node) setDatastore(kind string) error {
+	_, err := newDatastore(kind)
+	if err != nil {
+		return err
+	}
+
+	if datastoreKind(kind) == datastoreKind(node.dskind) {
+		return nil
+	}
+
+	have, err := datastoreExists(node.home, node.dskind)
+	if err != nil {
+		return err
+	}
+
+	if have {
+		return fmt.Errorf("Node has a %s datastore; use -convert-ds to change the backend", datastoreKind(node.dskind))
+	}
+
+	node.dskind = kind
+	return node.saveConfig()
+}
+
+// doConvertDS copies all objects from the datastore to a new backend and
+// switches the node to it; the old datastore is left in place.
+// Returns the number of objects copied.
+func (node *Node) doConvertDS(kind string) (int, error) {
+	const batch = 1024
+
+	dst, err := newDatastore(kind)
+	if err != nil {
+		return 0, err
+	}
+
+	if datastoreKind(kind) == datastoreKind(node.dskind) {
+		return 0, fmt.Errorf("Datastore backend is already %s", datastoreKind(kind))
+	}
+
+	src, err := newDatastore(node.dskind)
+	if err != nil {
+		return 0, err
+	}
+
+	err = src.Open(node.home)
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	err = dst.Open(node.home)
+	if err != nil {
+		return 0, err
+	}
+	defer dst.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	keys, errch := src.IterKeys(ctx)
+	count := 0
+	data := make([][]byte, 0, batch)
+	for key := range keys {
+		val, err := src.Get(key)
+		if err != nil {
+			return count, err
+		}
+
+		if val == nil {
+			continue
+		}
+
+		data = append(data, val)
+		if len(data) >= batch {
+			_, err = dst.PutBatch(data)
+			if err != nil {
+				return count, err
+			}
+			count += len(data)
+			data = data[:0]
+		}
+	}
+
+	// an incomplete copy must not replace the current datastore
+	err = <-errch
+	if err != nil {
+		return count, err
+	}
+
+	if len(data) > 0 {
+		_, err = dst.PutBatch(data)
+		if err != nil {
+			return count, err
+		}
+		count += len(data)
+	}
+
+	err = dst.Sync()
+	if err != nil {
+		return count, err
+	}
+
+	log.Printf("Copied %d objects to the %s datastore; the %s datastore in %s can be removed",
+		count, datastoreKind(kind), datastoreKind(node.dskind), datastorePath(node.home, node.dskind))
+
+	node.dskind = kind
+	return count, node.saveConfig()
+}
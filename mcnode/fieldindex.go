@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	mcq "github.com/mediachain/concat/mc/query"
+	pb "github.com/mediachain/concat/proto"
+	"log"
+	"regexp"
+)
+
+// Field indexes are maintained by catching up with the statements added
+// to their namespace since the last update. Updates happen after statements
+// are published or imported, and at the end of merges, when the statement
+// metadata has been retrieved.
+// Statements whose objects are missing when they are indexed have no entries.
+
+var fieldrx *regexp.Regexp
+
+func init() {
+	rx, err := regexp.Compile("^[a-zA-Z0-9_-]+([.][a-zA-Z0-9_-]+)*$")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fieldrx = rx
+}
+
+// Text indexes are maintained in the same manner, with the field values
+// indexed for full-text search.
+
+type PutFieldValues func(ns, path string, counter int64, fields []FieldValue) error
+
+func (node *Node) doCreateFieldIndex(ctx context.Context, ns, path string) error {
+	return node.doCreateIndex(ctx, ns, path, node.db.PutFieldIndex)
+}
+
+func (node *Node) doDropFieldIndex(ns, path string) error {
+	return node.doDropIndex(ns, path, node.db.DropFieldIndex)
+}
+
+func (node *Node) doCreateTextIndex(ctx context.Context, ns, path string) error {
+	return node.doCreateIndex(ctx, ns, path, node.db.PutTextIndex)
+}
+
+func (node *Node) doDropTextIndex(ns, path string) error {
+	return node.doDropIndex(ns, path, node.db.DropTextIndex)
+}
+
+func (node *Node) doCreateIndex(ctx context.Context, ns, path string, putf func(ns, path string) error) error {
+	if !nsrx.Match([]byte(ns)) {
+		return BadNamespace
+	}
+
+	if !fieldrx.Match([]byte(path)) {
+		return BadFieldPath
+	}
+
+	err := putf(ns, path)
+	if err != nil {
+		return err
+	}
+
+	// backfill
+	return node.updateFieldIndexes(ctx)
+}
+
+func (node *Node) doDropIndex(ns, path string, dropf func(ns, path string) error) error {
+	node.fimx.Lock()
+	defer node.fimx.Unlock()
+
+	return dropf(ns, path)
+}
+
+// catchupFieldIndexes updates the field indexes after writes; failures
+// are logged, as the indexes will catch up with the next update.
+func (node *Node) catchupFieldIndexes() {
+	err := node.updateFieldIndexes(context.Background())
+	if err != nil {
+		log.Printf("Error updating field indexes: %s", err.Error())
+	}
+}
+
+func (node *Node) updateFieldIndexes(ctx context.Context) error {
+	node.fimx.Lock()
+	defer node.fimx.Unlock()
+
+	idxs, err := node.db.FieldIndexes()
+	if err != nil {
+		return err
+	}
+
+	for _, idx := range idxs {
+		err = node.updateFieldIndex(ctx, idx, node.db.PutFields)
+		if err != nil {
+			return err
+		}
+	}
+
+	idxs, err = node.db.TextIndexes()
+	if err != nil {
+		return err
+	}
+
+	for _, idx := range idxs {
+		err = node.updateFieldIndex(ctx, idx, node.db.PutText)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (node *Node) updateFieldIndex(ctx context.Context, idx FieldIndex, putf PutFieldValues) error {
+	const batch = 1024
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	counter := idx.Counter
+	for {
+		qs := fmt.Sprintf("SELECT * FROM %s WHERE counter > %d ORDER BY counter LIMIT %d", idx.Namespace, counter, batch)
+		q, err := mcq.ParseQuery(qs)
+		if err != nil {
+			return err
+		}
+
+		ch, err := node.db.QueryStreamCursor(ctx, q)
+		if err != nil {
+			return err
+		}
+
+		fields := make([]FieldValue, 0)
+		count := 0
+		for val := range ch {
+			switch val := val.(type) {
+			case *pb.Statement:
+				xfields, err := node.statementFields(val, idx.Path)
+				if err != nil {
+					return err
+				}
+				fields = append(fields, xfields...)
+				count++
+
+			case QueryCursor:
+				counter = val.Counter
+
+			case StreamError:
+				return val
+
+			default:
+				return BadResult
+			}
+		}
+
+		err = ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		if count == 0 {
+			return nil
+		}
+
+		err = putf(idx.Namespace, idx.Path, counter, fields)
+		if err != nil {
+			return err
+		}
+
+		if count < batch {
+			return nil
+		}
+	}
+}
+
+func (node *Node) statementFields(stmt *pb.Statement, path string) ([]FieldValue, error) {
+	fields := make([]FieldValue, 0)
+	seen := make(map[string]bool)
+	for key, _ := range mcq.StatementObjects(stmt) {
+		obj, err := node.getDataObject(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if obj == nil {
+			continue
+		}
+
+		for _, val := range mcq.DataValues(obj, path) {
+			if !seen[val] {
+				fields = append(fields, FieldValue{stmt.Id, val})
+				seen[val] = true
+			}
+		}
+	}
+
+	return fields, nil
+}
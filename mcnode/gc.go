@@ -140,6 +140,15 @@ func (gc *GCDB) addKeys(stmt *pb.Statement, keys map[string]bool) error {
 		}
 		return nil
 
+	case *pb.StatementBody_Retract, *pb.StatementBody_Archive:
+		return nil
+
+	case *pb.StatementBody_Supersede:
+		if body.Supersede.Body != nil {
+			gc.addSimpleKeys(body.Supersede.Body, keys)
+		}
+		return nil
+
 	default:
 		return BadStatementBody
 	}
@@ -176,11 +185,7 @@ func (gc *GCDB) mergeKeys(keys map[string]bool) error {
 }
 
 func (gc *GCDB) GC(ctx context.Context, ds Datastore) (count int, err error) {
-	keys, err := ds.IterKeys(ctx)
-	if err != nil {
-		return
-	}
-
+	keys, errch := ds.IterKeys(ctx)
 	for key := range keys {
 		var valid bool
 		valid, err = gc.validKey(key)
@@ -199,6 +204,11 @@ func (gc *GCDB) GC(ctx context.Context, ds Datastore) (count int, err error) {
 		count += 1
 	}
 
+	err = <-errch
+	if err != nil {
+		return
+	}
+
 	if count > 0 {
 		ds.Compact()
 	}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	ggproto "github.com/gogo/protobuf/proto"
+	floodsub "github.com/libp2p/go-floodsub"
+	p2p_peer "github.com/libp2p/go-libp2p-peer"
+	p2p_pstore "github.com/libp2p/go-libp2p-peerstore"
+	pb "github.com/mediachain/concat/proto"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Statements published by the node are announced on a pubsub topic for
+// their namespace. Nodes subscribed to the namespace topic fetch the
+// announced statements they don't have from the publishing peer, with the
+// query and data protocols, and merge them after verification.
+// Statements in private namespaces are not announced.
+//
+// Pubsub only reaches connected peers, so nodes rendezvous on the topic:
+// nodes following a namespace provide the topic key in the DHT and connect
+// to the other providers and to the peers listed by the directory for the
+// namespace; publishing nodes do the same when they announce.
+
+const (
+	GossipBatch             = 1024
+	GossipRendezvousPeriod  = 5 * time.Minute
+	GossipRendezvousTimeout = 1 * time.Minute
+)
+
+var idrx *regexp.Regexp
+
+func init() {
+	idrx = regexp.MustCompile("^[a-zA-Z0-9:]+$")
+}
+
+func gossipTopic(ns string) string {
+	return "/mediachain/gossip/" + ns
+}
+
+// announceStatements announces newly published statements; failures are
+// logged, as gossip is best effort.
+func (node *Node) announceStatements(ns string, ids []string) {
+	node.mx.Lock()
+	ps := node.ps
+	ctx := node.netCtx
+	node.mx.Unlock()
+
+	if ps == nil || node.rauth.isPrivate(ns) {
+		return
+	}
+
+	node.announceRendezvous(ctx, ns)
+
+	for len(ids) > 0 {
+		batch := ids
+		if len(batch) > GossipBatch {
+			batch = batch[:GossipBatch]
+		}
+		ids = ids[len(batch):]
+
+		data, err := ggproto.Marshal(&pb.GossipAnnounce{batch})
+		if err != nil {
+			log.Printf("Error encoding gossip announcement: %s", err.Error())
+			return
+		}
+
+		err = ps.Publish(gossipTopic(ns), data)
+		if err != nil {
+			log.Printf("Error announcing statements in %s: %s", ns, err.Error())
+			return
+		}
+	}
+}
+
+// doGossip sets the namespaces the node follows through gossip
+func (node *Node) doGossip(nss []string) error {
+	for _, ns := range nss {
+		if !nsrx.Match([]byte(ns)) {
+			return BadNamespace
+		}
+	}
+
+	node.mx.Lock()
+	defer node.mx.Unlock()
+
+	node.gmx.Lock()
+	defer node.gmx.Unlock()
+
+	gossip := make(map[string]context.CancelFunc)
+	for _, ns := range nss {
+		cancel, ok := node.gossip[ns]
+		if ok {
+			gossip[ns] = cancel
+			delete(node.gossip, ns)
+			continue
+		}
+
+		gossip[ns] = nil
+		if node.ps != nil {
+			err := node.startGossipNS(gossip, ns)
+			if err != nil {
+				log.Printf("Error subscribing to %s: %s", ns, err.Error())
+			}
+		}
+	}
+
+	// cancel the dropped namespaces
+	for _, cancel := range node.gossip {
+		if cancel != nil {
+			cancel()
+		}
+	}
+
+	node.gossip = gossip
+	return nil
+}
+
+func (node *Node) getGossip() []string {
+	node.gmx.Lock()
+	defer node.gmx.Unlock()
+
+	nss := make([]string, 0, len(node.gossip))
+	for ns, _ := range node.gossip {
+		nss = append(nss, ns)
+	}
+
+	return nss
+}
+
+// setGossip loads the gossip namespaces from the configuration
+func (node *Node) setGossip(nss []string) {
+	node.gmx.Lock()
+	defer node.gmx.Unlock()
+
+	node.gossip = make(map[string]context.CancelFunc)
+	for _, ns := range nss {
+		node.gossip[ns] = nil
+	}
+}
+
+// startGossip is called with the node lock held when the node goes online
+func (node *Node) startGossip() {
+	node.gmx.Lock()
+	defer node.gmx.Unlock()
+
+	for ns, _ := range node.gossip {
+		err := node.startGossipNS(node.gossip, ns)
+		if err != nil {
+			log.Printf("Error subscribing to %s: %s", ns, err.Error())
+		}
+	}
+}
+
+func (node *Node) startGossipNS(gossip map[string]context.CancelFunc, ns string) error {
+	sub, err := node.ps.Subscribe(gossipTopic(ns))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(node.netCtx)
+	gossip[ns] = cancel
+	go node.runGossip(ctx, ns, sub)
+	go node.runGossipRendezvous(ctx, ns)
+	return nil
+}
+
+func (node *Node) runGossip(ctx context.Context, ns string, sub *floodsub.Subscription) {
+	defer sub.Cancel()
+
+	for {
+		msg, err := sub.Next(ctx)
+		if err != nil {
+			return
+		}
+
+		pid := p2p_peer.ID(msg.GetFrom())
+		if pid == node.ID {
+			continue
+		}
+
+		var ann pb.GossipAnnounce
+		err = ggproto.Unmarshal(msg.GetData(), &ann)
+		if err != nil {
+			log.Printf("Bad gossip announcement from %s: %s", pid.Pretty(), err.Error())
+			continue
+		}
+
+		count, ocount, err := node.mergeGossip(ctx, pid, ns, ann.Ids)
+		switch {
+		case err != nil:
+			log.Printf("Error merging statements announced by %s in %s: %s", pid.Pretty(), ns, err.Error())
+		case count > 0:
+			log.Printf("Merged %d statements and %d objects announced by %s in %s", count, ocount, pid.Pretty(), ns)
+		}
+	}
+}
+
+func (node *Node) runGossipRendezvous(ctx context.Context, ns string) {
+	for {
+		node.gossipRendezvous(ctx, ns)
+
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-time.After(GossipRendezvousPeriod):
+			continue
+		}
+	}
+}
+
+// announceRendezvous runs a rendezvous for a namespace the node announces
+// statements in, at most once per rendezvous period; followed namespaces
+// have their own periodic rendezvous.
+func (node *Node) announceRendezvous(ctx context.Context, ns string) {
+	node.gmx.Lock()
+	defer node.gmx.Unlock()
+
+	_, ok := node.gossip[ns]
+	if ok {
+		return
+	}
+
+	now := time.Now()
+	last, ok := node.grdv[ns]
+	if ok && now.Sub(last) < GossipRendezvousPeriod {
+		return
+	}
+
+	if node.grdv == nil {
+		node.grdv = make(map[string]time.Time)
+	}
+	node.grdv[ns] = now
+
+	go node.gossipRendezvous(ctx, ns)
+}
+
+// gossipRendezvous provides the namespace topic in the DHT and connects
+// to the peers interested in it
+func (node *Node) gossipRendezvous(ctx context.Context, ns string) {
+	node.mx.Lock()
+	dht := node.dht
+	host := node.host
+	dirs := node.dir
+	node.mx.Unlock()
+
+	if dht == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, GossipRendezvousTimeout)
+	defer cancel()
+
+	topic := gossipTopic(ns)
+	err := dht.Provide(ctx, topic)
+	if err != nil {
+		log.Printf("Error providing gossip topic for %s: %s", ns, err.Error())
+	}
+
+	peers := make(map[p2p_peer.ID]bool)
+	for pinfo := range dht.FindProviders(ctx, topic) {
+		if pinfo.ID == node.ID {
+			continue
+		}
+
+		host.Peerstore().AddAddrs(pinfo.ID, pinfo.Addrs, p2p_pstore.ProviderAddrTTL)
+		peers[pinfo.ID] = true
+	}
+
+	if len(dirs) > 0 {
+		lst, err := node.doDirList(ctx, ns)
+		if err != nil {
+			log.Printf("Error listing peers for %s: %s", ns, err.Error())
+		}
+
+		for _, peer := range lst {
+			pid, err := p2p_peer.IDB58Decode(peer)
+			if err != nil || pid == node.ID {
+				continue
+			}
+			peers[pid] = true
+		}
+	}
+
+	for pid, _ := range peers {
+		if ctx.Err() != nil {
+			return
+		}
+
+		err := node.doConnectPeer(ctx, pid)
+		if err != nil {
+			log.Printf("Error connecting to %s for %s gossip: %s", pid.Pretty(), ns, err.Error())
+		}
+	}
+}
+
+// mergeGossip fetches and merges the announced statements the node
+// doesn't already have; announcements are batched by the publisher, so
+// ids past the batch size are ignored.
+func (node *Node) mergeGossip(ctx context.Context, pid p2p_peer.ID, ns string, ids []string) (int, int, error) {
+	if len(ids) > GossipBatch {
+		ids = ids[:GossipBatch]
+	}
+
+	fetch := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !idrx.Match([]byte(id)) {
+			continue
+		}
+
+		_, err := node.db.Get(id)
+		switch {
+		case err == UnknownStatement:
+			fetch = append(fetch, id)
+		case err != nil:
+			return 0, 0, err
+		}
+	}
+
+	if len(fetch) == 0 {
+		return 0, 0, nil
+	}
+
+	q := fmt.Sprintf("SELECT * FROM %s WHERE id IN (%s)", ns, strings.Join(fetch, ", "))
+	count, ocount, _, err := node.doMerge(ctx, pid, q)
+	return count, ocount, err
+}
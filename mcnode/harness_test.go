@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	p2p_host "github.com/libp2p/go-libp2p-host"
+	p2p_peer "github.com/libp2p/go-libp2p-peer"
+	p2p_pstore "github.com/libp2p/go-libp2p-peerstore"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	mc "github.com/mediachain/concat/mc"
+	mcdir "github.com/mediachain/concat/mc/dir"
+	pb "github.com/mediachain/concat/proto"
+	multiaddr "github.com/multiformats/go-multiaddr"
+	multihash "github.com/multiformats/go-multihash"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testNet is an in-process network of nodes and a directory for protocol
+// tests; hosts live in an in-memory libp2p network, nodes use in-memory
+// statement dbs and datastores and a shared in-memory DHT, so tests need
+// no network or disk state beyond a temporary home for the identities.
+type testNet struct {
+	t     *testing.T
+	mnet  mocknet.Mocknet
+	home  string
+	nodes []*Node
+	dir   p2p_pstore.PeerInfo
+	dhost p2p_host.Host
+	dht   *testDHT
+	hosts int
+}
+
+// testDHT is a provider table standing in for the DHT, which can't
+// bootstrap in the mock network
+type testDHT struct {
+	providers map[string]map[p2p_peer.ID]p2p_pstore.PeerInfo
+	mx        sync.Mutex
+}
+
+// testDHTClient is the DHT of a node in the testNet
+type testDHTClient struct {
+	dht  *testDHT
+	host p2p_host.Host
+}
+
+func (dht *testDHT) countProviders(key string) int {
+	dht.mx.Lock()
+	defer dht.mx.Unlock()
+	return len(dht.providers[key])
+}
+
+func (dht *testDHTClient) Bootstrap() error {
+	return nil
+}
+
+func (dht *testDHTClient) Lookup(ctx context.Context, pid p2p_peer.ID) (empty p2p_pstore.PeerInfo, err error) {
+	return empty, UnknownPeer
+}
+
+func (dht *testDHTClient) Provide(ctx context.Context, key string) error {
+	dht.dht.mx.Lock()
+	defer dht.dht.mx.Unlock()
+
+	pmap, ok := dht.dht.providers[key]
+	if !ok {
+		pmap = make(map[p2p_peer.ID]p2p_pstore.PeerInfo)
+		dht.dht.providers[key] = pmap
+	}
+	pmap[dht.host.ID()] = p2p_pstore.PeerInfo{ID: dht.host.ID(), Addrs: dht.host.Addrs()}
+	return nil
+}
+
+func (dht *testDHTClient) FindProviders(ctx context.Context, key string) <-chan p2p_pstore.PeerInfo {
+	dht.dht.mx.Lock()
+	defer dht.dht.mx.Unlock()
+
+	pmap := dht.dht.providers[key]
+	ch := make(chan p2p_pstore.PeerInfo, len(pmap))
+	for _, pinfo := range pmap {
+		ch <- pinfo
+	}
+	close(ch)
+	return ch
+}
+
+func (dht *testDHTClient) Close() error {
+	return nil
+}
+
+func newTestNet(t *testing.T) *testNet {
+	home, err := ioutil.TempDir("", "mcnode")
+	checkErrorNow(t, "TempDir", err)
+
+	tn := &testNet{t: t, mnet: mocknet.New(context.Background()), home: home}
+	tn.dht = &testDHT{providers: make(map[string]map[p2p_peer.ID]p2p_pstore.PeerInfo)}
+
+	id, err := mc.MakePeerIdentity(tn.makeHome("dir"))
+	checkErrorNow(t, "MakePeerIdentity", err)
+
+	host := tn.addHost(id)
+	mcdir.NewDirectory(id, host)
+	tn.dir = p2p_pstore.PeerInfo{id.ID, host.Addrs()}
+	tn.dhost = host
+
+	return tn
+}
+
+// addNode creates an online node in the network, configured to use
+// the network directory
+func (tn *testNet) addNode() *Node {
+	t := tn.t
+	home := tn.makeHome(fmt.Sprintf("node%d", len(tn.nodes)))
+
+	id, err := mc.MakePeerIdentity(home)
+	checkErrorNow(t, "MakePeerIdentity", err)
+
+	pubid, err := mc.MakePublisherIdentity(home)
+	checkErrorNow(t, "MakePublisherIdentity", err)
+
+	node := &Node{PeerIdentity: id, publisher: pubid, home: home}
+	node.dir = []p2p_pstore.PeerInfo{tn.dir}
+
+	db := &SQLiteDB{}
+	err = db.Open(":memory:")
+	checkErrorNow(t, "Open db", err)
+	node.db = db
+
+	ds := &MemDS{}
+	err = ds.Open(home)
+	checkErrorNow(t, "Open ds", err)
+	node.ds = ds
+
+	tn.nodes = append(tn.nodes, node)
+
+	node.mx.Lock()
+	ctx, cancel := context.WithCancel(context.Background())
+	node.startNetwork(ctx, cancel, tn.addHost(id))
+	node.dht.Close()
+	node.dht = &testDHTClient{dht: tn.dht, host: node.host}
+	node.status = StatusOnline
+	node.mx.Unlock()
+
+	return node
+}
+
+// addHost creates a host in the mock network, linked to all other hosts
+func (tn *testNet) addHost(id mc.PeerIdentity) p2p_host.Host {
+	t := tn.t
+
+	addr, err := multiaddr.NewMultiaddr(fmt.Sprintf("/ip4/127.0.0.1/tcp/%d", 9000+tn.hosts))
+	checkErrorNow(t, "NewMultiaddr", err)
+	tn.hosts++
+
+	host, err := tn.mnet.AddPeer(id.PrivKey, addr)
+	checkErrorNow(t, "AddPeer", err)
+
+	for _, pid := range tn.mnet.Peers() {
+		if pid != id.ID {
+			_, err = tn.mnet.LinkPeers(id.ID, pid)
+			checkErrorNow(t, "LinkPeers", err)
+		}
+	}
+
+	return host
+}
+
+func (tn *testNet) makeHome(name string) string {
+	home := fmt.Sprintf("%s/%s", tn.home, name)
+	err := os.Mkdir(home, 0755)
+	checkErrorNow(tn.t, "Mkdir", err)
+	return home
+}
+
+// introduce adds the addresses of peer b to the peerstore of a, so that
+// a can connect without a directory lookup
+func (tn *testNet) introduce(a, b *Node) {
+	a.host.Peerstore().AddAddrs(b.ID, b.host.Addrs(), p2p_pstore.PermanentAddrTTL)
+}
+
+// partition disconnects two nodes and prevents them from reconnecting
+func (tn *testNet) partition(a, b *Node) {
+	err := tn.mnet.UnlinkPeers(a.ID, b.ID)
+	checkErrorNow(tn.t, "UnlinkPeers", err)
+	tn.mnet.DisconnectPeers(a.ID, b.ID)
+}
+
+func (tn *testNet) Close() {
+	for _, node := range tn.nodes {
+		node.goOffline()
+		node.db.Close()
+		node.ds.Close()
+	}
+	tn.dhost.Close()
+	os.RemoveAll(tn.home)
+}
+
+// publishTestObjects publishes count statements in ns with new objects;
+// returns the statement ids and object keys
+func publishTestObjects(t *testing.T, node *Node, ns string, count int) ([]string, []Key) {
+	ids := make([]string, count)
+	keys := make([]Key, count)
+	for x := 0; x < count; x++ {
+		data := []byte(fmt.Sprintf("%s object %d by %s", ns, x, node.publisher.ID58))
+		key, err := node.ds.Put(data)
+		checkErrorNow(t, "Put", err)
+
+		key58 := multihash.Multihash(key).B58String()
+		body := &pb.SimpleStatement{Object: key58, Refs: []string{fmt.Sprintf("test:%d", x)}}
+		id, err := node.doPublish(ns, body)
+		checkErrorNow(t, "doPublish", err)
+
+		ids[x] = id
+		keys[x] = key
+	}
+	return ids, keys
+}
+
+// checkStatementIds checks that the query returns exactly the statement ids
+func checkStatementIds(t *testing.T, node *Node, qs string, ids []string) {
+	res, err := node.db.Query(parseQueryNow(t, qs))
+	checkErrorNow(t, qs, err)
+
+	xids := make([]string, len(res))
+	for x, val := range res {
+		xids[x] = val.(string)
+	}
+
+	sort.Strings(xids)
+	ids = append([]string(nil), ids...)
+	sort.Strings(ids)
+
+	if len(ids) != len(xids) {
+		t.Errorf("%s: expected %d statements, got %d", qs, len(ids), len(xids))
+		return
+	}
+
+	for x := range ids {
+		if ids[x] != xids[x] {
+			t.Errorf("%s: expected %s, got %s", qs, ids[x], xids[x])
+		}
+	}
+}
+
+// countStatements returns the number of statements in the node's db
+func countStatements(t *testing.T, node *Node) int {
+	res, err := node.db.Query(parseQueryNow(t, "SELECT COUNT(*) FROM *"))
+	checkErrorNow(t, "Query", err)
+	return res[0].(int)
+}
+
+// checkObjects checks the presence of objects in the datastore
+func checkObjects(t *testing.T, node *Node, keys []Key, have bool) {
+	for _, key := range keys {
+		xhave, err := node.ds.Has(key)
+		checkErrorNow(t, "Has", err)
+		if xhave != have {
+			t.Errorf("Object %s: expected presence %v", multihash.Multihash(key).B58String(), have)
+		}
+	}
+}
+
+// loadTestConfig reads the persisted configuration of a node
+func loadTestConfig(t *testing.T, node *Node) NodeConfig {
+	data, err := ioutil.ReadFile(path.Join(node.home, "config.json"))
+	checkErrorNow(t, "ReadFile", err)
+
+	var cfg NodeConfig
+	err = json.Unmarshal(data, &cfg)
+	checkErrorNow(t, "Unmarshal config", err)
+	return cfg
+}
+
+// waitFor polls the condition until it holds or the test times out
+func waitFor(t *testing.T, what string, cond func() bool) {
+	deadline := time.Now().Add(10 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
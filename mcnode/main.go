@@ -18,11 +18,22 @@ func main() {
 	cport := flag.Int("c", 9002, "Peer control interface port [http]")
 	bindaddr := flag.String("b", "127.0.0.1", "Peer control bind address [http]")
 	hdir := flag.String("d", "~/.mediachain/mcnode", "Node home")
+	pgurl := flag.String("pg", "", "PostgreSQL connection URL for the statement db [persistent]")
+	dskind := flag.String("ds", "", "Datastore backend for new nodes: rocksdb or bolt [persistent]")
+	convds := flag.String("convert-ds", "", "Convert the datastore to another backend and exit: rocksdb or bolt")
 	ver := flag.Bool("version", false, "print version and exit")
 	flag.Parse()
 
-	if len(flag.Args()) != 0 {
-		fmt.Fprintf(os.Stderr, "Usage: %s [options ...]\nOptions:\n", os.Args[0])
+	var migrate, dryrun bool
+	switch {
+	case len(flag.Args()) == 0:
+	case flag.Arg(0) == "migrate":
+		mflag := flag.NewFlagSet("migrate", flag.ExitOnError)
+		mflag.BoolVar(&dryrun, "dry-run", false, "report pending migrations without applying them")
+		mflag.Parse(flag.Args()[1:])
+		migrate = true
+	default:
+		fmt.Fprintf(os.Stderr, "Usage: %s [options ...] [migrate [-dry-run]]\nOptions:\n", os.Args[0])
 		flag.PrintDefaults()
 		os.Exit(1)
 	}
@@ -64,6 +75,37 @@ func main() {
 		log.Fatal(err)
 	}
 
+	if *pgurl != "" && *pgurl != node.dburl {
+		node.dburl = *pgurl
+		err = node.saveConfig()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if *convds != "" {
+		_, err = node.doConvertDS(*convds)
+		if err != nil {
+			log.Fatal(err)
+		}
+		os.Exit(0)
+	}
+
+	if *dskind != "" {
+		err = node.setDatastore(*dskind)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if migrate {
+		err = node.doMigrate(dryrun)
+		if err != nil {
+			log.Fatal(err)
+		}
+		os.Exit(0)
+	}
+
 	err = node.openDB()
 	if err != nil {
 		log.Fatal(err)
@@ -83,15 +125,30 @@ func main() {
 	router.HandleFunc("/ping/{peerId}", node.httpPing)
 	router.HandleFunc("/publish/{namespace}", node.httpPublish)
 	router.HandleFunc("/publish/{namespace}/{combine}", node.httpPublishCompound)
+	router.HandleFunc("/retract/{namespace}", node.httpRetract)
+	router.HandleFunc("/supersede/{namespace}", node.httpSupersede)
+	router.HandleFunc("/archive/{namespace}", node.httpArchive)
 	router.HandleFunc("/import", node.httpImport)
 	router.HandleFunc("/stmt/{statementId}", node.httpStatement)
 	router.HandleFunc("/query", node.httpQuery)
 	router.HandleFunc("/query/{peerId}", node.httpRemoteQuery)
+	router.HandleFunc("/merge/jobs", node.httpMergeJobs)
+	router.HandleFunc("/merge/jobs/{id}", node.httpMergeJobDelete)
+	router.HandleFunc("/merge/jobs/{id}/resume", node.httpMergeJobResume)
 	router.HandleFunc("/merge/{peerId}", node.httpMerge)
+	router.HandleFunc("/diff/{peerId}", node.httpDiff)
 	router.HandleFunc("/push/{peerId}", node.httpPush)
+	router.HandleFunc("/sync/{peerId}/{namespace}", node.httpSync)
+	router.HandleFunc("/subscribe", node.httpSubscriptions)
+	router.HandleFunc("/subscribe/{peerId}", node.httpSubscribe)
+	router.HandleFunc("/gossip", node.httpGossip)
 	router.HandleFunc("/delete", node.httpDelete)
 	router.HandleFunc("/vacuum/incremental", node.httpVacuumIncremental)
 	router.HandleFunc("/vacuum/full", node.httpVacuumFull)
+	router.HandleFunc("/index", node.httpFieldIndexes)
+	router.HandleFunc("/index/{namespace}", node.httpCreateFieldIndex)
+	router.HandleFunc("/index/{namespace}/drop", node.httpDropFieldIndex)
+	router.HandleFunc("/search/{namespace}", node.httpSearch)
 	router.HandleFunc("/data/put", node.httpPutData)
 	router.HandleFunc("/data/get", node.httpGetDataBatch)
 	router.HandleFunc("/data/get/{objectId}", node.httpGetData)
@@ -105,7 +162,15 @@ func main() {
 	router.HandleFunc("/config/dir", node.httpConfigDir)
 	router.HandleFunc("/config/nat", node.httpConfigNAT)
 	router.HandleFunc("/config/info", node.httpConfigInfo)
+	router.HandleFunc("/config/explain", node.httpConfigExplain)
+	router.HandleFunc("/config/policy", node.httpConfigPolicy)
+	router.HandleFunc("/config/trust", node.httpConfigTrust)
+	router.HandleFunc("/trust", node.httpTrust)
+	router.HandleFunc("/trust/refresh", node.httpTrustRefresh)
 	router.HandleFunc("/auth", node.httpAuth)
+	router.HandleFunc("/auth/read", node.httpAuthRead)
+	router.HandleFunc("/auth/read/{peerId}", node.httpAuthReadPeer)
+	router.HandleFunc("/auth/private", node.httpAuthPrivate)
 	router.HandleFunc("/auth/{peerId}", node.httpAuthPeer)
 	router.HandleFunc("/manifest", node.httpManifest)
 	router.HandleFunc("/manifest/self", node.httpManifestSelf)
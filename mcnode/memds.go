@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	mc "github.com/mediachain/concat/mc"
+	"sort"
+	"sync"
+)
+
+// In-memory datastore, for testing and ephemeral nodes; objects are lost
+// when the datastore is closed.
+type MemDS struct {
+	data map[string][]byte
+	mx   sync.RWMutex
+}
+
+func (ds *MemDS) Open(home string) error {
+	ds.data = make(map[string][]byte)
+	return nil
+}
+
+func (ds *MemDS) Put(data []byte) (Key, error) {
+	key := mc.Hash(data)
+	ds.mx.Lock()
+	ds.data[string(key)] = copyBytes(data)
+	ds.mx.Unlock()
+	return Key(key), nil
+}
+
+func (ds *MemDS) PutBatch(batch [][]byte) ([]Key, error) {
+	keys := make([]Key, len(batch))
+	ds.mx.Lock()
+	for x, data := range batch {
+		key := mc.Hash(data)
+		ds.data[string(key)] = copyBytes(data)
+		keys[x] = Key(key)
+	}
+	ds.mx.Unlock()
+	return keys, nil
+}
+
+func (ds *MemDS) Has(key Key) (bool, error) {
+	ds.mx.RLock()
+	_, have := ds.data[string(key)]
+	ds.mx.RUnlock()
+	return have, nil
+}
+
+func (ds *MemDS) Get(key Key) ([]byte, error) {
+	ds.mx.RLock()
+	data, have := ds.data[string(key)]
+	ds.mx.RUnlock()
+	if !have {
+		return nil, nil
+	}
+	return copyBytes(data), nil
+}
+
+func (ds *MemDS) Delete(key Key) error {
+	ds.mx.Lock()
+	delete(ds.data, string(key))
+	ds.mx.Unlock()
+	return nil
+}
+
+// IterKeys iterates over a snapshot of the keys, in key order
+func (ds *MemDS) IterKeys(ctx context.Context) (<-chan Key, <-chan error) {
+	ds.mx.RLock()
+	keys := make([]string, 0, len(ds.data))
+	for key, _ := range ds.data {
+		keys = append(keys, key)
+	}
+	ds.mx.RUnlock()
+	sort.Strings(keys)
+
+	ch := make(chan Key)
+	errch := make(chan error, 1)
+	go func() {
+		defer close(ch)
+		defer func() { errch <- nil }()
+		for _, key := range keys {
+			select {
+			case ch <- Key(key):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, errch
+}
+
+func (ds *MemDS) Sync() error {
+	return nil
+}
+
+func (ds *MemDS) Compact() {}
+
+func (ds *MemDS) Close() {
+	ds.mx.Lock()
+	ds.data = nil
+	ds.mx.Unlock()
+}
+
+func copyBytes(data []byte) []byte {
+	xdata := make([]byte, len(data))
+	copy(xdata, data)
+	return xdata
+}
@@ -0,0 +1,376 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	p2p_peer "github.com/libp2p/go-libp2p-peer"
+	mcq "github.com/mediachain/concat/mc/query"
+	pb "github.com/mediachain/concat/proto"
+	"log"
+)
+
+// Merge jobs run merges in the background, independently of the client
+// that started them. Streaming queries are merged in batches ordered by
+// the remote statement counter, and the job is checkpointed after each
+// batch with the counter of its last statement; a resumed job continues
+// from its checkpoint. Other queries are merged in a single batch.
+// Unfinished jobs are persisted in the node configuration; jobs interrupted
+// by shutdown are loaded as stopped. Done jobs are not persisted, and only
+// the most recent MergeJobHistory of them are kept for inspection.
+type MergeJob struct {
+	Id         string `json:"id"`
+	Peer       string `json:"peer"`
+	Query      string `json:"query"`
+	Counter    int64  `json:"counter"`
+	Statements int    `json:"statements"`
+	Objects    int    `json:"objects"`
+	Rejected   int    `json:"rejected"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	err        error
+	cancel     context.CancelFunc
+	done       chan struct{}
+}
+
+const (
+	MergeJobRunning = "running"
+	MergeJobDone    = "done"
+	MergeJobFailed  = "failed"
+	MergeJobStopped = "stopped"
+)
+
+const MergeJobBatch = 8192
+
+const MergeJobHistory = 32
+
+// doMergeJob creates and starts a merge job
+func (node *Node) doMergeJob(pid p2p_peer.ID, q string) (*MergeJob, error) {
+	qq, err := mcq.ParseQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	if !qq.IsSimpleSelect("*") {
+		return nil, BadQuery
+	}
+
+	id, err := newMergeJobId()
+	if err != nil {
+		return nil, err
+	}
+
+	node.mx.Lock()
+	if node.status == StatusOffline {
+		node.mx.Unlock()
+		return nil, NodeOffline
+	}
+
+	node.jobmx.Lock()
+	job := &MergeJob{Id: id, Peer: pid.Pretty(), Query: q}
+	node.jobs = append(node.jobs, job)
+	node.startMergeJob(pid, job)
+	node.jobmx.Unlock()
+	node.mx.Unlock()
+
+	return job, node.saveConfig()
+}
+
+// doResumeMergeJob restarts a merge job that is not running
+func (node *Node) doResumeMergeJob(id string) (*MergeJob, error) {
+	job, err := node.resumeMergeJob(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return job, node.saveConfig()
+}
+
+func (node *Node) resumeMergeJob(id string) (*MergeJob, error) {
+	node.mx.Lock()
+	defer node.mx.Unlock()
+
+	if node.status == StatusOffline {
+		return nil, NodeOffline
+	}
+
+	node.jobmx.Lock()
+	defer node.jobmx.Unlock()
+
+	job := node.findMergeJob(id)
+	if job == nil {
+		return nil, UnknownMergeJob
+	}
+
+	if job.Status == MergeJobRunning {
+		return nil, MergeJobActive
+	}
+
+	pid, err := p2p_peer.IDB58Decode(job.Peer)
+	if err != nil {
+		return nil, err
+	}
+
+	node.startMergeJob(pid, job)
+	return job, nil
+}
+
+// doDeleteMergeJob cancels a merge job if it is running and removes it
+func (node *Node) doDeleteMergeJob(id string) error {
+	node.jobmx.Lock()
+	var job *MergeJob
+	for x, xjob := range node.jobs {
+		if xjob.Id == id {
+			job = xjob
+			node.jobs = append(node.jobs[:x], node.jobs[x+1:]...)
+			break
+		}
+	}
+
+	if job == nil {
+		node.jobmx.Unlock()
+		return UnknownMergeJob
+	}
+
+	if job.cancel != nil {
+		job.cancel()
+	}
+	node.jobmx.Unlock()
+
+	return node.saveConfig()
+}
+
+// waitMergeJob waits for a merge job to finish and returns a snapshot of
+// the job; the job keeps running if the context is canceled first.
+func (node *Node) waitMergeJob(ctx context.Context, job *MergeJob) (MergeJob, error) {
+	node.jobmx.Lock()
+	done := job.done
+	node.jobmx.Unlock()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return MergeJob{}, ctx.Err()
+	}
+
+	node.jobmx.Lock()
+	defer node.jobmx.Unlock()
+	return *job, job.err
+}
+
+func (node *Node) getMergeJobs() []MergeJob {
+	node.jobmx.Lock()
+	defer node.jobmx.Unlock()
+
+	jobs := make([]MergeJob, len(node.jobs))
+	for x, job := range node.jobs {
+		jobs[x] = *job
+	}
+
+	return jobs
+}
+
+// getPersistentMergeJobs returns the jobs persisted in the configuration
+func (node *Node) getPersistentMergeJobs() []MergeJob {
+	node.jobmx.Lock()
+	defer node.jobmx.Unlock()
+
+	jobs := make([]MergeJob, 0, len(node.jobs))
+	for _, job := range node.jobs {
+		if job.Status != MergeJobDone {
+			jobs = append(jobs, *job)
+		}
+	}
+
+	return jobs
+}
+
+func (node *Node) setMergeJobs(jobs []MergeJob) {
+	node.jobmx.Lock()
+	defer node.jobmx.Unlock()
+
+	node.jobs = make([]*MergeJob, len(jobs))
+	for x, job := range jobs {
+		xjob := job
+		if xjob.Status == MergeJobRunning {
+			xjob.Status = MergeJobStopped
+		}
+		node.jobs[x] = &xjob
+	}
+}
+
+// pruneMergeJobs drops the oldest done jobs beyond the history limit;
+// called with the job lock held
+func (node *Node) pruneMergeJobs() {
+	done := 0
+	for _, job := range node.jobs {
+		if job.Status == MergeJobDone {
+			done++
+		}
+	}
+
+	if done <= MergeJobHistory {
+		return
+	}
+
+	jobs := make([]*MergeJob, 0, len(node.jobs))
+	for _, job := range node.jobs {
+		if job.Status == MergeJobDone && done > MergeJobHistory {
+			done--
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	node.jobs = jobs
+}
+
+func (node *Node) findMergeJob(id string) *MergeJob {
+	for _, job := range node.jobs {
+		if job.Id == id {
+			return job
+		}
+	}
+	return nil
+}
+
+func newMergeJobId() (string, error) {
+	buf := make([]byte, 8)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// startMergeJob is called with the node and job locks held
+func (node *Node) startMergeJob(pid p2p_peer.ID, job *MergeJob) {
+	ctx, cancel := context.WithCancel(node.netCtx)
+	job.Status = MergeJobRunning
+	job.Error = ""
+	job.err = nil
+	job.cancel = cancel
+	job.done = make(chan struct{})
+	go node.runMergeJob(ctx, pid, job)
+}
+
+func (node *Node) runMergeJob(ctx context.Context, pid p2p_peer.ID, job *MergeJob) {
+	err := node.doMergeJobStream(ctx, pid, job)
+	if err != nil {
+		log.Printf("Merge job %s from %s failed: %s", job.Id, pid.Pretty(), err.Error())
+	}
+
+	node.jobmx.Lock()
+	job.cancel()
+	job.cancel = nil
+	if err != nil {
+		job.Status = MergeJobFailed
+		job.Error = err.Error()
+		job.err = err
+	} else {
+		job.Status = MergeJobDone
+		node.pruneMergeJobs()
+	}
+	close(job.done)
+	node.jobmx.Unlock()
+
+	err = node.saveConfig()
+	if err != nil {
+		log.Printf("Error saving configuration: %s", err.Error())
+	}
+}
+
+func (node *Node) doMergeJobStream(ctx context.Context, pid p2p_peer.ID, job *MergeJob) error {
+	q, err := mcq.ParseQuery(job.Query)
+	if err != nil {
+		return err
+	}
+
+	if !q.IsStreamSelect() {
+		count, ocount, rejects, err := node.doMerge(ctx, pid, job.Query)
+		xerr := node.checkpointMergeJob(job, 0, count, ocount, rejects)
+		if err != nil {
+			return err
+		}
+		return xerr
+	}
+
+	for {
+		node.jobmx.Lock()
+		counter := job.Counter
+		node.jobmx.Unlock()
+
+		xq := q.WithCounterCursor(counter, MergeJobBatch)
+		ch, err := node.doRemoteQueryCursor(ctx, pid, xq.String())
+		if err != nil {
+			return err
+		}
+
+		scount, cursor, count, ocount, rejects, err := node.mergeJobBatch(ctx, pid, ch)
+		xerr := node.checkpointMergeJob(job, cursor, count, ocount, rejects)
+		if err != nil {
+			return err
+		}
+
+		if xerr != nil {
+			return xerr
+		}
+
+		if scount < MergeJobBatch {
+			return nil
+		}
+	}
+}
+
+// mergeJobBatch merges a batch of statements from a cursor query; it returns
+// the number of statements received, the cursor, and the merge counts.
+// The cursor is 0 if the batch was not completely merged.
+func (node *Node) mergeJobBatch(ctx context.Context, pid p2p_peer.ID, ch <-chan interface{}) (scount int, cursor int64, count int, ocount int, rejects int, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	xch := make(chan interface{})
+
+	go func() {
+		defer close(done)
+		defer close(xch)
+
+		for val := range ch {
+			switch val := val.(type) {
+			case QueryCursor:
+				cursor = val.Counter
+				continue
+
+			case *pb.Statement:
+				scount++
+			}
+
+			select {
+			case xch <- val:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	count, ocount, rejects, err = node.doMergeStream(ctx, pid, xch)
+	cancel()
+	<-done
+
+	if err != nil {
+		cursor = 0
+	}
+
+	return
+}
+
+func (node *Node) checkpointMergeJob(job *MergeJob, cursor int64, count, ocount, rejects int) error {
+	node.jobmx.Lock()
+	if cursor > job.Counter {
+		job.Counter = cursor
+	}
+	job.Statements += count
+	job.Objects += ocount
+	job.Rejected += rejects
+	node.jobmx.Unlock()
+
+	return node.saveConfig()
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	mcq "github.com/mediachain/concat/mc/query"
+	"log"
+)
+
+// Schema versioning
+// The statement db records its schema version in the SchemaVersion table;
+// the migrations introduced since are applied in order when the db is
+// opened, each in its own transaction together with the version update.
+// New databases are created by applying all migrations; databases created
+// before versioning are assigned the version of the tables they have.
+// The legacy tables were not introduced in migration order (field indexes
+// predate object indexes), so create migrations are skipped for tables
+// that already exist.
+// Migrations are only ever appended, and must handle all sql dialects;
+// postgres databases are created with the latest schema.
+// Offline migrations rewrite the whole db, so opening an existing db defers
+// them, together with the migrations that follow, to `mcnode migrate`.
+type schemaMigration struct {
+	version int
+	desc    string
+	apply   func(sdb *SQLDB, tx *sql.Tx) error
+	// notx migrations can't run inside a transaction; they must be
+	// idempotent, as the version is updated after they are applied.
+	notx bool
+	// table is the table created by the migration, if any
+	table string
+	// offline migrations are only applied by mcnode migrate
+	offline bool
+}
+
+var schemaMigrations = []schemaMigration{
+	{1, "create statement, envelope and ref tables", (*SQLDB).createStatementTables, false, "Statement", false},
+	{2, "create tag and dependency tables", (*SQLDB).createTagTables, false, "Tags", false},
+	{3, "create object tables", (*SQLDB).createObjectTables, false, "Objects", false},
+	{4, "create field index tables", (*SQLDB).createFieldTables, false, "FieldIndex", false},
+	{5, "create supersession tables", (*SQLDB).createSupersedeTables, false, "Supersede", false},
+	{6, "enable incremental vacuum", (*SQLDB).enableIncrementalVacuum, true, "", true},
+}
+
+func latestSchemaVersion() int {
+	return schemaMigrations[len(schemaMigrations)-1].version
+}
+
+// migrationDB is a statement db with a versioned schema
+type migrationDB interface {
+	openSchema(home string) error
+	schemaVersion() (int, error)
+	pendingMigrations() ([]schemaMigration, error)
+	migrate(offline bool) error
+	Close() error
+}
+
+func (sdb *SQLDB) schemaVersion() (int, error) {
+	have, err := sdb.hasTable("SchemaVersion")
+	if err != nil {
+		return 0, err
+	}
+
+	if !have {
+		return sdb.legacySchemaVersion()
+	}
+
+	var version int
+	row := sdb.db.QueryRow("SELECT version FROM SchemaVersion")
+	err = row.Scan(&version)
+	if err != nil {
+		return 0, err
+	}
+
+	return version, nil
+}
+
+// legacySchemaVersion is the version of the leading create migrations
+// whose tables exist; later tables are skipped when migrating.
+func (sdb *SQLDB) legacySchemaVersion() (int, error) {
+	version := 0
+	for _, m := range schemaMigrations {
+		if m.table == "" {
+			break
+		}
+
+		have, err := sdb.hasTable(m.table)
+		if err != nil {
+			return 0, err
+		}
+
+		if !have {
+			break
+		}
+
+		version = m.version
+	}
+
+	return version, nil
+}
+
+func (sdb *SQLDB) pendingMigrations() ([]schemaMigration, error) {
+	version, err := sdb.schemaVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	if version > latestSchemaVersion() {
+		return nil, fmt.Errorf("Statement db schema version %d is newer than this node's version %d", version, latestSchemaVersion())
+	}
+
+	pending := make([]schemaMigration, 0)
+	for _, m := range schemaMigrations {
+		if m.version > version {
+			pending = append(pending, m)
+		}
+	}
+
+	return pending, nil
+}
+
+// migrate applies the pending migrations; offline migrations of existing
+// dbs are only applied in offline mode, and are otherwise deferred.
+func (sdb *SQLDB) migrate(offline bool) error {
+	pending, err := sdb.pendingMigrations()
+	if err != nil {
+		return err
+	}
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	err = sdb.initSchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	// new databases are created by applying all migrations
+	create := pending[0].version == 1
+
+	for _, m := range pending {
+		if m.offline && !offline && !create {
+			log.Printf("Deferring statement db migration %d: %s; apply it with mcnode migrate", m.version, m.desc)
+			return nil
+		}
+
+		if !create {
+			log.Printf("Migrating statement db to version %d: %s", m.version, m.desc)
+		}
+		err = sdb.applyMigration(m)
+		if err != nil {
+			return fmt.Errorf("Statement db migration %d failed: %s", m.version, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (sdb *SQLDB) applyMigration(m schemaMigration) error {
+	if m.table != "" {
+		have, err := sdb.hasTable(m.table)
+		if err != nil {
+			return err
+		}
+
+		if have {
+			_, err = sdb.db.Exec(sdb.bind("UPDATE SchemaVersion SET version = ?"), m.version)
+			return err
+		}
+	}
+
+	if m.notx {
+		err := m.apply(sdb, nil)
+		if err != nil {
+			return err
+		}
+
+		_, err = sdb.db.Exec(sdb.bind("UPDATE SchemaVersion SET version = ?"), m.version)
+		return err
+	}
+
+	tx, err := sdb.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	err = m.apply(sdb, tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = tx.Exec(sdb.bind("UPDATE SchemaVersion SET version = ?"), m.version)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// initSchemaVersion creates the version table, recording the version
+// of legacy databases
+func (sdb *SQLDB) initSchemaVersion() error {
+	have, err := sdb.hasTable("SchemaVersion")
+	if err != nil || have {
+		return err
+	}
+
+	version, err := sdb.legacySchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	return sdb.createSchemaVersion(sdb.db, version)
+}
+
+type sqlExecer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+func (sdb *SQLDB) createSchemaVersion(db sqlExecer, version int) error {
+	_, err := db.Exec("CREATE TABLE SchemaVersion (version INTEGER)")
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(sdb.bind("INSERT INTO SchemaVersion VALUES (?)"), version)
+	return err
+}
+
+// enableIncrementalVacuum switches legacy sqlite databases to incremental
+// vacuum, which takes effect with a full vacuum.
+func (sdb *SQLDB) enableIncrementalVacuum(tx *sql.Tx) error {
+	if sdb.dialect != mcq.SQLite {
+		return nil
+	}
+
+	var autovac int
+	row := sdb.db.QueryRow("PRAGMA auto_vacuum")
+	err := row.Scan(&autovac)
+	if err != nil {
+		return err
+	}
+
+	if autovac == 2 {
+		return nil
+	}
+
+	_, err = sdb.db.Exec("PRAGMA auto_vacuum=INCREMENTAL")
+	if err != nil {
+		return err
+	}
+
+	_, err = sdb.db.Exec("VACUUM")
+	return err
+}
+
+// doMigrate reports the pending statement db migrations and applies them
+// unless it is a dry run
+func (node *Node) doMigrate(dry bool) error {
+	db := node.newDB().(migrationDB)
+	err := db.openSchema(node.home)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	version, err := db.schemaVersion()
+	if err != nil {
+		return err
+	}
+
+	pending, err := db.pendingMigrations()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Statement db schema version %d\n", version)
+	if len(pending) == 0 {
+		fmt.Println("No pending migrations")
+		return nil
+	}
+
+	for _, m := range pending {
+		fmt.Printf("Pending migration %d: %s\n", m.version, m.desc)
+	}
+
+	if dry {
+		return nil
+	}
+
+	err = db.migrate(true)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Statement db migrated to version %d\n", latestSchemaVersion())
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	ggio "github.com/gogo/protobuf/io"
+	floodsub "github.com/libp2p/go-floodsub"
+	p2p_host "github.com/libp2p/go-libp2p-host"
 	p2p_net "github.com/libp2p/go-libp2p-net"
 	p2p_peer "github.com/libp2p/go-libp2p-peer"
 	p2p_pstore "github.com/libp2p/go-libp2p-peerstore"
@@ -40,6 +42,7 @@ func (node *Node) goOffline() error {
 		}
 		node.host = nil
 		node.ping = nil
+		node.ps = nil
 
 		node.status = StatusOffline
 		node.natCfg.Clear()
@@ -85,18 +88,30 @@ func (node *Node) _goOnline() error {
 		return err
 	}
 
+	node.startNetwork(ctx, cancel, host)
+	return nil
+}
+
+// startNetwork is called with the node lock held to set up the protocol
+// handlers and network services on a newly created host; the host is owned
+// by the node and closed when it goes offline.
+func (node *Node) startNetwork(ctx context.Context, cancel context.CancelFunc, host p2p_host.Host) {
 	host.SetStreamHandler("/mediachain/node/id", node.idHandler)
 	host.SetStreamHandler("/mediachain/node/manifest", node.manifestHandler)
 	host.SetStreamHandler("/mediachain/node/ping", node.pingHandler)
 	host.SetStreamHandler("/mediachain/node/query", node.queryHandler)
 	host.SetStreamHandler("/mediachain/node/data", node.dataHandler)
 	host.SetStreamHandler("/mediachain/node/push", node.pushHandler)
+	host.SetStreamHandler("/mediachain/node/subscribe", node.subscribeHandler)
+	host.SetStreamHandler("/mediachain/node/sync", node.syncHandler)
 
 	ping := p2p_ping.NewPingService(host)
 
+	ps := floodsub.NewFloodSub(ctx, host)
+
 	dht := NewDHT(ctx, host)
 
-	err = dht.Bootstrap()
+	err := dht.Bootstrap()
 	if err != nil {
 		// that's non-fatal, it will just fail to lookup
 		log.Printf("Error boostrapping DHT: %s", err.Error())
@@ -107,8 +122,11 @@ func (node *Node) _goOnline() error {
 	node.netCancel = cancel
 	node.ping = ping
 	node.dht = dht
+	node.ps = ps
 
-	return nil
+	node.startSubscriptions()
+	node.startGossip()
+	node.startRefreshTrust()
 }
 
 // goPublic starts the network if it's not already up and registers with the
@@ -236,9 +254,13 @@ func (node *Node) publicNamespaces() []string {
 		return nil
 	}
 
-	pns := make([]string, len(res))
-	for x, ns := range res {
-		pns[x] = ns.(string)
+	// private namespace names are not disclosed to the directory
+	pns := make([]string, 0, len(res))
+	for _, ns := range res {
+		xns := ns.(string)
+		if !node.rauth.isPrivate(xns) {
+			pns = append(pns, xns)
+		}
 	}
 
 	return pns
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	ggproto "github.com/gogo/protobuf/proto"
+	floodsub "github.com/libp2p/go-floodsub"
 	p2p_crypto "github.com/libp2p/go-libp2p-crypto"
 	p2p_host "github.com/libp2p/go-libp2p-host"
 	p2p_peer "github.com/libp2p/go-libp2p-peer"
@@ -39,12 +40,32 @@ type Node struct {
 	dir       []p2p_pstore.PeerInfo
 	natCfg    mc.NATConfig
 	home      string
+	dburl     string
 	db        StatementDB
+	dskind    string
 	ds        Datastore
 	auth      PeerAuth
+	rauth     ReadAuth
+	explain   bool
 	mfs       []*pb.Manifest
 	mx        sync.Mutex
+	cfgmx     sync.Mutex
+	fimx      sync.Mutex
+	subs      map[p2p_peer.ID]*Subscription
+	submx     sync.Mutex
+	snotify   StatementNotify
+	ps        *floodsub.PubSub
+	gossip    map[string]context.CancelFunc
+	grdv      map[string]time.Time
+	gmx       sync.Mutex
+	jobs      []*MergeJob
+	jobmx     sync.Mutex
+	policy    *MergePolicy
+	pmx       sync.Mutex
+	trust     PublisherTrust
 	counter   int
+	syncs     map[p2p_peer.ID]bool
+	syncmx    sync.Mutex
 }
 
 type StatementDB interface {
@@ -54,10 +75,23 @@ type StatementDB interface {
 	Get(id string) (*pb.Statement, error)
 	Query(*mcq.Query) ([]interface{}, error)
 	QueryStream(context.Context, *mcq.Query) (<-chan interface{}, error)
+	QueryStreamCursor(context.Context, *mcq.Query) (<-chan interface{}, error)
 	QueryOne(*mcq.Query) (interface{}, error)
+	Explain(*mcq.Query) ([]interface{}, error)
 	Merge(*pb.Statement) (bool, error)
 	MergeBatch([]*pb.Statement) (int, error)
 	Delete(*mcq.Query) (int, error)
+	PutFieldIndex(ns, path string) error
+	DropFieldIndex(ns, path string) error
+	FieldIndexes() ([]FieldIndex, error)
+	PutFields(ns, path string, counter int64, fields []FieldValue) error
+	PutTextIndex(ns, path string) error
+	DropTextIndex(ns, path string) error
+	TextIndexes() ([]FieldIndex, error)
+	PutText(ns, path string, counter int64, fields []FieldValue) error
+	Search(ns string, q string, limit int) ([]SearchResult, error)
+	ObjectNamespaces(key string) ([]string, error)
+	Retracted(stmts []*pb.Statement) (map[string]bool, error)
 	Vacuum(full bool) error
 	Close() error
 }
@@ -70,7 +104,9 @@ type Datastore interface {
 	Has(Key) (bool, error)
 	Get(Key) ([]byte, error)
 	Delete(Key) error
-	IterKeys(ctx context.Context) (<-chan Key, error)
+	// IterKeys streams the datastore keys; the error channel receives the
+	// iteration error, or nil, once the key channel is closed.
+	IterKeys(ctx context.Context) (<-chan Key, <-chan error)
 	Sync() error
 	Compact()
 	Close()
@@ -89,6 +125,14 @@ type PeerAuth struct {
 	mx    sync.Mutex
 }
 
+// ReadAuth governs remote reads: namespaces matching the private rules
+// are only readable by peers granted access to them; all other namespaces
+// are public.
+type ReadAuth struct {
+	PeerAuth
+	private []string
+}
+
 type NodeInfo struct {
 	Peer      string `json:"peer"`
 	Publisher string `json:"publisher"`
@@ -127,6 +171,22 @@ var (
 	LookupError      = errors.New("Peer lookup failure")
 	UnknownPeer      = errors.New("Unknown peer")
 	IllegalState     = errors.New("Illegal node state")
+	ExplainDenied    = errors.New("EXPLAIN queries are not allowed")
+	BadPolicy        = errors.New("Bad policy; expected allow or deny")
+	BadFieldPath     = errors.New("Illegal field path")
+	UnknownIndex     = errors.New("Unknown field index")
+	NoTextSearch     = errors.New("Full-text search is not available; it requires sqlite built with FTS5")
+	ReadDenied       = errors.New("Read access denied")
+	UnknownMergeJob  = errors.New("Unknown merge job")
+	MergeJobActive   = errors.New("Merge job is running")
+	BadMergePolicy   = errors.New("Bad merge policy")
+	BadTarget        = errors.New("Bad target; expected a statement published by this node in the namespace")
+	BadArchive       = errors.New("Bad archive; verification failed")
+	UntrustedArchive = errors.New("Archive published by an untrusted publisher")
+	UnknownDatastore = errors.New("Unknown datastore backend; expected rocksdb or bolt")
+	SyncBusy         = errors.New("Sync already in progress")
+	SyncRequestLimit = errors.New("Sync request limit exceeded")
+	NoRocksDB        = errors.New("The rocksdb datastore is not available in this build; use the bolt datastore")
 )
 
 const (
@@ -151,6 +211,42 @@ func (s StreamError) Error() string {
 	return s.Err
 }
 
+// FieldIndex is a declared index over a field of the metadata objects
+// of statements in a namespace; Counter tracks the last statement indexed.
+// Text indexes index the field for full-text search.
+type FieldIndex struct {
+	Namespace string `json:"namespace"`
+	Path      string `json:"path"`
+	Text      bool   `json:"text,omitempty"`
+	Counter   int64  `json:"counter"`
+	Count     int    `json:"count"`
+}
+
+type FieldValue struct {
+	Id    string
+	Value string
+}
+
+type SearchResult struct {
+	Statement *pb.Statement `json:"statement"`
+	Score     float64       `json:"score"`
+}
+
+// SearchQueryError is a full-text search query rejected by the text index
+type SearchQueryError struct {
+	Err string
+}
+
+func (e SearchQueryError) Error() string {
+	return "Bad search query: " + e.Err
+}
+
+// QueryCursor terminates the result stream of cursor queries with the
+// counter of the last statement, so that the query can be resumed.
+type QueryCursor struct {
+	Counter int64 `json:"cursor"`
+}
+
 func sendStreamError(ctx context.Context, ch chan interface{}, what string) {
 	select {
 	case ch <- StreamError{what}:
@@ -173,7 +269,13 @@ func (node *Node) doPublish(ns string, body interface{}) (string, error) {
 	}
 
 	err = node.db.Put(stmt)
-	return stmt.Id, err
+	if err != nil {
+		return "", err
+	}
+
+	node.statementsAdded(hasRetractions([]*pb.Statement{stmt}))
+	node.announceStatements(ns, []string{stmt.Id})
+	return stmt.Id, nil
 }
 
 func (node *Node) doPublishBatch(ns string, lst []interface{}) ([]string, error) {
@@ -193,26 +295,64 @@ func (node *Node) doPublishBatch(ns string, lst []interface{}) ([]string, error)
 		return nil, err
 	}
 
+	node.statementsAdded(hasRetractions(stmts))
+	node.announceStatements(ns, sids)
 	return sids, err
 }
 
-func (node *Node) doImport(stmts []*pb.Statement, pkcache map[string]p2p_crypto.PubKey) (int, error) {
+// statementsAdded is called after statements have been written to the db;
+// it deletes retracted statements if retractions were added, updates the
+// field indexes and wakes up subscription streams.
+func (node *Node) statementsAdded(retract bool) {
+	if retract {
+		node.honorRetractions()
+	}
+	node.catchupFieldIndexes()
+	node.snotify.notify()
+}
+
+func (node *Node) doImport(stmts []*pb.Statement, pkcache map[string]p2p_crypto.PubKey) (int, int, error) {
+	trusted := make([]*pb.Statement, 0, len(stmts))
 	for _, stmt := range stmts {
 		if !node.checkStatement(stmt) {
-			return 0, BadStatement
+			return 0, 0, BadStatement
 		}
 
 		verify, err := node.verifyStatementCacheKeys(stmt, pkcache)
 		if err != nil {
-			return 0, err
+			return 0, 0, err
 		}
 
 		if !verify {
-			return 0, BadStatement
+			return 0, 0, BadStatement
+		}
+
+		// statements by untrusted publishers are skipped
+		if node.trustPublisher(stmt.Publisher) {
+			trusted = append(trusted, stmt)
 		}
 	}
 
-	return node.db.MergeBatch(stmts)
+	rejects := len(stmts) - len(trusted)
+	if rejects > 0 {
+		log.Printf("Import: rejected %d statements by untrusted publishers", rejects)
+	}
+
+	trusted, err := node.skipRetracted(trusted)
+	if err != nil {
+		return 0, rejects, err
+	}
+
+	if len(trusted) == 0 {
+		return 0, rejects, nil
+	}
+
+	count, err := node.db.MergeBatch(trusted)
+	if count > 0 {
+		node.statementsAdded(hasRetractions(trusted))
+	}
+
+	return count, rejects, err
 }
 
 func (node *Node) makeStatement(ns string, body interface{}) (*pb.Statement, error) {
@@ -237,6 +377,12 @@ func (node *Node) makeStatement(ns string, body interface{}) (*pb.Statement, err
 	case *pb.ArchiveStatement:
 		stmt.Body = &pb.StatementBody{&pb.StatementBody_Archive{body}}
 
+	case *pb.RetractStatement:
+		stmt.Body = &pb.StatementBody{&pb.StatementBody_Retract{body}}
+
+	case *pb.SupersedeStatement:
+		stmt.Body = &pb.StatementBody{&pb.StatementBody_Supersede{body}}
+
 	default:
 		return nil, BadStatementBody
 	}
@@ -312,15 +458,44 @@ func (node *Node) verifyStatementSig(stmt *pb.Statement, pubk p2p_crypto.PubKey)
 }
 
 func (node *Node) openDB() error {
-	node.db = &SQLiteDB{}
+	node.db = node.newDB()
 	return node.db.Open(node.home)
 }
 
+func (node *Node) newDB() StatementDB {
+	if node.dburl != "" {
+		return &PostgresDB{url: node.dburl}
+	}
+	return &SQLiteDB{}
+}
+
 func (node *Node) openDS() error {
-	node.ds = &RocksDS{}
+	ds, err := newDatastore(node.dskind)
+	if err != nil {
+		return err
+	}
+
+	node.ds = ds
 	return node.ds.Open(node.home)
 }
 
+// datastore backends; nodes without a configured backend use rocksdb
+const (
+	DatastoreRocksDB = "rocksdb"
+	DatastoreBolt    = "bolt"
+)
+
+func newDatastore(kind string) (Datastore, error) {
+	switch kind {
+	case "", DatastoreRocksDB:
+		return newRocksDS()
+	case DatastoreBolt:
+		return &BoltDS{}, nil
+	default:
+		return nil, UnknownDatastore
+	}
+}
+
 // persistent configuration
 type NodeConfig struct {
 	Info     string                 `json:"info,omitempty"`
@@ -329,10 +504,27 @@ type NodeConfig struct {
 	Dirs     []string               `json:"dirs,omitempty"`
 	Auth     map[string]interface{} `json:"auth,omitempty"`
 	Manifest []*pb.Manifest         `json:"manifest,omitempty"`
-}
-
+	Explain  bool                   `json:"explain,omitempty"`
+	ReadAuth map[string]interface{} `json:"read_auth,omitempty"`
+	Private  []string               `json:"private,omitempty"`
+	Subs     []Subscription         `json:"subscriptions,omitempty"`
+	Gossip   []string               `json:"gossip,omitempty"`
+	Jobs     []MergeJob             `json:"merge_jobs,omitempty"`
+	Policy   *MergePolicy           `json:"policy,omitempty"`
+	Trust    TrustPolicy            `json:"trust"`
+	DB       string                 `json:"db,omitempty"`
+	DS       string                 `json:"ds,omitempty"`
+}
+
+// saveConfig is called from background tasks as well as the api, so saves
+// are serialized and the config file is replaced atomically; it must be
+// called without the node lock held.
 func (node *Node) saveConfig() error {
+	node.cfgmx.Lock()
+	defer node.cfgmx.Unlock()
+
 	var cfg NodeConfig
+	node.mx.Lock()
 	cfg.Info = node.info
 	cfg.NAT = node.natCfg.String()
 	if len(node.dir) > 0 {
@@ -342,8 +534,20 @@ func (node *Node) saveConfig() error {
 		}
 		cfg.Dirs = dirs
 	}
-	cfg.Auth = node.auth.toJSON()
+	cfg.Explain = node.explain
 	cfg.Manifest = node.mfs
+	node.mx.Unlock()
+
+	cfg.Auth = node.auth.toJSON()
+	cfg.ReadAuth = node.rauth.toJSON()
+	cfg.Private = node.rauth.getPrivate()
+	cfg.Subs = node.getSubscriptions()
+	cfg.Gossip = node.getGossip()
+	cfg.Jobs = node.getPersistentMergeJobs()
+	cfg.Policy = node.getPolicy()
+	cfg.Trust = node.trust.getPolicy()
+	cfg.DB = node.dburl
+	cfg.DS = node.dskind
 
 	bytes, err := json.Marshal(cfg)
 	if err != nil {
@@ -351,7 +555,13 @@ func (node *Node) saveConfig() error {
 	}
 
 	cfgpath := path.Join(node.home, "config.json")
-	return ioutil.WriteFile(cfgpath, bytes, 0644)
+	tmppath := cfgpath + ".tmp"
+	err = ioutil.WriteFile(tmppath, bytes, 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmppath, cfgpath)
 }
 
 func (node *Node) loadConfig() error {
@@ -404,7 +614,29 @@ func (node *Node) loadConfig() error {
 		return err
 	}
 
+	err = node.rauth.fromJSON(cfg.ReadAuth)
+	if err != nil {
+		return err
+	}
+	if !validNamespaceRules(cfg.Private) {
+		return BadNamespace
+	}
+	node.rauth.setPrivate(cfg.Private)
+
+	err = node.setSubscriptions(cfg.Subs)
+	if err != nil {
+		return err
+	}
+
+	node.setGossip(cfg.Gossip)
+	node.setMergeJobs(cfg.Jobs)
+	node.setPolicy(cfg.Policy)
+	node.trust.setPolicy(cfg.Trust)
+
 	node.mfs = cfg.Manifest
+	node.explain = cfg.Explain
+	node.dburl = cfg.DB
+	node.dskind = cfg.DS
 
 	return nil
 }
@@ -442,6 +674,12 @@ func (auth *PeerAuth) fromJSON(rmap map[string]interface{}) error {
 				return BadRuleset
 			}
 
+			// older nodes stored the rules of comma separated lists untrimmed
+			rule = strings.TrimSpace(rule)
+			if !validNamespaceRule(rule) {
+				return BadRuleset
+			}
+
 			rules[x] = rule
 		}
 
@@ -486,13 +724,20 @@ func (auth *PeerAuth) authorize(pid p2p_peer.ID, nss []string) bool {
 }
 
 func (auth *PeerAuth) authorizeAllow(rules []string, ns string) bool {
+	return matchNamespace(rules, ns)
+}
+
+// matchNamespace checks whether a namespace matches any of the rules;
+// rules are namespaces or ns wildcards, which match the namespace and the
+// namespaces under it: foo.* matches foo and foo.bar, but not foobar.
+func matchNamespace(rules []string, ns string) bool {
 	for _, rule := range rules {
 		switch {
 		case rule == "*":
 			return true
 
 		case strings.HasSuffix(rule, ".*"):
-			if strings.HasPrefix(ns, rule[:len(rule)-2]) {
+			if ns == rule[:len(rule)-2] || strings.HasPrefix(ns, rule[:len(rule)-1]) {
 				return true
 			}
 
@@ -504,6 +749,31 @@ func (auth *PeerAuth) authorizeAllow(rules []string, ns string) bool {
 	return false
 }
 
+// parseNamespaceRules parses a comma separated list of namespace rules
+func parseNamespaceRules(str string) ([]string, error) {
+	rules := strings.Split(str, ",")
+	for x, rule := range rules {
+		rules[x] = strings.TrimSpace(rule)
+	}
+
+	if !validNamespaceRules(rules) {
+		return nil, BadNamespace
+	}
+
+	return rules, nil
+}
+
+// validNamespaceRules checks namespace rules before they are used, as the
+// read rules are compiled into remote queries.
+func validNamespaceRules(rules []string) bool {
+	for _, rule := range rules {
+		if !validNamespaceRule(rule) {
+			return false
+		}
+	}
+	return true
+}
+
 func (auth *PeerAuth) getRules(pid p2p_peer.ID) []string {
 	auth.mx.Lock()
 	defer auth.mx.Unlock()
@@ -524,3 +794,59 @@ func (auth *PeerAuth) clearRules(pid p2p_peer.ID) {
 	delete(auth.peers, pid)
 	auth.mx.Unlock()
 }
+
+func (auth *ReadAuth) getPrivate() []string {
+	auth.mx.Lock()
+	defer auth.mx.Unlock()
+	return auth.private
+}
+
+func (auth *ReadAuth) setPrivate(rules []string) {
+	auth.mx.Lock()
+	auth.private = rules
+	auth.mx.Unlock()
+}
+
+func (auth *ReadAuth) hasPrivate() bool {
+	auth.mx.Lock()
+	defer auth.mx.Unlock()
+	return len(auth.private) > 0
+}
+
+func (auth *ReadAuth) isPrivate(ns string) bool {
+	auth.mx.Lock()
+	defer auth.mx.Unlock()
+	return auth.authorizeAllow(auth.private, ns)
+}
+
+func (auth *ReadAuth) authorizeRead(pid p2p_peer.ID, ns string) bool {
+	auth.mx.Lock()
+	defer auth.mx.Unlock()
+
+	if !auth.authorizeAllow(auth.private, ns) {
+		return true
+	}
+
+	return auth.authorizeAllow(auth.peers[pid], ns)
+}
+
+// restrictQuery restricts a query to the namespaces readable by the peer;
+// queries for a single unreadable namespace are denied.
+func (auth *ReadAuth) restrictQuery(pid p2p_peer.ID, q *mcq.Query) (*mcq.Query, error) {
+	auth.mx.Lock()
+	defer auth.mx.Unlock()
+
+	if len(auth.private) == 0 {
+		return q, nil
+	}
+
+	ns := q.Namespace()
+	if !strings.HasSuffix(ns, "*") {
+		if auth.authorizeAllow(auth.private, ns) && !auth.authorizeAllow(auth.peers[pid], ns) {
+			return nil, ReadDenied
+		}
+		return q, nil
+	}
+
+	return q.WithNamespaceRestriction(auth.private, auth.peers[pid]), nil
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"database/sql"
+	ggproto "github.com/gogo/protobuf/proto"
+	_ "github.com/lib/pq"
+	mcq "github.com/mediachain/concat/mc/query"
+	pb "github.com/mediachain/concat/proto"
+	"sort"
+)
+
+// PostgreSQL backend
+// Postgres supports concurrent writers, so unlike sqlite writes are not
+// serialized by the node; concurrent merges of the same statements are
+// resolved by the Statement primary key with ON CONFLICT DO NOTHING.
+// Transactions insert their envelopes last, under an advisory lock held
+// until they commit, so that counters are assigned in commit order while
+// the rest of concurrent writes overlap.
+// Full-text indexes are not supported.
+type PostgresDB struct {
+	SQLDB
+	url               string
+	mergeStmtData     *sql.Stmt
+	mergeStmtEnvelope *sql.Stmt
+}
+
+// advisory lock key for envelope inserts
+const envelopeLockKey = 0x6d636e6f6465
+
+type nopLocker struct{}
+
+func (nopLocker) Lock()   {}
+func (nopLocker) Unlock() {}
+
+// The value columns of the index tables use the pattern operator class, so
+// that LIKE prefix criteria can use the index regardless of the collation.
+var postgresSchema = []string{
+	"CREATE TABLE Statement (id VARCHAR(128) PRIMARY KEY, data BYTEA)",
+	"CREATE TABLE Envelope (counter BIGSERIAL PRIMARY KEY, id VARCHAR(128), namespace VARCHAR, publisher VARCHAR, source VARCHAR, timestamp BIGINT)",
+	"CREATE UNIQUE INDEX EnvelopeId ON Envelope (id)",
+	"CREATE INDEX EnvelopeNS ON Envelope (namespace varchar_pattern_ops)",
+	"CREATE TABLE Refs (id VARCHAR(128), wki VARCHAR)",
+	"CREATE INDEX RefsId ON Refs (id)",
+	"CREATE INDEX RefsWki ON Refs (wki varchar_pattern_ops)",
+	"CREATE TABLE Tags (id VARCHAR(128), tag VARCHAR)",
+	"CREATE INDEX TagsId ON Tags (id)",
+	"CREATE INDEX TagsTag ON Tags (tag varchar_pattern_ops)",
+	"CREATE TABLE Deps (id VARCHAR(128), dep VARCHAR)",
+	"CREATE INDEX DepsId ON Deps (id)",
+	"CREATE INDEX DepsDep ON Deps (dep varchar_pattern_ops)",
+	"CREATE TABLE Objects (id VARCHAR(128), object VARCHAR)",
+	"CREATE INDEX ObjectsId ON Objects (id)",
+	"CREATE INDEX ObjectsObject ON Objects (object)",
+	"CREATE TABLE Supersede (id VARCHAR(128), publisher VARCHAR, target VARCHAR(128), retract BOOLEAN)",
+	"CREATE INDEX SupersedeId ON Supersede (id)",
+	"CREATE INDEX SupersedeTarget ON Supersede (target)",
+	"CREATE TABLE FieldIndex (namespace VARCHAR, path VARCHAR, counter BIGINT, PRIMARY KEY (namespace, path))",
+	"CREATE TABLE Fields (id VARCHAR(128), namespace VARCHAR, path VARCHAR, value VARCHAR)",
+	"CREATE INDEX FieldsId ON Fields (id)",
+	"CREATE INDEX FieldsPathValue ON Fields (path, value)",
+	"CREATE INDEX FieldsNSPath ON Fields (namespace, path)",
+}
+
+func (sdb *PostgresDB) Open(home string) error {
+	err := sdb.openSchema(home)
+	if err != nil {
+		return err
+	}
+
+	err = sdb.migrate(false)
+	if err != nil {
+		return err
+	}
+
+	err = sdb.prepareStatements()
+	if err != nil {
+		return err
+	}
+
+	return sdb.prepareMergeStatements()
+}
+
+// openSchema connects to the database without migrating it; new databases
+// are created with the latest schema.
+func (sdb *PostgresDB) openSchema(home string) error {
+	db, err := sql.Open("postgres", sdb.url)
+	if err != nil {
+		return err
+	}
+
+	sdb.db = db
+	sdb.wlock = nopLocker{}
+	sdb.dialect = mcq.Postgres
+
+	err = db.Ping()
+	if err != nil {
+		return err
+	}
+
+	have, err := sdb.hasTable("Statement")
+	if err != nil || have {
+		return err
+	}
+
+	return sdb.createTables()
+}
+
+// createTables creates the schema in a single transaction, so that a failed
+// initialization doesn't leave a partial schema behind.
+func (sdb *PostgresDB) createTables() error {
+	tx, err := sdb.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	for _, decl := range postgresSchema {
+		_, err = tx.Exec(decl)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	err = sdb.createSchemaVersion(tx, latestSchemaVersion())
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (sdb *PostgresDB) prepareMergeStatements() error {
+	stmt, err := sdb.db.Prepare("INSERT INTO Statement VALUES ($1, $2) ON CONFLICT (id) DO NOTHING")
+	if err != nil {
+		return err
+	}
+	sdb.mergeStmtData = stmt
+
+	stmt, err = sdb.db.Prepare("INSERT INTO Envelope (id, namespace, publisher, source, timestamp) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (id) DO NOTHING")
+	if err != nil {
+		return err
+	}
+	sdb.mergeStmtEnvelope = stmt
+
+	return nil
+}
+
+func (sdb *PostgresDB) Merge(stmt *pb.Statement) (bool, error) {
+	count, err := sdb.MergeBatch([]*pb.Statement{stmt})
+	return count > 0, err
+}
+
+func (sdb *PostgresDB) MergeBatch(stmts []*pb.Statement) (count int, err error) {
+	// statements are inserted in id order, so that concurrent merges of
+	// overlapping batches acquire their row locks in the same order and
+	// can't deadlock
+	xstmts := make([]*pb.Statement, len(stmts))
+	copy(xstmts, stmts)
+	sort.Sort(statementsById(xstmts))
+
+	tx, err := sdb.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	insertData := tx.Stmt(sdb.mergeStmtData)
+	insertRefs := tx.Stmt(sdb.insertStmtRefs)
+	insertTags := tx.Stmt(sdb.insertStmtTags)
+	insertDeps := tx.Stmt(sdb.insertStmtDeps)
+	insertObjects := tx.Stmt(sdb.insertStmtObjects)
+	insertTargets := tx.Stmt(sdb.insertStmtTargets)
+
+	merged := make([]*pb.Statement, 0, len(xstmts))
+	for _, stmt := range xstmts {
+		bytes, err := ggproto.Marshal(stmt)
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+
+		res, err := insertData.Exec(stmt.Id, bytes)
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+
+		rows, err := res.RowsAffected()
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+
+		if rows == 0 {
+			continue
+		}
+
+		err = insertStatementIndex(insertRefs, stmt.Id, mcq.StatementRefs(stmt))
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+
+		err = insertStatementIndex(insertTags, stmt.Id, mcq.StatementTags(stmt))
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+
+		err = insertStatementIndex(insertDeps, stmt.Id, mcq.StatementDeps(stmt))
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+
+		err = insertStatementIndex(insertObjects, stmt.Id, mcq.StatementObjects(stmt))
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+
+		err = insertStatementTargets(insertTargets, stmt)
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+
+		merged = append(merged, stmt)
+	}
+
+	err = sdb.insertEnvelopes(tx, tx.Stmt(sdb.mergeStmtEnvelope), merged)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+
+	return len(merged), nil
+}
+
+type statementsById []*pb.Statement
+
+func (s statementsById) Len() int           { return len(s) }
+func (s statementsById) Less(i, j int) bool { return s[i].Id < s[j].Id }
+func (s statementsById) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// Vacuum runs a plain vacuum, which reclaims space for reuse without
+// blocking writers, or a full vacuum, which compacts the tables but locks
+// them for the duration. Both update the planner statistics.
+func (sdb *PostgresDB) Vacuum(full bool) error {
+	if full {
+		_, err := sdb.db.Exec("VACUUM FULL ANALYZE")
+		return err
+	}
+
+	_, err := sdb.db.Exec("VACUUM ANALYZE")
+	return err
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	mcq "github.com/mediachain/concat/mc/query"
+	pb "github.com/mediachain/concat/proto"
+	"strings"
+	"time"
+)
+
+// MergePolicy filters the statements merged from remote peers, whether
+// they are merged from queries or pushed by the peer. Signed statements
+// cannot be rewritten, so statements that don't satisfy the policy are
+// skipped.
+// Aliases map local namespace names to namespaces (or ns wildcards); they
+// are resolved in the FROM clause of local queries.
+type MergePolicy struct {
+	// if non-empty, only statements by these publishers are accepted
+	AllowPublishers []string `json:"allowPublishers,omitempty"`
+	// statements by these publishers are rejected
+	DenyPublishers []string `json:"denyPublishers,omitempty"`
+	// if non-empty, only statements in matching namespaces are accepted
+	Namespaces []string `json:"namespaces,omitempty"`
+	// if non-zero, statements older than MaxAge seconds are rejected
+	MaxAge int64 `json:"maxAge,omitempty"`
+	// statements without well-known identifiers are rejected
+	RequireRefs bool              `json:"requireRefs,omitempty"`
+	Aliases     map[string]string `json:"aliases,omitempty"`
+}
+
+func (p *MergePolicy) validate() error {
+	if p.MaxAge < 0 {
+		return BadMergePolicy
+	}
+
+	for _, rule := range p.Namespaces {
+		if !validNamespaceRule(rule) {
+			return BadNamespace
+		}
+	}
+
+	for alias, ns := range p.Aliases {
+		if !nsrx.Match([]byte(alias)) || !validNamespaceRule(ns) {
+			return BadNamespace
+		}
+	}
+
+	return nil
+}
+
+func validNamespaceRule(rule string) bool {
+	switch {
+	case rule == "*":
+		return true
+	case strings.HasSuffix(rule, ".*"):
+		return nsrx.Match([]byte(rule[:len(rule)-2]))
+	default:
+		return nsrx.Match([]byte(rule))
+	}
+}
+
+// accept checks a statement against the policy
+func (p *MergePolicy) accept(stmt *pb.Statement) bool {
+	if len(p.AllowPublishers) > 0 && !containsString(p.AllowPublishers, stmt.Publisher) {
+		return false
+	}
+
+	if containsString(p.DenyPublishers, stmt.Publisher) {
+		return false
+	}
+
+	if len(p.Namespaces) > 0 && !matchNamespace(p.Namespaces, stmt.Namespace) {
+		return false
+	}
+
+	if p.MaxAge > 0 && stmt.Timestamp < time.Now().Unix()-p.MaxAge {
+		return false
+	}
+
+	if p.RequireRefs && len(mcq.StatementRefs(stmt)) == 0 {
+		return false
+	}
+
+	return true
+}
+
+// acceptNamespaces checks whether statements in any of the namespaces
+// may be accepted
+func (p *MergePolicy) acceptNamespaces(nss []string) bool {
+	if len(p.Namespaces) == 0 {
+		return true
+	}
+
+	for _, ns := range nss {
+		if matchNamespace(p.Namespaces, ns) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsString(lst []string, s string) bool {
+	for _, x := range lst {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (node *Node) getPolicy() *MergePolicy {
+	node.pmx.Lock()
+	defer node.pmx.Unlock()
+	if node.policy == nil {
+		return &MergePolicy{}
+	}
+	return node.policy
+}
+
+// setPolicy replaces the policy; policies are not modified once set, so
+// that they can be used without holding the lock.
+func (node *Node) setPolicy(p *MergePolicy) {
+	node.pmx.Lock()
+	node.policy = p
+	node.pmx.Unlock()
+}
+
+// resolveAlias resolves namespace aliases in the FROM clause of a local query
+func (node *Node) resolveAlias(q *mcq.Query) *mcq.Query {
+	ns, ok := node.getPolicy().Aliases[q.Namespace()]
+	if !ok {
+		return q
+	}
+
+	return q.WithNamespace(ns)
+}
@@ -109,6 +109,9 @@ func (node *Node) queryHandler(s p2p_net.Stream) {
 
 	writeValue := func(val interface{}) error {
 		switch val := val.(type) {
+		case QueryCursor:
+			res.Result = &pb.QueryResult_Cursor{&pb.QueryCursor{val.Counter}}
+
 		case map[string]interface{}:
 			cv, err := mc.CompoundValue(val)
 			if err != nil {
@@ -149,12 +152,60 @@ func (node *Node) queryHandler(s p2p_net.Stream) {
 			return
 		}
 
-		if q.Op != mcq.OpSelect {
+		q, err = node.rauth.restrictQuery(pid, q)
+		if err != nil {
+			log.Printf("node/query: rejected query from %s; not authorized", pid.Pretty())
+			writeError(err)
+			return
+		}
+
+		switch q.Op {
+		case mcq.OpSelect:
+		case mcq.OpExplain:
+			node.mx.Lock()
+			explain := node.explain
+			node.mx.Unlock()
+
+			if !explain {
+				writeError(ExplainDenied)
+				return
+			}
+
+			// the compiled sql of restricted queries contains the private
+			// namespace rules and the grants of the peer
+			if node.rauth.hasPrivate() {
+				log.Printf("node/query: rejected EXPLAIN from %s; private namespaces", pid.Pretty())
+				writeError(ExplainDenied)
+				return
+			}
+
+			res, err := node.db.Explain(q)
+			if err != nil {
+				writeError(err)
+				return
+			}
+
+			for _, val := range res {
+				err = writeValue(val)
+				if err != nil {
+					return
+				}
+			}
+
+			err = writeEnd()
+			if err != nil {
+				return
+			}
+
+			req.Reset()
+			continue
+
+		default:
 			writeError(BadQuery)
 			return
 		}
 
-		ch, err := node.db.QueryStream(ctx, q)
+		ch, err := node.doQuery(ctx, q, req.Cursor, RemoteDataQueryCost)
 		if err != nil {
 			writeError(err)
 			return
@@ -176,6 +227,23 @@ func (node *Node) queryHandler(s p2p_net.Stream) {
 	}
 }
 
+// authorizeObject checks whether an object is readable by a peer; objects
+// are readable if some statement in a readable namespace references them.
+func (node *Node) authorizeObject(pid p2p_peer.ID, key58 string) (bool, error) {
+	nss, err := node.db.ObjectNamespaces(key58)
+	if err != nil {
+		return false, err
+	}
+
+	for _, ns := range nss {
+		if node.rauth.authorizeRead(pid, ns) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 func (node *Node) dataHandler(s p2p_net.Stream) {
 	defer s.Close()
 
@@ -210,6 +278,7 @@ func (node *Node) dataHandler(s p2p_net.Stream) {
 
 		log.Printf("node/data: %s asked for %d objects", pid.Pretty(), len(req.Keys))
 
+		checkRead := node.rauth.hasPrivate()
 		for _, key58 := range req.Keys {
 			key, err := multihash.FromB58String(key58)
 			if err != nil {
@@ -217,6 +286,19 @@ func (node *Node) dataHandler(s p2p_net.Stream) {
 				return
 			}
 
+			if checkRead {
+				ok, err := node.authorizeObject(pid, key58)
+				if err != nil {
+					writeError(err)
+					return
+				}
+
+				// unreadable objects are treated as missing
+				if !ok {
+					continue
+				}
+			}
+
 			data, err := node.ds.Get(Key(key))
 			if err != nil {
 				writeError(err)
@@ -266,6 +348,13 @@ func (node *Node) pushHandler(s p2p_net.Stream) {
 		return
 	}
 
+	if !node.getPolicy().acceptNamespaces(req.Namespaces) {
+		log.Printf("node/push: rejected push from %s; not accepted by merge policy", pid.Pretty())
+		res.Body = &pb.PushResponse_Reject{&pb.PushReject{"Not accepted by merge policy"}}
+		w.WriteMsg(&res)
+		return
+	}
+
 	res.Body = &pb.PushResponse_Accept{&pb.PushAccept{}}
 	err = w.WriteMsg(&res)
 	if err != nil {
@@ -281,8 +370,8 @@ func (node *Node) pushHandler(s p2p_net.Stream) {
 	var mdone bool
 
 	go func() {
-		scount, ocount, err := node.doMergeStream(ctx, pid, wch)
-		rch <- PushMergeResult{scount, ocount, err}
+		scount, ocount, rejects, err := node.doMergeStream(ctx, pid, wch)
+		rch <- PushMergeResult{scount, ocount, rejects, err}
 	}()
 
 	nsfilter := make(map[string]bool)
@@ -336,6 +425,7 @@ loop:
 
 	end.Statements = int64(mres.scount)
 	end.Objects = int64(mres.ocount)
+	end.Rejected = int64(mres.rejects)
 	if err == nil && mres.err != nil {
 		err = mres.err
 	}
@@ -348,9 +438,10 @@ loop:
 }
 
 type PushMergeResult struct {
-	scount int
-	ocount int
-	err    error
+	scount  int
+	ocount  int
+	rejects int
+	err     error
 }
 
 func (node *Node) doRemoteId(ctx context.Context, pid p2p_peer.ID) (empty NodeInfo, err error) {
@@ -428,14 +519,23 @@ func (node *Node) doPing(ctx context.Context, pid p2p_peer.ID) error {
 }
 
 func (node *Node) doRemoteQuery(ctx context.Context, pid p2p_peer.ID, q string) (<-chan interface{}, error) {
+	return node.doRemoteQueryImpl(ctx, pid, &pb.QueryRequest{Query: q})
+}
+
+// doRemoteQueryCursor is like doRemoteQuery, but cursor queries are
+// terminated with a QueryCursor
+func (node *Node) doRemoteQueryCursor(ctx context.Context, pid p2p_peer.ID, q string) (<-chan interface{}, error) {
+	return node.doRemoteQueryImpl(ctx, pid, &pb.QueryRequest{Query: q, Cursor: true})
+}
+
+func (node *Node) doRemoteQueryImpl(ctx context.Context, pid p2p_peer.ID, req *pb.QueryRequest) (<-chan interface{}, error) {
 	s, err := node.doConnect(ctx, pid, "/mediachain/node/query")
 	if err != nil {
 		return nil, err
 	}
 
-	req := pb.QueryRequest{q}
 	w := ggio.NewDelimitedWriter(s)
-	err = w.WriteMsg(&req)
+	err = w.WriteMsg(req)
 	if err != nil {
 		s.Close()
 		return nil, err
@@ -475,6 +575,13 @@ func (node *Node) doRemoteQueryStream(ctx context.Context, s p2p_net.Stream, ch
 				return
 			}
 
+		case *pb.QueryResult_Cursor:
+			select {
+			case ch <- QueryCursor{res.Cursor.Counter}:
+			case <-ctx.Done():
+				return
+			}
+
 		case *pb.QueryResult_End:
 			return
 
@@ -491,19 +598,25 @@ func (node *Node) doRemoteQueryStream(ctx context.Context, s p2p_net.Stream, ch
 	}
 }
 
-func (node *Node) doMerge(ctx context.Context, pid p2p_peer.ID, q string) (count int, ocount int, err error) {
+func (node *Node) doMerge(ctx context.Context, pid p2p_peer.ID, q string) (count int, ocount int, rejects int, err error) {
 	ch, err := node.doRemoteQuery(ctx, pid, q)
 	if err != nil {
-		return 0, 0, err
+		return 0, 0, 0, err
 	}
 
 	return node.doMergeStream(ctx, pid, ch)
 }
 
-func (node *Node) doMergeStream(ctx context.Context, pid p2p_peer.ID, ch <-chan interface{}) (count int, ocount int, err error) {
+// doMergeStream merges a stream of statements and their data; it returns
+// the number of statements and objects merged, and the number of statements
+// rejected by publisher trust.
+func (node *Node) doMergeStream(ctx context.Context, pid p2p_peer.ID, ch <-chan interface{}) (count int, ocount int, rejects int, err error) {
 	// publisher key cache
 	pkcache := make(map[string]p2p_crypto.PubKey)
 
+	policy := node.getPolicy()
+	retract := false
+
 	// background data merges
 	workers := runtime.NumCPU()
 	workch := make(chan map[string]Key, 64*workers) // ~ 3MB/worker
@@ -525,6 +638,11 @@ loop:
 				break loop
 			}
 
+			// statements rejected by policy are skipped
+			if !policy.accept(val) {
+				continue
+			}
+
 			var verify bool
 			verify, err = node.verifyStatementCacheKeys(val, pkcache)
 			if err != nil {
@@ -537,6 +655,12 @@ loop:
 				break loop
 			}
 
+			// only statements with valid signatures count as rejections
+			if !node.trustPublisher(val.Publisher) {
+				rejects++
+				continue
+			}
+
 			err = node.mergeStatementKeys(val, keys)
 			if err != nil {
 				break loop
@@ -563,11 +687,12 @@ loop:
 
 			if len(stmts) >= batch {
 				var xcount int
-				xcount, err = node.db.MergeBatch(stmts)
+				xcount, err = node.mergeStatementBatch(stmts)
 				count += xcount
 				if err != nil {
 					break loop
 				}
+				retract = retract || hasRetractions(stmts)
 				stmts = stmts[:0]
 			}
 
@@ -597,8 +722,9 @@ loop:
 
 	if len(stmts) > 0 && err == nil {
 		var xcount int
-		xcount, err = node.db.MergeBatch(stmts)
+		xcount, err = node.mergeStatementBatch(stmts)
 		count += xcount
+		retract = retract || hasRetractions(stmts)
 	}
 
 	close(workch)
@@ -610,7 +736,27 @@ loop:
 		}
 	}
 
-	return count, ocount, err
+	// index the merged statements now that their metadata is here
+	if count > 0 {
+		node.statementsAdded(retract)
+	}
+
+	if rejects > 0 {
+		log.Printf("Rejected %d statements by untrusted publishers from %s", rejects, pid.Pretty())
+	}
+
+	return count, ocount, rejects, err
+}
+
+// mergeStatementBatch merges a batch of statements, skipping the statements
+// already retracted
+func (node *Node) mergeStatementBatch(stmts []*pb.Statement) (int, error) {
+	stmts, err := node.skipRetracted(stmts)
+	if err != nil || len(stmts) == 0 {
+		return 0, err
+	}
+
+	return node.db.MergeBatch(stmts)
 }
 
 type MergeResult struct {
@@ -783,6 +929,15 @@ func (node *Node) mergeStatementKeys(stmt *pb.Statement, keys map[string]Key) er
 		}
 		return nil
 
+	case *pb.StatementBody_Retract, *pb.StatementBody_Archive:
+		return nil
+
+	case *pb.StatementBody_Supersede:
+		if body.Supersede.Body != nil {
+			return mergeSimple(body.Supersede.Body)
+		}
+		return nil
+
 	default:
 		return BadStatementBody
 	}
@@ -803,15 +958,18 @@ func (node *Node) mergeObjectKey(key58 string, keys map[string]Key) error {
 	return nil
 }
 
-func (node *Node) doPush(ctx context.Context, pid p2p_peer.ID, q *mcq.Query) (int, int, error) {
+// doPush pushes the statements selected by the query to a peer; it returns
+// the number of statements and objects merged by the peer, and the number of
+// statements it rejected by publisher trust.
+func (node *Node) doPush(ctx context.Context, pid p2p_peer.ID, q *mcq.Query) (int, int, int, error) {
 	nsq := q.WithSimpleSelect("namespace")
 	nsr, err := node.db.Query(nsq)
 	if err != nil {
-		return 0, 0, err
+		return 0, 0, 0, err
 	}
 
 	if len(nsr) == 0 {
-		return 0, 0, err
+		return 0, 0, 0, err
 	}
 
 	nss := make([]string, len(nsr))
@@ -821,7 +979,7 @@ func (node *Node) doPush(ctx context.Context, pid p2p_peer.ID, q *mcq.Query) (in
 
 	s, err := node.doConnect(ctx, pid, "/mediachain/node/push")
 	if err != nil {
-		return 0, 0, err
+		return 0, 0, 0, err
 	}
 	defer s.Close()
 
@@ -835,26 +993,26 @@ func (node *Node) doPush(ctx context.Context, pid p2p_peer.ID, q *mcq.Query) (in
 
 	err = w.WriteMsg(&req)
 	if err != nil {
-		return 0, 0, err
+		return 0, 0, 0, err
 	}
 
 	err = r.ReadMsg(&res)
 	if err != nil {
-		return 0, 0, err
+		return 0, 0, 0, err
 	}
 
 	switch body := res.Body.(type) {
 	case *pb.PushResponse_Accept:
 		break
 	case *pb.PushResponse_Reject:
-		return 0, 0, PushError(body.Reject.Error)
+		return 0, 0, 0, PushError(body.Reject.Error)
 	default:
-		return 0, 0, BadResponse
+		return 0, 0, 0, BadResponse
 	}
 
 	qch, err := node.db.QueryStream(ctx, q)
 	if err != nil {
-		return 0, 0, err
+		return 0, 0, 0, err
 	}
 
 	rch := make(chan PushMergeResult, 1)
@@ -863,13 +1021,14 @@ func (node *Node) doPush(ctx context.Context, pid p2p_peer.ID, q *mcq.Query) (in
 		var end pb.PushEnd
 		err := r.ReadMsg(&end)
 		if err != nil {
-			rch <- PushMergeResult{-1, -1, err}
+			rch <- PushMergeResult{-1, -1, 0, err}
 			return
 		}
 
 		var res PushMergeResult
 		res.scount = int(end.Statements)
 		res.ocount = int(end.Objects)
+		res.rejects = int(end.Rejected)
 		if end.Error != "" {
 			res.err = PushError(end.Error)
 		}
@@ -898,12 +1057,12 @@ loop:
 
 			err = writeValue(stmt.(*pb.Statement))
 			if err != nil {
-				return -1, -1, err
+				return -1, -1, 0, err
 			}
 
 		case res := <-rch:
 			log.Printf("node/push: premature push end: %s", res.err)
-			return res.scount, res.ocount, res.err
+			return res.scount, res.ocount, res.rejects, res.err
 
 		case <-ctx.Done():
 			break loop
@@ -912,9 +1071,9 @@ loop:
 
 	err = writeEnd()
 	if err != nil {
-		return -1, -1, err
+		return -1, -1, 0, err
 	}
 
 	pres := <-rch
-	return pres.scount, pres.ocount, pres.err
+	return pres.scount, pres.ocount, pres.rejects, pres.err
 }
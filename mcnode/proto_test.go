@@ -0,0 +1,663 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	ggproto "github.com/gogo/protobuf/proto"
+	mux "github.com/gorilla/mux"
+	pb "github.com/mediachain/concat/proto"
+	multihash "github.com/multiformats/go-multihash"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// End-to-end protocol tests, running on an in-process testNet
+
+func TestNetMerge(t *testing.T) {
+	tn := newTestNet(t)
+	defer tn.Close()
+
+	a := tn.addNode()
+	b := tn.addNode()
+	tn.introduce(b, a)
+
+	ids, keys := publishTestObjects(t, a, "test.a", 10)
+	xids, xkeys := publishTestObjects(t, a, "test.b", 5)
+
+	ctx := context.Background()
+	count, ocount, _, err := b.doMerge(ctx, a.ID, "SELECT * FROM test.a")
+	checkErrorNow(t, "doMerge", err)
+	checkBool(t, "doMerge statements", count == 10)
+	checkBool(t, "doMerge objects", ocount == 10)
+	checkStatementIds(t, b, "SELECT id FROM *", ids)
+	checkObjects(t, b, keys, true)
+	checkObjects(t, b, xkeys, false)
+
+	// merged statements are identical to the originals
+	for _, id := range ids {
+		stmt, err := a.db.Get(id)
+		checkErrorNow(t, "Get", err)
+		xstmt, err := b.db.Get(id)
+		checkErrorNow(t, "Get merged", err)
+		checkBool(t, "merged statement", ggproto.Equal(stmt, xstmt))
+	}
+
+	// merges are idempotent
+	count, ocount, _, err = b.doMerge(ctx, a.ID, "SELECT * FROM test.a")
+	checkErrorNow(t, "doMerge again", err)
+	checkBool(t, "doMerge again statements", count == 0)
+	checkBool(t, "doMerge again objects", ocount == 0)
+
+	// only new statements and objects are transferred
+	count, ocount, _, err = b.doMerge(ctx, a.ID, "SELECT * FROM test.*")
+	checkErrorNow(t, "doMerge wildcard", err)
+	checkBool(t, "doMerge wildcard statements", count == 5)
+	checkBool(t, "doMerge wildcard objects", ocount == 5)
+	checkStatementIds(t, b, "SELECT id FROM *", append(ids, xids...))
+	checkObjects(t, b, xkeys, true)
+}
+
+func TestNetMergeBadStatement(t *testing.T) {
+	tn := newTestNet(t)
+	defer tn.Close()
+
+	a := tn.addNode()
+	b := tn.addNode()
+	tn.introduce(b, a)
+
+	publishTestObjects(t, a, "test.a", 3)
+
+	// a statement modified after signing
+	stmt, err := a.makeStatement("test.a", &pb.SimpleStatement{Object: "QmAAA"})
+	checkErrorNow(t, "makeStatement", err)
+	stmt.Body.GetSimple().Refs = []string{"test:forged"}
+	err = a.db.Put(stmt)
+	checkErrorNow(t, "Put", err)
+
+	// the forgery taints the whole result set
+	count, _, _, err := b.doMerge(context.Background(), a.ID, "SELECT * FROM test.a")
+	checkBool(t, "doMerge forged", err == BadStatement)
+	checkBool(t, "doMerge forged statements", count == 0)
+	checkStatementIds(t, b, "SELECT id FROM *", nil)
+}
+
+func TestNetMergePartial(t *testing.T) {
+	tn := newTestNet(t)
+	defer tn.Close()
+
+	a := tn.addNode()
+	b := tn.addNode()
+	tn.introduce(b, a)
+
+	ids, keys := publishTestObjects(t, a, "test.a", 10)
+
+	// the source is missing some of the metadata
+	lost := make([][]byte, 3)
+	for x, key := range keys[:3] {
+		data, err := a.ds.Get(key)
+		checkErrorNow(t, "Get", err)
+		lost[x] = data
+
+		err = a.ds.Delete(key)
+		checkErrorNow(t, "Delete", err)
+	}
+
+	ctx := context.Background()
+	count, ocount, _, err := b.doMerge(ctx, a.ID, "SELECT * FROM test.a")
+	checkBool(t, "doMerge missing data", err == MissingData)
+	checkBool(t, "doMerge missing data statements", count == 10)
+	checkBool(t, "doMerge missing data objects", ocount == 7)
+	checkStatementIds(t, b, "SELECT id FROM *", ids)
+	checkObjects(t, b, keys[:3], false)
+	checkObjects(t, b, keys[3:], true)
+
+	// merging again once the source has the data fills in the gaps
+	_, err = a.ds.PutBatch(lost)
+	checkErrorNow(t, "PutBatch", err)
+
+	count, ocount, _, err = b.doMerge(ctx, a.ID, "SELECT * FROM test.a")
+	checkErrorNow(t, "doMerge again", err)
+	checkBool(t, "doMerge again statements", count == 0)
+	checkBool(t, "doMerge again objects", ocount == 3)
+	checkObjects(t, b, keys, true)
+
+	// unreachable peers fail the merge without side effects
+	publishTestObjects(t, a, "test.b", 5)
+	tn.partition(a, b)
+
+	_, _, _, err = b.doMerge(ctx, a.ID, "SELECT * FROM test.b")
+	checkBool(t, "doMerge partition", err != nil)
+	checkStatementIds(t, b, "SELECT id FROM *", ids)
+}
+
+func TestNetMergeJob(t *testing.T) {
+	tn := newTestNet(t)
+	defer tn.Close()
+
+	a := tn.addNode()
+	b := tn.addNode()
+	tn.introduce(b, a)
+
+	ids, keys := publishTestObjects(t, a, "test.a", 10)
+
+	ctx := context.Background()
+	job, err := b.doMergeJob(a.ID, "SELECT * FROM test.a")
+	checkErrorNow(t, "doMergeJob", err)
+	xjob, err := b.waitMergeJob(ctx, job)
+	checkErrorNow(t, "waitMergeJob", err)
+	checkBool(t, "merge job status", xjob.Status == MergeJobDone)
+	checkBool(t, "merge job statements", xjob.Statements == 10)
+	checkBool(t, "merge job objects", xjob.Objects == 10)
+	checkStatementIds(t, b, "SELECT id FROM *", ids)
+	checkObjects(t, b, keys, true)
+
+	// done jobs are not persisted; the config is saved after the job is done
+	waitFor(t, "merge job config", func() bool {
+		return len(loadTestConfig(t, b).Jobs) == 0
+	})
+
+	// and only the most recent are kept
+	for x := 0; x < MergeJobHistory; x++ {
+		job, err = b.doMergeJob(a.ID, "SELECT * FROM test.a")
+		checkErrorNow(t, "doMergeJob", err)
+		_, err = b.waitMergeJob(ctx, job)
+		checkErrorNow(t, "waitMergeJob", err)
+	}
+
+	jobs := b.getMergeJobs()
+	checkBool(t, "merge job history", len(jobs) == MergeJobHistory)
+	checkBool(t, "merge job history order", jobs[len(jobs)-1].Id == job.Id)
+}
+
+func TestNetDiff(t *testing.T) {
+	tn := newTestNet(t)
+	defer tn.Close()
+
+	a := tn.addNode()
+	b := tn.addNode()
+	c := tn.addNode()
+	tn.introduce(b, a)
+	tn.introduce(b, c)
+
+	_, akeys := publishTestObjects(t, a, "test.a", 5)
+	publishTestObjects(t, c, "test.a", 3)
+
+	ctx := context.Background()
+	diff, err := b.doDiff(ctx, a.ID, "SELECT * FROM test.a")
+	checkErrorNow(t, "doDiff", err)
+	checkBool(t, "doDiff statements", diff.Statements == 5)
+	checkBool(t, "doDiff new", diff.New == 5)
+	checkBool(t, "doDiff missing objects", diff.MissingObjects == 5)
+
+	// statements by untrusted publishers are rejected, as in merges,
+	// without counting them in the trust report
+	b.trust.setPolicy(TrustPolicy{Publishers: []string{c.publisher.ID58}})
+	diff, err = b.doDiff(ctx, a.ID, "SELECT * FROM test.a")
+	checkErrorNow(t, "doDiff untrusted", err)
+	checkBool(t, "doDiff untrusted statements", diff.Statements == 5)
+	checkBool(t, "doDiff untrusted rejected", diff.Rejected == 5)
+	checkBool(t, "doDiff untrusted new", diff.New == 0)
+	checkBool(t, "doDiff untrusted objects", diff.Objects == 0)
+	checkBool(t, "doDiff untrusted report", len(b.trust.report().Rejected) == 0)
+
+	diff, err = b.doDiff(ctx, c.ID, "SELECT * FROM test.a")
+	checkErrorNow(t, "doDiff trusted", err)
+	checkBool(t, "doDiff trusted new", diff.New == 3)
+	checkBool(t, "doDiff trusted rejected", diff.Rejected == 0)
+
+	count, _, rejects, err := b.doMerge(ctx, a.ID, "SELECT * FROM test.a")
+	checkErrorNow(t, "doMerge untrusted", err)
+	checkBool(t, "doMerge untrusted", count == 0)
+	checkBool(t, "doMerge untrusted rejected", rejects == 5)
+	checkBool(t, "doMerge untrusted report", b.trust.report().Rejected[a.publisher.ID58] == 5)
+
+	// forged statements fail the merge without counting as rejections
+	stmt, err := a.makeStatement("test.a", &pb.SimpleStatement{Object: multihash.Multihash(akeys[0]).B58String()})
+	checkErrorNow(t, "makeStatement", err)
+	stmt.Body.GetSimple().Refs = []string{"test:forged"}
+	err = a.db.Put(stmt)
+	checkErrorNow(t, "Put", err)
+
+	_, _, rejects, err = b.doMerge(ctx, a.ID, fmt.Sprintf("SELECT * FROM test.a WHERE id = %s", stmt.Id))
+	checkBool(t, "doMerge forged", err == BadStatement)
+	checkBool(t, "doMerge forged rejected", rejects == 0)
+	checkBool(t, "doMerge forged report", b.trust.report().Rejected[a.publisher.ID58] == 5)
+
+	// rejections are counted for at most TrustMaxRejects publishers
+	for x := 0; x < TrustMaxRejects+10; x++ {
+		b.trust.accept(b.publisher.ID58, fmt.Sprintf("publisher%d", x))
+	}
+	report := b.trust.report()
+	checkBool(t, "trust report bounded", len(report.Rejected) <= TrustMaxRejects+1)
+	checkBool(t, "trust report overflow", report.Rejected["*"] == 11)
+
+	// statements not accepted by the merge policy are filtered
+	b.setPolicy(&MergePolicy{DenyPublishers: []string{c.publisher.ID58}})
+	diff, err = b.doDiff(ctx, c.ID, "SELECT * FROM test.a")
+	checkErrorNow(t, "doDiff policy", err)
+	checkBool(t, "doDiff policy statements", diff.Statements == 3)
+	checkBool(t, "doDiff policy filtered", diff.Filtered == 3)
+	checkBool(t, "doDiff policy new", diff.New == 0)
+	checkBool(t, "doDiff policy objects", diff.Objects == 0)
+}
+
+func TestNetRetract(t *testing.T) {
+	tn := newTestNet(t)
+	defer tn.Close()
+
+	a := tn.addNode()
+	b := tn.addNode()
+	c := tn.addNode()
+	tn.introduce(b, a)
+	tn.introduce(c, a)
+	tn.introduce(c, b)
+
+	ids, _ := publishTestObjects(t, a, "test.a", 3)
+
+	ctx := context.Background()
+	_, _, _, err := b.doMerge(ctx, a.ID, "SELECT * FROM test.a")
+	checkErrorNow(t, "doMerge", err)
+
+	rid, err := a.doRetract("test.a", ids[:1])
+	checkErrorNow(t, "doRetract", err)
+	checkStatementIds(t, a, "SELECT id FROM *", append([]string{rid}, ids[1:]...))
+
+	count, _, _, err := c.doMerge(ctx, a.ID, "SELECT * FROM test.a")
+	checkErrorNow(t, "doMerge retraction", err)
+	checkBool(t, "doMerge retraction", count == 3)
+
+	// retracted targets arriving after their retraction are skipped
+	b.auth.setRules(c.ID, []string{"test.*"})
+	stats, err := c.doSync(ctx, b.ID, "test.a")
+	checkErrorNow(t, "doSync", err)
+	checkBool(t, "doSync merged", stats.Merged == 0)
+	checkBool(t, "doSync pushed", stats.Pushed == 1)
+	checkStatementIds(t, c, "SELECT id FROM *", append([]string{rid}, ids[1:]...))
+
+	// and the pushed retraction is honored
+	checkStatementIds(t, b, "SELECT id FROM *", append([]string{rid}, ids[1:]...))
+}
+
+func TestNetPush(t *testing.T) {
+	tn := newTestNet(t)
+	defer tn.Close()
+
+	a := tn.addNode()
+	b := tn.addNode()
+	tn.introduce(a, b)
+
+	ids, keys := publishTestObjects(t, a, "test.a", 5)
+
+	ctx := context.Background()
+	q := parseQueryNow(t, "SELECT * FROM test.a")
+
+	// pushes require authorization for all namespaces
+	_, _, _, err := a.doPush(ctx, b.ID, q)
+	checkBool(t, "doPush unauthorized", err == PushError("Not authorized"))
+
+	b.auth.setRules(a.ID, []string{"test.b"})
+	_, _, _, err = a.doPush(ctx, b.ID, q)
+	checkBool(t, "doPush wrong namespace", err == PushError("Not authorized"))
+	checkStatementIds(t, b, "SELECT id FROM *", nil)
+
+	b.auth.setRules(a.ID, []string{"test.*"})
+	count, ocount, _, err := a.doPush(ctx, b.ID, q)
+	checkErrorNow(t, "doPush", err)
+	checkBool(t, "doPush statements", count == 5)
+	checkBool(t, "doPush objects", ocount == 5)
+	checkStatementIds(t, b, "SELECT id FROM *", ids)
+	checkObjects(t, b, keys, true)
+
+	// pushes are also subject to the merge policy
+	b.setPolicy(&MergePolicy{Namespaces: []string{"test.b"}})
+	_, _, _, err = a.doPush(ctx, b.ID, q)
+	checkBool(t, "doPush policy", err == PushError("Not accepted by merge policy"))
+}
+
+func TestNetSync(t *testing.T) {
+	tn := newTestNet(t)
+	defer tn.Close()
+
+	a := tn.addNode()
+	b := tn.addNode()
+	tn.introduce(a, b)
+
+	// enough statements to descend below the root buckets
+	ids, _ := publishTestObjects(t, a, "test.sync", 600)
+
+	ctx := context.Background()
+	count, _, _, err := b.doMerge(ctx, a.ID, "SELECT * FROM test.sync")
+	checkErrorNow(t, "doMerge", err)
+	checkBool(t, "doMerge", count == 600)
+
+	// the nodes differ in many buckets, in both directions
+	aids, _ := publishTestObjects(t, a, "test.sync", 40)
+	bids, _ := publishTestObjects(t, b, "test.sync", 30)
+	all := append(append(ids, aids...), bids...)
+
+	b.auth.setRules(a.ID, []string{"test.*"})
+	stats, err := a.doSync(ctx, b.ID, "test.sync")
+	checkErrorNow(t, "doSync", err)
+	checkBool(t, "doSync merged", stats.Merged == 30)
+	checkBool(t, "doSync pushed", stats.Pushed == 40)
+	checkStatementIds(t, a, "SELECT id FROM test.sync", all)
+	checkStatementIds(t, b, "SELECT id FROM test.sync", all)
+
+	// and nothing is transferred once they are reconciled
+	stats, err = a.doSync(ctx, b.ID, "test.sync")
+	checkErrorNow(t, "doSync reconciled", err)
+	checkBool(t, "doSync reconciled merged", stats.Merged == 0)
+	checkBool(t, "doSync reconciled pushed", stats.Pushed == 0)
+
+	// a peer can only run one sync session at a time
+	b.syncBegin(a.ID)
+	_, err = a.doSync(ctx, b.ID, "test.sync")
+	checkBool(t, "doSync busy", err != nil && err.Error() == SyncBusy.Error())
+	b.syncEnd(a.ID)
+
+	_, err = a.doSync(ctx, b.ID, "test.sync")
+	checkErrorNow(t, "doSync after busy", err)
+}
+
+func TestNetReadAuth(t *testing.T) {
+	tn := newTestNet(t)
+	defer tn.Close()
+
+	a := tn.addNode()
+	b := tn.addNode()
+	tn.introduce(a, b)
+
+	ids, _ := publishTestObjects(t, b, "test.public", 3)
+	xids, xkeys := publishTestObjects(t, b, "test.private", 3)
+	b.rauth.setPrivate([]string{"test.private"})
+
+	// private namespaces are not registered with the directory
+	pns := b.publicNamespaces()
+	checkBool(t, "publicNamespaces", containsString(pns, "test.public") && !containsString(pns, "test.private"))
+
+	// private namespaces are invisible to unauthorized peers
+	ctx := context.Background()
+	count, _, _, err := a.doMerge(ctx, b.ID, "SELECT * FROM test.*")
+	checkErrorNow(t, "doMerge", err)
+	checkBool(t, "doMerge statements", count == 3)
+	checkStatementIds(t, a, "SELECT id FROM *", ids)
+
+	// and so is their metadata
+	keys := make(map[string]Key)
+	for _, key := range xkeys {
+		keys[multihash.Multihash(key).B58String()] = key
+	}
+	ocount, err := a.doRawMerge(ctx, b.ID, keys)
+	checkBool(t, "doRawMerge private", err == MissingData)
+	checkBool(t, "doRawMerge private objects", ocount == 0)
+
+	b.rauth.setRules(a.ID, []string{"test.private"})
+	count, ocount, _, err = a.doMerge(ctx, b.ID, "SELECT * FROM test.*")
+	checkErrorNow(t, "doMerge authorized", err)
+	checkBool(t, "doMerge authorized statements", count == 3)
+	checkBool(t, "doMerge authorized objects", ocount == 3)
+	checkStatementIds(t, a, "SELECT id FROM *", append(ids, xids...))
+	checkObjects(t, a, xkeys, true)
+
+	// wildcard grants cover whole namespace components, so a peer granted
+	// test.foo.* can't read a private test.foobar.*
+	yids, _ := publishTestObjects(t, b, "test.foo.x", 2)
+	publishTestObjects(t, b, "test.foobar.x", 2)
+	b.rauth.setPrivate([]string{"test.foo.*", "test.foobar.*"})
+	b.rauth.setRules(a.ID, []string{"test.foo.*"})
+	checkBool(t, "authorizeRead test.foo", b.rauth.authorizeRead(a.ID, "test.foo"))
+	checkBool(t, "authorizeRead test.foo.x", b.rauth.authorizeRead(a.ID, "test.foo.x"))
+	checkBool(t, "authorizeRead test.foobar.x", !b.rauth.authorizeRead(a.ID, "test.foobar.x"))
+
+	count, _, _, err = a.doMerge(ctx, b.ID, "SELECT * FROM test.*")
+	checkErrorNow(t, "doMerge wildcard", err)
+	checkBool(t, "doMerge wildcard statements", count == 2)
+	checkStatementIds(t, a, "SELECT id FROM *", append(append(ids, xids...), yids...))
+
+	// EXPLAIN is denied, as the compiled sql reveals the private rules
+	b.mx.Lock()
+	b.explain = true
+	b.mx.Unlock()
+
+	ch, err := a.doRemoteQuery(ctx, b.ID, "EXPLAIN SELECT * FROM test.*")
+	checkErrorNow(t, "doRemoteQuery EXPLAIN", err)
+	res, ok := (<-ch).(StreamError)
+	checkBool(t, "doRemoteQuery EXPLAIN private", ok && res.Error() == ExplainDenied.Error())
+}
+
+func TestReadAuthRules(t *testing.T) {
+	tn := newTestNet(t)
+	defer tn.Close()
+
+	a := tn.addNode()
+	b := tn.addNode()
+
+	router := mux.NewRouter()
+	router.HandleFunc("/auth/read", b.httpAuthRead)
+	router.HandleFunc("/auth/read/{peerId}", b.httpAuthReadPeer)
+	router.HandleFunc("/auth/private", b.httpAuthPrivate)
+
+	post := func(url, body string) int {
+		req := httptest.NewRequest(http.MethodPost, url, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// rules are trimmed, so that every namespace in the list is private
+	code := post("/auth/private", "test.a, test.b.*")
+	checkBool(t, "private list", code == http.StatusOK)
+	checkBool(t, "private test.a", b.rauth.isPrivate("test.a"))
+	checkBool(t, "private test.b.x", b.rauth.isPrivate("test.b.x"))
+
+	// malformed rules are rejected and leave the rules unchanged
+	for _, rule := range []string{"x' OR 1=1 --", "test.*, foo*", "test.a,,test.b"} {
+		code = post("/auth/private", rule)
+		checkBool(t, "private malformed "+rule, code == http.StatusBadRequest)
+
+		code = post("/auth/read/"+a.ID.Pretty(), rule)
+		checkBool(t, "read malformed "+rule, code == http.StatusBadRequest)
+	}
+	checkBool(t, "private unchanged", len(b.rauth.getPrivate()) == 2)
+	checkBool(t, "read unchanged", len(b.rauth.getRules(a.ID)) == 0)
+
+	code = post("/auth/read/"+a.ID.Pretty(), "test.a , test.b.*")
+	checkBool(t, "read list", code == http.StatusOK)
+	checkBool(t, "read test.b.x", b.rauth.authorizeRead(a.ID, "test.b.x"))
+
+	// malformed rules are rejected at config load as well
+	cfgpath := path.Join(b.home, "config.json")
+	err := ioutil.WriteFile(cfgpath, []byte(`{"nat": "none", "private": ["x' OR 1=1 --"]}`), 0644)
+	checkErrorNow(t, "WriteFile", err)
+	err = b.loadConfig()
+	checkBool(t, "loadConfig private", err == BadNamespace)
+
+	err = ioutil.WriteFile(cfgpath, []byte(`{"nat": "none", "private": [" test.a"]}`), 0644)
+	checkErrorNow(t, "WriteFile", err)
+	err = b.loadConfig()
+	checkBool(t, "loadConfig private space", err == BadNamespace)
+
+	// but untrimmed peer rules stored by older nodes are trimmed
+	cfg := fmt.Sprintf(`{"nat": "none", "auth": {"%s": ["test.a", " test.b.*"]}}`, a.ID.Pretty())
+	err = ioutil.WriteFile(cfgpath, []byte(cfg), 0644)
+	checkErrorNow(t, "WriteFile", err)
+	err = b.loadConfig()
+	checkErrorNow(t, "loadConfig untrimmed", err)
+	checkBool(t, "loadConfig untrimmed rules", reflect.DeepEqual(b.auth.getRules(a.ID), []string{"test.a", "test.b.*"}))
+
+	err = ioutil.WriteFile(cfgpath, []byte(fmt.Sprintf(`{"nat": "none", "auth": {"%s": ["test.a", " foo*"]}}`, a.ID.Pretty())), 0644)
+	checkErrorNow(t, "WriteFile", err)
+	err = b.loadConfig()
+	checkBool(t, "loadConfig bad rule", err == BadRuleset)
+}
+
+func TestNetDirectory(t *testing.T) {
+	tn := newTestNet(t)
+	defer tn.Close()
+
+	a := tn.addNode()
+	b := tn.addNode()
+
+	ids, keys := publishTestObjects(t, a, "test.dir", 4)
+
+	err := a.goPublic()
+	checkErrorNow(t, "goPublic", err)
+	err = b.goPublic()
+	checkErrorNow(t, "goPublic", err)
+
+	ctx := context.Background()
+	waitFor(t, "directory registration", func() bool {
+		peers, err := b.doDirList(ctx, "test.dir")
+		return err == nil && len(peers) == 1 && peers[0] == a.ID.Pretty()
+	})
+
+	nss, err := b.doDirListNS(ctx)
+	checkErrorNow(t, "doDirListNS", err)
+	checkBool(t, "doDirListNS", len(nss) == 1 && nss[0] == "test.dir")
+
+	// peers are reachable through the directory
+	count, ocount, _, err := b.doMerge(ctx, a.ID, "SELECT * FROM test.dir")
+	checkErrorNow(t, "doMerge", err)
+	checkBool(t, "doMerge statements", count == 4)
+	checkBool(t, "doMerge objects", ocount == 4)
+	checkStatementIds(t, b, "SELECT id FROM *", ids)
+	checkObjects(t, b, keys, true)
+
+	pinfo, err := b.doLookup(ctx, a.ID)
+	checkErrorNow(t, "doLookup", err)
+	checkBool(t, "doLookup", pinfo.ID == a.ID && len(pinfo.Addrs) > 0)
+
+	// nodes are unregistered when they go offline
+	err = a.goOffline()
+	checkErrorNow(t, "goOffline", err)
+	waitFor(t, "directory unregistration", func() bool {
+		peers, err := b.doDirList(ctx, "")
+		return err == nil && len(peers) == 1 && peers[0] == b.ID.Pretty()
+	})
+}
+
+func TestNetSubscribe(t *testing.T) {
+	tn := newTestNet(t)
+	defer tn.Close()
+
+	a := tn.addNode()
+	b := tn.addNode()
+	tn.introduce(b, a)
+
+	ids, keys := publishTestObjects(t, a, "test.sub", 3)
+
+	err := b.doSubscribe(a.ID, "SELECT * FROM test.*")
+	checkErrorNow(t, "doSubscribe", err)
+	waitFor(t, "subscription", func() bool { return countStatements(t, b) == 3 })
+	checkObjects(t, b, keys, true)
+
+	// statements are delivered as they are published
+	xids, xkeys := publishTestObjects(t, a, "test.sub", 2)
+	waitFor(t, "live subscription", func() bool { return countStatements(t, b) == 5 })
+	checkStatementIds(t, b, "SELECT id FROM *", append(ids, xids...))
+	checkObjects(t, b, xkeys, true)
+
+	// the counter is persisted when the stream breaks
+	tn.partition(a, b)
+	waitFor(t, "subscription counter", func() bool {
+		cfg := loadTestConfig(t, b)
+		return len(cfg.Subs) == 1 && cfg.Subs[0].Counter == 5
+	})
+
+	// and the subscription resumes from it, fetching only new statements
+	cfg := loadTestConfig(t, b)
+	err = b.doSubscribe(a.ID, "")
+	checkErrorNow(t, "doSubscribe cancel", err)
+
+	_, err = b.db.Delete(parseQueryNow(t, "DELETE FROM *"))
+	checkErrorNow(t, "Delete", err)
+
+	yids, _ := publishTestObjects(t, a, "test.sub", 2)
+
+	_, err = tn.mnet.LinkPeers(a.ID, b.ID)
+	checkErrorNow(t, "LinkPeers", err)
+
+	err = b.setSubscriptions(cfg.Subs)
+	checkErrorNow(t, "setSubscriptions", err)
+	b.mx.Lock()
+	b.startSubscriptions()
+	b.mx.Unlock()
+
+	waitFor(t, "resumed subscription", func() bool { return countStatements(t, b) == 2 })
+	checkStatementIds(t, b, "SELECT id FROM *", yids)
+}
+
+func TestNetGossip(t *testing.T) {
+	tn := newTestNet(t)
+	defer tn.Close()
+
+	a := tn.addNode()
+	b := tn.addNode()
+	tn.introduce(b, a)
+	a.rauth.setPrivate([]string{"test.private"})
+
+	err := b.doGossip([]string{"test.gossip", "test.private"})
+	checkErrorNow(t, "doGossip", err)
+
+	ctx := context.Background()
+	err = b.doConnectPeer(ctx, a.ID)
+	checkErrorNow(t, "doConnectPeer", err)
+
+	// published statements are announced, fetched and merged; announcements
+	// are repeated until the peers have exchanged their topic subscriptions
+	ids, keys := publishTestObjects(t, a, "test.gossip", 3)
+	waitFor(t, "gossip", func() bool {
+		if countStatements(t, b) == 3 {
+			return true
+		}
+		a.announceStatements("test.gossip", ids)
+		return false
+	})
+	checkStatementIds(t, b, "SELECT id FROM *", ids)
+	checkObjects(t, b, keys, true)
+
+	// statements in private namespaces are not announced
+	pids, _ := publishTestObjects(t, a, "test.private", 2)
+	a.announceStatements("test.private", pids)
+
+	xids, _ := publishTestObjects(t, a, "test.gossip", 2)
+	waitFor(t, "gossip after private", func() bool { return countStatements(t, b) >= 5 })
+	checkStatementIds(t, b, "SELECT id FROM *", append(ids, xids...))
+}
+
+func TestNetGossipRendezvous(t *testing.T) {
+	tn := newTestNet(t)
+	defer tn.Close()
+
+	a := tn.addNode()
+	b := tn.addNode()
+	c := tn.addNode()
+
+	// nodes that have never been connected find each other through the
+	// topic rendezvous
+	err := b.doGossip([]string{"test.gossip"})
+	checkErrorNow(t, "doGossip", err)
+	err = c.doGossip([]string{"test.gossip"})
+	checkErrorNow(t, "doGossip", err)
+	waitFor(t, "rendezvous", func() bool {
+		return tn.dht.countProviders(gossipTopic("test.gossip")) == 2
+	})
+
+	ids, keys := publishTestObjects(t, a, "test.gossip", 3)
+	waitFor(t, "gossip", func() bool {
+		if countStatements(t, b) == 3 && countStatements(t, c) == 3 {
+			return true
+		}
+		a.announceStatements("test.gossip", ids)
+		return false
+	})
+	checkStatementIds(t, b, "SELECT id FROM *", ids)
+	checkStatementIds(t, c, "SELECT id FROM *", ids)
+	checkObjects(t, b, keys, true)
+	checkObjects(t, c, keys, true)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	mcq "github.com/mediachain/concat/mc/query"
+	pb "github.com/mediachain/concat/proto"
+	"log"
+)
+
+// Retraction and supersession statements let publishers withdraw or correct
+// their statements across the network. They are ordinary signed statements
+// in the namespace of their targets, so they propagate with merges, pushes
+// and gossip like any other statement.
+// Targets are only honored when they have the same publisher as the
+// retraction or supersession: retracted statements are deleted when the
+// retraction is added, and targets that arrive after their retraction are
+// skipped by merges and imports, while superseded statements are kept and
+// can be excluded from queries with the superseded criterion.
+
+// doRetract publishes a retraction of statements in a namespace
+func (node *Node) doRetract(ns string, targets []string) (string, error) {
+	err := node.checkTargets(ns, targets)
+	if err != nil {
+		return "", err
+	}
+
+	return node.doPublish(ns, &pb.RetractStatement{Targets: targets})
+}
+
+// doSupersede publishes a replacement for statements in a namespace
+func (node *Node) doSupersede(ns string, body *pb.SupersedeStatement) (string, error) {
+	if body.Body == nil {
+		return "", BadStatementBody
+	}
+
+	err := node.checkTargets(ns, body.Targets)
+	if err != nil {
+		return "", err
+	}
+
+	return node.doPublish(ns, body)
+}
+
+// checkTargets checks that the targets are statements published by the
+// node in the namespace
+func (node *Node) checkTargets(ns string, targets []string) error {
+	if len(targets) == 0 {
+		return BadTarget
+	}
+
+	for _, target := range targets {
+		if !idrx.Match([]byte(target)) {
+			return BadTarget
+		}
+
+		stmt, err := node.db.Get(target)
+		switch {
+		case err == UnknownStatement:
+			return BadTarget
+		case err != nil:
+			return err
+		case stmt.Namespace != ns || stmt.Publisher != node.publisher.ID58:
+			return BadTarget
+		}
+	}
+
+	return nil
+}
+
+// hasRetractions checks whether there are retractions among the statements
+func hasRetractions(stmts []*pb.Statement) bool {
+	for _, stmt := range stmts {
+		_, retract := mcq.StatementTargets(stmt)
+		if retract {
+			return true
+		}
+	}
+	return false
+}
+
+// skipRetracted filters out the statements retracted by their publishers
+func (node *Node) skipRetracted(stmts []*pb.Statement) ([]*pb.Statement, error) {
+	retracted, err := node.db.Retracted(stmts)
+	if err != nil || len(retracted) == 0 {
+		return stmts, err
+	}
+
+	xstmts := make([]*pb.Statement, 0, len(stmts))
+	for _, stmt := range stmts {
+		if !retracted[stmt.Id] {
+			xstmts = append(xstmts, stmt)
+		}
+	}
+
+	return xstmts, nil
+}
+
+// honorRetractions deletes the statements retracted by their publishers;
+// failures are logged, as retractions are honored again with the next
+// retraction.
+func (node *Node) honorRetractions() {
+	q, err := mcq.ParseQuery("DELETE FROM * WHERE retracted")
+	if err != nil {
+		log.Printf("Error parsing retraction query: %s", err.Error())
+		return
+	}
+
+	count, err := node.db.Delete(q)
+	if err != nil {
+		log.Printf("Error deleting retracted statements: %s", err.Error())
+		return
+	}
+
+	if count > 0 {
+		log.Printf("Deleted %d retracted statements", count)
+	}
+}
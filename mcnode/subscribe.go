@@ -0,0 +1,414 @@
+package main
+
+import (
+	"context"
+	ggio "github.com/gogo/protobuf/io"
+	p2p_net "github.com/libp2p/go-libp2p-net"
+	p2p_peer "github.com/libp2p/go-libp2p-peer"
+	mc "github.com/mediachain/concat/mc"
+	mcq "github.com/mediachain/concat/mc/query"
+	pb "github.com/mediachain/concat/proto"
+	"log"
+	"sync"
+	"time"
+)
+
+// Subscriptions replicate statements from a remote peer as they are
+// published: the peer streams the statements matching the subscription
+// query in batches, each followed by the counter of its last statement.
+// Batches are merged as they arrive and the counter is persisted, so that
+// the subscription resumes where it left off after reconnecting.
+// The counter is persisted at most every SubscribeSave while streaming and
+// when the stream ends; merges are idempotent, so resuming from an older
+// counter only refetches statements.
+type Subscription struct {
+	Peer    string `json:"peer"`
+	Query   string `json:"query"`
+	Counter int64  `json:"counter"`
+	cancel  context.CancelFunc
+	saved   time.Time
+	dirty   bool
+}
+
+const (
+	SubscribeBatch = 1024
+	SubscribeRetry = 60 * time.Second
+	SubscribeSave  = 10 * time.Second
+)
+
+// StatementNotify wakes up subscription streams when new statements are
+// added to the db.
+type StatementNotify struct {
+	ch chan struct{}
+	mx sync.Mutex
+}
+
+func (sn *StatementNotify) wait() <-chan struct{} {
+	sn.mx.Lock()
+	defer sn.mx.Unlock()
+	if sn.ch == nil {
+		sn.ch = make(chan struct{})
+	}
+	return sn.ch
+}
+
+func (sn *StatementNotify) notify() {
+	sn.mx.Lock()
+	if sn.ch != nil {
+		close(sn.ch)
+		sn.ch = nil
+	}
+	sn.mx.Unlock()
+}
+
+func (node *Node) subscribeHandler(s p2p_net.Stream) {
+	defer s.Close()
+
+	pid := mc.LogStreamHandler(s)
+
+	ctx, cancel := context.WithCancel(node.netCtx)
+	defer cancel()
+
+	var req pb.SubscribeRequest
+	var res pb.QueryResult
+
+	r := ggio.NewDelimitedReader(s, mc.MaxMessageSize)
+	w := ggio.NewDelimitedWriter(s)
+
+	writeError := func(err error) {
+		res.Result = &pb.QueryResult_Error{&pb.StreamError{err.Error()}}
+		w.WriteMsg(&res)
+	}
+
+	writeValue := func(val interface{}) error {
+		switch val := val.(type) {
+		case QueryCursor:
+			res.Result = &pb.QueryResult_Cursor{&pb.QueryCursor{val.Counter}}
+
+		default:
+			sv, err := mc.SimpleValue(val)
+			if err != nil {
+				log.Printf("node/subscribe: error constructing value: %s", err.Error())
+				writeError(err)
+				return err
+			}
+
+			res.Result = &pb.QueryResult_Value{&pb.QueryResultValue{
+				&pb.QueryResultValue_Simple{sv}}}
+		}
+
+		return w.WriteMsg(&res)
+	}
+
+	err := r.ReadMsg(&req)
+	if err != nil {
+		return
+	}
+
+	log.Printf("node/subscribe: subscription from %s: %s", pid.Pretty(), req.Query)
+
+	q, err := mcq.ParseQuery(req.Query)
+	if err != nil {
+		writeError(err)
+		return
+	}
+
+	if !q.IsStreamSelect() {
+		writeError(BadQuery)
+		return
+	}
+
+	q, err = node.rauth.restrictQuery(pid, q)
+	if err != nil {
+		log.Printf("node/subscribe: rejected subscription from %s; not authorized", pid.Pretty())
+		writeError(err)
+		return
+	}
+
+	// the subscriber doesn't send anything else; the read returns when the
+	// stream is closed
+	go func() {
+		var xreq pb.SubscribeRequest
+		r.ReadMsg(&xreq)
+		cancel()
+	}()
+
+	counter := req.Counter
+	for {
+		wait := node.snotify.wait()
+
+		ch, err := node.db.QueryStreamCursor(ctx, q.WithCounterCursor(counter, SubscribeBatch))
+		if err != nil {
+			writeError(err)
+			return
+		}
+
+		count := 0
+		for val := range ch {
+			switch val := val.(type) {
+			case *pb.Statement:
+				count++
+
+			case QueryCursor:
+				counter = val.Counter
+
+			case StreamError:
+				writeError(val)
+				return
+
+			default:
+				writeError(BadResult)
+				return
+			}
+
+			err = writeValue(val)
+			if err != nil {
+				return
+			}
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if count == SubscribeBatch {
+			continue
+		}
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// doSubscribe creates or replaces the subscription to a peer; an empty
+// query cancels the subscription.
+func (node *Node) doSubscribe(pid p2p_peer.ID, q string) error {
+	node.mx.Lock()
+	defer node.mx.Unlock()
+
+	node.submx.Lock()
+	defer node.submx.Unlock()
+
+	var counter int64
+	osub, ok := node.subs[pid]
+	if ok {
+		if osub.cancel != nil {
+			osub.cancel()
+		}
+		delete(node.subs, pid)
+
+		// changing the query starts over
+		if osub.Query == q {
+			counter = osub.Counter
+		}
+	}
+
+	if q == "" {
+		return nil
+	}
+
+	if node.subs == nil {
+		node.subs = make(map[p2p_peer.ID]*Subscription)
+	}
+
+	sub := &Subscription{Peer: pid.Pretty(), Query: q, Counter: counter}
+	node.subs[pid] = sub
+
+	if node.status != StatusOffline {
+		node.startSubscription(pid, sub)
+	}
+
+	return nil
+}
+
+func (node *Node) getSubscription(pid p2p_peer.ID) (Subscription, bool) {
+	node.submx.Lock()
+	defer node.submx.Unlock()
+
+	sub, ok := node.subs[pid]
+	if !ok {
+		return Subscription{}, false
+	}
+
+	return *sub, true
+}
+
+func (node *Node) getSubscriptions() []Subscription {
+	node.submx.Lock()
+	defer node.submx.Unlock()
+
+	subs := make([]Subscription, 0, len(node.subs))
+	for _, sub := range node.subs {
+		subs = append(subs, *sub)
+	}
+
+	return subs
+}
+
+func (node *Node) setSubscriptions(subs []Subscription) error {
+	node.submx.Lock()
+	defer node.submx.Unlock()
+
+	node.subs = make(map[p2p_peer.ID]*Subscription)
+	for _, sub := range subs {
+		pid, err := p2p_peer.IDB58Decode(sub.Peer)
+		if err != nil {
+			return err
+		}
+
+		xsub := sub
+		node.subs[pid] = &xsub
+	}
+
+	return nil
+}
+
+// startSubscriptions is called with the node lock held when the node
+// goes online
+func (node *Node) startSubscriptions() {
+	node.submx.Lock()
+	defer node.submx.Unlock()
+
+	for pid, sub := range node.subs {
+		node.startSubscription(pid, sub)
+	}
+}
+
+func (node *Node) startSubscription(pid p2p_peer.ID, sub *Subscription) {
+	ctx, cancel := context.WithCancel(node.netCtx)
+	sub.cancel = cancel
+	go node.runSubscription(ctx, pid, sub)
+}
+
+func (node *Node) runSubscription(ctx context.Context, pid p2p_peer.ID, sub *Subscription) {
+	for {
+		err := node.doSubscribeStream(ctx, pid, sub)
+
+		xerr := node.saveSubscriptionCounter(sub)
+		if xerr != nil {
+			log.Printf("Error saving subscription to %s: %s", pid.Pretty(), xerr.Error())
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			log.Printf("Subscription to %s failed: %s", pid.Pretty(), err.Error())
+		} else {
+			log.Printf("Subscription to %s ended", pid.Pretty())
+		}
+
+		select {
+		case <-time.After(SubscribeRetry):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (node *Node) doSubscribeStream(ctx context.Context, pid p2p_peer.ID, sub *Subscription) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	node.submx.Lock()
+	counter := sub.Counter
+	node.submx.Unlock()
+
+	s, err := node.doConnect(ctx, pid, "/mediachain/node/subscribe")
+	if err != nil {
+		return err
+	}
+	// unblock the reader when we are done
+	defer s.Close()
+
+	w := ggio.NewDelimitedWriter(s)
+	err = w.WriteMsg(&pb.SubscribeRequest{Query: sub.Query, Counter: counter})
+	if err != nil {
+		return err
+	}
+
+	ch := make(chan interface{})
+	go node.doRemoteQueryStream(ctx, s, ch)
+
+	stmts := make([]*pb.Statement, 0)
+	for val := range ch {
+		switch val := val.(type) {
+		case *pb.Statement:
+			// the peer ends each batch with a cursor
+			if len(stmts) == SubscribeBatch {
+				return BadResponse
+			}
+			stmts = append(stmts, val)
+
+		case QueryCursor:
+			err = node.mergeSubscriptionBatch(ctx, pid, stmts)
+			if err != nil {
+				return err
+			}
+
+			err = node.setSubscriptionCounter(sub, val.Counter)
+			if err != nil {
+				return err
+			}
+
+			stmts = make([]*pb.Statement, 0)
+
+		case StreamError:
+			return val
+
+		default:
+			return BadResult
+		}
+	}
+
+	return ctx.Err()
+}
+
+func (node *Node) mergeSubscriptionBatch(ctx context.Context, pid p2p_peer.ID, stmts []*pb.Statement) error {
+	ch := make(chan interface{}, len(stmts))
+	for _, stmt := range stmts {
+		ch <- stmt
+	}
+	close(ch)
+
+	count, ocount, _, err := node.doMergeStream(ctx, pid, ch)
+	if count > 0 || ocount > 0 {
+		log.Printf("Subscription to %s: merged %d statements and %d objects", pid.Pretty(), count, ocount)
+	}
+
+	return err
+}
+
+func (node *Node) setSubscriptionCounter(sub *Subscription, counter int64) error {
+	node.submx.Lock()
+	sub.Counter = counter
+	sub.dirty = true
+	save := time.Since(sub.saved) >= SubscribeSave
+	node.submx.Unlock()
+
+	if !save {
+		return nil
+	}
+
+	return node.saveSubscriptionCounter(sub)
+}
+
+// saveSubscriptionCounter persists the counter if it changed since it
+// was last saved
+func (node *Node) saveSubscriptionCounter(sub *Subscription) error {
+	node.submx.Lock()
+	dirty := sub.dirty
+	sub.dirty = false
+	sub.saved = time.Now()
+	node.submx.Unlock()
+
+	if !dirty {
+		return nil
+	}
+
+	return node.saveConfig()
+}
@@ -0,0 +1,575 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	ggio "github.com/gogo/protobuf/io"
+	p2p_net "github.com/libp2p/go-libp2p-net"
+	p2p_peer "github.com/libp2p/go-libp2p-peer"
+	mc "github.com/mediachain/concat/mc"
+	mcq "github.com/mediachain/concat/mc/query"
+	pb "github.com/mediachain/concat/proto"
+	"log"
+	"sort"
+	"strings"
+)
+
+// Namespace sync reconciles the statements of a namespace between two
+// nodes without transferring the statements both have.
+// Statement ids are assigned to buckets by the prefix of their hash, and
+// each bucket is summarized by the xor of the hashes of its ids and their
+// count. The buckets form a tree, with each bucket split into 2^SyncFanoutBits
+// sub-buckets by the next bits of the prefix. The initiator descends the tree
+// from the root, comparing the remote summaries of the sub-buckets of the
+// buckets that differ with its own, until the differing buckets hold at most
+// SyncBucketSize ids on either side, and then retrieves the remote ids in them.
+// The missing statements are then merged from the peer, and the statements
+// the peer is missing are pushed to it, with the query and push protocols.
+// Each side hashes the namespace once per session; a peer may run a single
+// sync session at a time, with at most SyncMaxRequests bucket requests.
+const (
+	SyncBucketSize  = 32
+	SyncFanoutBits  = 4
+	SyncMaxBits     = 32
+	SyncBatch       = 1024
+	SyncMaxRequests = 1024
+)
+
+// Rejected counts the statements rejected by publisher trust when merged,
+// and PushRejected those rejected by the peer when pushed.
+type SyncStats struct {
+	Merged        int `json:"merged"`
+	MergedObjects int `json:"mergedObjects"`
+	Rejected      int `json:"rejected"`
+	Pushed        int `json:"pushed"`
+	PushedObjects int `json:"pushedObjects"`
+	PushRejected  int `json:"pushRejected"`
+}
+
+func syncHash(id string) (prefix uint32, digest uint64) {
+	hash := sha256.Sum256([]byte(id))
+	prefix = binary.BigEndian.Uint32(hash[:4])
+	digest = binary.BigEndian.Uint64(hash[8:16])
+	return
+}
+
+func syncBucket(prefix uint32, bits uint32) uint32 {
+	if bits == 0 {
+		return 0
+	}
+	return prefix >> (32 - bits)
+}
+
+// syncNamespaceIds calls f with the id of every statement in the namespace
+func (node *Node) syncNamespaceIds(ctx context.Context, ns string, f func(id string) error) error {
+	q, err := mcq.ParseQuery(fmt.Sprintf("SELECT id FROM %s", ns))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ch, err := node.db.QueryStream(ctx, q)
+	if err != nil {
+		return err
+	}
+
+	for val := range ch {
+		switch val := val.(type) {
+		case string:
+			err = f(val)
+			if err != nil {
+				return err
+			}
+
+		case StreamError:
+			return val
+
+		default:
+			return BadResult
+		}
+	}
+
+	return ctx.Err()
+}
+
+// syncIndex holds the hashes of the ids of a namespace, sorted by prefix.
+// It is computed once per sync session, so that the summaries and the ids
+// of buckets are served without rescanning the namespace.
+type syncIndex []syncEntry
+
+type syncEntry struct {
+	prefix uint32
+	digest uint64
+	id     string
+}
+
+func (idx syncIndex) Len() int           { return len(idx) }
+func (idx syncIndex) Less(i, j int) bool { return idx[i].prefix < idx[j].prefix }
+func (idx syncIndex) Swap(i, j int)      { idx[i], idx[j] = idx[j], idx[i] }
+
+func (node *Node) syncNamespaceIndex(ctx context.Context, ns string) (syncIndex, error) {
+	idx := make(syncIndex, 0)
+	err := node.syncNamespaceIds(ctx, ns, func(id string) error {
+		prefix, digest := syncHash(id)
+		idx = append(idx, syncEntry{prefix, digest, id})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Sort(idx)
+	return idx, nil
+}
+
+// bucket returns the entries in a bucket
+func (idx syncIndex) bucket(bits uint32, bucket uint32) syncIndex {
+	lo := uint64(bucket) << (32 - bits)
+	hi := lo + uint64(1)<<(32-bits)
+	start := sort.Search(len(idx), func(x int) bool { return uint64(idx[x].prefix) >= lo })
+	end := sort.Search(len(idx), func(x int) bool { return uint64(idx[x].prefix) >= hi })
+	return idx[start:end]
+}
+
+// summary summarizes the sub-buckets of buckets, in order
+func (idx syncIndex) summary(bits uint32, buckets []uint32) ([]uint64, []uint32) {
+	digests := make([]uint64, len(buckets)<<SyncFanoutBits)
+	counts := make([]uint32, len(buckets)<<SyncFanoutBits)
+	mask := uint32(1<<SyncFanoutBits - 1)
+	for x, bucket := range buckets {
+		for _, entry := range idx.bucket(bits, bucket) {
+			sub := x<<SyncFanoutBits | int(syncBucket(entry.prefix, bits+SyncFanoutBits)&mask)
+			digests[sub] ^= entry.digest
+			counts[sub]++
+		}
+	}
+
+	return digests, counts
+}
+
+// ids calls f with the ids in buckets
+func (idx syncIndex) ids(bits uint32, buckets []uint32, f func(id string) error) error {
+	for _, bucket := range buckets {
+		for _, entry := range idx.bucket(bits, bucket) {
+			err := f(entry.id)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+type syncBuckets []uint32
+
+func (b syncBuckets) Len() int           { return len(b) }
+func (b syncBuckets) Less(i, j int) bool { return b[i] < b[j] }
+func (b syncBuckets) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+
+func validSyncBuckets(req *pb.SyncBucketsRequest) bool {
+	switch {
+	case len(req.Buckets) > SyncBatch:
+		return false
+	case req.Ids && req.Bits > SyncMaxBits:
+		return false
+	case !req.Ids && req.Bits+SyncFanoutBits > SyncMaxBits:
+		return false
+	}
+
+	// buckets must be distinct and in order
+	for x, bucket := range req.Buckets {
+		if uint64(bucket) >= uint64(1)<<req.Bits {
+			return false
+		}
+		if x > 0 && bucket <= req.Buckets[x-1] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (node *Node) syncHandler(s p2p_net.Stream) {
+	defer s.Close()
+
+	pid := mc.LogStreamHandler(s)
+
+	ctx, cancel := context.WithCancel(node.netCtx)
+	defer cancel()
+
+	var req pb.SyncRequest
+	var breq pb.SyncBucketsRequest
+	var res pb.SyncResult
+
+	r := ggio.NewDelimitedReader(s, mc.MaxMessageSize)
+	w := ggio.NewDelimitedWriter(s)
+
+	writeError := func(err error) {
+		res.Result = &pb.SyncResult_Error{&pb.StreamError{err.Error()}}
+		w.WriteMsg(&res)
+	}
+
+	writeIds := func(ids []string) error {
+		res.Result = &pb.SyncResult_Ids{&pb.SyncIds{ids}}
+		return w.WriteMsg(&res)
+	}
+
+	writeEnd := func() error {
+		res.Result = &pb.SyncResult_End{&pb.StreamEnd{}}
+		return w.WriteMsg(&res)
+	}
+
+	err := r.ReadMsg(&req)
+	if err != nil {
+		return
+	}
+
+	ns := req.Namespace
+	log.Printf("node/sync: sync from %s: %s", pid.Pretty(), ns)
+
+	if !nsrx.Match([]byte(ns)) {
+		writeError(BadNamespace)
+		return
+	}
+
+	if !node.rauth.authorizeRead(pid, ns) {
+		log.Printf("node/sync: rejected sync from %s; not authorized", pid.Pretty())
+		writeError(ReadDenied)
+		return
+	}
+
+	if !node.syncBegin(pid) {
+		log.Printf("node/sync: rejected sync from %s; sync in progress", pid.Pretty())
+		writeError(SyncBusy)
+		return
+	}
+	defer node.syncEnd(pid)
+
+	var idx syncIndex
+	for reqs := 0; ; reqs++ {
+		breq.Reset()
+		err = r.ReadMsg(&breq)
+		if err != nil {
+			return
+		}
+
+		if reqs == SyncMaxRequests {
+			log.Printf("node/sync: sync from %s exceeded the request limit", pid.Pretty())
+			writeError(SyncRequestLimit)
+			return
+		}
+
+		if !validSyncBuckets(&breq) {
+			writeError(BadQuery)
+			return
+		}
+
+		if idx == nil {
+			idx, err = node.syncNamespaceIndex(ctx, ns)
+			if err != nil {
+				writeError(err)
+				return
+			}
+		}
+
+		if !breq.Ids {
+			digests, counts := idx.summary(breq.Bits, breq.Buckets)
+			res.Result = &pb.SyncResult_Summary{&pb.SyncSummary{digests, counts}}
+			err = w.WriteMsg(&res)
+			if err != nil {
+				return
+			}
+			continue
+		}
+
+		ids := make([]string, 0, SyncBatch)
+		err = idx.ids(breq.Bits, breq.Buckets, func(id string) error {
+			ids = append(ids, id)
+			if len(ids) < SyncBatch {
+				return nil
+			}
+
+			err := writeIds(ids)
+			ids = ids[:0]
+			return err
+		})
+		if err != nil {
+			writeError(err)
+			return
+		}
+
+		if len(ids) > 0 {
+			err = writeIds(ids)
+			if err != nil {
+				return
+			}
+		}
+
+		err = writeEnd()
+		if err != nil {
+			return
+		}
+	}
+}
+
+// syncBegin registers a sync session from a peer, unless one is in progress
+func (node *Node) syncBegin(pid p2p_peer.ID) bool {
+	node.syncmx.Lock()
+	defer node.syncmx.Unlock()
+
+	if node.syncs == nil {
+		node.syncs = make(map[p2p_peer.ID]bool)
+	}
+
+	if node.syncs[pid] {
+		return false
+	}
+
+	node.syncs[pid] = true
+	return true
+}
+
+func (node *Node) syncEnd(pid p2p_peer.ID) {
+	node.syncmx.Lock()
+	delete(node.syncs, pid)
+	node.syncmx.Unlock()
+}
+
+// doSync reconciles a namespace with a peer
+func (node *Node) doSync(ctx context.Context, pid p2p_peer.ID, ns string) (stats SyncStats, err error) {
+	if !nsrx.Match([]byte(ns)) {
+		return stats, BadNamespace
+	}
+
+	pull, push, err := node.syncDiff(ctx, pid, ns)
+	if err != nil {
+		return stats, err
+	}
+
+	for len(pull) > 0 {
+		batch := pull
+		if len(batch) > SyncBatch {
+			batch = batch[:SyncBatch]
+		}
+		pull = pull[len(batch):]
+
+		q := fmt.Sprintf("SELECT * FROM %s WHERE id IN (%s)", ns, strings.Join(batch, ", "))
+		count, ocount, rejects, err := node.doMerge(ctx, pid, q)
+		stats.Merged += count
+		stats.MergedObjects += ocount
+		stats.Rejected += rejects
+		if err != nil {
+			return stats, err
+		}
+	}
+
+	for len(push) > 0 {
+		batch := push
+		if len(batch) > SyncBatch {
+			batch = batch[:SyncBatch]
+		}
+		push = push[len(batch):]
+
+		q, err := mcq.ParseQuery(fmt.Sprintf("SELECT * FROM %s WHERE id IN (%s)", ns, strings.Join(batch, ", ")))
+		if err != nil {
+			return stats, err
+		}
+
+		count, ocount, rejects, err := node.doPush(ctx, pid, q)
+		if count > 0 {
+			stats.Pushed += count
+		}
+		if ocount > 0 {
+			stats.PushedObjects += ocount
+		}
+		stats.PushRejected += rejects
+		if err != nil {
+			return stats, err
+		}
+	}
+
+	return stats, nil
+}
+
+// syncDiff computes the statements to merge from and push to a peer
+func (node *Node) syncDiff(ctx context.Context, pid p2p_peer.ID, ns string) (pull []string, push []string, err error) {
+	s, err := node.doConnect(ctx, pid, "/mediachain/node/sync")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer s.Close()
+
+	r := ggio.NewDelimitedReader(s, mc.MaxMessageSize)
+	w := ggio.NewDelimitedWriter(s)
+
+	err = w.WriteMsg(&pb.SyncRequest{Namespace: ns})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	idx, err := node.syncNamespaceIndex(ctx, ns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mask := uint32(1<<SyncFanoutBits - 1)
+	remote := make(map[string]bool)
+	push = make([]string, 0)
+
+	// descend the bucket tree from the root, one level at a time
+	bits := uint32(0)
+	buckets := []uint32{0}
+	for len(buckets) > 0 {
+		next := make([]uint32, 0)
+		fetch := make(map[uint32]bool)
+		leaf := bits+2*SyncFanoutBits > SyncMaxBits
+
+		for len(buckets) > 0 {
+			batch := buckets
+			if len(batch) > SyncBatch {
+				batch = batch[:SyncBatch]
+			}
+			buckets = buckets[len(batch):]
+
+			rdigests, rcounts, err := syncRemoteSummary(r, w, bits, batch)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			digests, counts := idx.summary(bits, batch)
+
+			for x, digest := range digests {
+				if digest == rdigests[x] && counts[x] == rcounts[x] {
+					continue
+				}
+
+				sub := batch[x>>SyncFanoutBits]<<SyncFanoutBits | uint32(x)&mask
+				if leaf || (counts[x] <= SyncBucketSize && rcounts[x] <= SyncBucketSize) {
+					fetch[sub] = true
+				} else {
+					next = append(next, sub)
+				}
+			}
+		}
+
+		bits += SyncFanoutBits
+		buckets = next
+
+		if len(fetch) == 0 {
+			continue
+		}
+
+		fetchList := make([]uint32, 0, len(fetch))
+		for bucket, _ := range fetch {
+			fetchList = append(fetchList, bucket)
+		}
+		sort.Sort(syncBuckets(fetchList))
+
+		err = syncRemoteIds(r, w, bits, fetchList, remote)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		err = idx.ids(bits, fetchList, func(id string) error {
+			if remote[id] {
+				delete(remote, id)
+			} else {
+				push = append(push, id)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	pull = make([]string, 0, len(remote))
+	for id, _ := range remote {
+		pull = append(pull, id)
+	}
+
+	return pull, push, nil
+}
+
+// syncRemoteSummary retrieves the remote summary of the sub-buckets of buckets
+func syncRemoteSummary(r ggio.Reader, w ggio.Writer, bits uint32, buckets []uint32) ([]uint64, []uint32, error) {
+	err := w.WriteMsg(&pb.SyncBucketsRequest{Buckets: buckets, Bits: bits})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var res pb.SyncResult
+	err = r.ReadMsg(&res)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch res := res.Result.(type) {
+	case *pb.SyncResult_Summary:
+		count := len(buckets) << SyncFanoutBits
+		if len(res.Summary.Digests) != count || len(res.Summary.Counts) != count {
+			return nil, nil, BadResponse
+		}
+		return res.Summary.Digests, res.Summary.Counts, nil
+
+	case *pb.SyncResult_Error:
+		return nil, nil, StreamError{res.Error.Error}
+
+	default:
+		return nil, nil, BadResponse
+	}
+}
+
+// syncRemoteIds retrieves the remote ids in buckets into remote
+func syncRemoteIds(r ggio.Reader, w ggio.Writer, bits uint32, buckets []uint32, remote map[string]bool) error {
+	bucketList := buckets
+	for len(bucketList) > 0 {
+		batch := bucketList
+		if len(batch) > SyncBatch {
+			batch = batch[:SyncBatch]
+		}
+		bucketList = bucketList[len(batch):]
+
+		err := w.WriteMsg(&pb.SyncBucketsRequest{Buckets: batch, Bits: bits, Ids: true})
+		if err != nil {
+			return err
+		}
+
+		var res pb.SyncResult
+	loop:
+		for {
+			res.Reset()
+			err = r.ReadMsg(&res)
+			if err != nil {
+				return err
+			}
+
+			switch res := res.Result.(type) {
+			case *pb.SyncResult_Ids:
+				for _, id := range res.Ids.Ids {
+					// ids are spliced into queries
+					if !idrx.Match([]byte(id)) {
+						return BadResponse
+					}
+					remote[id] = true
+				}
+
+			case *pb.SyncResult_End:
+				break loop
+
+			case *pb.SyncResult_Error:
+				return StreamError{res.Error.Error}
+
+			default:
+				return BadResponse
+			}
+		}
+	}
+
+	return nil
+}
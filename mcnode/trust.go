@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	p2p_crypto "github.com/libp2p/go-libp2p-crypto"
+	mc "github.com/mediachain/concat/mc"
+	pb "github.com/mediachain/concat/proto"
+	"log"
+	"sync"
+)
+
+// Publisher trust restricts the statements accepted by merges, pushes and
+// imports to trusted publishers: the publishers in the local trust list,
+// and the publishers bound to trusted entities by verified node manifests.
+// Manifests for the trusted entities are retrieved from the directory and
+// verified with the entity key when the trust policy is set, when the node
+// goes online, and with /trust/refresh.
+// The node's own publisher is always trusted; with an empty trust policy
+// all publishers are trusted.
+// Statements by untrusted publishers are skipped and counted per publisher,
+// once their signature has been verified; the counts track at most
+// TrustMaxRejects publishers, and the rejections of any further publishers
+// are counted together under "*".
+type TrustPolicy struct {
+	Publishers []string `json:"publishers,omitempty"`
+	Entities   []string `json:"entities,omitempty"`
+}
+
+const TrustMaxRejects = 1024
+
+type PublisherTrust struct {
+	mx      sync.Mutex
+	policy  TrustPolicy
+	trusted map[string]bool
+	bound   map[string]string
+	rejects map[string]int
+}
+
+type TrustReport struct {
+	Publishers []string          `json:"publishers"`
+	Bound      map[string]string `json:"bound"`
+	Rejected   map[string]int    `json:"rejected"`
+}
+
+func (p *TrustPolicy) validate() error {
+	for _, pub := range p.Publishers {
+		_, err := mc.PublisherKey(pub)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, entity := range p.Entities {
+		err := mc.CheckEntityId(entity)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (trust *PublisherTrust) getPolicy() TrustPolicy {
+	trust.mx.Lock()
+	defer trust.mx.Unlock()
+	return trust.policy
+}
+
+func (trust *PublisherTrust) setPolicy(policy TrustPolicy) {
+	trust.mx.Lock()
+	defer trust.mx.Unlock()
+
+	trust.policy = policy
+	trust.trusted = make(map[string]bool)
+	for _, pub := range policy.Publishers {
+		trust.trusted[pub] = true
+	}
+
+	// drop bindings to entities no longer trusted
+	entities := make(map[string]bool)
+	for _, entity := range policy.Entities {
+		entities[entity] = true
+	}
+
+	for pub, entity := range trust.bound {
+		if !entities[entity] {
+			delete(trust.bound, pub)
+		}
+	}
+}
+
+func (trust *PublisherTrust) setBound(bound map[string]string) {
+	trust.mx.Lock()
+	defer trust.mx.Unlock()
+
+	// the policy may have changed while the manifests were retrieved
+	entities := make(map[string]bool)
+	for _, entity := range trust.policy.Entities {
+		entities[entity] = true
+	}
+
+	trust.bound = make(map[string]string)
+	for pub, entity := range bound {
+		if entities[entity] {
+			trust.bound[pub] = entity
+		}
+	}
+}
+
+// accept checks whether a publisher is trusted; rejections are counted
+func (trust *PublisherTrust) accept(self, pub string) bool {
+	trust.mx.Lock()
+	defer trust.mx.Unlock()
+
+	if trust.trusts(self, pub) {
+		return true
+	}
+
+	if trust.rejects == nil {
+		trust.rejects = make(map[string]int)
+	}
+
+	_, ok := trust.rejects[pub]
+	if !ok && len(trust.rejects) >= TrustMaxRejects {
+		pub = "*"
+	}
+	trust.rejects[pub]++
+
+	return false
+}
+
+// check is like accept, but doesn't count rejections
+func (trust *PublisherTrust) check(self, pub string) bool {
+	trust.mx.Lock()
+	defer trust.mx.Unlock()
+	return trust.trusts(self, pub)
+}
+
+// trusts is called with the lock held
+func (trust *PublisherTrust) trusts(self, pub string) bool {
+	if len(trust.policy.Publishers) == 0 && len(trust.policy.Entities) == 0 {
+		return true
+	}
+
+	_, bound := trust.bound[pub]
+	return pub == self || trust.trusted[pub] || bound
+}
+
+func (trust *PublisherTrust) report() TrustReport {
+	trust.mx.Lock()
+	defer trust.mx.Unlock()
+
+	pubs := make([]string, len(trust.policy.Publishers))
+	copy(pubs, trust.policy.Publishers)
+
+	bound := make(map[string]string)
+	for pub, entity := range trust.bound {
+		bound[pub] = entity
+	}
+
+	rejects := make(map[string]int)
+	for pub, count := range trust.rejects {
+		rejects[pub] = count
+	}
+
+	return TrustReport{pubs, bound, rejects}
+}
+
+func (node *Node) trustPublisher(pub string) bool {
+	return node.trust.accept(node.publisher.ID58, pub)
+}
+
+// checkTrustPublisher checks the trust of a publisher for merges that are
+// not performed, without counting rejections
+func (node *Node) checkTrustPublisher(pub string) bool {
+	return node.trust.check(node.publisher.ID58, pub)
+}
+
+// doRefreshTrust retrieves and verifies the manifests of trusted entities,
+// and binds their publishers; returns the number of bound publishers.
+func (node *Node) doRefreshTrust(ctx context.Context) (int, error) {
+	policy := node.trust.getPolicy()
+
+	bound := make(map[string]string)
+	keys := make(map[string]p2p_crypto.PubKey)
+	for _, entity := range policy.Entities {
+		mfs, err := node.doDirListMF(ctx, entity)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, mf := range mfs {
+			pub, ok := verifyEntityManifest(mf, entity, keys)
+			if ok {
+				bound[pub] = entity
+			}
+		}
+	}
+
+	node.trust.setBound(bound)
+	return len(bound), nil
+}
+
+// verifyEntityManifest verifies a node manifest for an entity and returns
+// the publisher it binds
+func verifyEntityManifest(mf *pb.Manifest, entity string, keys map[string]p2p_crypto.PubKey) (string, bool) {
+	nmf := mf.GetBody().GetNode()
+	if mf.Entity != entity || nmf == nil {
+		return "", false
+	}
+
+	kid := mf.Entity + ":" + mf.KeyId
+	pubk, ok := keys[kid]
+	if !ok {
+		var err error
+		pubk, err = mc.LookupEntityKey(mf.Entity, mf.KeyId)
+		if err != nil {
+			log.Printf("Error looking up entity key %s: %s", kid, err.Error())
+			return "", false
+		}
+		keys[kid] = pubk
+	}
+
+	ok, err := mc.VerifyManifest(mf, pubk)
+	switch {
+	case err != nil:
+		log.Printf("Error verifying manifest for %s: %s", entity, err.Error())
+		return "", false
+
+	case !ok:
+		log.Printf("Error verifying manifest for %s: signature verification failed", entity)
+		return "", false
+
+	default:
+		return nmf.Publisher, true
+	}
+}
+
+// refreshTrust refreshes the trusted entity bindings in the background
+// when the node is online; failures are logged.
+func (node *Node) refreshTrust() {
+	node.mx.Lock()
+	defer node.mx.Unlock()
+
+	if node.status != StatusOffline {
+		node.startRefreshTrust()
+	}
+}
+
+// startRefreshTrust is called with the node lock held when the node goes
+// online
+func (node *Node) startRefreshTrust() {
+	if len(node.trust.getPolicy().Entities) == 0 {
+		return
+	}
+
+	ctx := node.netCtx
+	go func() {
+		count, err := node.doRefreshTrust(ctx)
+		if err != nil {
+			log.Printf("Error refreshing trusted entities: %s", err.Error())
+			return
+		}
+		log.Printf("Trusted entities bind %d publishers", count)
+	}()
+}
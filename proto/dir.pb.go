@@ -36,6 +36,7 @@ It has these top-level messages:
 	Pong
 	QueryRequest
 	QueryResult
+	QueryCursor
 	QueryResultValue
 	SimpleValue
 	CompoundValue
@@ -49,12 +50,21 @@ It has these top-level messages:
 	PushReject
 	PushValue
 	PushEnd
+	SubscribeRequest
+	GossipAnnounce
+	SyncRequest
+	SyncBucketsRequest
+	SyncResult
+	SyncSummary
+	SyncIds
 	Statement
 	StatementBody
 	SimpleStatement
 	CompoundStatement
 	EnvelopeStatement
 	ArchiveStatement
+	RetractStatement
+	SupersedeStatement
 */
 package proto
 
@@ -97,6 +97,8 @@ func (*Pong) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{7}
 // /mediachain/node/query
 type QueryRequest struct {
 	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// request a cursor trailer for queries ordered by counter
+	Cursor bool `protobuf:"varint,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
 }
 
 func (m *QueryRequest) Reset()                    { *m = QueryRequest{} }
@@ -109,6 +111,7 @@ type QueryResult struct {
 	//	*QueryResult_Value
 	//	*QueryResult_End
 	//	*QueryResult_Error
+	//	*QueryResult_Cursor
 	Result isQueryResult_Result `protobuf_oneof:"result"`
 }
 
@@ -130,10 +133,14 @@ type QueryResult_End struct {
 type QueryResult_Error struct {
 	Error *StreamError `protobuf:"bytes,3,opt,name=error,oneof"`
 }
+type QueryResult_Cursor struct {
+	Cursor *QueryCursor `protobuf:"bytes,4,opt,name=cursor,oneof"`
+}
 
-func (*QueryResult_Value) isQueryResult_Result() {}
-func (*QueryResult_End) isQueryResult_Result()   {}
-func (*QueryResult_Error) isQueryResult_Result() {}
+func (*QueryResult_Value) isQueryResult_Result()  {}
+func (*QueryResult_End) isQueryResult_Result()    {}
+func (*QueryResult_Error) isQueryResult_Result()  {}
+func (*QueryResult_Cursor) isQueryResult_Result() {}
 
 func (m *QueryResult) GetResult() isQueryResult_Result {
 	if m != nil {
@@ -163,12 +170,20 @@ func (m *QueryResult) GetError() *StreamError {
 	return nil
 }
 
+func (m *QueryResult) GetCursor() *QueryCursor {
+	if x, ok := m.GetResult().(*QueryResult_Cursor); ok {
+		return x.Cursor
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*QueryResult) XXX_OneofFuncs() (func(msg proto1.Message, b *proto1.Buffer) error, func(msg proto1.Message, tag, wire int, b *proto1.Buffer) (bool, error), func(msg proto1.Message) (n int), []interface{}) {
 	return _QueryResult_OneofMarshaler, _QueryResult_OneofUnmarshaler, _QueryResult_OneofSizer, []interface{}{
 		(*QueryResult_Value)(nil),
 		(*QueryResult_End)(nil),
 		(*QueryResult_Error)(nil),
+		(*QueryResult_Cursor)(nil),
 	}
 }
 
@@ -191,6 +206,11 @@ func _QueryResult_OneofMarshaler(msg proto1.Message, b *proto1.Buffer) error {
 		if err := b.EncodeMessage(x.Error); err != nil {
 			return err
 		}
+	case *QueryResult_Cursor:
+		_ = b.EncodeVarint(4<<3 | proto1.WireBytes)
+		if err := b.EncodeMessage(x.Cursor); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("QueryResult.Result has unexpected type %T", x)
@@ -225,6 +245,14 @@ func _QueryResult_OneofUnmarshaler(msg proto1.Message, tag, wire int, b *proto1.
 		err := b.DecodeMessage(msg)
 		m.Result = &QueryResult_Error{msg}
 		return true, err
+	case 4: // result.cursor
+		if wire != proto1.WireBytes {
+			return true, proto1.ErrInternalBadWireType
+		}
+		msg := new(QueryCursor)
+		err := b.DecodeMessage(msg)
+		m.Result = &QueryResult_Cursor{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -249,6 +277,11 @@ func _QueryResult_OneofSizer(msg proto1.Message) (n int) {
 		n += proto1.SizeVarint(3<<3 | proto1.WireBytes)
 		n += proto1.SizeVarint(uint64(s))
 		n += s
+	case *QueryResult_Cursor:
+		s := proto1.Size(x.Cursor)
+		n += proto1.SizeVarint(4<<3 | proto1.WireBytes)
+		n += proto1.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -256,6 +289,16 @@ func _QueryResult_OneofSizer(msg proto1.Message) (n int) {
 	return n
 }
 
+// counter of the last statement in the result set; sent before StreamEnd
+type QueryCursor struct {
+	Counter int64 `protobuf:"varint,1,opt,name=counter,proto3" json:"counter,omitempty"`
+}
+
+func (m *QueryCursor) Reset()                    { *m = QueryCursor{} }
+func (m *QueryCursor) String() string            { return proto1.CompactTextString(m) }
+func (*QueryCursor) ProtoMessage()               {}
+func (*QueryCursor) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{10} }
+
 type QueryResultValue struct {
 	// Types that are valid to be assigned to Value:
 	//	*QueryResultValue_Simple
@@ -266,7 +309,7 @@ type QueryResultValue struct {
 func (m *QueryResultValue) Reset()                    { *m = QueryResultValue{} }
 func (m *QueryResultValue) String() string            { return proto1.CompactTextString(m) }
 func (*QueryResultValue) ProtoMessage()               {}
-func (*QueryResultValue) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{10} }
+func (*QueryResultValue) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{11} }
 
 type isQueryResultValue_Value interface {
 	isQueryResultValue_Value()
@@ -389,7 +432,7 @@ type SimpleValue struct {
 func (m *SimpleValue) Reset()                    { *m = SimpleValue{} }
 func (m *SimpleValue) String() string            { return proto1.CompactTextString(m) }
 func (*SimpleValue) ProtoMessage()               {}
-func (*SimpleValue) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{11} }
+func (*SimpleValue) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{12} }
 
 type isSimpleValue_Value interface {
 	isSimpleValue_Value()
@@ -558,7 +601,7 @@ type CompoundValue struct {
 func (m *CompoundValue) Reset()                    { *m = CompoundValue{} }
 func (m *CompoundValue) String() string            { return proto1.CompactTextString(m) }
 func (*CompoundValue) ProtoMessage()               {}
-func (*CompoundValue) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{12} }
+func (*CompoundValue) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{13} }
 
 func (m *CompoundValue) GetBody() []*KeyValuePair {
 	if m != nil {
@@ -575,7 +618,7 @@ type KeyValuePair struct {
 func (m *KeyValuePair) Reset()                    { *m = KeyValuePair{} }
 func (m *KeyValuePair) String() string            { return proto1.CompactTextString(m) }
 func (*KeyValuePair) ProtoMessage()               {}
-func (*KeyValuePair) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{13} }
+func (*KeyValuePair) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{14} }
 
 func (m *KeyValuePair) GetValue() *SimpleValue {
 	if m != nil {
@@ -592,7 +635,7 @@ type DataRequest struct {
 func (m *DataRequest) Reset()                    { *m = DataRequest{} }
 func (m *DataRequest) String() string            { return proto1.CompactTextString(m) }
 func (*DataRequest) ProtoMessage()               {}
-func (*DataRequest) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{14} }
+func (*DataRequest) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{15} }
 
 type DataResult struct {
 	// Types that are valid to be assigned to Result:
@@ -605,7 +648,7 @@ type DataResult struct {
 func (m *DataResult) Reset()                    { *m = DataResult{} }
 func (m *DataResult) String() string            { return proto1.CompactTextString(m) }
 func (*DataResult) ProtoMessage()               {}
-func (*DataResult) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{15} }
+func (*DataResult) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{16} }
 
 type isDataResult_Result interface {
 	isDataResult_Result()
@@ -754,7 +797,7 @@ type DataObject struct {
 func (m *DataObject) Reset()                    { *m = DataObject{} }
 func (m *DataObject) String() string            { return proto1.CompactTextString(m) }
 func (*DataObject) ProtoMessage()               {}
-func (*DataObject) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{16} }
+func (*DataObject) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{17} }
 
 // /mediachain/node/push
 type PushRequest struct {
@@ -764,7 +807,7 @@ type PushRequest struct {
 func (m *PushRequest) Reset()                    { *m = PushRequest{} }
 func (m *PushRequest) String() string            { return proto1.CompactTextString(m) }
 func (*PushRequest) ProtoMessage()               {}
-func (*PushRequest) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{17} }
+func (*PushRequest) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{18} }
 
 type PushResponse struct {
 	// Types that are valid to be assigned to Body:
@@ -776,7 +819,7 @@ type PushResponse struct {
 func (m *PushResponse) Reset()                    { *m = PushResponse{} }
 func (m *PushResponse) String() string            { return proto1.CompactTextString(m) }
 func (*PushResponse) ProtoMessage()               {}
-func (*PushResponse) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{18} }
+func (*PushResponse) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{19} }
 
 type isPushResponse_Body interface {
 	isPushResponse_Body()
@@ -893,7 +936,7 @@ type PushAccept struct {
 func (m *PushAccept) Reset()                    { *m = PushAccept{} }
 func (m *PushAccept) String() string            { return proto1.CompactTextString(m) }
 func (*PushAccept) ProtoMessage()               {}
-func (*PushAccept) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{19} }
+func (*PushAccept) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{20} }
 
 type PushReject struct {
 	Error string `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
@@ -902,7 +945,7 @@ type PushReject struct {
 func (m *PushReject) Reset()                    { *m = PushReject{} }
 func (m *PushReject) String() string            { return proto1.CompactTextString(m) }
 func (*PushReject) ProtoMessage()               {}
-func (*PushReject) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{20} }
+func (*PushReject) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{21} }
 
 type PushValue struct {
 	// Types that are valid to be assigned to Value:
@@ -914,7 +957,7 @@ type PushValue struct {
 func (m *PushValue) Reset()                    { *m = PushValue{} }
 func (m *PushValue) String() string            { return proto1.CompactTextString(m) }
 func (*PushValue) ProtoMessage()               {}
-func (*PushValue) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{21} }
+func (*PushValue) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{22} }
 
 type isPushValue_Value interface {
 	isPushValue_Value()
@@ -1029,12 +1072,274 @@ type PushEnd struct {
 	Statements int64  `protobuf:"varint,1,opt,name=statements,proto3" json:"statements,omitempty"`
 	Objects    int64  `protobuf:"varint,2,opt,name=objects,proto3" json:"objects,omitempty"`
 	Error      string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	// statements rejected by publisher trust
+	Rejected int64 `protobuf:"varint,4,opt,name=rejected,proto3" json:"rejected,omitempty"`
 }
 
 func (m *PushEnd) Reset()                    { *m = PushEnd{} }
 func (m *PushEnd) String() string            { return proto1.CompactTextString(m) }
 func (*PushEnd) ProtoMessage()               {}
-func (*PushEnd) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{22} }
+func (*PushEnd) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{23} }
+
+// /mediachain/node/subscribe
+// The subscriber sends a single SubscribeRequest and receives QueryResult
+// messages: batches of statements, each followed by a QueryCursor with the
+// counter of its last statement.
+type SubscribeRequest struct {
+	// MCQL SELECT * query filtering the statements of interest
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// resume after the statement with this counter
+	Counter int64 `protobuf:"varint,2,opt,name=counter,proto3" json:"counter,omitempty"`
+}
+
+func (m *SubscribeRequest) Reset()                    { *m = SubscribeRequest{} }
+func (m *SubscribeRequest) String() string            { return proto1.CompactTextString(m) }
+func (*SubscribeRequest) ProtoMessage()               {}
+func (*SubscribeRequest) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{24} }
+
+// pubsub topic /mediachain/gossip/{namespace}
+// announces statements published in the namespace
+type GossipAnnounce struct {
+	Ids []string `protobuf:"bytes,1,rep,name=ids" json:"ids,omitempty"`
+}
+
+func (m *GossipAnnounce) Reset()                    { *m = GossipAnnounce{} }
+func (m *GossipAnnounce) String() string            { return proto1.CompactTextString(m) }
+func (*GossipAnnounce) ProtoMessage()               {}
+func (*GossipAnnounce) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{25} }
+
+// /mediachain/node/sync
+// Statement ids are assigned to buckets by the prefix of their hash; each
+// bucket of a prefix of n bits is split into 16 sub-buckets of n + 4 bits.
+// The initiator sends a SyncRequest followed by SyncBucketsRequests, and
+// closes the stream when done. A request for the summary of some buckets
+// receives the digests and counts of their sub-buckets in a SyncSummary;
+// a request for the ids in some buckets receives the statement ids in them,
+// terminated by StreamEnd.
+type SyncRequest struct {
+	Namespace string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+}
+
+func (m *SyncRequest) Reset()                    { *m = SyncRequest{} }
+func (m *SyncRequest) String() string            { return proto1.CompactTextString(m) }
+func (*SyncRequest) ProtoMessage()               {}
+func (*SyncRequest) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{26} }
+
+type SyncBucketsRequest struct {
+	Buckets []uint32 `protobuf:"varint,1,rep,packed,name=buckets" json:"buckets,omitempty"`
+	// the number of bits in the bucket prefixes
+	Bits uint32 `protobuf:"varint,2,opt,name=bits,proto3" json:"bits,omitempty"`
+	// request the ids in the buckets instead of the summary
+	Ids bool `protobuf:"varint,3,opt,name=ids,proto3" json:"ids,omitempty"`
+}
+
+func (m *SyncBucketsRequest) Reset()                    { *m = SyncBucketsRequest{} }
+func (m *SyncBucketsRequest) String() string            { return proto1.CompactTextString(m) }
+func (*SyncBucketsRequest) ProtoMessage()               {}
+func (*SyncBucketsRequest) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{27} }
+
+type SyncResult struct {
+	// Types that are valid to be assigned to Result:
+	//	*SyncResult_Summary
+	//	*SyncResult_Ids
+	//	*SyncResult_End
+	//	*SyncResult_Error
+	Result isSyncResult_Result `protobuf_oneof:"result"`
+}
+
+func (m *SyncResult) Reset()                    { *m = SyncResult{} }
+func (m *SyncResult) String() string            { return proto1.CompactTextString(m) }
+func (*SyncResult) ProtoMessage()               {}
+func (*SyncResult) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{28} }
+
+type isSyncResult_Result interface {
+	isSyncResult_Result()
+}
+
+type SyncResult_Summary struct {
+	Summary *SyncSummary `protobuf:"bytes,1,opt,name=summary,oneof"`
+}
+type SyncResult_Ids struct {
+	Ids *SyncIds `protobuf:"bytes,2,opt,name=ids,oneof"`
+}
+type SyncResult_End struct {
+	End *StreamEnd `protobuf:"bytes,3,opt,name=end,oneof"`
+}
+type SyncResult_Error struct {
+	Error *StreamError `protobuf:"bytes,4,opt,name=error,oneof"`
+}
+
+func (*SyncResult_Summary) isSyncResult_Result() {}
+func (*SyncResult_Ids) isSyncResult_Result()     {}
+func (*SyncResult_End) isSyncResult_Result()     {}
+func (*SyncResult_Error) isSyncResult_Result()   {}
+
+func (m *SyncResult) GetResult() isSyncResult_Result {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (m *SyncResult) GetSummary() *SyncSummary {
+	if x, ok := m.GetResult().(*SyncResult_Summary); ok {
+		return x.Summary
+	}
+	return nil
+}
+
+func (m *SyncResult) GetIds() *SyncIds {
+	if x, ok := m.GetResult().(*SyncResult_Ids); ok {
+		return x.Ids
+	}
+	return nil
+}
+
+func (m *SyncResult) GetEnd() *StreamEnd {
+	if x, ok := m.GetResult().(*SyncResult_End); ok {
+		return x.End
+	}
+	return nil
+}
+
+func (m *SyncResult) GetError() *StreamError {
+	if x, ok := m.GetResult().(*SyncResult_Error); ok {
+		return x.Error
+	}
+	return nil
+}
+
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*SyncResult) XXX_OneofFuncs() (func(msg proto1.Message, b *proto1.Buffer) error, func(msg proto1.Message, tag, wire int, b *proto1.Buffer) (bool, error), func(msg proto1.Message) (n int), []interface{}) {
+	return _SyncResult_OneofMarshaler, _SyncResult_OneofUnmarshaler, _SyncResult_OneofSizer, []interface{}{
+		(*SyncResult_Summary)(nil),
+		(*SyncResult_Ids)(nil),
+		(*SyncResult_End)(nil),
+		(*SyncResult_Error)(nil),
+	}
+}
+
+func _SyncResult_OneofMarshaler(msg proto1.Message, b *proto1.Buffer) error {
+	m := msg.(*SyncResult)
+	// result
+	switch x := m.Result.(type) {
+	case *SyncResult_Summary:
+		_ = b.EncodeVarint(1<<3 | proto1.WireBytes)
+		if err := b.EncodeMessage(x.Summary); err != nil {
+			return err
+		}
+	case *SyncResult_Ids:
+		_ = b.EncodeVarint(2<<3 | proto1.WireBytes)
+		if err := b.EncodeMessage(x.Ids); err != nil {
+			return err
+		}
+	case *SyncResult_End:
+		_ = b.EncodeVarint(3<<3 | proto1.WireBytes)
+		if err := b.EncodeMessage(x.End); err != nil {
+			return err
+		}
+	case *SyncResult_Error:
+		_ = b.EncodeVarint(4<<3 | proto1.WireBytes)
+		if err := b.EncodeMessage(x.Error); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		return fmt.Errorf("SyncResult.Result has unexpected type %T", x)
+	}
+	return nil
+}
+
+func _SyncResult_OneofUnmarshaler(msg proto1.Message, tag, wire int, b *proto1.Buffer) (bool, error) {
+	m := msg.(*SyncResult)
+	switch tag {
+	case 1: // result.summary
+		if wire != proto1.WireBytes {
+			return true, proto1.ErrInternalBadWireType
+		}
+		msg := new(SyncSummary)
+		err := b.DecodeMessage(msg)
+		m.Result = &SyncResult_Summary{msg}
+		return true, err
+	case 2: // result.ids
+		if wire != proto1.WireBytes {
+			return true, proto1.ErrInternalBadWireType
+		}
+		msg := new(SyncIds)
+		err := b.DecodeMessage(msg)
+		m.Result = &SyncResult_Ids{msg}
+		return true, err
+	case 3: // result.end
+		if wire != proto1.WireBytes {
+			return true, proto1.ErrInternalBadWireType
+		}
+		msg := new(StreamEnd)
+		err := b.DecodeMessage(msg)
+		m.Result = &SyncResult_End{msg}
+		return true, err
+	case 4: // result.error
+		if wire != proto1.WireBytes {
+			return true, proto1.ErrInternalBadWireType
+		}
+		msg := new(StreamError)
+		err := b.DecodeMessage(msg)
+		m.Result = &SyncResult_Error{msg}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _SyncResult_OneofSizer(msg proto1.Message) (n int) {
+	m := msg.(*SyncResult)
+	// result
+	switch x := m.Result.(type) {
+	case *SyncResult_Summary:
+		s := proto1.Size(x.Summary)
+		n += proto1.SizeVarint(1<<3 | proto1.WireBytes)
+		n += proto1.SizeVarint(uint64(s))
+		n += s
+	case *SyncResult_Ids:
+		s := proto1.Size(x.Ids)
+		n += proto1.SizeVarint(2<<3 | proto1.WireBytes)
+		n += proto1.SizeVarint(uint64(s))
+		n += s
+	case *SyncResult_End:
+		s := proto1.Size(x.End)
+		n += proto1.SizeVarint(3<<3 | proto1.WireBytes)
+		n += proto1.SizeVarint(uint64(s))
+		n += s
+	case *SyncResult_Error:
+		s := proto1.Size(x.Error)
+		n += proto1.SizeVarint(4<<3 | proto1.WireBytes)
+		n += proto1.SizeVarint(uint64(s))
+		n += s
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
+type SyncSummary struct {
+	// the digest of a bucket is the xor of the hashes of its ids
+	Digests []uint64 `protobuf:"fixed64,1,rep,packed,name=digests" json:"digests,omitempty"`
+	Counts  []uint32 `protobuf:"varint,2,rep,packed,name=counts" json:"counts,omitempty"`
+}
+
+func (m *SyncSummary) Reset()                    { *m = SyncSummary{} }
+func (m *SyncSummary) String() string            { return proto1.CompactTextString(m) }
+func (*SyncSummary) ProtoMessage()               {}
+func (*SyncSummary) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{29} }
+
+type SyncIds struct {
+	Ids []string `protobuf:"bytes,1,rep,name=ids" json:"ids,omitempty"`
+}
+
+func (m *SyncIds) Reset()                    { *m = SyncIds{} }
+func (m *SyncIds) String() string            { return proto1.CompactTextString(m) }
+func (*SyncIds) ProtoMessage()               {}
+func (*SyncIds) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{30} }
 
 func init() {
 	proto1.RegisterType((*StreamEnd)(nil), "proto.StreamEnd")
@@ -1047,6 +1352,7 @@ func init() {
 	proto1.RegisterType((*Pong)(nil), "proto.Pong")
 	proto1.RegisterType((*QueryRequest)(nil), "proto.QueryRequest")
 	proto1.RegisterType((*QueryResult)(nil), "proto.QueryResult")
+	proto1.RegisterType((*QueryCursor)(nil), "proto.QueryCursor")
 	proto1.RegisterType((*QueryResultValue)(nil), "proto.QueryResultValue")
 	proto1.RegisterType((*SimpleValue)(nil), "proto.SimpleValue")
 	proto1.RegisterType((*CompoundValue)(nil), "proto.CompoundValue")
@@ -1060,52 +1366,72 @@ func init() {
 	proto1.RegisterType((*PushReject)(nil), "proto.PushReject")
 	proto1.RegisterType((*PushValue)(nil), "proto.PushValue")
 	proto1.RegisterType((*PushEnd)(nil), "proto.PushEnd")
+	proto1.RegisterType((*SubscribeRequest)(nil), "proto.SubscribeRequest")
+	proto1.RegisterType((*GossipAnnounce)(nil), "proto.GossipAnnounce")
+	proto1.RegisterType((*SyncRequest)(nil), "proto.SyncRequest")
+	proto1.RegisterType((*SyncBucketsRequest)(nil), "proto.SyncBucketsRequest")
+	proto1.RegisterType((*SyncResult)(nil), "proto.SyncResult")
+	proto1.RegisterType((*SyncSummary)(nil), "proto.SyncSummary")
+	proto1.RegisterType((*SyncIds)(nil), "proto.SyncIds")
 }
 
 func init() { proto1.RegisterFile("node.proto", fileDescriptorNode) }
 
 var fileDescriptorNode = []byte{
-	// 664 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xb4, 0x94, 0x4d, 0x6f, 0xd3, 0x4c,
-	0x10, 0xc7, 0xe3, 0xda, 0x4d, 0x93, 0x71, 0x9e, 0xa7, 0xe9, 0x3e, 0x95, 0x9e, 0xa8, 0xaa, 0x50,
-	0x59, 0x2a, 0x5a, 0xf1, 0x52, 0xa4, 0x70, 0xe1, 0x86, 0x08, 0x54, 0x0a, 0x20, 0x20, 0x18, 0x09,
-	0x89, 0xa3, 0x13, 0x4f, 0x5b, 0xd3, 0x78, 0xd7, 0xf5, 0xda, 0x48, 0x39, 0xf0, 0x21, 0xb8, 0xf2,
-	0x39, 0xf8, 0x80, 0x68, 0xf6, 0xc5, 0x76, 0x2a, 0x2a, 0xb8, 0x70, 0xf2, 0xce, 0xcc, 0x6f, 0x67,
-	0xfe, 0x33, 0x3b, 0x09, 0x80, 0x90, 0x09, 0x9e, 0xe4, 0x85, 0x2c, 0x25, 0xdb, 0xd4, 0x9f, 0x3d,
-	0x50, 0x65, 0x56, 0x1a, 0xd7, 0xde, 0xbf, 0x59, 0x2c, 0xd2, 0x33, 0x54, 0xd6, 0xe6, 0x21, 0xf4,
-	0x3f, 0x94, 0x05, 0xc6, 0xd9, 0xa9, 0x48, 0xf8, 0x1d, 0x08, 0xad, 0x51, 0x14, 0xb2, 0x60, 0xbb,
-	0xb0, 0x89, 0x74, 0x18, 0x79, 0x07, 0xde, 0x71, 0x3f, 0x32, 0x06, 0xdf, 0x81, 0xed, 0xb7, 0x32,
-	0xc1, 0x97, 0xe2, 0x4c, 0x46, 0x78, 0x55, 0xa1, 0x2a, 0xf9, 0x0c, 0x7a, 0xce, 0xc5, 0x18, 0x04,
-	0x39, 0xa2, 0xbb, 0xa3, 0xcf, 0x6c, 0x1f, 0xfa, 0x79, 0x35, 0x5f, 0xa6, 0xea, 0x02, 0x8b, 0xd1,
-	0x86, 0x0e, 0x34, 0x0e, 0xba, 0x91, 0x8a, 0x33, 0x39, 0xf2, 0xcd, 0x0d, 0x3a, 0x53, 0x91, 0x37,
-	0x56, 0xa8, 0x2b, 0xf2, 0x14, 0x86, 0x8d, 0x4b, 0xe5, 0x52, 0x28, 0x64, 0xf7, 0xa1, 0xe7, 0xfa,
-	0x19, 0x79, 0x07, 0xfe, 0x71, 0x38, 0xde, 0x36, 0x7d, 0x9d, 0xd4, 0x68, 0x0d, 0xf0, 0x2e, 0x04,
-	0xb3, 0x54, 0x9c, 0xeb, 0xaf, 0x14, 0xe7, 0xfc, 0x10, 0x06, 0xef, 0x2b, 0x2c, 0x56, 0xb6, 0x00,
-	0xb5, 0x7b, 0x45, 0xb6, 0x6b, 0x57, 0x1b, 0xfc, 0xbb, 0x07, 0xa1, 0xc5, 0x54, 0xb5, 0x2c, 0xd9,
-	0x23, 0xd8, 0xfc, 0x12, 0x2f, 0x2b, 0xd4, 0x54, 0x38, 0xfe, 0xdf, 0xd6, 0x6b, 0x21, 0x1f, 0x29,
-	0x3c, 0xed, 0x44, 0x86, 0x63, 0x87, 0xe0, 0xa3, 0x48, 0x74, 0xdb, 0xe1, 0x78, 0x68, 0xf1, 0x7a,
-	0xe6, 0xd3, 0x4e, 0x44, 0x61, 0x76, 0xcf, 0xcd, 0xda, 0xd7, 0x1c, 0x5b, 0xe7, 0x28, 0x42, 0x19,
-	0x35, 0x32, 0xe9, 0x41, 0xb7, 0xd0, 0x95, 0xf8, 0x57, 0x18, 0x5e, 0x2f, 0xcc, 0x1e, 0x40, 0x57,
-	0xa5, 0x59, 0xbe, 0x74, 0x0a, 0xeb, 0x54, 0xda, 0xe9, 0xc4, 0x59, 0x86, 0x8d, 0xa1, 0xb7, 0x90,
-	0x59, 0x2e, 0xab, 0x5a, 0xe2, 0xae, 0xe5, 0x9f, 0x5b, 0xb7, 0xbb, 0x51, 0x73, 0x93, 0x2d, 0x3b,
-	0x02, 0xfe, 0xc3, 0x83, 0xb0, 0x95, 0x96, 0xed, 0x43, 0x2f, 0x15, 0x46, 0x86, 0x2e, 0xee, 0xd3,
-	0x35, 0xe7, 0x61, 0x1c, 0x42, 0x55, 0x16, 0xa9, 0x38, 0x37, 0x80, 0xde, 0x83, 0x69, 0x27, 0x6a,
-	0x3b, 0xd9, 0x5d, 0x08, 0x68, 0x59, 0xed, 0x14, 0x9a, 0x69, 0xc5, 0x25, 0x66, 0x28, 0xca, 0x69,
-	0x27, 0xd2, 0x71, 0x92, 0x4d, 0xdf, 0x89, 0x4c, 0x56, 0xa3, 0x60, 0x4d, 0x76, 0xcd, 0x52, 0x8c,
-	0xea, 0x3b, 0xae, 0x91, 0xfd, 0x04, 0xfe, 0x59, 0x6b, 0x8e, 0x1d, 0x41, 0x30, 0xa7, 0x4c, 0x66,
-	0x85, 0xfe, 0xb3, 0x99, 0x5e, 0xe3, 0x4a, 0x87, 0x67, 0x71, 0x5a, 0x44, 0x1a, 0xe0, 0xaf, 0x60,
-	0xd0, 0xf6, 0xb2, 0x21, 0xf8, 0x97, 0xe8, 0x16, 0x86, 0x8e, 0xec, 0xd8, 0xad, 0xc7, 0xc6, 0x4d,
-	0xc3, 0xb7, 0x7b, 0xc1, 0x6f, 0x43, 0xf8, 0x22, 0x2e, 0x63, 0xb7, 0x7d, 0x0c, 0x82, 0x4b, 0x5c,
-	0x29, 0xad, 0xa1, 0x1f, 0xe9, 0x33, 0xff, 0xe6, 0x01, 0x18, 0x46, 0xaf, 0xde, 0x11, 0x04, 0x49,
-	0x5c, 0xc6, 0xf6, 0x5d, 0x77, 0x6c, 0x6a, 0x02, 0xde, 0xcd, 0x3f, 0xe3, 0x42, 0x4f, 0x87, 0x80,
-	0xbf, 0xba, 0x72, 0x63, 0x23, 0xc9, 0x54, 0xfc, 0xc5, 0x00, 0x98, 0x15, 0x49, 0xc5, 0x07, 0x46,
-	0x0f, 0x7f, 0x08, 0xe1, 0xac, 0x52, 0x17, 0xae, 0xd5, 0x5b, 0x00, 0x22, 0xce, 0x50, 0xe5, 0xf1,
-	0x02, 0x5d, 0xc3, 0x2d, 0x0f, 0xcf, 0x61, 0x60, 0xf0, 0xfa, 0x57, 0xde, 0x8d, 0x17, 0x0b, 0xcc,
-	0xcb, 0x6b, 0x9d, 0x13, 0xf4, 0x4c, 0x07, 0x68, 0xa1, 0x0d, 0x42, 0x70, 0x81, 0xa4, 0xcd, 0xb6,
-	0xdf, 0x86, 0x23, 0xb4, 0x63, 0xb2, 0xc8, 0xa4, 0x6b, 0x1e, 0x9e, 0x0f, 0x00, 0x9a, 0x64, 0x9c,
-	0x1b, 0xcb, 0xd0, 0x37, 0xfc, 0x0b, 0xce, 0xa1, 0x4f, 0xcc, 0xfa, 0xd6, 0x7a, 0xbf, 0xd9, 0xda,
-	0x3f, 0x7a, 0x97, 0x66, 0x4f, 0x3f, 0xc1, 0x16, 0xd5, 0x38, 0x15, 0x09, 0x8d, 0x4c, 0xb9, 0x74,
-	0xca, 0xfc, 0xb6, 0xa2, 0x96, 0x87, 0x8d, 0x60, 0x4b, 0xea, 0x17, 0x51, 0x3a, 0xbb, 0x1f, 0x39,
-	0xb3, 0x91, 0xef, 0xb7, 0xe4, 0xcf, 0xbb, 0xba, 0xf6, 0xe3, 0x9f, 0x01, 0x00, 0x00, 0xff, 0xff,
-	0xfa, 0xff, 0x2a, 0xed, 0x2e, 0x06, 0x00, 0x00,
+	// 867 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xac, 0x54, 0xdd, 0x6e, 0xeb, 0x44,
+	0x10, 0x8e, 0x63, 0x37, 0x3f, 0xe3, 0xb4, 0x27, 0x67, 0xa9, 0xc0, 0x3a, 0x54, 0xe8, 0xb0, 0x20,
+	0x5a, 0x51, 0x28, 0x52, 0xb8, 0xe1, 0x02, 0xa9, 0x6a, 0x4a, 0x45, 0x0a, 0x02, 0x82, 0x23, 0x21,
+	0x71, 0xe9, 0x9f, 0x6d, 0x6a, 0x1a, 0xef, 0xba, 0x5e, 0x1b, 0x29, 0x17, 0x3c, 0x04, 0xef, 0xc2,
+	0x25, 0x0f, 0xc0, 0x63, 0xa1, 0xd9, 0x1f, 0xdb, 0x69, 0x29, 0x14, 0xe9, 0x5c, 0x79, 0x77, 0xe6,
+	0xdb, 0x99, 0xef, 0xdb, 0xf9, 0xbc, 0x00, 0x5c, 0xa4, 0xec, 0xac, 0x28, 0x45, 0x25, 0xc8, 0x9e,
+	0xfa, 0xbc, 0x02, 0x59, 0xe5, 0x95, 0x0e, 0xbd, 0x3a, 0xc8, 0x23, 0x9e, 0xdd, 0x30, 0x69, 0xf6,
+	0xd4, 0x87, 0xf1, 0xaa, 0x2a, 0x59, 0x94, 0x5f, 0xf1, 0x94, 0x7e, 0x00, 0xbe, 0xd9, 0x94, 0xa5,
+	0x28, 0xc9, 0x21, 0xec, 0x31, 0x5c, 0x04, 0xce, 0x6b, 0xe7, 0x64, 0x1c, 0xea, 0x0d, 0x7d, 0x09,
+	0x2f, 0xbe, 0x17, 0x29, 0xbb, 0xe6, 0x37, 0x22, 0x64, 0xf7, 0x35, 0x93, 0x15, 0x5d, 0xc2, 0xc8,
+	0x86, 0x08, 0x01, 0xaf, 0x60, 0xcc, 0x9e, 0x51, 0x6b, 0x72, 0x04, 0xe3, 0xa2, 0x8e, 0x37, 0x99,
+	0xbc, 0x65, 0x65, 0xd0, 0x57, 0x89, 0x36, 0x80, 0x27, 0x32, 0x7e, 0x23, 0x02, 0x57, 0x9f, 0xc0,
+	0x35, 0x36, 0xf9, 0xce, 0x10, 0xb5, 0x4d, 0xce, 0x61, 0xda, 0x86, 0x64, 0x21, 0xb8, 0x64, 0xe4,
+	0x14, 0x46, 0x56, 0x4f, 0xe0, 0xbc, 0x76, 0x4f, 0xfc, 0xd9, 0x0b, 0xad, 0xeb, 0xac, 0x81, 0x36,
+	0x00, 0x3a, 0x00, 0x6f, 0x99, 0xf1, 0xb5, 0xfa, 0x0a, 0xbe, 0xa6, 0x5f, 0xc2, 0xe4, 0xc7, 0x9a,
+	0x95, 0x5b, 0xd3, 0x00, 0xe5, 0xde, 0xe3, 0xde, 0xca, 0x55, 0x1b, 0xf2, 0x36, 0x0c, 0x92, 0xba,
+	0x94, 0x42, 0x13, 0x1f, 0x85, 0x66, 0x47, 0xff, 0x72, 0xc0, 0x37, 0xc7, 0x65, 0xbd, 0xa9, 0xc8,
+	0x67, 0xb0, 0xf7, 0x6b, 0xb4, 0xa9, 0x99, 0x3a, 0xed, 0xcf, 0xde, 0x31, 0x3c, 0x3a, 0x90, 0x9f,
+	0x30, 0xbd, 0xe8, 0x85, 0x1a, 0x47, 0x3e, 0x04, 0x97, 0xf1, 0x54, 0x55, 0xf5, 0x67, 0x53, 0x03,
+	0x6f, 0x66, 0xb1, 0xe8, 0x85, 0x98, 0x26, 0x1f, 0xdb, 0x19, 0xb8, 0x0a, 0x47, 0x76, 0x71, 0x98,
+	0xc1, 0x8a, 0x0a, 0x42, 0x3e, 0x69, 0xa8, 0x7a, 0x3b, 0x60, 0xc5, 0xe1, 0x52, 0x65, 0x16, 0x3d,
+	0x2b, 0x60, 0x3e, 0x82, 0x41, 0xa9, 0x78, 0xd1, 0x63, 0xf0, 0x3b, 0x10, 0x12, 0xc0, 0x30, 0x11,
+	0x35, 0xaf, 0xcc, 0x10, 0xdd, 0xd0, 0x6e, 0xe9, 0x6f, 0x30, 0x7d, 0xa8, 0x07, 0x9b, 0xca, 0x2c,
+	0x2f, 0x36, 0x56, 0x78, 0xc3, 0x50, 0x05, 0xad, 0x66, 0x83, 0x21, 0x33, 0x18, 0x25, 0x22, 0x2f,
+	0x44, 0xdd, 0x28, 0x3f, 0x34, 0xf8, 0x4b, 0x13, 0xb6, 0x27, 0x1a, 0xdc, 0x7c, 0x68, 0x6e, 0x96,
+	0xfe, 0xe1, 0x80, 0xdf, 0x29, 0x4b, 0x8e, 0x60, 0x94, 0x71, 0x4d, 0x43, 0x33, 0xc5, 0x63, 0x36,
+	0x42, 0x28, 0xf8, 0xb2, 0x2a, 0x33, 0xbe, 0xd6, 0x00, 0x65, 0xbb, 0x45, 0x2f, 0xec, 0x06, 0xc9,
+	0x47, 0xe0, 0xe1, 0xbf, 0x11, 0xb8, 0x0f, 0x86, 0x10, 0x55, 0x2c, 0x67, 0xbc, 0x5a, 0xf4, 0x42,
+	0x95, 0x47, 0xda, 0xf8, 0x9d, 0x8b, 0x74, 0x1b, 0x78, 0x3b, 0xb4, 0x1b, 0x2c, 0xe6, 0xb0, 0xbf,
+	0xc5, 0xb5, 0xb4, 0xbf, 0x80, 0xfd, 0x1d, 0x71, 0xe4, 0x18, 0xbc, 0x18, 0x2b, 0x69, 0xc7, 0xbe,
+	0x65, 0x2a, 0x7d, 0xcb, 0xb6, 0x2a, 0xbd, 0x8c, 0xb2, 0x32, 0x54, 0x00, 0xfa, 0x0d, 0x4c, 0xba,
+	0x51, 0x32, 0x05, 0xf7, 0x8e, 0x59, 0x7f, 0xe2, 0x92, 0x9c, 0x58, 0xd7, 0xf5, 0x9f, 0xba, 0x7c,
+	0x63, 0x37, 0xfa, 0x3e, 0xf8, 0x5f, 0x45, 0x55, 0x64, 0xcd, 0x4e, 0xc0, 0xbb, 0x63, 0x5b, 0xa9,
+	0x38, 0x8c, 0x43, 0xb5, 0xa6, 0xbf, 0x3b, 0x00, 0x1a, 0xa3, 0x1c, 0x7d, 0x0c, 0x5e, 0x1a, 0x55,
+	0x91, 0x99, 0xeb, 0x4b, 0x53, 0x1a, 0x01, 0x3f, 0xc4, 0xbf, 0xb0, 0x44, 0xdd, 0x0e, 0x02, 0xde,
+	0xbc, 0x93, 0x3b, 0xde, 0x9c, 0x01, 0xb4, 0x1d, 0xff, 0xe1, 0x02, 0x88, 0x21, 0x89, 0xcd, 0x27,
+	0x9a, 0x0f, 0xfd, 0x14, 0xfc, 0x65, 0x2d, 0x6f, 0xad, 0xd4, 0xf7, 0x00, 0x78, 0x94, 0x33, 0x59,
+	0x44, 0x09, 0xb3, 0x82, 0x3b, 0x11, 0x5a, 0xc0, 0x44, 0xc3, 0x9b, 0x47, 0x65, 0x10, 0x25, 0x09,
+	0x2b, 0xaa, 0x07, 0xca, 0x11, 0x74, 0xa1, 0x12, 0x68, 0x68, 0x0d, 0x41, 0x70, 0xc9, 0x90, 0x5b,
+	0xd0, 0x7f, 0x04, 0x0e, 0x99, 0xb9, 0x26, 0x03, 0x99, 0x0f, 0xf4, 0xe0, 0xe9, 0x04, 0xa0, 0x2d,
+	0x46, 0x29, 0x40, 0x8b, 0x7e, 0xe2, 0xd1, 0x8d, 0x61, 0x8c, 0x98, 0x5d, 0xd7, 0x3a, 0xff, 0xe1,
+	0xda, 0x67, 0xcd, 0xa5, 0xf5, 0xe9, 0xcf, 0x30, 0xc4, 0x1e, 0x57, 0x3c, 0xc5, 0x2b, 0x93, 0xb6,
+	0x9c, 0x34, 0xaf, 0x40, 0x27, 0x82, 0x4f, 0x84, 0x50, 0x13, 0x91, 0xaa, 0xba, 0x1b, 0xda, 0x2d,
+	0x39, 0xec, 0x4e, 0xb9, 0xa1, 0x3f, 0x87, 0xe9, 0xaa, 0x8e, 0x65, 0x52, 0x66, 0x31, 0xfb, 0xf7,
+	0xe7, 0xb6, 0xf3, 0xf8, 0xf4, 0x77, 0x1f, 0x1f, 0x0a, 0x07, 0x5f, 0x0b, 0x29, 0xb3, 0xe2, 0x82,
+	0x73, 0x51, 0xf3, 0x84, 0xa1, 0x1b, 0xb2, 0xd4, 0x4e, 0x14, 0x97, 0xf4, 0x1c, 0xfc, 0xd5, 0x96,
+	0x27, 0xb6, 0xc5, 0x11, 0x8c, 0x9b, 0x39, 0x9b, 0x36, 0x6d, 0x00, 0xad, 0x13, 0x67, 0x46, 0xc1,
+	0x7e, 0xa8, 0xd6, 0x74, 0x06, 0x04, 0x0b, 0xcc, 0xeb, 0xe4, 0x8e, 0x55, 0xb2, 0xad, 0x33, 0x8c,
+	0x75, 0x44, 0x35, 0xdb, 0x9f, 0xf7, 0xa7, 0x4e, 0x68, 0x43, 0xf4, 0x4f, 0x07, 0x40, 0x77, 0x55,
+	0xbf, 0xcd, 0x19, 0x0c, 0x65, 0x9d, 0xe7, 0x91, 0x51, 0xd6, 0x71, 0xfa, 0x96, 0x27, 0x2b, 0x9d,
+	0x59, 0xf4, 0x42, 0x0b, 0x22, 0x54, 0xab, 0xd0, 0x53, 0x3a, 0xe8, 0x60, 0xaf, 0x53, 0x89, 0x33,
+	0xca, 0x52, 0x69, 0x27, 0xe9, 0x3e, 0xf3, 0x0f, 0xf3, 0xfe, 0xcf, 0x1f, 0x76, 0x0a, 0x7e, 0x87,
+	0x19, 0x6a, 0x4d, 0xb3, 0x35, 0x93, 0x46, 0xeb, 0x40, 0x6b, 0x35, 0x21, 0xfa, 0x2e, 0x0c, 0x0d,
+	0xb5, 0xc7, 0xb7, 0x1f, 0x0f, 0x54, 0xbf, 0xcf, 0xff, 0x1e, 0x00, 0x6c, 0x3d, 0x59, 0x03, 0x83,
+	0x08, 0x00, 0x00,
 }
@@ -40,6 +40,8 @@ type StatementBody struct {
 	//	*StatementBody_Compound
 	//	*StatementBody_Envelope
 	//	*StatementBody_Archive
+	//	*StatementBody_Retract
+	//	*StatementBody_Supersede
 	Body isStatementBody_Body `protobuf_oneof:"body"`
 }
 
@@ -64,11 +66,19 @@ type StatementBody_Envelope struct {
 type StatementBody_Archive struct {
 	Archive *ArchiveStatement `protobuf:"bytes,4,opt,name=archive,oneof"`
 }
+type StatementBody_Retract struct {
+	Retract *RetractStatement `protobuf:"bytes,5,opt,name=retract,oneof"`
+}
+type StatementBody_Supersede struct {
+	Supersede *SupersedeStatement `protobuf:"bytes,6,opt,name=supersede,oneof"`
+}
 
-func (*StatementBody_Simple) isStatementBody_Body()   {}
-func (*StatementBody_Compound) isStatementBody_Body() {}
-func (*StatementBody_Envelope) isStatementBody_Body() {}
-func (*StatementBody_Archive) isStatementBody_Body()  {}
+func (*StatementBody_Simple) isStatementBody_Body()    {}
+func (*StatementBody_Compound) isStatementBody_Body()  {}
+func (*StatementBody_Envelope) isStatementBody_Body()  {}
+func (*StatementBody_Archive) isStatementBody_Body()   {}
+func (*StatementBody_Retract) isStatementBody_Body()   {}
+func (*StatementBody_Supersede) isStatementBody_Body() {}
 
 func (m *StatementBody) GetBody() isStatementBody_Body {
 	if m != nil {
@@ -105,6 +115,20 @@ func (m *StatementBody) GetArchive() *ArchiveStatement {
 	return nil
 }
 
+func (m *StatementBody) GetRetract() *RetractStatement {
+	if x, ok := m.GetBody().(*StatementBody_Retract); ok {
+		return x.Retract
+	}
+	return nil
+}
+
+func (m *StatementBody) GetSupersede() *SupersedeStatement {
+	if x, ok := m.GetBody().(*StatementBody_Supersede); ok {
+		return x.Supersede
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*StatementBody) XXX_OneofFuncs() (func(msg proto1.Message, b *proto1.Buffer) error, func(msg proto1.Message, tag, wire int, b *proto1.Buffer) (bool, error), func(msg proto1.Message) (n int), []interface{}) {
 	return _StatementBody_OneofMarshaler, _StatementBody_OneofUnmarshaler, _StatementBody_OneofSizer, []interface{}{
@@ -112,6 +136,8 @@ func (*StatementBody) XXX_OneofFuncs() (func(msg proto1.Message, b *proto1.Buffe
 		(*StatementBody_Compound)(nil),
 		(*StatementBody_Envelope)(nil),
 		(*StatementBody_Archive)(nil),
+		(*StatementBody_Retract)(nil),
+		(*StatementBody_Supersede)(nil),
 	}
 }
 
@@ -139,6 +165,16 @@ func _StatementBody_OneofMarshaler(msg proto1.Message, b *proto1.Buffer) error {
 		if err := b.EncodeMessage(x.Archive); err != nil {
 			return err
 		}
+	case *StatementBody_Retract:
+		_ = b.EncodeVarint(5<<3 | proto1.WireBytes)
+		if err := b.EncodeMessage(x.Retract); err != nil {
+			return err
+		}
+	case *StatementBody_Supersede:
+		_ = b.EncodeVarint(6<<3 | proto1.WireBytes)
+		if err := b.EncodeMessage(x.Supersede); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("StatementBody.Body has unexpected type %T", x)
@@ -181,6 +217,22 @@ func _StatementBody_OneofUnmarshaler(msg proto1.Message, tag, wire int, b *proto
 		err := b.DecodeMessage(msg)
 		m.Body = &StatementBody_Archive{msg}
 		return true, err
+	case 5: // body.retract
+		if wire != proto1.WireBytes {
+			return true, proto1.ErrInternalBadWireType
+		}
+		msg := new(RetractStatement)
+		err := b.DecodeMessage(msg)
+		m.Body = &StatementBody_Retract{msg}
+		return true, err
+	case 6: // body.supersede
+		if wire != proto1.WireBytes {
+			return true, proto1.ErrInternalBadWireType
+		}
+		msg := new(SupersedeStatement)
+		err := b.DecodeMessage(msg)
+		m.Body = &StatementBody_Supersede{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -210,6 +262,16 @@ func _StatementBody_OneofSizer(msg proto1.Message) (n int) {
 		n += proto1.SizeVarint(4<<3 | proto1.WireBytes)
 		n += proto1.SizeVarint(uint64(s))
 		n += s
+	case *StatementBody_Retract:
+		s := proto1.Size(x.Retract)
+		n += proto1.SizeVarint(5<<3 | proto1.WireBytes)
+		n += proto1.SizeVarint(uint64(s))
+		n += s
+	case *StatementBody_Supersede:
+		s := proto1.Size(x.Supersede)
+		n += proto1.SizeVarint(6<<3 | proto1.WireBytes)
+		n += proto1.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -262,6 +324,10 @@ func (m *EnvelopeStatement) GetBody() []*Statement {
 }
 
 type ArchiveStatement struct {
+	Count int64  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	First string `protobuf:"bytes,2,opt,name=first,proto3" json:"first,omitempty"`
+	Last  string `protobuf:"bytes,3,opt,name=last,proto3" json:"last,omitempty"`
+	Root  []byte `protobuf:"bytes,4,opt,name=root,proto3" json:"root,omitempty"`
 }
 
 func (m *ArchiveStatement) Reset()                    { *m = ArchiveStatement{} }
@@ -269,6 +335,32 @@ func (m *ArchiveStatement) String() string            { return proto1.CompactTex
 func (*ArchiveStatement) ProtoMessage()               {}
 func (*ArchiveStatement) Descriptor() ([]byte, []int) { return fileDescriptorStmt, []int{5} }
 
+type RetractStatement struct {
+	Targets []string `protobuf:"bytes,1,rep,name=targets" json:"targets,omitempty"`
+}
+
+func (m *RetractStatement) Reset()                    { *m = RetractStatement{} }
+func (m *RetractStatement) String() string            { return proto1.CompactTextString(m) }
+func (*RetractStatement) ProtoMessage()               {}
+func (*RetractStatement) Descriptor() ([]byte, []int) { return fileDescriptorStmt, []int{6} }
+
+type SupersedeStatement struct {
+	Targets []string         `protobuf:"bytes,1,rep,name=targets" json:"targets,omitempty"`
+	Body    *SimpleStatement `protobuf:"bytes,2,opt,name=body" json:"body,omitempty"`
+}
+
+func (m *SupersedeStatement) Reset()                    { *m = SupersedeStatement{} }
+func (m *SupersedeStatement) String() string            { return proto1.CompactTextString(m) }
+func (*SupersedeStatement) ProtoMessage()               {}
+func (*SupersedeStatement) Descriptor() ([]byte, []int) { return fileDescriptorStmt, []int{7} }
+
+func (m *SupersedeStatement) GetBody() *SimpleStatement {
+	if m != nil {
+		return m.Body
+	}
+	return nil
+}
+
 func init() {
 	proto1.RegisterType((*Statement)(nil), "proto.Statement")
 	proto1.RegisterType((*StatementBody)(nil), "proto.StatementBody")
@@ -276,33 +368,42 @@ func init() {
 	proto1.RegisterType((*CompoundStatement)(nil), "proto.CompoundStatement")
 	proto1.RegisterType((*EnvelopeStatement)(nil), "proto.EnvelopeStatement")
 	proto1.RegisterType((*ArchiveStatement)(nil), "proto.ArchiveStatement")
+	proto1.RegisterType((*RetractStatement)(nil), "proto.RetractStatement")
+	proto1.RegisterType((*SupersedeStatement)(nil), "proto.SupersedeStatement")
 }
 
 func init() { proto1.RegisterFile("stmt.proto", fileDescriptorStmt) }
 
 var fileDescriptorStmt = []byte{
-	// 363 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x74, 0x92, 0xcd, 0x6a, 0xdb, 0x40,
-	0x10, 0xc7, 0xbb, 0x92, 0xac, 0x56, 0xe3, 0x7e, 0xd8, 0x4b, 0x71, 0xf7, 0xd0, 0x83, 0x10, 0x3d,
-	0x88, 0x1e, 0x4c, 0xb1, 0xa1, 0x90, 0x53, 0x88, 0x43, 0x20, 0x67, 0xe5, 0x09, 0xf4, 0x31, 0xb1,
-	0x15, 0x2c, 0xed, 0xa2, 0x5d, 0x1b, 0xfc, 0x70, 0x79, 0xa5, 0x3c, 0x43, 0xd8, 0x0f, 0x4b, 0xfe,
-	0x20, 0x27, 0x8d, 0x7e, 0xf3, 0xff, 0xcf, 0x30, 0x33, 0x0b, 0x20, 0x55, 0xa3, 0xe6, 0xa2, 0xe3,
-	0x8a, 0xd3, 0x91, 0xf9, 0x24, 0xaf, 0x04, 0xa2, 0x27, 0x95, 0x2b, 0x6c, 0xb0, 0x55, 0xf4, 0x3b,
-	0x78, 0x75, 0xc5, 0x48, 0x4c, 0xd2, 0x28, 0xf3, 0xea, 0x8a, 0xfe, 0x86, 0x48, 0xec, 0x8a, 0x6d,
-	0x2d, 0x37, 0xd8, 0x31, 0xcf, 0xe0, 0x01, 0xe8, 0x6c, 0x9b, 0x37, 0x28, 0x45, 0x5e, 0x22, 0xf3,
-	0x6d, 0xb6, 0x07, 0x34, 0x85, 0xa0, 0xe0, 0xd5, 0x81, 0x05, 0x31, 0x49, 0xc7, 0x8b, 0x9f, 0xb6,
-	0xed, 0xbc, 0xef, 0xb5, 0xe2, 0xd5, 0x21, 0x33, 0x0a, 0x5d, 0x47, 0xd5, 0x0d, 0x4a, 0x95, 0x37,
-	0x82, 0x8d, 0x62, 0x92, 0xfa, 0xd9, 0x00, 0x74, 0x56, 0xd6, 0xeb, 0x36, 0x57, 0xbb, 0x0e, 0x59,
-	0x18, 0x93, 0xf4, 0x6b, 0x36, 0x80, 0xe4, 0x8d, 0xc0, 0xb7, 0xb3, 0x9a, 0xf4, 0x1f, 0x84, 0xb2,
-	0x6e, 0xc4, 0x16, 0xcd, 0x1c, 0xe3, 0xc5, 0xec, 0xd8, 0xd9, 0xc0, 0x5e, 0xfb, 0xf8, 0x29, 0x73,
-	0x3a, 0xfa, 0x1f, 0xbe, 0x94, 0xbc, 0x11, 0x7c, 0xd7, 0x56, 0x66, 0xc8, 0xf1, 0x82, 0x39, 0xcf,
-	0xbd, 0xc3, 0xa7, 0xae, 0x5e, 0xab, 0x7d, 0xd8, 0xee, 0x71, 0xcb, 0x85, 0x1d, 0x7f, 0xf0, 0x3d,
-	0x38, 0x7c, 0xe6, 0x3b, 0x6a, 0xe9, 0x12, 0x3e, 0xe7, 0x5d, 0xb9, 0xa9, 0xf7, 0xe8, 0x96, 0xf3,
-	0xcb, 0xd9, 0xee, 0x2c, 0x3d, 0x75, 0x1d, 0x95, 0xab, 0xd0, 0xae, 0x33, 0x41, 0xf8, 0x71, 0x31,
-	0x09, 0x9d, 0x41, 0xc8, 0x8b, 0x17, 0x2c, 0x95, 0xbb, 0x9c, 0xfb, 0xa3, 0x14, 0x82, 0x0e, 0x9f,
-	0x25, 0xf3, 0x62, 0x3f, 0x8d, 0x32, 0x13, 0x6b, 0xa6, 0xf2, 0xb5, 0x64, 0xbe, 0x65, 0x3a, 0xd6,
-	0xac, 0x42, 0x21, 0x59, 0x60, 0x99, 0x8e, 0x93, 0x5b, 0x98, 0x5e, 0x0d, 0x4f, 0xff, 0xba, 0x93,
-	0x92, 0xd8, 0xff, 0x78, 0xb1, 0xf6, 0xa8, 0xc9, 0x0d, 0x4c, 0xaf, 0xb6, 0x40, 0xff, 0x9c, 0x15,
-	0x98, 0x5c, 0xbe, 0x09, 0x67, 0xa5, 0x30, 0xb9, 0xdc, 0x44, 0x11, 0x1a, 0xe9, 0xf2, 0x3d, 0x00,
-	0x00, 0xff, 0xff, 0xc4, 0xae, 0x82, 0x5f, 0xc3, 0x02, 0x00, 0x00,
+	// 469 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x7c, 0x53, 0x4d, 0x6b, 0xdb, 0x40,
+	0x10, 0xad, 0x24, 0xdb, 0xa9, 0xc6, 0x69, 0xeb, 0x2c, 0x21, 0xdd, 0x42, 0x0f, 0x42, 0xf4, 0x20,
+	0x4a, 0x09, 0xc5, 0x81, 0x42, 0x4e, 0xa5, 0x29, 0x85, 0x9e, 0xb7, 0xb7, 0xde, 0xd6, 0xd2, 0xd8,
+	0x51, 0xb1, 0xb4, 0xcb, 0xee, 0x2a, 0x90, 0x1f, 0xd7, 0xbf, 0xd2, 0xdf, 0x52, 0xf6, 0x43, 0x92,
+	0x65, 0x93, 0x9c, 0x34, 0xf3, 0xe6, 0xbd, 0x5d, 0xcd, 0x9b, 0x59, 0x00, 0x6d, 0x1a, 0x73, 0x2d,
+	0x95, 0x30, 0x82, 0xcc, 0xdd, 0x27, 0xff, 0x1b, 0x41, 0xfa, 0xcb, 0x70, 0x83, 0x0d, 0xb6, 0x86,
+	0xbc, 0x86, 0xb8, 0xae, 0x68, 0x94, 0x45, 0x45, 0xca, 0xe2, 0xba, 0x22, 0xef, 0x21, 0x95, 0xdd,
+	0x66, 0x5f, 0xeb, 0x7b, 0x54, 0x34, 0x76, 0xf0, 0x08, 0xd8, 0x6a, 0xcb, 0x1b, 0xd4, 0x92, 0x97,
+	0x48, 0x13, 0x5f, 0x1d, 0x00, 0x52, 0xc0, 0x6c, 0x23, 0xaa, 0x47, 0x3a, 0xcb, 0xa2, 0x62, 0xb9,
+	0xbe, 0xf4, 0xd7, 0x5e, 0x0f, 0x77, 0xdd, 0x89, 0xea, 0x91, 0x39, 0x86, 0x3d, 0xc7, 0xd4, 0x0d,
+	0x6a, 0xc3, 0x1b, 0x49, 0xe7, 0x59, 0x54, 0x24, 0x6c, 0x04, 0x6c, 0x55, 0xd7, 0xbb, 0x96, 0x9b,
+	0x4e, 0x21, 0x5d, 0x64, 0x51, 0x71, 0xce, 0x46, 0x20, 0xff, 0x17, 0xc3, 0xab, 0xc9, 0x99, 0xe4,
+	0x33, 0x2c, 0x74, 0xdd, 0xc8, 0x3d, 0xba, 0x3e, 0x96, 0xeb, 0xab, 0xfe, 0x66, 0x07, 0x0e, 0xdc,
+	0x9f, 0x2f, 0x58, 0xe0, 0x91, 0x2f, 0xf0, 0xb2, 0x14, 0x8d, 0x14, 0x5d, 0x5b, 0xb9, 0x26, 0x97,
+	0x6b, 0x1a, 0x34, 0xdf, 0x03, 0x7c, 0xa8, 0x1a, 0xb8, 0x56, 0x87, 0xed, 0x03, 0xee, 0x85, 0xf4,
+	0xed, 0x8f, 0xba, 0x1f, 0x01, 0x9e, 0xe8, 0x7a, 0x2e, 0xb9, 0x81, 0x33, 0xae, 0xca, 0xfb, 0xfa,
+	0x01, 0x83, 0x39, 0x6f, 0x83, 0xec, 0x9b, 0x47, 0x0f, 0x55, 0x3d, 0xd3, 0x8a, 0x14, 0x1a, 0xc5,
+	0x4b, 0x43, 0xe7, 0x13, 0x11, 0xf3, 0xe8, 0x44, 0x14, 0x98, 0xe4, 0x16, 0x52, 0xdd, 0x49, 0x54,
+	0x1a, 0x2b, 0xef, 0xdd, 0x72, 0xfd, 0xae, 0xb7, 0xa3, 0xc7, 0x0f, 0x85, 0x23, 0xfb, 0x6e, 0xe1,
+	0xc7, 0x97, 0x23, 0xbc, 0x39, 0x72, 0x8e, 0x5c, 0xc1, 0x42, 0x6c, 0xfe, 0x60, 0x69, 0xc2, 0xa6,
+	0x84, 0x8c, 0x10, 0x98, 0x29, 0xdc, 0x6a, 0x1a, 0x67, 0x49, 0x91, 0x32, 0x17, 0x5b, 0xcc, 0xf0,
+	0x9d, 0xa6, 0x89, 0xc7, 0x6c, 0x6c, 0xb1, 0x0a, 0xa5, 0xa6, 0x33, 0x8f, 0xd9, 0x38, 0xff, 0x0a,
+	0x17, 0x27, 0x66, 0x93, 0x8f, 0x61, 0x85, 0xa2, 0x2c, 0x79, 0x7a, 0x90, 0x7e, 0x89, 0xf2, 0x5b,
+	0xb8, 0x38, 0x71, 0x9d, 0x7c, 0x98, 0x1c, 0xb0, 0x3a, 0xde, 0xc1, 0x20, 0xdd, 0xc2, 0xea, 0xd8,
+	0x79, 0x72, 0x09, 0xf3, 0x52, 0x74, 0xad, 0x6f, 0x31, 0x61, 0x3e, 0xb1, 0xe8, 0xb6, 0x56, 0xda,
+	0x84, 0xb7, 0xe0, 0x13, 0xdb, 0xcf, 0x9e, 0x6b, 0x13, 0x9e, 0x80, 0x8b, 0x9d, 0x17, 0x42, 0x18,
+	0x37, 0xe0, 0x73, 0xe6, 0xe2, 0xfc, 0x13, 0xac, 0x8e, 0x87, 0x45, 0x28, 0x9c, 0x19, 0xae, 0x76,
+	0x68, 0xb4, 0xfb, 0xc9, 0x94, 0xf5, 0x69, 0xfe, 0x1b, 0xc8, 0xe9, 0x8c, 0x9e, 0xe6, 0x0f, 0x66,
+	0xc5, 0xcf, 0x6d, 0xbd, 0xef, 0x78, 0xb3, 0x70, 0xc5, 0x9b, 0xff, 0x03, 0x00, 0x42, 0xd8, 0xfb,
+	0x0e, 0x11, 0x04, 0x00, 0x00,
 }
@@ -15,9 +15,14 @@ echo "Installing x/crypto deps"
 go get golang.org/x/crypto/scrypt golang.org/x/crypto/nacl/secretbox || die
 
 echo "Installing unvendored deps"
-go get github.com/gorilla/mux github.com/mattn/go-sqlite3 github.com/mitchellh/go-homedir github.com/howeyc/gopass gopkg.in/alecthomas/kingpin.v2 || die
+go get github.com/gorilla/mux github.com/mattn/go-sqlite3 github.com/lib/pq go.etcd.io/bbolt github.com/mitchellh/go-homedir github.com/howeyc/gopass gopkg.in/alecthomas/kingpin.v2 github.com/ugorji/go/codec || die
 
-echo "Installing gorocksdb; this can take a while!"
-go get -tags=embed github.com/mediachain/gorocksdb || die
+# ./setup.sh norocksdb skips gorocksdb, for pure-Go datastore builds
+if [ "$1" = "norocksdb" ]; then
+    echo "Skipping gorocksdb"
+else
+    echo "Installing gorocksdb; this can take a while!"
+    go get -tags=embed github.com/mediachain/gorocksdb || die
+fi
 
 echo "DONE"